@@ -0,0 +1,39 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfgadmin provides an HTTP handler to administrate the configuration
+// values of config.Sections.
+//
+// The handler serves the section, group and field tree as JSON, the values of
+// a section for a scope as JSON and renders HTML forms per scope. Each value
+// shows whether it has been set in the requested scope or inherited from a
+// parent scope or from the default value of a field. Submitted values get
+// validated first and written via config.Service.SetBatch only if all values
+// are valid, hence they pass all registered observer validators and the
+// WriteScopePerm checks of the FieldMeta.
+//
+// Routes, relative to the mount point of the handler:
+//
+//	GET      /sections                           JSON tree of all visible fields
+//	GET      /sections/{id}?website=1&store=2    JSON values of a section
+//	PUT/POST /sections/{id}?website=1&store=2    JSON object route => value
+//	GET      /forms/{id}?website=1&store=2       HTML form of a section
+//	POST     /forms/{id}?website=1&store=2       submits the HTML form
+//
+// Omitting website and store selects the default scope. The handler must be
+// protected by an authentication middleware, for example
+// auth.Service.WithAuthentication of package net/auth. Writing requests from
+// other origins get rejected, see Options.TrustedOrigins. JSON requests require
+// the Content-Type application/json.
+package cfgadmin
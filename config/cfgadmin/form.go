@@ -0,0 +1,221 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgadmin
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/store/scope"
+)
+
+// formTpl renders a section. Comment and Tooltip of a field can contain HTML
+// and do not get escaped.
+var formTpl = template.Must(template.New("form").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Section.Label}}</title></head>
+<body>
+<h1>{{.Section.Label}}</h1>
+<p class="scope">Scope: {{.Scope}}</p>
+{{- if .Saved}}
+<p class="saved">The configuration has been saved.</p>
+{{- end}}
+<form method="post">
+{{- range .Groups}}
+<fieldset>
+<legend>{{.Label}}</legend>
+{{- with .Comment}}
+<p class="comment">{{.}}</p>
+{{- end}}
+{{- range .Fields}}
+<div class="field{{if .Error}} error{{end}}">
+{{- if eq .Input "hidden"}}
+<input type="hidden" name="{{.Route}}" value="{{.Value.Value}}">
+{{- else}}
+<label for="{{.Route}}"{{with .Tooltip}} title="{{.}}"{{end}}>{{.Label}}</label>
+{{- if eq .Input "label"}}
+<span id="{{.Route}}">{{.Value.Value}}</span>
+{{- else if eq .Input "textarea"}}
+<textarea id="{{.Route}}" name="{{.Route}}"{{if not .Editable}} disabled{{end}}>{{.Value.Value}}</textarea>
+{{- else}}
+<input type="{{.Input}}" id="{{.Route}}" name="{{.Route}}" value="{{.Value.Value}}"{{if not .Editable}} disabled{{end}}>
+{{- end}}
+{{- if .Inherited}}
+<span class="inherited">inherited from {{.Source}}</span>
+{{- end}}
+{{- with .Error}}
+<span class="error">{{.}}</span>
+{{- end}}
+{{- with .Comment}}
+<p class="comment">{{.}}</p>
+{{- end}}
+{{- end}}
+</div>
+{{- end}}
+</fieldset>
+{{- end}}
+<button type="submit">Save</button>
+</form>
+</body>
+</html>
+`))
+
+type formField struct {
+	Value
+	Label   string
+	Input   string
+	Comment template.HTML
+	Tooltip template.HTML
+	Error   string
+}
+
+type formGroup struct {
+	Label   string
+	Comment template.HTML
+	Fields  []formField
+}
+
+type formData struct {
+	Section *config.Section
+	Scope   string
+	Saved   bool
+	Groups  []formGroup
+}
+
+// inputType returns the type of the HTML input element. Select and
+// multiselect fields get rendered as text input with comma separated values
+// because a Field does not provide its options.
+func inputType(ft config.FieldType) string {
+	switch ft {
+	case config.TypeTextarea:
+		return "textarea"
+	case config.TypeObscure:
+		return "password"
+	case config.TypeHidden:
+		return "hidden"
+	case config.TypeLabel:
+		return "label"
+	}
+	return "text"
+}
+
+func (h *Handler) getForm(w http.ResponseWriter, r *http.Request, sec *config.Section, chain []scope.TypeID) {
+	h.renderForm(w, r, sec, chain, nil, nil, r.URL.Query().Get("saved") == "1", http.StatusOK)
+}
+
+// renderForm writes the HTML form. Fields with an error show the posted value
+// instead of the stored value.
+func (h *Handler) renderForm(w http.ResponseWriter, r *http.Request, sec *config.Section, chain []scope.TypeID, posted url.Values, fErrs FieldErrors, saved bool, code int) {
+	fd := formData{
+		Section: sec,
+		Scope:   scopeName(chain[0]),
+		Saved:   saved,
+	}
+	for _, g := range sec.Groups {
+		fg := formGroup{Label: g.Label, Comment: template.HTML(g.Comment)}
+		for _, f := range g.Fields {
+			if !f.Visible || f.Type == config.TypeButton {
+				continue
+			}
+			fv, err := h.lookup(h.fields[fieldRoute(sec, g, f)], chain)
+			if err != nil {
+				h.o.ErrorHandler(err).ServeHTTP(w, r)
+				return
+			}
+			ff := formField{
+				Value:   fv,
+				Label:   f.Label,
+				Input:   inputType(f.Type),
+				Comment: template.HTML(f.Comment),
+				Tooltip: template.HTML(f.Tooltip),
+				Error:   fErrs[fv.Route],
+			}
+			if ff.Error != "" && f.Type != config.TypeObscure {
+				ff.Value.Value = strings.Join(posted[fv.Route], ",")
+			}
+			fg.Fields = append(fg.Fields, ff)
+		}
+		if len(fg.Fields) > 0 {
+			fd.Groups = append(fd.Groups, fg)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := formTpl.Execute(&buf, fd); err != nil {
+		h.o.ErrorHandler(errors.Wrapf(err, "[cfgadmin] Failed to render form of section %q", sec.ID)).ServeHTTP(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	if _, err := buf.WriteTo(w); err != nil && h.o.Log != nil && h.o.Log.IsInfo() {
+		h.o.Log.Info("cfgadmin.Handler.renderForm", log.Err(err))
+	}
+}
+
+// postForm writes all changed values, or none if a value is invalid. Values
+// equal to the effective value do not get written to avoid overwriting
+// inherited values in the requested scope. Empty obscure fields keep their
+// stored value. On success the client gets redirected to the form.
+func (h *Handler) postForm(w http.ResponseWriter, r *http.Request, sec *config.Section, chain []scope.TypeID) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.o.MaxRequestSize)
+	if err := r.ParseForm(); err != nil {
+		h.o.ErrorHandler(errors.BadEncoding.New(err, "[cfgadmin] Failed to parse form")).ServeHTTP(w, r)
+		return
+	}
+
+	var b batch
+	for _, f := range h.sectionFields(sec) {
+		vals, ok := r.PostForm[f.route]
+		if !ok {
+			continue
+		}
+		val := strings.Join(vals, ",")
+		if f.Type == config.TypeObscure && val == "" {
+			continue
+		}
+		if f.Type != config.TypeObscure {
+			cur, err := h.lookup(f, chain)
+			if err != nil {
+				h.o.ErrorHandler(err).ServeHTTP(w, r)
+				return
+			}
+			if cur.Value == val {
+				continue
+			}
+		}
+		b.add(h, f, chain[0], val)
+	}
+	if len(b.errs) > 0 {
+		h.renderForm(w, r, sec, chain, r.PostForm, b.errs, false, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := h.writeBatch(b, chain[0]); err != nil {
+		h.o.ErrorHandler(err).ServeHTTP(w, r)
+		return
+	}
+
+	// A relative reference keeps the path of the client, even when the handler
+	// runs behind http.StripPrefix. http.Redirect would rewrite it.
+	q := r.URL.Query()
+	q.Set("saved", "1")
+	w.Header().Set("Location", "?"+q.Encode())
+	w.WriteHeader(http.StatusSeeOther)
+}
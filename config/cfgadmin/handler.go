@@ -0,0 +1,541 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgadmin
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/net/mw"
+	"github.com/corestoreio/pkg/store/scope"
+)
+
+// DefaultMaxRequestSize limits the size of a submitted request body.
+const DefaultMaxRequestSize = 64 << 10
+
+// Service gets implemented by config.Service.
+type Service interface {
+	Get(p config.Path) *config.Value
+	// Validate checks a value like Set without writing it.
+	Validate(p config.Path, v []byte) error
+	// SetBatch writes all values if all are valid.
+	SetBatch(ps []config.Path, vs [][]byte) error
+}
+
+// Options applies options to the Handler.
+type Options struct {
+	// Sections defines the fields which can be administrated. Required.
+	Sections config.Sections
+	// Authenticate wraps the handler with an authentication middleware, for
+	// example auth.Service.WithAuthentication. Required.
+	Authenticate func(http.Handler) http.Handler
+	// ErrorHandler gets called for errors which are not related to a single
+	// field. Defaults to a handler which maps the error kind to a status code
+	// and prints the error message.
+	ErrorHandler mw.ErrorHandler
+	// MaxRequestSize defaults to DefaultMaxRequestSize.
+	MaxRequestSize int64
+	// TrustedOrigins lists the hosts, e.g. "admin.example.com", which can
+	// submit forms or JSON in addition to the host of the handler itself. See
+	// checkOrigin.
+	TrustedOrigins []string
+	// Log if set, logs written values and failures with level info.
+	Log log.Logger
+}
+
+// fieldRoute returns the ConfigRoute of a field or joins the IDs of the
+// section, group and field.
+func fieldRoute(s *config.Section, g *config.Group, f *config.Field) string {
+	if f.ConfigRoute != "" {
+		return f.ConfigRoute
+	}
+	return s.ID + "/" + g.ID + "/" + f.ID
+}
+
+// field references a field and its parents in the Sections.
+type field struct {
+	route string
+	sec   *config.Section
+	grp   *config.Group
+	*config.Field
+}
+
+// perm returns the scopes for which the field can be written. It falls back to
+// the permission of the group and the section and eventually to the default
+// scope.
+func (f field) perm() scope.Perm {
+	switch {
+	case f.Scopes > 0:
+		return f.Scopes
+	case f.grp.Scopes > 0:
+		return f.grp.Scopes
+	case f.sec.Scopes > 0:
+		return f.sec.Scopes
+	}
+	return scope.PermDefault
+}
+
+// isEditable reports whether the field can be written in scope scp.
+func (f field) isEditable(scp scope.TypeID) bool {
+	switch f.Type {
+	case config.TypeLabel, config.TypeButton:
+		return false
+	}
+	return f.Visible && f.perm().Has(scp.Type())
+}
+
+// Handler serves the admin API and the HTML forms. Safe for concurrent use.
+type Handler struct {
+	srv    Service
+	o      Options
+	fields map[string]field // key: route
+	h      http.Handler
+}
+
+// NewHandler creates a new Handler. Mount it with http.StripPrefix when it
+// does not run at the root path.
+func NewHandler(srv Service, o Options) (*Handler, error) {
+	if len(o.Sections) == 0 {
+		return nil, errors.NotValid.Newf("[cfgadmin] NewHandler: Sections cannot be empty")
+	}
+	if o.Authenticate == nil {
+		return nil, errors.NotValid.Newf("[cfgadmin] NewHandler: Authenticate middleware is required")
+	}
+	if err := o.Sections.Validate(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if o.ErrorHandler == nil {
+		o.ErrorHandler = errorWithKind
+	}
+	if o.MaxRequestSize < 1 {
+		o.MaxRequestSize = DefaultMaxRequestSize
+	}
+	h := &Handler{
+		srv:    srv,
+		o:      o,
+		fields: make(map[string]field, o.Sections.TotalFields()),
+	}
+	for _, s := range o.Sections {
+		for _, g := range s.Groups {
+			for _, f := range g.Fields {
+				route := fieldRoute(s, g, f)
+				h.fields[route] = field{route: route, sec: s, grp: g, Field: f}
+			}
+		}
+	}
+	h.h = o.Authenticate(http.HandlerFunc(h.serve))
+	return h, nil
+}
+
+// MustNewHandler same as NewHandler but panics on error.
+func MustNewHandler(srv Service, o Options) *Handler {
+	h, err := NewHandler(srv, o)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.h.ServeHTTP(w, r)
+}
+
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) == 1 && parts[0] == "sections" {
+		if r.Method != http.MethodGet {
+			h.methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		h.writeJSON(w, http.StatusOK, h.visibleSections())
+		return
+	}
+	if len(parts) != 2 || (parts[0] != "sections" && parts[0] != "forms") {
+		h.o.ErrorHandler(errors.NotFound.Newf("[cfgadmin] Route %q not found", r.URL.Path)).ServeHTTP(w, r)
+		return
+	}
+	sec, _ := h.o.Sections.Find(parts[1])
+	if sec == nil || !h.isVisible(sec) {
+		h.o.ErrorHandler(errors.NotFound.Newf("[cfgadmin] Section %q not found", parts[1])).ServeHTTP(w, r)
+		return
+	}
+	chain, err := parseScopes(r)
+	if err != nil {
+		h.o.ErrorHandler(err).ServeHTTP(w, r)
+		return
+	}
+
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if err := h.checkOrigin(r, parts[0] == "sections"); err != nil {
+			h.o.ErrorHandler(err).ServeHTTP(w, r)
+			return
+		}
+	}
+
+	switch {
+	case parts[0] == "sections" && r.Method == http.MethodGet:
+		h.getValues(w, r, sec, chain)
+	case parts[0] == "sections" && (r.Method == http.MethodPut || r.Method == http.MethodPost):
+		h.putValues(w, r, sec, chain)
+	case parts[0] == "forms" && r.Method == http.MethodGet:
+		h.getForm(w, r, sec, chain)
+	case parts[0] == "forms" && r.Method == http.MethodPost:
+		h.postForm(w, r, sec, chain)
+	case parts[0] == "sections":
+		h.methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodPost)
+	default:
+		h.methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+// checkOrigin protects the writing requests against cross-site request
+// forgery. A request gets rejected when the browser marks it as cross-site via
+// Sec-Fetch-Site or when its Origin, or if absent its Referer, does not match
+// the host of the request or one of the TrustedOrigins. Form submissions must
+// provide Origin or Referer. JSON requests without both are allowed because
+// they do not come from a browser form: their Content-Type must be
+// application/json, which a browser cannot send cross-site without a CORS
+// preflight.
+func (h *Handler) checkOrigin(r *http.Request, isJSON bool) error {
+	if isJSON {
+		if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+			return errors.NotSupported.Newf("[cfgadmin] Content-Type must be application/json")
+		}
+	}
+	if r.Header.Get("Sec-Fetch-Site") == "cross-site" {
+		return errors.NotAllowed.Newf("[cfgadmin] Cross-site request rejected")
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		origin = r.Referer()
+	}
+	if origin == "" {
+		if isJSON {
+			return nil
+		}
+		return errors.NotAllowed.Newf("[cfgadmin] Form submission requires an Origin or Referer header")
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return errors.NotAllowed.Newf("[cfgadmin] Invalid origin %q", origin)
+	}
+	if u.Host == r.Host {
+		return nil
+	}
+	for _, o := range h.o.TrustedOrigins {
+		if u.Host == o {
+			return nil
+		}
+	}
+	return errors.NotAllowed.Newf("[cfgadmin] Origin %q is not allowed", u.Host)
+}
+
+func (h *Handler) methodNotAllowed(w http.ResponseWriter, allow ...string) {
+	w.Header().Set("Allow", strings.Join(allow, ", "))
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil && h.o.Log != nil && h.o.Log.IsInfo() {
+		h.o.Log.Info("cfgadmin.Handler.writeJSON", log.Err(err))
+	}
+}
+
+// parseScopes returns the scope chain store, website and default which gets
+// used to look up inherited values. The first entry is the requested scope.
+func parseScopes(r *http.Request) ([]scope.TypeID, error) {
+	var ids [2]uint32
+	for i, key := range [...]string{"website", "store"} {
+		v := r.URL.Query().Get(key)
+		if v == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, errors.BadEncoding.New(err, "[cfgadmin] Invalid %s ID %q", key, v)
+		}
+		ids[i] = uint32(id)
+	}
+	websiteID, storeID := ids[0], ids[1]
+	switch {
+	case storeID > 0 && websiteID == 0:
+		return nil, errors.NotValid.Newf("[cfgadmin] Store ID %d requires a website ID", storeID)
+	case storeID > 0:
+		return []scope.TypeID{scope.Store.WithID(storeID), scope.Website.WithID(websiteID), scope.DefaultTypeID}, nil
+	case websiteID > 0:
+		return []scope.TypeID{scope.Website.WithID(websiteID), scope.DefaultTypeID}, nil
+	}
+	return []scope.TypeID{scope.DefaultTypeID}, nil
+}
+
+// scopeName returns "default", "websites/1" or "stores/2".
+func scopeName(scp scope.TypeID) string {
+	if b := scp.AppendHuman(nil, '/'); len(b) > 0 {
+		return string(b)
+	}
+	return scope.Default.StrType()
+}
+
+func (h *Handler) isVisible(sec *config.Section) bool {
+	for _, g := range sec.Groups {
+		for _, f := range g.Fields {
+			if f.Visible {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// visibleSections returns a copy of the Sections which only contains visible
+// fields.
+func (h *Handler) visibleSections() config.Sections {
+	secs := make(config.Sections, 0, len(h.o.Sections))
+	for _, s := range h.o.Sections {
+		sc := *s
+		sc.Groups = nil
+		for _, g := range s.Groups {
+			gc := *g
+			gc.Fields = nil
+			for _, f := range g.Fields {
+				if f.Visible {
+					gc.Fields = append(gc.Fields, f)
+				}
+			}
+			if len(gc.Fields) > 0 {
+				sc.Groups = append(sc.Groups, &gc)
+			}
+		}
+		if len(sc.Groups) > 0 {
+			secs = append(secs, &sc)
+		}
+	}
+	return secs
+}
+
+// Value represents the value of a field in a requested scope.
+type Value struct {
+	Route string `json:"route"`
+	// Value contains the effective value. Empty for obscure fields.
+	Value string `json:"value"`
+	// Inherited reports whether the value has not been set in the requested
+	// scope.
+	Inherited bool `json:"inherited"`
+	// Source contains the scope of the value, e.g. "default", "websites/1",
+	// "stores/2" or "defaults" for the default value of the field.
+	Source   string        `json:"source"`
+	Editable bool          `json:"editable"`
+	Field    *config.Field `json:"field"`
+}
+
+// SourceDefaults identifies a value which has been taken from the default
+// value of a field.
+const SourceDefaults = "defaults"
+
+// lookup walks the scope chain and returns the first value which has been
+// set. A default value provided by a FieldMeta does not count as set.
+func (h *Handler) lookup(f field, chain []scope.TypeID) (Value, error) {
+	fv := Value{
+		Route:    f.route,
+		Editable: f.isEditable(chain[0]),
+		Field:    f.Field,
+	}
+	p, err := config.MakePath(f.route)
+	if err != nil {
+		return fv, errors.WithStack(err)
+	}
+	for i, scp := range chain {
+		v := h.srv.Get(p.Bind(scp))
+		s, ok, err := v.Str()
+		if err != nil {
+			return fv, errors.Wrapf(err, "[cfgadmin] Failed to get value of route %q in scope %q", f.route, scopeName(scp))
+		}
+		if ok && !v.IsDefault() {
+			fv.Value, fv.Inherited, fv.Source = s, i > 0, scopeName(scp)
+			break
+		}
+	}
+	if fv.Source == "" {
+		fv.Value, fv.Inherited, fv.Source = f.Default, true, SourceDefaults
+	}
+	if f.Type == config.TypeObscure {
+		fv.Value = ""
+	}
+	return fv, nil
+}
+
+// sectionFields returns the visible fields of a section in the order of the
+// groups and fields.
+func (h *Handler) sectionFields(sec *config.Section) []field {
+	var fs []field
+	for _, g := range sec.Groups {
+		for _, f := range g.Fields {
+			if f.Visible {
+				fs = append(fs, h.fields[fieldRoute(sec, g, f)])
+			}
+		}
+	}
+	return fs
+}
+
+func (h *Handler) getValues(w http.ResponseWriter, r *http.Request, sec *config.Section, chain []scope.TypeID) {
+	fields := h.sectionFields(sec)
+	vals := make([]Value, 0, len(fields))
+	for _, f := range fields {
+		fv, err := h.lookup(f, chain)
+		if err != nil {
+			h.o.ErrorHandler(err).ServeHTTP(w, r)
+			return
+		}
+		vals = append(vals, fv)
+	}
+	h.writeJSON(w, http.StatusOK, vals)
+}
+
+// FieldErrors maps a route to the validation error message of the submitted
+// value.
+type FieldErrors map[string]string
+
+func (h *Handler) putValues(w http.ResponseWriter, r *http.Request, sec *config.Section, chain []scope.TypeID) {
+	var data map[string]string
+	dec := json.NewDecoder(io.LimitReader(r.Body, h.o.MaxRequestSize))
+	if err := dec.Decode(&data); err != nil {
+		h.o.ErrorHandler(errors.BadEncoding.New(err, "[cfgadmin] Failed to decode request body")).ServeHTTP(w, r)
+		return
+	}
+	routes := make([]string, 0, len(data))
+	for route := range data {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	var b batch
+	for _, route := range routes {
+		f, ok := h.fields[route]
+		if !ok || f.sec != sec || !f.Visible {
+			b.fail(route, "route not found in section "+sec.ID)
+			continue
+		}
+		b.add(h, f, chain[0], data[route])
+	}
+	if len(b.errs) > 0 {
+		h.writeJSON(w, http.StatusUnprocessableEntity, struct {
+			Errors FieldErrors `json:"errors"`
+		}{b.errs})
+		return
+	}
+	if err := h.writeBatch(b, chain[0]); err != nil {
+		h.o.ErrorHandler(err).ServeHTTP(w, r)
+		return
+	}
+	h.getValues(w, r, sec, chain)
+}
+
+// batch collects the validated values of a request. Nothing gets written when
+// a single value is invalid.
+type batch struct {
+	routes []string
+	ps     []config.Path
+	vs     [][]byte
+	errs   FieldErrors
+}
+
+func (b *batch) fail(route, msg string) {
+	if b.errs == nil {
+		b.errs = FieldErrors{}
+	}
+	b.errs[route] = msg
+}
+
+// add validates the value and adds it to the batch or records the error.
+func (b *batch) add(h *Handler, f field, scp scope.TypeID, val string) {
+	p, err := h.validate(f, scp, val)
+	if err != nil {
+		b.fail(f.route, err.Error())
+		return
+	}
+	b.routes = append(b.routes, f.route)
+	b.ps = append(b.ps, p)
+	b.vs = append(b.vs, []byte(val))
+}
+
+// validate checks the field restrictions, the observer validators and the
+// WriteScopePerm of the FieldMeta without writing the value.
+func (h *Handler) validate(f field, scp scope.TypeID, val string) (config.Path, error) {
+	switch {
+	case !f.isEditable(scp):
+		return config.Path{}, errors.NotAllowed.Newf("[cfgadmin] Route %q cannot be written in scope %q", f.route, scopeName(scp))
+	case f.Type == config.TypeMultiselect && val == "" && !f.CanBeEmpty:
+		return config.Path{}, errors.NotValid.Newf("[cfgadmin] Route %q requires at least one selected value", f.route)
+	}
+	p, err := config.MakePath(f.route)
+	if err != nil {
+		return config.Path{}, errors.WithStack(err)
+	}
+	p = p.Bind(scp)
+	if err := h.srv.Validate(p, []byte(val)); err != nil {
+		return config.Path{}, errors.WithStack(err)
+	}
+	return p, nil
+}
+
+// writeBatch writes all values of the batch with one call to the Service.
+func (h *Handler) writeBatch(b batch, scp scope.TypeID) (err error) {
+	if len(b.ps) == 0 {
+		return nil
+	}
+	if h.o.Log != nil && h.o.Log.IsInfo() {
+		defer func() {
+			h.o.Log.Info("cfgadmin.Handler.writeBatch", log.Strings("routes", b.routes...), log.Stringer("scope", scp), log.Err(err))
+		}()
+	}
+	if err = h.srv.SetBatch(b.ps, b.vs); err != nil {
+		return errors.Wrapf(err, "[cfgadmin] Failed to write routes %q in scope %q", b.routes, scopeName(scp))
+	}
+	return nil
+}
+
+// errorWithKind writes the error message with a status code depending on the
+// error kind.
+func errorWithKind(err error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		code := http.StatusInternalServerError
+		switch {
+		case errors.NotAllowed.Match(err):
+			code = http.StatusForbidden
+		case errors.NotFound.Match(err):
+			code = http.StatusNotFound
+		case errors.NotValid.Match(err):
+			code = http.StatusUnprocessableEntity
+		case errors.BadEncoding.Match(err):
+			code = http.StatusBadRequest
+		case errors.NotSupported.Match(err):
+			code = http.StatusUnsupportedMediaType
+		}
+		http.Error(w, err.Error(), code)
+	})
+}
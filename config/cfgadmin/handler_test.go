@@ -0,0 +1,376 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgadmin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/cfgadmin"
+	"github.com/corestoreio/pkg/config/observer"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+var testSections = config.MustMakeSectionsValidate(
+	&config.Section{
+		ID:    "payment",
+		Label: "Payment",
+		Groups: config.MakeGroups(
+			&config.Group{
+				ID:    "stripe",
+				Label: "Stripe",
+				Fields: config.MakeFields(
+					&config.Field{ID: "heading", Type: config.TypeLabel, Label: "Heading", Visible: true},
+					&config.Field{ID: "user_name", Type: config.TypeText, Label: "User Name", Visible: true, Scopes: scope.PermStore, Default: "anonymous", Comment: "<b>API</b> user"},
+					&config.Field{ID: "password", Type: config.TypeObscure, Label: "Password", Visible: true, Scopes: scope.PermWebsite},
+					&config.Field{ID: "country", Type: config.TypeText, Label: "Country", Visible: true, Scopes: scope.PermDefault},
+					&config.Field{ID: "methods", Type: config.TypeMultiselect, Label: "Methods", Visible: true, Scopes: scope.PermStore},
+					&config.Field{ID: "internal", Type: config.TypeText, Label: "Internal"},
+				),
+			},
+		),
+	},
+)
+
+func fakeAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer admin" {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newHandler(t *testing.T) (*config.Service, *cfgadmin.Handler) {
+	cfgSrv := config.MustNewService(storage.NewMap(
+		"default/0/payment/stripe/user_name", "shop",
+		"websites/1/payment/stripe/user_name", "shop_ch",
+		"websites/1/payment/stripe/password", "secret",
+		"default/0/payment/stripe/country", "CH",
+	), config.Options{}, config.WithApplySections(testSections...))
+
+	val, err := observer.NewValidator(observer.ValidatorArg{Funcs: []string{"ISO3166Alpha2"}})
+	assert.NoError(t, err)
+	assert.NoError(t, cfgSrv.RegisterObserver(config.EventOnBeforeSet, "payment/stripe/country", val))
+
+	h, err := cfgadmin.NewHandler(cfgSrv, cfgadmin.Options{
+		Sections:     testSections,
+		Authenticate: fakeAuth,
+	})
+	assert.NoError(t, err)
+	return cfgSrv, h
+}
+
+func serve(h http.Handler, method, target, contentType, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer admin")
+	if method != "GET" {
+		r.Header.Set("Origin", "http://example.com") // same host as httptest.NewRequest
+	}
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+// value same as cfgadmin.Value but without the field because config.FieldType
+// cannot be decoded from JSON.
+type value struct {
+	Route     string `json:"route"`
+	Value     string `json:"value"`
+	Inherited bool   `json:"inherited"`
+	Source    string `json:"source"`
+	Editable  bool   `json:"editable"`
+}
+
+func storedValue(t *testing.T, cfgSrv *config.Service, scp scope.TypeID, route string) string {
+	v := cfgSrv.Get(config.MustMakePath(route).Bind(scp))
+	s, _, err := v.Str()
+	assert.NoError(t, err)
+	return s
+}
+
+func TestNewHandler(t *testing.T) {
+	_, err := cfgadmin.NewHandler(nil, cfgadmin.Options{Sections: testSections})
+	assert.ErrorIsKind(t, errors.NotValid, err)
+
+	_, err = cfgadmin.NewHandler(nil, cfgadmin.Options{Authenticate: fakeAuth})
+	assert.ErrorIsKind(t, errors.NotValid, err)
+}
+
+func TestHandler_Authenticate(t *testing.T) {
+	_, h := newHandler(t)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/sections", nil))
+	assert.Exactly(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestHandler_Sections(t *testing.T) {
+	_, h := newHandler(t)
+
+	t.Run("tree", func(t *testing.T) {
+		w := serve(h, "GET", "/sections", "", "")
+		assert.Exactly(t, http.StatusOK, w.Code)
+		// config.FieldType cannot be decoded from JSON.
+		var secs []struct {
+			ID     string
+			Groups []struct {
+				ID     string
+				Fields []struct{ ID, Type string }
+			}
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &secs))
+		assert.Len(t, secs, 1)
+		assert.Len(t, secs[0].Groups[0].Fields, 5)
+		assert.NotContains(t, w.Body.String(), "internal")
+		assert.Len(t, testSections[0].Groups[0].Fields, 6, "Sections must not be modified")
+	})
+
+	t.Run("unknown section", func(t *testing.T) {
+		w := serve(h, "GET", "/sections/catalog", "", "")
+		assert.Exactly(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("store without website", func(t *testing.T) {
+		w := serve(h, "GET", "/sections/payment?store=2", "", "")
+		assert.Exactly(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		w := serve(h, "DELETE", "/sections/payment", "", "")
+		assert.Exactly(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestHandler_GetValues(t *testing.T) {
+	_, h := newHandler(t)
+
+	w := serve(h, "GET", "/sections/payment?website=1&store=2", "", "")
+	assert.Exactly(t, http.StatusOK, w.Code)
+
+	var vals []value
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &vals))
+	got := map[string]value{}
+	for _, v := range vals {
+		got[v.Route] = v
+	}
+	assert.Len(t, got, 5)
+
+	un := got["payment/stripe/user_name"]
+	assert.Exactly(t, "shop_ch", un.Value)
+	assert.Exactly(t, "websites/1", un.Source)
+	assert.True(t, un.Inherited)
+	assert.True(t, un.Editable)
+
+	pw := got["payment/stripe/password"]
+	assert.Exactly(t, "", pw.Value, "obscure values must not be exposed")
+	assert.Exactly(t, "websites/1", pw.Source)
+	assert.False(t, pw.Editable)
+
+	c := got["payment/stripe/country"]
+	assert.Exactly(t, "CH", c.Value)
+	assert.Exactly(t, "default", c.Source)
+	assert.False(t, c.Editable)
+
+	assert.False(t, got["payment/stripe/heading"].Editable)
+
+	w = serve(h, "GET", "/sections/payment", "", "")
+	vals = nil
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &vals))
+	for _, v := range vals {
+		if v.Route == "payment/stripe/methods" {
+			assert.Exactly(t, cfgadmin.SourceDefaults, v.Source)
+			assert.True(t, v.Inherited)
+		}
+		if v.Route == "payment/stripe/user_name" {
+			assert.Exactly(t, "default", v.Source)
+			assert.False(t, v.Inherited)
+		}
+	}
+}
+
+func TestHandler_PutValues(t *testing.T) {
+	t.Run("store scope", func(t *testing.T) {
+		cfgSrv, h := newHandler(t)
+		w := serve(h, "PUT", "/sections/payment?website=1&store=2", "application/json",
+			`{"payment/stripe/user_name":"shop_de","payment/stripe/methods":"visa,amex"}`)
+		assert.Exactly(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Exactly(t, "shop_de", storedValue(t, cfgSrv, scope.Store.WithID(2), "payment/stripe/user_name"))
+		assert.Exactly(t, "visa,amex", storedValue(t, cfgSrv, scope.Store.WithID(2), "payment/stripe/methods"))
+		assert.Exactly(t, "shop_ch", storedValue(t, cfgSrv, scope.Website.WithID(1), "payment/stripe/user_name"))
+	})
+
+	t.Run("field errors", func(t *testing.T) {
+		cfgSrv, h := newHandler(t)
+		w := serve(h, "PUT", "/sections/payment?website=1&store=2", "application/json",
+			`{"payment/stripe/country":"DE","payment/stripe/internal":"x","payment/stripe/methods":"","payment/stripe/user_name":"shop_de"}`)
+		assert.Exactly(t, http.StatusUnprocessableEntity, w.Code)
+
+		var res struct {
+			Errors cfgadmin.FieldErrors `json:"errors"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Len(t, res.Errors, 3)
+		assert.Contains(t, res.Errors["payment/stripe/country"], "cannot be written in scope")
+		assert.Contains(t, res.Errors["payment/stripe/internal"], "not found")
+		assert.Contains(t, res.Errors["payment/stripe/methods"], "at least one")
+		v := cfgSrv.Get(config.MustMakePath("payment/stripe/user_name").BindStore(2))
+		_, ok, err := v.Str()
+		assert.NoError(t, err)
+		assert.False(t, ok && !v.IsDefault(), "valid values must not be written when another value is invalid")
+	})
+
+	t.Run("observer validator", func(t *testing.T) {
+		cfgSrv, h := newHandler(t)
+		w := serve(h, "PUT", "/sections/payment", "application/json", `{"payment/stripe/country":"XX"}`)
+		assert.Exactly(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "payment/stripe/country")
+		assert.Exactly(t, "CH", storedValue(t, cfgSrv, scope.DefaultTypeID, "payment/stripe/country"))
+
+		w = serve(h, "PUT", "/sections/payment", "application/json", `{"payment/stripe/country":"DE"}`)
+		assert.Exactly(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Exactly(t, "DE", storedValue(t, cfgSrv, scope.DefaultTypeID, "payment/stripe/country"))
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		_, h := newHandler(t)
+		w := serve(h, "PUT", "/sections/payment", "application/json", `{"payment/stripe/country":`)
+		assert.Exactly(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandler_Form(t *testing.T) {
+	t.Run("render", func(t *testing.T) {
+		_, h := newHandler(t)
+		w := serve(h, "GET", "/forms/payment?website=1&store=2", "", "")
+		assert.Exactly(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, `value="shop_ch"`)
+		assert.Contains(t, body, "inherited from websites/1")
+		assert.Contains(t, body, "inherited from defaults")
+		assert.Contains(t, body, `<input type="password" id="payment/stripe/password" name="payment/stripe/password" value="" disabled>`)
+		assert.Contains(t, body, `<input type="text" id="payment/stripe/country" name="payment/stripe/country" value="CH" disabled>`)
+		assert.Contains(t, body, "<b>API</b> user")
+		assert.Contains(t, body, "Scope: stores/2")
+		assert.NotContains(t, body, "internal")
+		assert.NotContains(t, body, "secret")
+	})
+
+	t.Run("post changed values", func(t *testing.T) {
+		cfgSrv, h := newHandler(t)
+		form := url.Values{
+			"payment/stripe/user_name": {"shop_ch"},
+			"payment/stripe/methods":   {"visa", "amex"},
+			"payment/stripe/password":  {""},
+		}
+		w := serve(h, "POST", "/forms/payment?website=1&store=2", "application/x-www-form-urlencoded", form.Encode())
+		assert.Exactly(t, http.StatusSeeOther, w.Code, w.Body.String())
+		assert.Exactly(t, "?saved=1&store=2&website=1", w.Header().Get("Location"))
+
+		v := cfgSrv.Get(config.MustMakePath("payment/stripe/user_name").BindStore(2))
+		_, ok, err := v.Str()
+		assert.NoError(t, err)
+		assert.False(t, ok && !v.IsDefault(), "unchanged inherited value must not be written")
+		assert.Exactly(t, "visa,amex", storedValue(t, cfgSrv, scope.Store.WithID(2), "payment/stripe/methods"))
+
+		w = serve(h, "GET", "/forms/payment?website=1&store=2&saved=1", "", "")
+		assert.Contains(t, w.Body.String(), "The configuration has been saved.")
+	})
+
+	t.Run("post with errors", func(t *testing.T) {
+		cfgSrv, h := newHandler(t)
+		form := url.Values{
+			"payment/stripe/country":  {"XX"},
+			"payment/stripe/password": {"new_secret"},
+		}
+		w := serve(h, "POST", "/forms/payment", "application/x-www-form-urlencoded", form.Encode())
+		assert.Exactly(t, http.StatusUnprocessableEntity, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, `value="XX"`)
+		assert.Contains(t, body, `class="field error"`)
+		assert.NotContains(t, body, "new_secret")
+		assert.Exactly(t, "", storedValue(t, cfgSrv, scope.DefaultTypeID, "payment/stripe/password"), "nothing gets written")
+	})
+}
+
+func TestHandler_CheckOrigin(t *testing.T) {
+	cfgSrv, h := newHandler(t)
+	form := url.Values{"payment/stripe/country": {"DE"}}.Encode()
+
+	request := func(method, target, contentType, body string, header ...string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer admin")
+		r.Header.Set("Content-Type", contentType)
+		for i := 0; i < len(header); i += 2 {
+			r.Header.Set(header[i], header[i+1])
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		header      []string
+		wantCode    int
+	}{
+		{"form from other origin", "POST", "/forms/payment", "application/x-www-form-urlencoded", form, []string{"Origin", "https://evil.example"}, http.StatusForbidden},
+		{"form without origin", "POST", "/forms/payment", "application/x-www-form-urlencoded", form, nil, http.StatusForbidden},
+		{"form with cross-site fetch", "POST", "/forms/payment", "application/x-www-form-urlencoded", form, []string{"Origin", "http://example.com", "Sec-Fetch-Site", "cross-site"}, http.StatusForbidden},
+		{"form with referer", "POST", "/forms/payment", "application/x-www-form-urlencoded", form, []string{"Referer", "http://example.com/forms/payment"}, http.StatusSeeOther},
+		{"json as text/plain", "POST", "/sections/payment", "text/plain", `{"payment/stripe/country":"DE"}`, nil, http.StatusUnsupportedMediaType},
+		{"json from other origin", "PUT", "/sections/payment", "application/json", `{"payment/stripe/country":"DE"}`, []string{"Origin", "https://evil.example"}, http.StatusForbidden},
+		{"json without origin", "PUT", "/sections/payment", "application/json; charset=utf-8", `{"payment/stripe/country":"DE"}`, nil, http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := request(test.method, test.target, test.contentType, test.body, test.header...)
+			assert.Exactly(t, test.wantCode, w.Code, w.Body.String())
+		})
+	}
+	assert.Exactly(t, "DE", storedValue(t, cfgSrv, scope.DefaultTypeID, "payment/stripe/country"))
+
+	t.Run("trusted origin", func(t *testing.T) {
+		h2, err := cfgadmin.NewHandler(cfgSrv, cfgadmin.Options{
+			Sections:       testSections,
+			Authenticate:   fakeAuth,
+			TrustedOrigins: []string{"admin.example.com"},
+		})
+		assert.NoError(t, err)
+		r := httptest.NewRequest("POST", "/forms/payment", strings.NewReader(url.Values{"payment/stripe/country": {"AT"}}.Encode()))
+		r.Header.Set("Authorization", "Bearer admin")
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set("Origin", "https://admin.example.com")
+		w := httptest.NewRecorder()
+		h2.ServeHTTP(w, r)
+		assert.Exactly(t, http.StatusSeeOther, w.Code, w.Body.String())
+		assert.Exactly(t, "AT", storedValue(t, cfgSrv, scope.DefaultTypeID, "payment/stripe/country"))
+	})
+}
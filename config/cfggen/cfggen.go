@@ -0,0 +1,524 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfggen
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/codegen"
+	"github.com/corestoreio/pkg/util/strs"
+)
+
+// goTypes lists the supported Go types and the config.Value method which
+// converts the raw value.
+var goTypes = map[string]string{
+	"bool":          "Bool",
+	"int":           "Int",
+	"int64":         "Int64",
+	"uint64":        "Uint64",
+	"float64":       "Float64",
+	"string":        "Str",
+	"[]string":      "Strs",
+	"time.Time":     "Time",
+	"time.Duration": "Duration",
+}
+
+// Generator creates typed accessor functions for all fields of the Sections.
+type Generator struct {
+	// Package defines the name of the Go package.
+	Package string
+	// BuildTags get written at the top of the files, each entry is one line.
+	BuildTags []string
+	// Sections contains the sections, groups and fields for which the
+	// accessors get generated.
+	Sections config.Sections
+	// SectionsVar if not empty, writes the Sections as Go source code into a
+	// package level variable with this name.
+	SectionsVar string
+	// GoTypes maps a route to its Go type and overwrites the inferred type.
+	// Supported types: bool, int, int64, uint64, float64, string, []string,
+	// time.Time and time.Duration.
+	GoTypes map[string]string
+}
+
+// NewGenerator creates a new generator for the package name and sections.
+func NewGenerator(packageName string, sections ...*config.Section) *Generator {
+	return &Generator{
+		Package:  packageName,
+		Sections: config.MakeSections(sections...),
+		GoTypes:  map[string]string{},
+	}
+}
+
+// field contains the analyzed data of a config.Field.
+type field struct {
+	*config.Field
+	route     string
+	name      string // Go name of the accessor
+	goType    string
+	scopeTop  scope.Type
+	defaultGo string // default value as Go source code, empty if not set
+}
+
+// analyze validates the Sections and collects all fields.
+func (g *Generator) analyze() ([]field, error) {
+	if err := g.Sections.Validate(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var fs []field
+	names := map[string]string{}
+	for _, s := range g.Sections {
+		for _, gr := range s.Groups {
+			for _, f := range gr.Fields {
+				fd := field{
+					Field: f,
+					route: s.ID + "/" + gr.ID + "/" + f.ID,
+				}
+				perm, err := scopePerm(fd.route, s.Scopes, gr.Scopes, f.Scopes)
+				if err != nil {
+					return nil, errors.WithStack(err)
+				}
+				fd.scopeTop = perm.Top()
+
+				fd.name = strs.ToGoCamelCase(strings.Replace(fd.route, "/", "_", -1))
+				if other, ok := names[fd.name]; ok {
+					return nil, errors.Duplicated.Newf("[cfggen] The routes %q and %q result in the same accessor name %q", other, fd.route, fd.name)
+				}
+				names[fd.name] = fd.route
+
+				fd.goType = g.GoTypes[fd.route]
+				if fd.goType == "" {
+					fd.goType = inferGoType(f)
+				}
+				if _, ok := goTypes[fd.goType]; !ok {
+					return nil, errors.NotSupported.Newf("[cfggen] Go type %q of route %q not supported", fd.goType, fd.route)
+				}
+				if fd.defaultGo, err = goLiteral(fd.goType, f.Default); err != nil {
+					return nil, errors.NotValid.New(err, "[cfggen] Default value %q of route %q cannot be converted to %s", f.Default, fd.route, fd.goType)
+				}
+				fs = append(fs, fd)
+			}
+		}
+	}
+	return fs, nil
+}
+
+// scopePerm returns the effective permission of a field. A field inherits the
+// permission of its group and the group of its section. A field cannot allow a
+// higher scope than its group and a group cannot allow a higher scope than its
+// section. Without any permission only the default scope gets allowed.
+func scopePerm(route string, section, group, field scope.Perm) (scope.Perm, error) {
+	if group > 0 && section > 0 && group.Top() > section.Top() {
+		return 0, errors.NotValid.Newf("[cfggen] Route %q: group scope %q exceeds section scope %q", route, group.Top(), section.Top())
+	}
+	parent := group
+	if parent == 0 {
+		parent = section
+	}
+	if field > 0 && parent > 0 && field.Top() > parent.Top() {
+		return 0, errors.NotValid.Newf("[cfggen] Route %q: field scope %q exceeds group scope %q", route, field.Top(), parent.Top())
+	}
+	if field == 0 {
+		field = parent
+	}
+	if field == 0 {
+		field = scope.PermDefault // like the hidden defaults of a Magento config.xml
+	}
+	return field, nil
+}
+
+// inferGoType derives the Go type from the FieldType and the default value.
+func inferGoType(f *config.Field) string {
+	switch f.Type {
+	case config.TypeMultiselect:
+		return "[]string"
+	case config.TypeDuration:
+		return "time.Duration"
+	}
+	d := f.Default
+	switch {
+	case d == "true" || d == "false":
+		return "bool"
+	case d == "":
+		return "string"
+	}
+	if _, err := strconv.ParseInt(d, 10, 64); err == nil {
+		return "int"
+	}
+	if _, err := strconv.ParseFloat(d, 64); err == nil {
+		return "float64"
+	}
+	return "string"
+}
+
+// goLiteral converts the raw value with the same functions as config.Value
+// into Go source code.
+func goLiteral(goType, raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	v := config.NewValue([]byte(raw))
+	var err error
+	var ret string
+	switch goType {
+	case "bool":
+		var b bool
+		b, _, err = v.Bool()
+		ret = strconv.FormatBool(b)
+	case "int":
+		var i int
+		i, _, err = v.Int()
+		ret = strconv.Itoa(i)
+	case "int64":
+		var i int64
+		i, _, err = v.Int64()
+		ret = strconv.FormatInt(i, 10)
+	case "uint64":
+		var i uint64
+		i, _, err = v.Uint64()
+		ret = strconv.FormatUint(i, 10)
+	case "float64":
+		var f float64
+		f, _, err = v.Float64()
+		ret = strconv.FormatFloat(f, 'g', -1, 64)
+	case "string":
+		ret = strconv.Quote(raw)
+	case "[]string":
+		var ss []string
+		ss, err = v.Strs()
+		for i, s := range ss {
+			ss[i] = strconv.Quote(s)
+		}
+		ret = "[]string{" + strings.Join(ss, ", ") + "}"
+	case "time.Time":
+		var t time.Time
+		t, _, err = v.Time()
+		ret = fmt.Sprintf("time.Date(%d, %d, %d, %d, %d, %d, %d, time.UTC)", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond())
+	case "time.Duration":
+		var d time.Duration
+		d, _, err = v.Duration()
+		ret = fmt.Sprintf("time.Duration(%d)", int64(d))
+	}
+	if err != nil {
+		return "", err
+	}
+	return ret, nil
+}
+
+// zeroLiteral returns the typed zero value of a Go type.
+func zeroLiteral(goType string) string {
+	switch goType {
+	case "bool":
+		return "false"
+	case "string":
+		return `""`
+	case "[]string":
+		return "nil"
+	case "time.Time":
+		return "time.Time{}"
+	}
+	return "0"
+}
+
+func scopeTypeGo(t scope.Type) string {
+	switch t {
+	case scope.Store:
+		return "scope.Store"
+	case scope.Website:
+		return "scope.Website"
+	}
+	return "scope.Default"
+}
+
+// GenerateGo writes the accessor functions into wMain and their tests into
+// wTest.
+func (g *Generator) GenerateGo(wMain, wTest io.Writer) error {
+	fs, err := g.analyze()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	mainGen := codegen.NewGo(g.Package)
+	testGen := codegen.NewGo(g.Package)
+
+	mainGen.SecondLineComments = []string{"Generated by config/cfggen. DO NOT EDIT."}
+	testGen.SecondLineComments = []string{"Generated by config/cfggen. DO NOT EDIT."}
+
+	mainGen.BuildTags = g.BuildTags
+	testGen.BuildTags = g.BuildTags
+
+	g.fnSections(mainGen)
+	g.fnRoutes(mainGen, fs)
+	for _, f := range fs {
+		g.fnAccessor(mainGen, f)
+	}
+	g.fnTest(testGen, fs)
+
+	if err := mainGen.GenerateFile(wMain); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := testGen.GenerateFile(wTest); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (g *Generator) fnRoutes(mainGen *codegen.Go, fs []field) {
+	if len(fs) == 0 {
+		return
+	}
+	lines := make([]string, 0, len(fs))
+	for _, f := range fs {
+		lines = append(lines, fmt.Sprintf("Route%s = %q", f.name, f.route))
+	}
+	mainGen.C("Route* defines the routes of all configuration fields of this package.")
+	mainGen.WriteConstants(lines...)
+}
+
+func (g *Generator) fnAccessor(mainGen *codegen.Go, f field) {
+	mainGen.AddImports("github.com/corestoreio/errors", "github.com/corestoreio/pkg/config", "github.com/corestoreio/pkg/store/scope")
+	if strings.HasPrefix(f.goType, "time.") {
+		mainGen.AddImports("time")
+	}
+
+	label := f.Label
+	if label == "" {
+		label = f.ID
+	}
+	mainGen.C(f.name, `returns the value of`, strconv.Quote(label), `up to the`, f.scopeTop.String(), `scope.`)
+	mainGen.Pln(`// Path:`, f.route)
+	mainGen.Pln(f.Default != "", `// Default:`, f.Default)
+	mainGen.Pln(`func`, f.name+`(s config.Scoped) (`+f.goType+`, error) {`)
+	mainGen.In()
+	zero := zeroLiteral(f.goType)
+	getter := fmt.Sprintf("s.Get(%s, Route%s)", scopeTypeGo(f.scopeTop), f.name)
+	if f.goType == "[]string" {
+		mainGen.Pln(`cv :=`, getter)
+		mainGen.Pln(`v, err := cv.Strs()`)
+	} else if f.defaultGo != "" {
+		mainGen.Pln(`v, ok, err :=`, getter+`.`+goTypes[f.goType]+`()`)
+	} else {
+		mainGen.Pln(`v, _, err :=`, getter+`.`+goTypes[f.goType]+`()`)
+	}
+	mainGen.Pln(`if err != nil {`)
+	mainGen.In()
+	mainGen.Pln(`return`, zero+`, errors.Wrapf(err, "[`+g.Package+`]`, f.name, `with route %q", Route`+f.name+`)`)
+	mainGen.Out()
+	mainGen.Pln(`}`)
+	if f.defaultGo != "" {
+		if f.goType == "[]string" {
+			mainGen.Pln(`if !cv.IsValid() {`)
+		} else {
+			mainGen.Pln(`if !ok {`)
+		}
+		mainGen.In()
+		mainGen.Pln(`return`, f.defaultGo+`, nil`)
+		mainGen.Out()
+		mainGen.Pln(`}`)
+	}
+	mainGen.Pln(`return v, nil`)
+	mainGen.Out()
+	mainGen.Pln(`}`)
+}
+
+// testSamples returns two different raw values and their Go source code for a
+// Go type. The first value differs from the default value.
+func testSamples(f field) (raw [2]string, lit [2]string) {
+	switch f.goType {
+	case "bool":
+		raw = [2]string{"true", "false"}
+		if f.defaultGo == "true" {
+			raw = [2]string{"false", "true"}
+		}
+	case "int", "int64", "uint64":
+		raw = [2]string{"4711", "815"}
+	case "float64":
+		raw = [2]string{"47.11", "8.15"}
+	case "[]string":
+		raw = [2]string{"cs1,cs2", "cs3"}
+	case "time.Time":
+		raw = [2]string{"2022-01-02 03:04:05", "2021-02-03 04:05:06"}
+	case "time.Duration":
+		raw = [2]string{"90s", "1h"}
+	default:
+		raw = [2]string{"cfggen", "denied"}
+	}
+	for i, r := range raw {
+		lit[i], _ = goLiteral(f.goType, r)
+		lit[i] = typedLiteral(f.goType, lit[i])
+	}
+	return raw, lit
+}
+
+// typedLiteral makes a numeric literal comparable with assert.Exactly.
+func typedLiteral(goType, lit string) string {
+	switch goType {
+	case "int64", "uint64", "float64", "time.Duration":
+		if !strings.HasPrefix(lit, goType) {
+			return goType + "(" + lit + ")"
+		}
+	case "[]string":
+		if lit == "nil" {
+			return "[]string(nil)"
+		}
+	}
+	return lit
+}
+
+func scopePathGo(t scope.Type) string {
+	switch t {
+	case scope.Store:
+		return "scope.Store.WithID(2)"
+	case scope.Website:
+		return "scope.Website.WithID(1)"
+	}
+	return "scope.DefaultTypeID"
+}
+
+func (g *Generator) fnTest(testGen *codegen.Go, fs []field) {
+	if len(fs) == 0 {
+		return
+	}
+	testGen.AddImports("testing", "github.com/corestoreio/pkg/config", "github.com/corestoreio/pkg/config/storage",
+		"github.com/corestoreio/pkg/store/scope", "github.com/corestoreio/pkg/util/assert")
+
+	testGen.Pln(`func TestAccessors(t *testing.T) {`)
+	testGen.In()
+	testGen.Pln(`t.Parallel()`)
+	for _, f := range fs {
+		if strings.HasPrefix(f.goType, "time.") {
+			testGen.AddImports("time")
+		}
+		raw, lit := testSamples(f)
+		def := f.defaultGo
+		if def == "" {
+			def = zeroLiteral(f.goType)
+		}
+		testGen.Pln(`t.Run(` + strconv.Quote(f.name) + `, func(t *testing.T) {`)
+		testGen.In()
+		testGen.Pln(`m := storage.NewMap()`)
+		testGen.Pln(`scp := config.NewFakeService(m).Scoped(1, 2)`)
+		testGen.Pln(`v, err := `+f.name+`(scp)`, `// not set, returns the default value`)
+		testGen.Pln(`assert.NoError(t, err)`)
+		testGen.Pln(`assert.Exactly(t,`, typedLiteral(f.goType, def)+`, v)`)
+		testGen.Pln(`assert.NoError(t, m.Set(config.MustMakePathWithScope(` + scopePathGo(f.scopeTop) + `, Route` + f.name + `), []byte(` + strconv.Quote(raw[0]) + `)))`)
+		if f.scopeTop < scope.Store {
+			testGen.Pln(`// a value in a higher scope than allowed must be ignored`)
+			testGen.Pln(`assert.NoError(t, m.Set(config.MustMakePathWithScope(scope.Store.WithID(2), Route` + f.name + `), []byte(` + strconv.Quote(raw[1]) + `)))`)
+		}
+		testGen.Pln(`v, err = ` + f.name + `(scp)`)
+		testGen.Pln(`assert.NoError(t, err)`)
+		testGen.Pln(`assert.Exactly(t,`, lit[0]+`, v)`)
+		testGen.Out()
+		testGen.Pln(`})`)
+	}
+	testGen.Out()
+	testGen.Pln(`}`)
+}
+
+func permGo(p scope.Perm) string {
+	switch p {
+	case scope.PermStore:
+		return "scope.PermStore"
+	case scope.PermWebsite:
+		return "scope.PermWebsite"
+	case scope.PermDefault:
+		return "scope.PermDefault"
+	}
+	return fmt.Sprintf("scope.Perm(%d)", p)
+}
+
+func fieldTypeGo(t config.FieldType) string {
+	if t == config.TypeDuration {
+		return "config.TypeDuration"
+	}
+	return "config." + t.String()
+}
+
+// fnSections writes the Sections as Go source code into a variable.
+func (g *Generator) fnSections(mainGen *codegen.Go) {
+	if g.SectionsVar == "" {
+		return
+	}
+	mainGen.AddImports("github.com/corestoreio/pkg/config", "github.com/corestoreio/pkg/store/scope")
+	kv := func(cond bool, key, value string) {
+		mainGen.Pln(cond, key+`:`, value+`,`)
+	}
+
+	mainGen.C(g.SectionsVar, `contains the sections, groups and fields of package`, g.Package+`.`)
+	mainGen.Pln(`var`, g.SectionsVar, `= config.MustMakeSectionsValidate(`)
+	mainGen.In()
+	for _, s := range g.Sections {
+		mainGen.Pln(`&config.Section{`)
+		mainGen.In()
+		kv(true, "ID", strconv.Quote(s.ID))
+		kv(s.Label != "", "Label", strconv.Quote(s.Label))
+		kv(s.Scopes > 0, "Scopes", permGo(s.Scopes))
+		kv(s.SortOrder != 0, "SortOrder", strconv.Itoa(s.SortOrder))
+		kv(s.Resource > 0, "Resource", strconv.FormatUint(uint64(s.Resource), 10))
+		mainGen.Pln(`Groups: config.MakeGroups(`)
+		mainGen.In()
+		for _, gr := range s.Groups {
+			mainGen.Pln(`&config.Group{`)
+			mainGen.In()
+			kv(true, "ID", strconv.Quote(gr.ID))
+			kv(gr.Label != "", "Label", strconv.Quote(gr.Label))
+			kv(gr.Comment != "", "Comment", strconv.Quote(gr.Comment))
+			kv(gr.Scopes > 0, "Scopes", permGo(gr.Scopes))
+			kv(gr.SortOrder != 0, "SortOrder", strconv.Itoa(gr.SortOrder))
+			kv(gr.HelpURL != "", "HelpURL", strconv.Quote(gr.HelpURL))
+			kv(gr.MoreURL != "", "MoreURL", strconv.Quote(gr.MoreURL))
+			kv(gr.DemoLink != "", "DemoLink", strconv.Quote(gr.DemoLink))
+			mainGen.Pln(`Fields: config.MakeFields(`)
+			mainGen.In()
+			for _, f := range gr.Fields {
+				mainGen.Pln(`&config.Field{`)
+				mainGen.In()
+				mainGen.Pln(`// Path:`, s.ID+"/"+gr.ID+"/"+f.ID)
+				kv(true, "ID", strconv.Quote(f.ID))
+				kv(f.ConfigRoute != "", "ConfigRoute", strconv.Quote(f.ConfigRoute))
+				kv(f.Type > 0, "Type", fieldTypeGo(f.Type))
+				kv(f.Label != "", "Label", strconv.Quote(f.Label))
+				kv(f.Comment != "", "Comment", strconv.Quote(f.Comment))
+				kv(f.Tooltip != "", "Tooltip", strconv.Quote(f.Tooltip))
+				kv(f.SortOrder != 0, "SortOrder", strconv.Itoa(f.SortOrder))
+				kv(f.Visible, "Visible", "true")
+				kv(f.CanBeEmpty, "CanBeEmpty", "true")
+				kv(f.Scopes > 0, "Scopes", permGo(f.Scopes))
+				kv(f.Default != "", "Default", strconv.Quote(f.Default))
+				mainGen.Out()
+				mainGen.Pln(`},`)
+			}
+			mainGen.Out()
+			mainGen.Pln(`),`)
+			mainGen.Out()
+			mainGen.Pln(`},`)
+		}
+		mainGen.Out()
+		mainGen.Pln(`),`)
+		mainGen.Out()
+		mainGen.Pln(`},`)
+	}
+	mainGen.Out()
+	mainGen.Pln(`)`)
+}
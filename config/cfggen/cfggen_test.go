@@ -0,0 +1,149 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfggen_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/cfggen"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/codegen"
+)
+
+func testSections() []*config.Section {
+	return []*config.Section{
+		{
+			ID:     "carriers",
+			Scopes: scope.PermStore,
+			Groups: config.MakeGroups(
+				&config.Group{
+					ID:     "flatrate",
+					Label:  "Flat Rate",
+					Scopes: scope.PermStore,
+					Fields: config.MakeFields(
+						&config.Field{ID: "active", Label: "Enabled", Type: config.TypeSelect, Scopes: scope.PermWebsite, Default: "false"},
+						&config.Field{ID: "price", Label: "Price", Type: config.TypeText, Default: "5.5"},
+						&config.Field{ID: "sort_order", Label: "Sort Order", Type: config.TypeText, Scopes: scope.PermWebsite, Default: "100"},
+						&config.Field{ID: "title", Label: "Title", Type: config.TypeText, Default: "Flat Rate"},
+						&config.Field{ID: "specificerrmsg", Label: "Error Message", Type: config.TypeTextarea},
+						&config.Field{ID: "specificcountry", Label: "Ship to Specific Countries", Type: config.TypeMultiselect, Scopes: scope.PermWebsite, Default: "DE,CH"},
+						&config.Field{ID: "max_package_weight", Type: config.TypeText, Scopes: scope.PermDefault, Default: "150"},
+					),
+				},
+			),
+		},
+		{
+			ID: "system",
+			Groups: config.MakeGroups(
+				&config.Group{
+					ID:     "cron",
+					Scopes: scope.PermDefault,
+					Fields: config.MakeFields(
+						&config.Field{ID: "history_lifetime", Type: config.TypeDuration, Default: "60m"},
+						&config.Field{ID: "last_run", Type: config.TypeText},
+						&config.Field{ID: "installed_at", Type: config.TypeText, Default: "2018-04-01 12:13:14"},
+					),
+				},
+			),
+		},
+	}
+}
+
+func writeFile(t *testing.T, outFile string, wFn func(io.Writer, io.Writer) error) {
+	var main, test bytes.Buffer
+	err := wFn(&main, &test)
+	if fe, ok := errors.Cause(err).(*codegen.FormatError); ok {
+		t.Fatalf("Formatting failed: %s\n%s", fe.Error(), fe.Code)
+	}
+	assert.NoError(t, err, "%+v", err)
+	assert.NoError(t, os.WriteFile(outFile, main.Bytes(), 0o644))
+	assert.NoError(t, os.WriteFile(outFile[:len(outFile)-3]+"_test.go", test.Bytes(), 0o644))
+}
+
+// TestGenerator_GenerateGo writes the accessors into the package
+// cfgtestgenerated. Its generated test verifies the default values and scope
+// restrictions.
+func TestGenerator_GenerateGo(t *testing.T) {
+	g := cfggen.NewGenerator("cfgtestgenerated", testSections()...)
+	g.SectionsVar = "ConfigStructure"
+	g.GoTypes["carriers/flatrate/max_package_weight"] = "uint64"
+	g.GoTypes["system/cron/last_run"] = "time.Time"
+	g.GoTypes["system/cron/installed_at"] = "time.Time"
+
+	writeFile(t, "cfgtestgenerated/config_gen.go", g.GenerateGo)
+}
+
+func TestGenerator_Errors(t *testing.T) {
+	t.Run("field exceeds group scope", func(t *testing.T) {
+		ss := testSections()
+		ss[1].Groups[0].Fields[0].Scopes = scope.PermStore
+		err := cfggen.NewGenerator("x", ss...).GenerateGo(new(bytes.Buffer), new(bytes.Buffer))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		assert.Contains(t, err.Error(), `"system/cron/history_lifetime": field scope "Store" exceeds group scope "Default"`)
+	})
+	t.Run("group exceeds section scope", func(t *testing.T) {
+		ss := testSections()
+		ss[0].Scopes = scope.PermWebsite
+		err := cfggen.NewGenerator("x", ss...).GenerateGo(new(bytes.Buffer), new(bytes.Buffer))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+	t.Run("invalid default", func(t *testing.T) {
+		g := cfggen.NewGenerator("x", testSections()...)
+		g.GoTypes["carriers/flatrate/title"] = "int"
+		err := g.GenerateGo(new(bytes.Buffer), new(bytes.Buffer))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+	t.Run("unsupported type", func(t *testing.T) {
+		g := cfggen.NewGenerator("x", testSections()...)
+		g.GoTypes["carriers/flatrate/title"] = "complex128"
+		err := g.GenerateGo(new(bytes.Buffer), new(bytes.Buffer))
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+	t.Run("duplicate accessor name", func(t *testing.T) {
+		ss := testSections()
+		ss[0].Groups[0].Fields = append(ss[0].Groups[0].Fields, &config.Field{ID: "sort_order_"})
+		err := cfggen.NewGenerator("x", ss...).GenerateGo(new(bytes.Buffer), new(bytes.Buffer))
+		assert.ErrorIsKind(t, errors.Duplicated, err)
+	})
+}
+
+func TestParsePkgTemplate(t *testing.T) {
+	pt, err := cfggen.ParsePkgTemplate("../_pkgtpl/config_contact.go", nil)
+	assert.NoError(t, err)
+	assert.Exactly(t, "contact", pt.Package)
+	assert.Exactly(t, 4, pt.Sections.TotalFields())
+
+	f, _ := pt.Sections.FindField("contact/email/recipient_email")
+	assert.Exactly(t, "Send Emails To", f.Label)
+	assert.Exactly(t, "hello@example.com", f.Default)
+	assert.Exactly(t, config.TypeText, f.Type)
+	assert.Exactly(t, scope.PermStore, f.Scopes)
+	assert.True(t, f.Visible)
+	assert.Exactly(t, map[string]string{"contact/contact/enabled": "bool"}, pt.GoTypes)
+
+	g := cfggen.NewGenerator(pt.Package, pt.Sections...)
+	g.GoTypes = pt.GoTypes
+	g.SectionsVar = "ConfigStructure"
+	var main bytes.Buffer
+	assert.NoError(t, g.GenerateGo(&main, new(bytes.Buffer)))
+	assert.Contains(t, main.String(), "func ContactContactEnabled(s config.Scoped) (bool, error) {")
+	assert.Contains(t, main.String(), "var ConfigStructure = config.MustMakeSectionsValidate(")
+}
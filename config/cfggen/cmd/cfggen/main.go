@@ -0,0 +1,96 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command cfggen regenerates the templates in config/_pkgtpl into real
+// packages. Each package contains the variable ConfigStructure with the
+// sections, the typed accessor functions and their tests.
+//
+// Example usage:
+//
+//	cfggen -tpl 'config/_pkgtpl/config_*.go' -out ./magento
+//	cfggen -tpl config/_pkgtpl/config_contact.go -out ./magento
+//
+// Templates with invalid scope permissions or default values get reported and
+// skipped. The exit status is 1 if at least one template failed.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/corestoreio/pkg/config/cfggen"
+)
+
+var (
+	flagTpl  = flag.String("tpl", "config/_pkgtpl/config_*.go", "glob pattern of the template files")
+	flagOut  = flag.String("out", ".", "output directory, each package gets written into its own sub directory")
+	flagVar  = flag.String("var", "ConfigStructure", "name of the package level variable containing the sections")
+	flagTags = flag.String("tags", "", "optional build tags written at the top of the generated files")
+)
+
+func main() {
+	flag.Parse()
+
+	files, err := filepath.Glob(*flagTpl)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var failed int
+	for _, file := range files {
+		if err := generate(file); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", file, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d templates failed\n", failed, len(files))
+		os.Exit(1)
+	}
+}
+
+func generate(file string) error {
+	pt, err := cfggen.ParsePkgTemplate(file, nil)
+	if err != nil {
+		return err
+	}
+	if len(pt.Sections) == 0 {
+		return nil // e.g. the *_backend.go files
+	}
+
+	g := cfggen.NewGenerator(pt.Package, pt.Sections...)
+	g.GoTypes = pt.GoTypes
+	g.SectionsVar = *flagVar
+	if *flagTags != "" {
+		g.BuildTags = []string{*flagTags}
+	}
+
+	var main, test bytes.Buffer
+	if err := g.GenerateGo(&main, &test); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(*flagOut, pt.Package)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "config_gen.go"), main.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "config_gen_test.go"), test.Bytes(), 0o644)
+}
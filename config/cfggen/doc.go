@@ -0,0 +1,37 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfggen generates typed accessor functions from config.Sections.
+//
+// Instead of calling
+//
+//	v, ok, err := scoped.Get(scope.Store, "carriers/flatrate/price").Float64()
+//
+// a package calls the generated function
+//
+//	v, err := CarriersFlatratePrice(scoped)
+//
+// which knows the route, the Go type, the maximum scope and the default value
+// of a field. The generator validates the scope permissions of the sections,
+// groups and fields and checks that each default value can be parsed into the
+// Go type of the field. A generated test file verifies the default values and
+// the scope restrictions at runtime.
+//
+// The Go type of a field gets inferred from its FieldType and default value
+// and can be overwritten via Generator.GoTypes.
+//
+// ParsePkgTemplate reads the dormant templates in config/_pkgtpl, which have
+// been generated from Magento system.xml files, so that command cfggen can
+// regenerate them into real packages.
+package cfggen
@@ -0,0 +1,285 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfggen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/store/scope"
+)
+
+// PkgTemplate contains the data of a template in config/_pkgtpl.
+type PkgTemplate struct {
+	// Package defines the package name of the template.
+	Package string
+	// Sections contains all merged sections, including the hidden ones.
+	Sections config.Sections
+	// GoTypes contains the Go types derived from the typed default values,
+	// e.g. `Default: true` results in a bool. Can be assigned to
+	// Generator.GoTypes.
+	GoTypes map[string]string
+}
+
+var pkgTplFieldTypes = map[string]config.FieldType{
+	"TypeButton":        config.TypeButton,
+	"TypeCustom":        config.TypeCustom,
+	"TypeLabel":         config.TypeLabel,
+	"TypeHidden":        config.TypeHidden,
+	"TypeImage":         config.TypeImage,
+	"TypeObscure":       config.TypeObscure,
+	"TypeMultiselect":   config.TypeMultiselect,
+	"TypeSelect":        config.TypeSelect,
+	"TypeAllowspecific": config.TypeSelect,
+	"TypeText":          config.TypeText,
+	"TypeTextarea":      config.TypeTextarea,
+	"TypeTime":          config.TypeTime,
+	"TypeDuration":      config.TypeDuration,
+}
+
+var pkgTplScopes = map[string]scope.Perm{
+	"PermDefault": scope.PermDefault,
+	"PermWebsite": scope.PermWebsite,
+	"PermStore":   scope.PermStore,
+}
+
+// ParsePkgTemplate parses the Go source code of a template in config/_pkgtpl.
+// The templates use outdated APIs and do not compile, hence the composite
+// literals of the sections, groups and fields get evaluated statically.
+// Arguments filename and src have the same meaning as in go/parser.ParseFile.
+func ParsePkgTemplate(filename string, src interface{}) (*PkgTemplate, error) {
+	fset := token.NewFileSet()
+	af, err := parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return nil, errors.NotValid.New(err, "[cfggen] ParsePkgTemplate: failed to parse %q", filename)
+	}
+
+	pt := &PkgTemplate{
+		Package: af.Name.Name,
+		GoTypes: map[string]string{},
+	}
+	for _, sl := range findLiterals(af, "Section") {
+		s := new(config.Section)
+		for _, kv := range keyValues(sl) {
+			switch kv.key {
+			case "ID":
+				s.ID = evalString(kv.value)
+			case "Label":
+				s.Label = evalString(kv.value)
+			case "Scope", "Scopes":
+				s.Scopes = evalPerm(kv.value)
+			case "SortOrder":
+				s.SortOrder, _ = strconv.Atoi(evalString(kv.value))
+			case "Groups":
+				for _, gl := range findLiterals(kv.value, "Group") {
+					s.Groups = append(s.Groups, pt.parseGroup(s.ID, gl))
+				}
+			}
+		}
+		pt.Sections = pt.Sections.Merge(s)
+	}
+	if err := pt.Sections.Validate(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return pt, nil
+}
+
+func (pt *PkgTemplate) parseGroup(sectionID string, gl *ast.CompositeLit) *config.Group {
+	g := new(config.Group)
+	for _, kv := range keyValues(gl) {
+		switch kv.key {
+		case "ID":
+			g.ID = evalString(kv.value)
+		case "Label":
+			g.Label = evalString(kv.value)
+		case "Comment":
+			g.Comment = evalString(kv.value)
+		case "Scope", "Scopes":
+			g.Scopes = evalPerm(kv.value)
+		case "SortOrder":
+			g.SortOrder, _ = strconv.Atoi(evalString(kv.value))
+		case "HelpURL":
+			g.HelpURL = evalString(kv.value)
+		case "MoreURL":
+			g.MoreURL = evalString(kv.value)
+		case "DemoLink":
+			g.DemoLink = evalString(kv.value)
+		}
+	}
+	for _, kv := range keyValues(gl) {
+		if kv.key != "Fields" {
+			continue
+		}
+		for _, fl := range findLiterals(kv.value, "Field") {
+			g.Fields = append(g.Fields, pt.parseField(sectionID+"/"+g.ID+"/", fl))
+		}
+	}
+	return g
+}
+
+func (pt *PkgTemplate) parseField(routePrefix string, fl *ast.CompositeLit) *config.Field {
+	f := new(config.Field)
+	var goType string
+	for _, kv := range keyValues(fl) {
+		switch kv.key {
+		case "ID":
+			f.ID = evalString(kv.value)
+		case "ConfigPath", "ConfigRoute":
+			f.ConfigRoute = evalString(kv.value)
+		case "Type":
+			if sel, ok := kv.value.(*ast.SelectorExpr); ok {
+				f.Type = pkgTplFieldTypes[sel.Sel.Name]
+			}
+		case "Label":
+			f.Label = evalString(kv.value)
+		case "Comment":
+			f.Comment = evalString(kv.value)
+		case "Tooltip":
+			f.Tooltip = evalString(kv.value)
+		case "SortOrder":
+			f.SortOrder, _ = strconv.Atoi(evalString(kv.value))
+		case "Visible":
+			v := evalString(kv.value)
+			f.Visible = v == "VisibleYes" || v == "true"
+		case "CanBeEmpty":
+			f.CanBeEmpty = evalString(kv.value) == "true"
+		case "Scope", "Scopes":
+			f.Scopes = evalPerm(kv.value)
+		case "Default":
+			f.Default = evalString(kv.value)
+			goType = literalGoType(kv.value)
+		}
+	}
+	if goType != "" {
+		pt.GoTypes[routePrefix+f.ID] = goType
+	}
+	return f
+}
+
+type keyValue struct {
+	key   string
+	value ast.Expr
+}
+
+func keyValues(cl *ast.CompositeLit) []keyValue {
+	kvs := make([]keyValue, 0, len(cl.Elts))
+	for _, e := range cl.Elts {
+		kv, ok := e.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if id, ok := kv.Key.(*ast.Ident); ok {
+			kvs = append(kvs, keyValue{key: id.Name, value: kv.Value})
+		}
+	}
+	return kvs
+}
+
+// findLiterals returns all composite literals of the type name, e.g.
+// element.Section{} or &config.Section{}, without descending into them.
+func findLiterals(n ast.Node, typeName string) (cls []*ast.CompositeLit) {
+	ast.Inspect(n, func(n ast.Node) bool {
+		cl, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		var name string
+		switch t := cl.Type.(type) {
+		case *ast.SelectorExpr:
+			name = t.Sel.Name
+		case *ast.Ident:
+			name = t.Name
+		}
+		if name != typeName {
+			return true
+		}
+		cls = append(cls, cl)
+		return false
+	})
+	return cls
+}
+
+// evalString returns the string value of basic literals, identifiers and
+// selectors. Function calls like text.Long(`x`) return their first argument.
+func evalString(e ast.Expr) string {
+	switch v := e.(type) {
+	case *ast.BasicLit:
+		if v.Kind == token.STRING {
+			s, _ := strconv.Unquote(v.Value)
+			return s
+		}
+		return v.Value
+	case *ast.Ident:
+		if v.Name == "nil" {
+			return ""
+		}
+		return v.Name
+	case *ast.SelectorExpr:
+		return v.Sel.Name
+	case *ast.UnaryExpr:
+		if v.Op == token.SUB {
+			return "-" + evalString(v.X)
+		}
+	case *ast.CallExpr:
+		if len(v.Args) > 0 {
+			return evalString(v.Args[0])
+		}
+	}
+	return ""
+}
+
+// literalGoType returns the Go type of a typed default value.
+func literalGoType(e ast.Expr) string {
+	if u, ok := e.(*ast.UnaryExpr); ok {
+		e = u.X
+	}
+	switch v := e.(type) {
+	case *ast.BasicLit:
+		switch v.Kind {
+		case token.INT:
+			return "int"
+		case token.FLOAT:
+			return "float64"
+		}
+	case *ast.Ident:
+		if v.Name == "true" || v.Name == "false" {
+			return "bool"
+		}
+	}
+	return ""
+}
+
+func evalPerm(e ast.Expr) scope.Perm {
+	if call, ok := e.(*ast.CallExpr); ok {
+		// scope.NewPerm(scope.Default, scope.Website)
+		var p scope.Perm
+		for _, a := range call.Args {
+			switch evalString(a) {
+			case "Default", "DefaultID":
+				p = p.Set(scope.Default)
+			case "Website", "WebsiteID":
+				p = p.Set(scope.Website)
+			case "Store", "StoreID":
+				p = p.Set(scope.Store)
+			}
+		}
+		return p
+	}
+	return pkgTplScopes[evalString(e)]
+}
@@ -57,6 +57,16 @@ The examples show the overall best practices.
 //    |                                      |                |
 //    +                                      + <- Route ----> +
 //
+// Interpolation
+//
+// If Options.EnableInterpolation has been set, Scoped.Get expands the
+// placeholder {{config "web/secure/base_url"}} with the value of another route
+// and {CS_ENV} with the name of the environment. References get resolved
+// through the scope hierarchy of the Scoped type, hence a return URL stored
+// once in the default scope contains the base URL of each store. Cycles
+// return an error. Expanded values get cached until a referenced route
+// changes.
+//
 // Scope
 //
 // A scope can only be default, websites or stores. Those three strings are
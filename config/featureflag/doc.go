@@ -0,0 +1,37 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package featureflag provides scope aware feature flags on top of
+// config.Service.
+//
+// A flag definition gets stored as JSON in the configuration under the route
+// RoutePrefix + "/" + flag name, e.g. feature/flags/new_checkout, for the
+// default, website or store scope. The most specific scope wins:
+//
+//	{"enabled":true,"percentage":25,"allow":["4711"],"deny":["0815"],
+//	 "start":"2026-11-01T00:00:00Z","end":"2026-12-24T00:00:00Z"}
+//
+// A flag gets evaluated for the scope and the stable identifier, e.g. a
+// customer ID or a session ID, found in a context.Context. The scope gets set
+// via scope.WithContext and the identifier via WithIdentifier. The evaluation
+// order is: enabled, start and end time, deny list, allow list and the
+// percentage rollout. The rollout hashes the flag name together with the
+// identifier, so an identifier keeps its result while the percentage rises. A
+// missing percentage enables the flag for all identifiers, a percentage of
+// zero only for the allow list.
+//
+// Service.WithFlags is an HTTP middleware which evaluates the flags of
+// Options.Names and passes them to the next handlers. A handler retrieves them
+// via FromContext.
+package featureflag
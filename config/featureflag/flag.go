@@ -0,0 +1,89 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflag
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// Flag defines a feature flag. The JSON representation gets stored in the
+// configuration.
+type Flag struct {
+	// Name of the flag, gets set by the Service and is the last segment of the
+	// configuration route.
+	Name string `json:"-"`
+	// Enabled must be true to switch the flag on at all.
+	Enabled bool `json:"enabled"`
+	// Percentage defines the rollout in percent of the identifiers. Nil or 100
+	// and above enable the flag for all identifiers, zero only for the allowed
+	// ones. Requests without an identifier are excluded from a partial
+	// rollout.
+	Percentage *uint8 `json:"percentage,omitempty"`
+	// Allow lists identifiers for which the flag is always on, unless the flag
+	// is disabled or out of its time range.
+	Allow []string `json:"allow,omitempty"`
+	// Deny lists identifiers for which the flag is always off.
+	Deny []string `json:"deny,omitempty"`
+	// Start if not zero, defines the time from which on the flag is on.
+	Start time.Time `json:"start,omitempty"`
+	// End if not zero, defines the time from which on the flag is off.
+	End time.Time `json:"end,omitempty"`
+}
+
+// IsEnabled evaluates the flag for the identifier at time now.
+func (f Flag) IsEnabled(identifier string, now time.Time) bool {
+	switch {
+	case !f.Enabled:
+		return false
+	case !f.Start.IsZero() && now.Before(f.Start):
+		return false
+	case !f.End.IsZero() && !now.Before(f.End):
+		return false
+	case identifier != "" && contains(f.Deny, identifier):
+		return false
+	case identifier != "" && contains(f.Allow, identifier):
+		return true
+	case f.Percentage == nil || *f.Percentage >= 100:
+		return true
+	case identifier == "":
+		return false
+	}
+	return bucket(f.Name, identifier) < uint32(*f.Percentage)
+}
+
+// Percent returns a pointer to p for Flag.Percentage.
+func Percent(p uint8) *uint8 {
+	return &p
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// bucket assigns the identifier to a stable bucket between 0 and 99. The flag
+// name gets included, so the rollouts of different flags are independent.
+func bucket(name, identifier string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(identifier))
+	return h.Sum32() % 100
+}
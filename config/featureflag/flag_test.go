@@ -0,0 +1,111 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflag_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/pkg/config/featureflag"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestFlag_IsEnabled(t *testing.T) {
+	now := time.Date(2026, 11, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		f    featureflag.Flag
+		id   string
+		want bool
+	}{
+		{"disabled", featureflag.Flag{Allow: []string{"a"}}, "a", false},
+		{"enabled for all", featureflag.Flag{Enabled: true}, "", true},
+		{"not yet started", featureflag.Flag{Enabled: true, Start: now.Add(time.Hour)}, "a", false},
+		{"started", featureflag.Flag{Enabled: true, Start: now}, "a", true},
+		{"ended", featureflag.Flag{Enabled: true, End: now}, "a", false},
+		{"before end", featureflag.Flag{Enabled: true, End: now.Add(time.Second)}, "a", true},
+		{"denied", featureflag.Flag{Enabled: true, Deny: []string{"x", "a"}}, "a", false},
+		{"deny wins over allow", featureflag.Flag{Enabled: true, Deny: []string{"a"}, Allow: []string{"a"}}, "a", false},
+		{"allowed despite 1 percent", featureflag.Flag{Enabled: true, Percentage: featureflag.Percent(1), Allow: []string{"a"}}, "a", true},
+		{"allowed but ended", featureflag.Flag{Enabled: true, Allow: []string{"a"}, End: now}, "a", false},
+		{"rollout without identifier", featureflag.Flag{Enabled: true, Percentage: featureflag.Percent(50)}, "", false},
+		{"0 percent", featureflag.Flag{Enabled: true, Percentage: featureflag.Percent(0)}, "a", false},
+		{"0 percent allowed", featureflag.Flag{Enabled: true, Percentage: featureflag.Percent(0), Allow: []string{"a"}}, "a", true},
+		{"0 percent without identifier", featureflag.Flag{Enabled: true, Percentage: featureflag.Percent(0)}, "", false},
+		{"100 percent", featureflag.Flag{Enabled: true, Percentage: featureflag.Percent(100)}, "", true},
+	}
+	for _, test := range tests {
+		assert.Exactly(t, test.want, test.f.IsEnabled(test.id, now), test.name)
+	}
+}
+
+func TestFlag_IsEnabled_Rollout(t *testing.T) {
+	now := time.Now()
+	f0 := featureflag.Flag{Name: "new_checkout", Enabled: true, Percentage: featureflag.Percent(0)}
+	f1 := featureflag.Flag{Name: "new_checkout", Enabled: true, Percentage: featureflag.Percent(1)}
+	f10 := featureflag.Flag{Name: "new_checkout", Enabled: true, Percentage: featureflag.Percent(10)}
+	f50 := featureflag.Flag{Name: "new_checkout", Enabled: true, Percentage: featureflag.Percent(50)}
+	f99 := featureflag.Flag{Name: "new_checkout", Enabled: true, Percentage: featureflag.Percent(99)}
+	f100 := featureflag.Flag{Name: "new_checkout", Enabled: true, Percentage: featureflag.Percent(100)}
+
+	var on0, on1, on10, on50, on99, on100 int
+	for i := 0; i < 10000; i++ {
+		id := strconv.Itoa(i)
+		e10 := f10.IsEnabled(id, now)
+		e50 := f50.IsEnabled(id, now)
+		if e10 {
+			on10++
+			assert.True(t, e50, "identifier %q must stay enabled while the rollout rises", id)
+		}
+		if e50 {
+			on50++
+		}
+		for _, c := range []struct {
+			f  featureflag.Flag
+			on *int
+		}{{f0, &on0}, {f1, &on1}, {f99, &on99}, {f100, &on100}} {
+			if c.f.IsEnabled(id, now) {
+				*c.on++
+			}
+		}
+		assert.Exactly(t, e10, f10.IsEnabled(id, now), "stable result")
+	}
+	assert.Exactly(t, 0, on0)
+	assert.InDelta(t, 100, on1, 50)
+	assert.InDelta(t, 1000, on10, 150)
+	assert.InDelta(t, 5000, on50, 300)
+	assert.InDelta(t, 9900, on99, 50)
+	assert.Exactly(t, 10000, on100)
+}
+
+func TestFlag_JSON_Percentage(t *testing.T) {
+	for _, p := range []uint8{0, 1, 99, 100} {
+		data, err := json.Marshal(featureflag.Flag{Enabled: true, Percentage: featureflag.Percent(p)})
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"percentage":`+strconv.Itoa(int(p)))
+
+		var f featureflag.Flag
+		assert.NoError(t, json.Unmarshal(data, &f))
+		assert.Exactly(t, p, *f.Percentage)
+	}
+
+	var f featureflag.Flag
+	assert.NoError(t, json.Unmarshal([]byte(`{"enabled":true}`), &f))
+	assert.Nil(t, f.Percentage)
+	assert.True(t, f.IsEnabled("a", time.Now()), "without percentage for all identifiers")
+}
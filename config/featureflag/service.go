@@ -0,0 +1,204 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/store/scope"
+)
+
+// DefaultRoutePrefix defines the first two segments of the routes of the flag
+// definitions.
+const DefaultRoutePrefix = "feature/flags"
+
+// Scoper gets implemented by config.Service.
+type Scoper interface {
+	Scoped(websiteID, storeID uint32) config.Scoped
+}
+
+// Options applies options to the Service.
+type Options struct {
+	// RoutePrefix defaults to DefaultRoutePrefix. It must contain two route
+	// segments because the flag name gets appended as third segment.
+	RoutePrefix string
+	// Names lists the flags which the middleware WithFlags evaluates.
+	Names []string
+	// IdentifierFunc extracts the stable identifier, e.g. customer ID or
+	// session ID, from a request in middleware WithFlags. If nil, the
+	// identifier must already be set in the request context via
+	// WithIdentifier.
+	IdentifierFunc func(r *http.Request) string
+	// Now returns the current time, defaults to time.Now.
+	Now func() time.Time
+	// Log if set, logs evaluation errors with level info.
+	Log log.Logger
+}
+
+// Service evaluates feature flags stored in the configuration.
+type Service struct {
+	cfg Scoper
+	o   Options
+}
+
+// New creates a new feature flag service. The flag names get validated.
+func New(cfg Scoper, o Options) (*Service, error) {
+	if o.RoutePrefix == "" {
+		o.RoutePrefix = DefaultRoutePrefix
+	}
+	if o.Now == nil {
+		o.Now = time.Now
+	}
+	s := &Service{cfg: cfg, o: o}
+	for _, n := range o.Names {
+		if _, err := s.route(n); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return s, nil
+}
+
+// MustNew same as New but panics on error.
+func MustNew(cfg Scoper, o Options) *Service {
+	s, err := New(cfg, o)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// route builds and validates the configuration route of a flag.
+func (s *Service) route(name string) (string, error) {
+	r := s.o.RoutePrefix + "/" + name
+	p, err := config.MakePath(r)
+	if err != nil {
+		return "", errors.NotValid.New(err, "[featureflag] Invalid flag name %q", name)
+	}
+	if p.Separators() != config.PathLevels-1 {
+		return "", errors.NotValid.Newf("[featureflag] Flag name %q must be a single route segment", name)
+	}
+	return r, nil
+}
+
+// Flag loads the definition of the flag for the scope found in the context.
+// The store scope falls back to the website and the default scope. A
+// non-existing flag returns a disabled Flag.
+func (s *Service) Flag(ctx context.Context, name string) (Flag, error) {
+	route, err := s.route(name)
+	if err != nil {
+		return Flag{}, errors.WithStack(err)
+	}
+	websiteID, storeID, _ := scope.FromContext(ctx)
+	f := Flag{Name: name}
+	data, ok, err := s.cfg.Scoped(websiteID, storeID).Get(scope.Store, route).Str()
+	if err != nil {
+		return f, errors.Wrapf(err, "[featureflag] Failed to load flag %q", name)
+	}
+	if !ok || data == "" {
+		return f, nil
+	}
+	if err := json.Unmarshal([]byte(data), &f); err != nil {
+		return Flag{Name: name}, errors.BadEncoding.New(err, "[featureflag] Failed to decode flag %q: %q", name, data)
+	}
+	f.Name = name
+	return f, nil
+}
+
+// IsEnabled evaluates the flag for the scope and the identifier found in the
+// context.
+func (s *Service) IsEnabled(ctx context.Context, name string) (bool, error) {
+	f, err := s.Flag(ctx, name)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return f.IsEnabled(IdentifierFromContext(ctx), s.o.Now()), nil
+}
+
+// Evaluate evaluates all flags of Options.Names and returns the names of the
+// enabled flags. A flag which cannot be loaded gets logged and counts as
+// disabled.
+func (s *Service) Evaluate(ctx context.Context) Evaluated {
+	var e Evaluated
+	for _, n := range s.o.Names {
+		ok, err := s.IsEnabled(ctx, n)
+		if err != nil && s.o.Log != nil && s.o.Log.IsInfo() {
+			s.o.Log.Info("featureflag.Service.Evaluate", log.String("flag", n), log.Err(err))
+		}
+		if ok {
+			e = append(e, n)
+		}
+	}
+	sort.Strings(e)
+	return e
+}
+
+// Save stores the definition of the flag in the scope. The scope permissions
+// of the route get checked by the config.Setter.
+func (s *Service) Save(w config.Setter, scp scope.TypeID, f Flag) error {
+	route, err := s.route(f.Name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	p, err := config.MakePathWithScope(scp, route)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return errors.BadEncoding.New(err, "[featureflag] Failed to encode flag %q", f.Name)
+	}
+	return errors.WithStack(w.Set(p, data))
+}
+
+// Evaluated contains the sorted names of the enabled flags.
+type Evaluated []string
+
+// IsEnabled reports whether the flag name has been evaluated as enabled.
+func (e Evaluated) IsEnabled(name string) bool {
+	i := sort.SearchStrings(e, name)
+	return i < len(e) && e[i] == name
+}
+
+type ctxKey uint8
+
+const (
+	ctxKeyIdentifier ctxKey = iota
+	ctxKeyEvaluated
+)
+
+// WithIdentifier adds the stable identifier, e.g. a customer ID or a session
+// ID, for the percentage rollouts and the allow and deny lists to the context.
+func WithIdentifier(ctx context.Context, identifier string) context.Context {
+	return context.WithValue(ctx, ctxKeyIdentifier, identifier)
+}
+
+// IdentifierFromContext returns the identifier set via WithIdentifier.
+func IdentifierFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKeyIdentifier).(string)
+	return id
+}
+
+// FromContext returns the flags evaluated by middleware WithFlags.
+func FromContext(ctx context.Context) (Evaluated, bool) {
+	e, ok := ctx.Value(ctxKeyEvaluated).(Evaluated)
+	return e, ok
+}
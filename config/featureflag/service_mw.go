@@ -0,0 +1,36 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflag
+
+import (
+	"context"
+	"net/http"
+)
+
+// WithFlags is an HTTP middleware which evaluates the flags of Options.Names
+// for the scope and identifier of the request. The next handler retrieves the
+// enabled flags via FromContext.
+func (s *Service) WithFlags(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if s.o.IdentifierFunc != nil {
+			if id := s.o.IdentifierFunc(r); id != "" {
+				ctx = WithIdentifier(ctx, id)
+			}
+		}
+		ctx = context.WithValue(ctx, ctxKeyEvaluated, s.Evaluate(ctx))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
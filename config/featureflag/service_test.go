@@ -0,0 +1,121 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featureflag_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/featureflag"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func newService(t *testing.T) (*config.Service, *featureflag.Service) {
+	cfgSrv := config.MustNewService(storage.NewMap(
+		"default/0/feature/flags/new_checkout", `{"enabled":false}`,
+		"websites/1/feature/flags/new_checkout", `{"enabled":true,"deny":["0815"]}`,
+		"stores/3/feature/flags/new_checkout", `{"enabled":true,"allow":["4711"],"percentage":1}`,
+		"default/0/feature/flags/broken", `{"enabled":`,
+	), config.Options{})
+
+	ffs, err := featureflag.New(cfgSrv, featureflag.Options{
+		Names: []string{"new_checkout", "broken", "not_stored"},
+		Now:   func() time.Time { return time.Date(2026, 11, 15, 12, 0, 0, 0, time.UTC) },
+		IdentifierFunc: func(r *http.Request) string {
+			return r.Header.Get("X-Customer-ID")
+		},
+	})
+	assert.NoError(t, err)
+	return cfgSrv, ffs
+}
+
+func TestService_IsEnabled(t *testing.T) {
+	_, ffs := newService(t)
+
+	tests := []struct {
+		ctx  context.Context
+		want bool
+	}{
+		{context.Background(), false},
+		{scope.WithContext(context.Background(), 1, 0), true},
+		{featureflag.WithIdentifier(scope.WithContext(context.Background(), 1, 2), "0815"), false},
+		{featureflag.WithIdentifier(scope.WithContext(context.Background(), 1, 2), "4711"), true},
+		{featureflag.WithIdentifier(scope.WithContext(context.Background(), 1, 3), "4711"), true},
+		{featureflag.WithIdentifier(scope.WithContext(context.Background(), 1, 3), "0815"), false},
+	}
+	for i, test := range tests {
+		ok, err := ffs.IsEnabled(test.ctx, "new_checkout")
+		assert.NoError(t, err, "Index %d", i)
+		assert.Exactly(t, test.want, ok, "Index %d", i)
+	}
+
+	ok, err := ffs.IsEnabled(context.Background(), "not_stored")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = ffs.IsEnabled(context.Background(), "broken")
+	assert.ErrorIsKind(t, errors.BadEncoding, err)
+
+	_, err = ffs.IsEnabled(context.Background(), "new/checkout")
+	assert.ErrorIsKind(t, errors.NotValid, err)
+}
+
+func TestService_Save(t *testing.T) {
+	cfgSrv, ffs := newService(t)
+
+	assert.NoError(t, ffs.Save(cfgSrv, scope.Store.WithID(5), featureflag.Flag{Name: "new_checkout", Enabled: true}))
+	ok, err := ffs.IsEnabled(scope.WithContext(context.Background(), 1, 5), "new_checkout")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	f, err := ffs.Flag(scope.WithContext(context.Background(), 1, 3), "new_checkout")
+	assert.NoError(t, err)
+	assert.Exactly(t, featureflag.Flag{Name: "new_checkout", Enabled: true, Percentage: featureflag.Percent(1), Allow: []string{"4711"}}, f)
+}
+
+func TestNew_InvalidName(t *testing.T) {
+	_, err := featureflag.New(nil, featureflag.Options{Names: []string{"a/b"}})
+	assert.ErrorIsKind(t, errors.NotValid, err)
+}
+
+func TestService_WithFlags(t *testing.T) {
+	_, ffs := newService(t)
+
+	var have featureflag.Evaluated
+	h := ffs.WithFlags(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ok bool
+		have, ok = featureflag.FromContext(r.Context())
+		assert.True(t, ok)
+	}))
+
+	req := httptest.NewRequest("GET", "/checkout", nil)
+	req.Header.Set("X-Customer-ID", "4711")
+	req = req.WithContext(scope.WithContext(req.Context(), 1, 3))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Exactly(t, featureflag.Evaluated{"new_checkout"}, have)
+	assert.True(t, have.IsEnabled("new_checkout"))
+	assert.False(t, have.IsEnabled("broken"))
+
+	req.Header.Set("X-Customer-ID", "0815")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	assert.False(t, have.IsEnabled("new_checkout"))
+}
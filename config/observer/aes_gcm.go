@@ -111,6 +111,14 @@ func MustNewAESGCM(eventType uint8, eo *AESGCMOptions) config.Observer {
 	return enc
 }
 
+// IsAESGCM reports whether the observer has been created by NewAESGCM. Can be
+// passed to config.Service.ObservedRoutes to find the routes whose stored
+// values are encrypted.
+func IsAESGCM(o config.Observer) bool {
+	_, ok := o.(*aesGCM)
+	return ok
+}
+
 func (v *aesGCM) Observe(p config.Path, rawData []byte, found bool) ([]byte, error) {
 	switch v.eventType {
 	case config.EventOnBeforeSet:
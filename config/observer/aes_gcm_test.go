@@ -106,3 +106,14 @@ func TestMustNewAESGCM(t *testing.T) {
 	}()
 	_ = observer.MustNewAESGCM(19, &observer.AESGCMOptions{})
 }
+
+func TestIsAESGCM(t *testing.T) {
+	o := &observer.AESGCMOptions{}
+	cfgSrv := config.MustNewService(nil, config.Options{})
+	assert.NoError(t, cfgSrv.RegisterObserver(config.EventOnAfterGet, "payment/stripe/secret_key", observer.MustNewAESGCM(config.EventOnAfterGet, o)))
+	assert.NoError(t, cfgSrv.RegisterObserver(config.EventOnAfterGet, "carriers/dhl", observer.MustNewAESGCM(config.EventOnAfterGet, o)))
+	assert.NoError(t, cfgSrv.RegisterObserver(config.EventOnAfterGet, "web/secure/base_url", observer.MustNewModifier(observer.ModifierArg{Funcs: []string{"trim"}})))
+
+	assert.Exactly(t, []string{"carriers/dhl", "payment/stripe/secret_key"}, cfgSrv.ObservedRoutes(config.EventOnAfterGet, observer.IsAESGCM))
+	assert.Nil(t, cfgSrv.ObservedRoutes(config.EventOnBeforeSet, observer.IsAESGCM))
+}
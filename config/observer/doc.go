@@ -31,6 +31,12 @@
 //
 // Other encryption algorithms are getting later added.
 //
+// Secret references like secret://file/braintree_key or
+// secret://env/BRAINTREE_KEY get resolved by the observer NewSecret, also
+// available as factory type "secret". The provider "file" must be registered
+// with a base directory, see NewSecretFileProvider. Custom providers can be
+// added via function RegisterSecretProvider.
+//
 // Note: When using sha256 the fully qualified path gets prefixed to the value.
 //
 // To enabled HTTP handler or protobuf you must set build tags on the CLI.
@@ -19,6 +19,7 @@ package observer
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/pkg/config"
@@ -262,6 +263,31 @@ func TestRegisterObservers(t *testing.T) {
 		]}`))
 		assert.True(t, errors.BadEncoding.Match(err), "%+v", err)
 	})
+
+	t.Run("secret OK", func(t *testing.T) {
+		or := observerRegistererFake{
+			t:             t,
+			wantEvent:     config.EventOnAfterGet,
+			wantRoute:     "payment/braintree/private_key",
+			wantValidator: MustNewSecret(config.EventOnAfterGet, SecretOptions{TTL: 5 * time.Minute}),
+		}
+
+		err := RegisterWithJSON(or, bytes.NewBufferString(`{"Collection":[ { "event":"after_get", "route":"payment/braintree/private_key", "type":"secret",
+		  "condition":{"event":"after_get","ttl":"5m"}}
+		]}`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("secret invalid TTL", func(t *testing.T) {
+		or := observerRegistererFake{
+			t: t,
+		}
+
+		err := RegisterWithJSON(or, bytes.NewBufferString(`{"Collection":[ { "event":"after_get", "route":"payment/braintree/private_key", "type":"secret",
+		  "condition":{"event":"after_get","ttl":"5 years"}}
+		]}`))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
 }
 
 func TestValidator_MakeEventRoute(t *testing.T) {
@@ -0,0 +1,298 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observer
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+)
+
+// SecretPrefix marks a configuration value as a reference to a secret. The
+// full format is secret://<provider>/<name>, e.g.
+// secret://file/braintree_key or secret://env/BRAINTREE_KEY.
+const SecretPrefix = "secret://"
+
+// SecretProvider reads the value of a secret by its name. The name is the part
+// after the provider in a secret reference.
+type SecretProvider interface {
+	Secret(name string) ([]byte, error)
+}
+
+// SecretProviderFunc allows to use an ordinary function as a SecretProvider.
+type SecretProviderFunc func(name string) ([]byte, error)
+
+// Secret calls f(name).
+func (f SecretProviderFunc) Secret(name string) ([]byte, error) {
+	return f(name)
+}
+
+type secretReg struct {
+	sync.RWMutex
+	pool  map[string]SecretProvider
+	cache map[string]secretEntry
+}
+
+type secretEntry struct {
+	value   []byte
+	created time.Time
+}
+
+var secretRegistry = &secretReg{
+	pool: map[string]SecretProvider{
+		"env": SecretProviderFunc(secretFromEnv),
+	},
+	cache: map[string]secretEntry{},
+}
+
+type secretFile struct {
+	baseDir string
+}
+
+// NewSecretFileProvider creates a SecretProvider which reads secret files
+// below baseDir, e.g. /run/secrets. The name of a reference is relative to
+// baseDir, hence secret://file/braintree_key reads /run/secrets/braintree_key.
+// Names which escape baseDir, via ".." or via a symlink, get rejected.
+// Trailing line breaks, as written by most secret management tools, get
+// removed. The provider "file" is not installed by default, register it with:
+//
+//	sp, err := observer.NewSecretFileProvider("/run/secrets")
+//	observer.RegisterSecretProvider("file", sp)
+func NewSecretFileProvider(baseDir string) (SecretProvider, error) {
+	if baseDir == "" {
+		return nil, errors.Empty.Newf("[config/observer] Secret file provider requires a base directory")
+	}
+	dir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, errors.NotValid.New(err, "[config/observer] Secret base directory %q", baseDir)
+	}
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		return nil, errors.NotFound.New(err, "[config/observer] Secret base directory %q", baseDir)
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return nil, errors.NotValid.Newf("[config/observer] Secret base directory %q is not a directory", baseDir)
+	}
+	return secretFile{baseDir: dir}, nil
+}
+
+// Secret reads the file name relative to the base directory.
+func (sf secretFile) Secret(name string) ([]byte, error) {
+	fp := filepath.Join(sf.baseDir, name)
+	if !sf.contains(fp) {
+		return nil, errors.NotAllowed.Newf("[config/observer] Secret file %q escapes the base directory", name)
+	}
+	fp, err := filepath.EvalSymlinks(fp)
+	if err != nil {
+		return nil, errors.NotFound.New(err, "[config/observer] Secret file %q", name)
+	}
+	if !sf.contains(fp) {
+		return nil, errors.NotAllowed.Newf("[config/observer] Secret file %q escapes the base directory", name)
+	}
+	data, err := os.ReadFile(fp)
+	if err != nil {
+		return nil, errors.NotFound.New(err, "[config/observer] Secret file %q", name)
+	}
+	return bytes.TrimRight(data, "\r\n"), nil
+}
+
+// contains reports whether the cleaned path fp is located below the base
+// directory.
+func (sf secretFile) contains(fp string) bool {
+	rel, err := filepath.Rel(sf.baseDir, fp)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func secretFromEnv(name string) ([]byte, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, errors.NotFound.Newf("[config/observer] Secret environment variable %q not found", name)
+	}
+	return []byte(v), nil
+}
+
+// RegisterSecretProvider adds a custom SecretProvider to the global registry.
+// The provider name gets used in the secret reference:
+// secret://<provider>/<name>. The provider "env" is installed by default and
+// can be replaced. See NewSecretFileProvider for the provider "file".
+func RegisterSecretProvider(provider string, sp SecretProvider) {
+	secretRegistry.Lock()
+	defer secretRegistry.Unlock()
+	secretRegistry.pool[provider] = sp
+}
+
+func availableSecretProviders() []string {
+	ret := make([]string, 0, len(secretRegistry.pool))
+	for n := range secretRegistry.pool {
+		ret = append(ret, n)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// FlushSecrets removes all cached secrets, hence they get read again from
+// their providers, e.g. after a rotation of the secrets.
+func FlushSecrets() {
+	secretRegistry.Lock()
+	secretRegistry.cache = map[string]secretEntry{}
+	secretRegistry.Unlock()
+}
+
+// WithFlushSecrets flushes all cached secrets each time the config.Service
+// loads its data, which includes the hot reload signal.
+func WithFlushSecrets() config.LoadDataOption {
+	return config.MakeLoadDataOption(func(*config.Service) error {
+		FlushSecrets()
+		return nil
+	})
+}
+
+// parseSecretRef splits a secret reference into the provider and the name.
+func parseSecretRef(ref string) (provider, name string, err error) {
+	if !strings.HasPrefix(ref, SecretPrefix) {
+		return "", "", errors.NotValid.Newf("[config/observer] Secret reference must start with %q", SecretPrefix)
+	}
+	ref = ref[len(SecretPrefix):]
+	pos := strings.IndexByte(ref, '/')
+	if pos < 1 || pos == len(ref)-1 {
+		return "", "", errors.NotValid.Newf("[config/observer] Secret reference %q must have the format secret://<provider>/<name>", SecretPrefix+ref)
+	}
+	provider, name = ref[:pos], ref[pos+1:]
+
+	secretRegistry.RLock()
+	defer secretRegistry.RUnlock()
+	if _, ok := secretRegistry.pool[provider]; !ok {
+		return "", "", errors.NotFound.Newf("[config/observer] Secret provider %q not found in list %v", provider, availableSecretProviders())
+	}
+	return provider, name, nil
+}
+
+// resolveSecret returns the cached secret or reads it from its provider if the
+// cache entry does not exist or is older than ttl. A ttl of zero caches the
+// secret until FlushSecrets gets called.
+func resolveSecret(ref string, ttl time.Duration) ([]byte, error) {
+	now := time.Now()
+	secretRegistry.RLock()
+	se, ok := secretRegistry.cache[ref]
+	secretRegistry.RUnlock()
+	if ok && (ttl == 0 || now.Sub(se.created) < ttl) {
+		return se.value, nil
+	}
+
+	provider, name, err := parseSecretRef(ref)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	secretRegistry.RLock()
+	sp := secretRegistry.pool[provider]
+	secretRegistry.RUnlock()
+
+	v, err := sp.Secret(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[config/observer] Secret provider %q", provider)
+	}
+
+	secretRegistry.Lock()
+	secretRegistry.cache[ref] = secretEntry{value: v, created: now}
+	secretRegistry.Unlock()
+	return v, nil
+}
+
+// SecretOptions applies options to NewSecret.
+type SecretOptions struct {
+	// TTL defines the duration after which a cached secret gets read again
+	// from its provider. Zero caches a secret until FlushSecrets gets called.
+	TTL time.Duration
+}
+
+type secret struct {
+	o         SecretOptions
+	eventType uint8
+}
+
+// NewSecret creates a new observer which resolves secret references. Only two
+// events are supported: config.EventOnBeforeSet rejects all values which are
+// not a valid secret reference, so that secrets never get written to the
+// storage. config.EventOnAfterGet replaces a secret reference with the value
+// of the secret. Values without the SecretPrefix get returned unchanged.
+//
+// NewSecret is also available via RegisterWithJSON with type "secret" and a
+// condition like {"event":"after_get","ttl":"5m"}. The event in the condition
+// must match the event of the observer.
+func NewSecret(eventType uint8, o SecretOptions) (config.Observer, error) {
+	if eventType != config.EventOnBeforeSet && eventType != config.EventOnAfterGet {
+		return nil, errors.NotValid.Newf("[config/observer] Event type can only be: EventOnBeforeSet (reference validation) or EventOnAfterGet (resolving)")
+	}
+	return &secret{o: o, eventType: eventType}, nil
+}
+
+// MustNewSecret same as NewSecret but panics on error.
+func MustNewSecret(eventType uint8, o SecretOptions) config.Observer {
+	s, err := NewSecret(eventType, o)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (s *secret) Observe(p config.Path, rawData []byte, found bool) ([]byte, error) {
+	switch s.eventType {
+	case config.EventOnBeforeSet:
+		if _, _, err := parseSecretRef(string(rawData)); err != nil {
+			return nil, errors.NotAllowed.New(err, "[config/observer] Path %q accepts only secret references", p.String())
+		}
+		return rawData, nil
+	case config.EventOnAfterGet:
+		if !found || !bytes.HasPrefix(rawData, []byte(SecretPrefix)) {
+			return rawData, nil
+		}
+		v, err := resolveSecret(string(rawData), s.o.TTL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[config/observer] For Path %q", p.String())
+		}
+		return append([]byte(nil), v...), nil
+	}
+	return nil, errors.Fatal.Newf("[config/observer] A programmer made an error")
+}
+
+func init() {
+	RegisterFactory("secret", func(rawJSON []byte) (config.Observer, error) {
+		var sa struct {
+			Event string `json:"event"`
+			TTL   string `json:"ttl"`
+		}
+		if err := json.Unmarshal(rawJSON, &sa); err != nil {
+			return nil, errors.BadEncoding.New(err, "[config/observer] Failed to decode: %q", string(rawJSON))
+		}
+		event, err := config.MakeEvent(sa.Event)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		var o SecretOptions
+		if sa.TTL != "" {
+			if o.TTL, err = time.ParseDuration(sa.TTL); err != nil {
+				return nil, errors.NotValid.New(err, "[config/observer] Failed to parse TTL %q", sa.TTL)
+			}
+		}
+		return NewSecret(event, o)
+	})
+}
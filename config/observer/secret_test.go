@@ -0,0 +1,155 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observer_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/observer"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestNewSecret(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "braintree_key")
+	assert.NoError(t, os.WriteFile(keyFile, []byte("s3cr3t\n"), 0o600))
+	sp, err := observer.NewSecretFileProvider(dir)
+	assert.NoError(t, err)
+	observer.RegisterSecretProvider("file", sp)
+	t.Setenv("CS_TEST_BRAINTREE_MERCHANT", "merchant-4711")
+
+	pKey := config.MustMakePath("payment/braintree/private_key")
+	pMerchant := config.MustMakePath("payment/braintree/merchant_id").BindWebsite(2)
+	pPlain := config.MustMakePath("payment/braintree/title")
+
+	newService := func(o observer.SecretOptions) *config.Service {
+		cfgSrv := config.MustNewService(storage.NewMap(
+			pKey.String(), "secret://file/braintree_key",
+			pMerchant.String(), "secret://env/CS_TEST_BRAINTREE_MERCHANT",
+			pPlain.String(), "Braintree",
+		), config.Options{})
+		assert.NoError(t, cfgSrv.RegisterObserver(config.EventOnAfterGet, "payment/braintree", observer.MustNewSecret(config.EventOnAfterGet, o)))
+		assert.NoError(t, cfgSrv.RegisterObserver(config.EventOnBeforeSet, "payment/braintree/private_key", observer.MustNewSecret(config.EventOnBeforeSet, o)))
+		return cfgSrv
+	}
+
+	t.Run("resolve file and env", func(t *testing.T) {
+		observer.FlushSecrets()
+		cfgSrv := newService(observer.SecretOptions{})
+		assert.Exactly(t, `"s3cr3t"`, cfgSrv.Get(pKey).String())
+		assert.Exactly(t, `"merchant-4711"`, cfgSrv.Get(pMerchant).String())
+		assert.Exactly(t, `"Braintree"`, cfgSrv.Get(pPlain).String())
+	})
+
+	t.Run("before set accepts only references", func(t *testing.T) {
+		cfgSrv := newService(observer.SecretOptions{})
+		err := cfgSrv.Set(pKey, []byte("plain text key"))
+		assert.ErrorIsKind(t, errors.NotAllowed, err)
+		err = cfgSrv.Set(pKey, []byte("secret://vault/braintree"))
+		assert.ErrorIsKind(t, errors.NotAllowed, err)
+		assert.NoError(t, cfgSrv.Set(pKey, []byte("secret://env/CS_TEST_BRAINTREE_MERCHANT")))
+	})
+
+	t.Run("rotation via flush and TTL", func(t *testing.T) {
+		observer.FlushSecrets()
+		cfgSrv := newService(observer.SecretOptions{})
+		assert.Exactly(t, `"s3cr3t"`, cfgSrv.Get(pKey).String())
+
+		assert.NoError(t, os.WriteFile(keyFile, []byte("rotated"), 0o600))
+		assert.Exactly(t, `"s3cr3t"`, cfgSrv.Get(pKey).String(), "cached")
+		observer.FlushSecrets()
+		assert.Exactly(t, `"rotated"`, cfgSrv.Get(pKey).String())
+
+		cfgSrv = newService(observer.SecretOptions{TTL: time.Nanosecond})
+		assert.NoError(t, os.WriteFile(keyFile, []byte("rotated again"), 0o600))
+		time.Sleep(time.Millisecond)
+		assert.Exactly(t, `"rotated again"`, cfgSrv.Get(pKey).String())
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		observer.FlushSecrets()
+		cfgSrv := newService(observer.SecretOptions{})
+		assert.NoError(t, os.Remove(keyFile))
+		_, _, err := cfgSrv.Get(pKey).Str()
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+
+	t.Run("invalid event", func(t *testing.T) {
+		_, err := observer.NewSecret(config.EventOnAfterSet, observer.SecretOptions{})
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+}
+
+func TestNewSecretFileProvider(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "secrets")
+	assert.NoError(t, os.Mkdir(dir, 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "api_key"), []byte("k3y\n"), 0o600))
+	outside := filepath.Join(root, "shadow")
+	assert.NoError(t, os.WriteFile(outside, []byte("root:x"), 0o600))
+	assert.NoError(t, os.Symlink(outside, filepath.Join(dir, "link")))
+
+	t.Run("empty base directory", func(t *testing.T) {
+		sp, err := observer.NewSecretFileProvider("")
+		assert.ErrorIsKind(t, errors.Empty, err)
+		assert.Nil(t, sp)
+	})
+	t.Run("base directory is a file", func(t *testing.T) {
+		_, err := observer.NewSecretFileProvider(outside)
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+
+	sp, err := observer.NewSecretFileProvider(dir)
+	assert.NoError(t, err)
+
+	t.Run("read", func(t *testing.T) {
+		v, err := sp.Secret("api_key")
+		assert.NoError(t, err)
+		assert.Exactly(t, "k3y", string(v))
+	})
+	t.Run("escape", func(t *testing.T) {
+		for _, name := range []string{"../shadow", "a/../../shadow", "link", ".", ".."} {
+			v, err := sp.Secret(name)
+			assert.ErrorIsKind(t, errors.NotAllowed, err, "Name %q", name)
+			assert.Nil(t, v, "Name %q", name)
+		}
+	})
+	t.Run("missing", func(t *testing.T) {
+		_, err := sp.Secret("missing")
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+}
+
+func TestRegisterSecretProvider(t *testing.T) {
+	observer.RegisterSecretProvider("vault", observer.SecretProviderFunc(func(name string) ([]byte, error) {
+		return []byte("vault:" + name), nil
+	}))
+	defer observer.RegisterSecretProvider("vault", observer.SecretProviderFunc(func(name string) ([]byte, error) {
+		return nil, errors.NotImplemented.Newf("vault removed")
+	}))
+
+	p := config.MustMakePath("payment/stripe/secret_key")
+	cfgSrv := config.MustNewService(storage.NewMap(p.String(), "secret://vault/stripe/live"), config.Options{},
+		observer.WithFlushSecrets(),
+	)
+	assert.NoError(t, cfgSrv.RegisterObserver(config.EventOnAfterGet, "payment/stripe/secret_key", observer.MustNewSecret(config.EventOnAfterGet, observer.SecretOptions{})))
+	assert.Exactly(t, `"vault:stripe/live"`, cfgSrv.Get(p).String())
+}
@@ -16,6 +16,7 @@ package config
 
 import (
 	"os"
+	"time"
 	"unicode"
 
 	"github.com/corestoreio/errors"
@@ -26,7 +27,8 @@ import (
 
 // EnvNamePlaceHolder replaces in a file name or pattern argument applied to a
 // WithFiles, WithFile or WithGlob function with the current environment name of
-// *config.Service. You can load environment dependent configuration files.
+// *config.Service. You can load environment dependent configuration files. If
+// Options.EnableInterpolation has been set, it gets also replaced in values.
 const EnvNamePlaceHolder = `{CS_ENV}`
 
 // DefaultOSEnvVariableName default name of the OS environment variable.
@@ -42,6 +44,13 @@ type Options struct {
 	Level1       Storager
 	Log          log.Logger
 	EnablePubSub bool
+	// Broadcaster if set, announces the changed paths to the other processes
+	// of a cluster and receives their changes to evict the Level1 cache and
+	// to notify the subscribers. See interface Broadcaster.
+	Broadcaster Broadcaster
+	// BroadcastMaxBackoff caps the wait time before the Broadcaster listens
+	// again after a failure, e.g. a lost connection. Default 30s.
+	BroadcastMaxBackoff time.Duration
 	// OSEnvVariableName loads a string from an applied environment variable to
 	// use it as a prefix for the Path type and when loading configuration files
 	// as part of their filename or path (see cfgfile.EnvNamePlaceHolder). For
@@ -54,6 +63,22 @@ type Options struct {
 	// loading configuration files (see cfgfile.EnvNamePlaceHolder).
 	EnvName string
 
+	// StrictSections enables the strict mode. Each value written via Set,
+	// including all values of the LoadDataOptions, gets validated: its route
+	// must exist in StrictSections, its scope must be allowed by Field.Scopes
+	// (falls back to Group.Scopes, Section.Scopes and scope.PermDefault) and
+	// the value must match the FieldType. Invalid values and values rejected by
+	// observer validators which the LoadDataOptions write get skipped and
+	// NewService or the hot reload returns one error with all of them. All
+	// other calls to Set return their error immediately.
+	StrictSections Sections
+	// EnableInterpolation expands placeholders in values read via Scoped.Get.
+	// The placeholder {{config "web/secure/base_url"}} inserts the value of
+	// another route, resolved through the same scope hierarchy.
+	// EnvNamePlaceHolder inserts the name of the environment. Expanded values
+	// get cached and invalidated via pubsub, hence EnablePubSub must be set.
+	EnableInterpolation bool
+
 	// EnableHotReload if the Service receives an OS signal, it triggers a hot
 	// reload of the cached functions of type LoadDataOption. Errors during hot
 	// reloading do not trigger an exit of the config.Service.
@@ -200,6 +225,44 @@ func WithFieldMeta(fms ...*FieldMeta) LoadDataOption {
 	}
 }
 
+// WithSharedSections same as WithApplySections but converts the sections only
+// once into the default values and permissible scopes. The returned option can
+// be applied to many Services, e.g. one per tenant, which all read the same
+// data instead of holding a copy. Default values of the Service itself, e.g.
+// via WithApplySections, take precedence.
+func WithSharedSections(sections ...*Section) (LoadDataOption, error) {
+	secs := Sections(sections)
+	if err := secs.Validate(); err != nil {
+		return LoadDataOption{}, errors.WithStack(err)
+	}
+
+	shared := newTrieRoute()
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+	for _, sec := range secs {
+		for _, g := range sec.Groups {
+			for _, f := range g.Fields {
+				joinParts(buf, sec.ID, g.ID, f.ID)
+				shared.PutMeta(buf.String(), &FieldMeta{
+					WriteScopePerm: f.Scopes,
+					Default:        f.Default,
+					DefaultValid:   f.Default != "",
+				})
+				buf.Reset()
+			}
+		}
+	}
+
+	return LoadDataOption{
+		load: func(s *Service) error {
+			s.mu.Lock()
+			s.routeConfig.shared = shared
+			s.mu.Unlock()
+			return nil
+		},
+	}, nil
+}
+
 // WithApplySections sets the default values and permissible scopes for specific
 // routes. This function option cannot handle a default value for a specific
 // website/store scope. Storage level and sort order are not supported. Because
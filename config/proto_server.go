@@ -0,0 +1,227 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build csall || proto
+// +build csall proto
+
+package config
+
+import (
+	"context"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/gogo/protobuf/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProtoServerOptions applies optional settings to the gRPC server.
+type ProtoServerOptions struct {
+	// WatchBufferSize defines the amount of changed paths a Watch stream can
+	// queue. If a client can't keep up, the stream gets terminated with code
+	// ResourceExhausted. Default 64.
+	WatchBufferSize int
+	// AuthFunc authenticates each request with the full gRPC method name. The
+	// signature matches auth.ServiceAuthFunc of package net/csgrpc/auth. Get
+	// and GetBatch return decrypted values and resolved secrets, hence all
+	// requests get rejected with code Unauthenticated if AuthFunc is nil and
+	// InsecureSkipAuth is false.
+	AuthFunc func(ctx context.Context, fullMethodName string) (context.Context, error)
+	// InsecureSkipAuth disables the authentication, e.g. when an interceptor
+	// already authenticates or the server listens only on a local socket.
+	InsecureSkipAuth bool
+}
+
+type protoServer struct {
+	s *Service
+	o ProtoServerOptions
+}
+
+// NewProtoServiceServer creates a new gRPC server which exposes the
+// configuration Service. Get and GetBatch take the scope of a fully qualified
+// path into account, e.g. `stores/2/general/locale/timezone`. Watch requires
+// an enabled PubSub, see Options.EnablePubSub. The server requires
+// ProtoServerOptions.AuthFunc or an explicit
+// ProtoServerOptions.InsecureSkipAuth.
+func NewProtoServiceServer(s *Service, o ProtoServerOptions) ProtoServiceServer {
+	if o.WatchBufferSize < 1 {
+		o.WatchBufferSize = 64
+	}
+	return &protoServer{s: s, o: o}
+}
+
+func (ps *protoServer) authenticate(ctx context.Context, fullMethodName string) error {
+	if ps.o.InsecureSkipAuth {
+		return nil
+	}
+	if ps.o.AuthFunc == nil {
+		return status.Error(codes.Unauthenticated, "[config] ProtoServerOptions.AuthFunc not set")
+	}
+	if _, err := ps.o.AuthFunc(ctx, fullMethodName); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return nil
+}
+
+// methodName returns the full gRPC method name of a unary call.
+func methodName(ctx context.Context) string {
+	m, _ := grpc.Method(ctx)
+	return m
+}
+
+// statusError converts err into a gRPC status error. Internal errors get
+// logged and the client receives a generic message because the error might
+// contain details of the backend storage.
+func (ps *protoServer) statusError(err error) error {
+	switch {
+	case errors.NotValid.Match(err), errors.Empty.Match(err), errors.NotSupported.Match(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.NotImplemented.Match(err):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.NotFound.Match(err):
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if ps.s.Log != nil && ps.s.Log.IsInfo() {
+		ps.s.Log.Info("config.protoServer.Internal", log.Err(err))
+	}
+	return status.Error(codes.Internal, "[config] internal error")
+}
+
+func (ps *protoServer) Set(ctx context.Context, v *ProtoValue) (*types.Empty, error) {
+	if err := ps.authenticate(ctx, methodName(ctx)); err != nil {
+		return nil, err
+	}
+	var p Path
+	if err := p.Parse(v.Path); err != nil {
+		return nil, ps.statusError(err)
+	}
+	if err := ps.s.Set(p, v.Data); err != nil {
+		return nil, ps.statusError(err)
+	}
+	return &types.Empty{}, nil
+}
+
+func (ps *protoServer) get(fqPath string) (*ProtoValue, error) {
+	var p Path
+	if err := p.Parse(fqPath); err != nil {
+		return nil, ps.statusError(err)
+	}
+	v := ps.s.Get(p)
+	if v.lastErr != nil {
+		return nil, ps.statusError(v.lastErr)
+	}
+	return &ProtoValue{
+		Path:  fqPath,
+		Data:  v.data,
+		Found: v.found > valFoundNo,
+	}, nil
+}
+
+func (ps *protoServer) Get(ctx context.Context, p *ProtoPath) (*ProtoValue, error) {
+	if err := ps.authenticate(ctx, methodName(ctx)); err != nil {
+		return nil, err
+	}
+	return ps.get(p.Path)
+}
+
+func (ps *protoServer) GetBatch(ctx context.Context, p *ProtoPaths) (*ProtoValues, error) {
+	if err := ps.authenticate(ctx, methodName(ctx)); err != nil {
+		return nil, err
+	}
+	vs := &ProtoValues{
+		Values: make([]*ProtoValue, 0, len(p.Paths)),
+	}
+	for _, fq := range p.Paths {
+		v, err := ps.get(fq)
+		if err != nil {
+			return nil, err
+		}
+		vs.Values = append(vs.Values, v)
+	}
+	return vs, nil
+}
+
+// protoWatcher forwards the published paths into the Watch stream. It must
+// not block the publishing goroutine of the Service.
+type protoWatcher struct {
+	ctx     context.Context
+	changed chan Path
+	full    chan struct{}
+}
+
+func (pw *protoWatcher) MessageConfig(p Path) error {
+	select {
+	case <-pw.ctx.Done():
+		return pw.ctx.Err() // unsubscribes the watcher
+	case pw.changed <- p:
+		return nil
+	default:
+		select {
+		case pw.full <- struct{}{}:
+		default:
+		}
+		return errors.Exceeded.Newf("[config] Watch buffer full, dropping subscriber for path %q", p.String())
+	}
+}
+
+func (ps *protoServer) Watch(p *ProtoPaths, stream ProtoService_WatchServer) error {
+	fullMethodName, _ := grpc.MethodFromServerStream(stream)
+	if err := ps.authenticate(stream.Context(), fullMethodName); err != nil {
+		return err
+	}
+	if len(p.Paths) == 0 {
+		return status.Error(codes.InvalidArgument, "[config] Watch requires at least one path")
+	}
+	pw := &protoWatcher{
+		ctx:     stream.Context(),
+		changed: make(chan Path, ps.o.WatchBufferSize),
+		full:    make(chan struct{}, 1),
+	}
+
+	ids := make([]int, 0, len(p.Paths))
+	defer func() {
+		for _, id := range ids {
+			_ = ps.s.Unsubscribe(id)
+		}
+	}()
+	for _, path := range p.Paths {
+		id, err := ps.s.Subscribe(path, pw)
+		if err != nil {
+			return ps.statusError(err)
+		}
+		ids = append(ids, id)
+	}
+
+	for {
+		select {
+		case <-pw.ctx.Done():
+			return nil
+		case <-pw.full:
+			return status.Error(codes.ResourceExhausted, "[config] Watch client too slow, buffer full")
+		case cp := <-pw.changed:
+			fq, err := cp.FQ()
+			if err != nil {
+				return ps.statusError(err)
+			}
+			if err := stream.Send(&ProtoPath{Path: fq}); err != nil {
+				return err
+			}
+		}
+	}
+}
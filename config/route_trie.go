@@ -125,6 +125,10 @@ func segmentRoute(path string, start int) (segment string, next int) {
 type trieRoute struct {
 	fm       FieldMeta
 	children map[string]*trieRoute
+	// shared gets only set on the root node. It points to a read-only trie
+	// with default values and scope permissions used by many Services, see
+	// WithSharedSections.
+	shared *trieRoute
 }
 
 // newTrieRoute allocates and returns a new *trieRoute.
@@ -183,12 +187,28 @@ func (trie *trieRoute) Get(key string) FieldMeta {
 }
 
 // process runs on each tree level and dispatches the events and checks for
-// scope permission and default value.
+// scope permission and default value. The shared trie gets checked for the
+// scope permission before the own trie and provides the default value only if
+// the own trie has none.
 func (trie *trieRoute) process(key string, event uint8, p Path, v []byte, found bool) (v2 []byte, found2 bool, err error) {
 	if trie == nil {
 		return v, found, nil
 	}
+	if trie.shared != nil && event == EventOnBeforeSet {
+		if _, _, err = trie.shared.processRoute(key, event, p, v, found); err != nil {
+			return nil, false, err
+		}
+	}
+	if v, found, err = trie.processRoute(key, event, p, v, found); err != nil {
+		return nil, false, err
+	}
+	if trie.shared != nil && event == EventOnAfterGet && !found {
+		return trie.shared.processRoute(key, event, p, v, found)
+	}
+	return v, found, nil
+}
 
+func (trie *trieRoute) processRoute(key string, event uint8, p Path, v []byte, found bool) (v2 []byte, found2 bool, err error) {
 	node := trie
 	for part, i := segmentRoute(key, 0); ; part, i = segmentRoute(key, i) {
 		node = node.children[part]
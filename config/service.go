@@ -21,6 +21,7 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/log"
@@ -31,9 +32,11 @@ import (
 // its store ID. Scoper gets used in other packages to attach the config.Service
 // type or mocked version of it.
 type Scoper interface {
-	Scoped(websiteID, storeID int64) Scoped
+	Scoped(websiteID, storeID uint32) Scoped
 }
 
+var _ Scoper = (*Service)(nil)
+
 // Setter thread safe storing of configuration values under different paths and
 // scopes.
 type Setter interface {
@@ -85,8 +88,22 @@ type Service struct {
 	loadDataFns     loadDataOptions
 	envReplacer     *strings.Replacer
 
-	// more events can be added once needed.
-	mu sync.RWMutex
+	// broadcastStop gets closed in Close to terminate the reconnect backoff.
+	broadcastStop chan struct{}
+	// broadcastDone gets closed when the Broadcaster stops listening.
+	broadcastDone chan struct{}
+	// interpolator gets set when Options.EnableInterpolation is true.
+	interpolator *interpolator
+	// schema gets set when Options.StrictSections is not empty.
+	schema *schema
+	// loadReport gets only set in the copy of the Service which loadData
+	// passes to the LoadDataOptions in strict mode.
+	loadReport *schemaReport
+
+	// more events can be added once needed. mu is a pointer because loadData
+	// passes copies of the Service to the LoadDataOptions which must share the
+	// lock.
+	mu *sync.RWMutex
 	// routeConfig contains essential information about a route like scope for
 	// permission, default value or events.
 	routeConfig *trieRoute
@@ -101,6 +118,7 @@ func NewService(level2 Storager, o Options, fns ...LoadDataOption) (s *Service,
 		level2:      level2,
 		config:      o,
 		Log:         o.Log,
+		mu:          &sync.RWMutex{},
 		routeConfig: newTrieRoute(),
 	}
 
@@ -108,6 +126,12 @@ func NewService(level2 Storager, o Options, fns ...LoadDataOption) (s *Service,
 		return nil, errors.WithStack(err)
 	}
 
+	if len(o.StrictSections) > 0 {
+		if s.schema, err = newSchema(o.StrictSections); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
 	if o.EnablePubSub {
 		var l log.Logger
 		if o.Log != nil {
@@ -117,6 +141,15 @@ func NewService(level2 Storager, o Options, fns ...LoadDataOption) (s *Service,
 		go s.pubSub.publish() // yes we know how to quit this goroutine, just call Service.Close()
 	}
 
+	if o.EnableInterpolation {
+		if err := s.setupInterpolation(); err != nil {
+			if err2 := s.Close(); err2 != nil {
+				return nil, errors.WithStack(err2)
+			}
+			return nil, errors.WithStack(err)
+		}
+	}
+
 	s.loadDataFns = append(s.loadDataFns, fns...) // make a copy of fns slice
 	sort.Stable(s.loadDataFns)
 	if err := s.loadData(); err != nil {
@@ -131,6 +164,15 @@ func NewService(level2 Storager, o Options, fns ...LoadDataOption) (s *Service,
 		return nil, errors.WithStack(err)
 	}
 
+	if o.Broadcaster != nil {
+		if s.config.BroadcastMaxBackoff == 0 {
+			s.config.BroadcastMaxBackoff = 30 * time.Second
+		}
+		s.broadcastStop = make(chan struct{})
+		s.broadcastDone = make(chan struct{})
+		go s.listenBroadcast()
+	}
+
 	return s, nil
 }
 
@@ -196,22 +238,29 @@ func (s *Service) shouldEnableHotReload() error {
 }
 
 // loadData used for hot reloading and runs also within another goroutine but
-// reads only from *Service.
+// reads only from *Service. In strict mode all invalid values get skipped and
+// returned as one error after all LoadDataOptions have been applied.
 func (s *Service) loadData() error {
+	var report *schemaReport
+	if s.schema != nil {
+		report = newSchemaReport()
+	}
 	for _, opt := range s.loadDataFns {
 		s2 := s
-		if opt.level == 1 && s2.config.Level1 != nil {
+		if report != nil || (opt.level == 1 && s.config.Level1 != nil) {
 			s2 = new(Service)
-			// might be OK: assignment copies lock value to *s2: config.Service
-			// contains sync.RWMutex (vet)
-			*s2 = *s // might be racy. need to check that.
+			*s2 = *s
+			s2.loadReport = report
+		}
+		if opt.level == 1 && s2.config.Level1 != nil {
 			s2.level2 = s2.config.Level1
 		}
 		if err := opt.load(s2); err != nil {
+			_ = report.close()
 			return errors.WithStack(err)
 		}
 	}
-	return nil
+	return errors.WithStack(report.close())
 }
 
 // EnvName returns the environment name to which this service is bound to.
@@ -236,6 +285,18 @@ func (s *Service) Close() error {
 		close(s.hotReloadSignal)
 	}
 
+	if s.config.Broadcaster != nil && s.broadcastDone != nil {
+		select {
+		case <-s.broadcastStop:
+		default:
+			close(s.broadcastStop)
+		}
+		if err := s.config.Broadcaster.Close(); err != nil {
+			return errors.WithStack(err)
+		}
+		<-s.broadcastDone
+	}
+
 	if s.config.EnablePubSub {
 		if err := s.pubSub.Close(); err != nil {
 			return errors.WithStack(err)
@@ -244,6 +305,10 @@ func (s *Service) Close() error {
 	return nil
 }
 
+type flusher interface {
+	Flush() error
+}
+
 // Flush flushes the internal caches. Write operation also flushes the entry for
 // the given key. If a Storage service implements
 //		type flusher interface {
@@ -251,9 +316,6 @@ func (s *Service) Close() error {
 //		}
 // then it can flush its internal caches.
 func (s *Service) Flush() error {
-	type flusher interface {
-		Flush() error
-	}
 	if s.config.Level1 != nil {
 		if f, ok := s.config.Level1.(flusher); ok {
 			if err := f.Flush(); err != nil {
@@ -266,6 +328,9 @@ func (s *Service) Flush() error {
 			return errors.WithStack(err)
 		}
 	}
+	if s.interpolator != nil {
+		s.interpolator.flush()
+	}
 	return nil
 }
 
@@ -290,6 +355,30 @@ func (s *Service) RegisterObserver(event uint8, route string, eo Observer) error
 	return nil
 }
 
+// ObservedRoutes returns the sorted routes or route prefixes which have at
+// least one observer registered for the event for which function match returns
+// true. For example observer.IsAESGCM finds all routes with encrypted values.
+func (s *Service) ObservedRoutes(event uint8, match func(Observer) bool) []string {
+	if event >= eventMaxCount {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var routes []string
+	_ = s.routeConfig.Walk(func(key string, fm FieldMeta) error {
+		for _, o := range fm.Events[event] {
+			if match(o) {
+				routes = append(routes, strings.TrimPrefix(key, sPathSeparator))
+				return nil
+			}
+		}
+		return nil
+	})
+	sort.Strings(routes)
+	return routes
+}
+
 // DeregisterObserver removes all observers for a specific route or route
 // prefix. Event argument is one of the constants starting with `EventOn...`.
 func (s *Service) DeregisterObserver(event uint8, route string) error {
@@ -330,10 +419,12 @@ func (s *Service) Set(p Path, v []byte) (err error) { // TODO v should be an imm
 	}
 
 	s.mu.RLock()
-	key := p.separatorSuffixRoute() // this can be optimized to move it into the process signature
-	key = buildTrieKey(key, p.ScopeID)
-	if v, _, err = s.routeConfig.process(key, EventOnBeforeSet, p, v, true); err != nil {
+	var key string
+	if key, v, err = s.beforeSet(p, v); err != nil {
 		s.mu.RUnlock()
+		if s.loadReport.add(p, err) {
+			return nil // reported by loadData
+		}
 		return errors.WithStack(err)
 	}
 	defer func() {
@@ -350,10 +441,113 @@ func (s *Service) Set(p Path, v []byte) (err error) { // TODO v should be an imm
 	if s.pubSub != nil {
 		s.pubSub.sendMsg(p)
 	}
+	if s.config.Broadcaster != nil {
+		if err := s.config.Broadcaster.Broadcast(p); err != nil {
+			return errors.Wrap(err, "[config] Service.Broadcaster.Broadcast")
+		}
+	}
 
 	return
 }
 
+// beforeSet checks the strict sections and runs the EventOnBeforeSet observers
+// and scope permissions. It returns the trie key and the possibly modified
+// value. The caller must hold the read lock.
+func (s *Service) beforeSet(p Path, v []byte) (key string, _ []byte, err error) {
+	if s.schema != nil {
+		if err = s.schema.validate(p, v); err != nil {
+			return "", nil, err
+		}
+	}
+	key = p.separatorSuffixRoute() // this can be optimized to move it into the process signature
+	key = buildTrieKey(key, p.ScopeID)
+	if v, _, err = s.routeConfig.process(key, EventOnBeforeSet, p, v, true); err != nil {
+		return "", nil, err
+	}
+	return key, v, nil
+}
+
+// Validate runs the same checks as Set, including the EventOnBeforeSet
+// observers, without writing the value.
+func (s *Service) Validate(p Path, v []byte) error {
+	if p.UseEnvSuffix && p.envSuffix != s.envName {
+		p.envSuffix = s.envName
+	}
+	if err := p.IsValid(); err != nil {
+		return errors.WithStack(err)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, _, err := s.beforeSet(p, v)
+	return errors.WithStack(err)
+}
+
+// BatchSetter gets optionally implemented by a level 2 Storager to write
+// several values at once, e.g. within one database transaction.
+type BatchSetter interface {
+	SetBatch(ps []Path, vs [][]byte) error
+}
+
+// SetBatch validates all values like Set and writes them only if all values
+// are valid. If the level 2 Storager implements BatchSetter, the values get
+// written with one call, otherwise one after another where a storage error can
+// leave the values partially written. Argument vs must have the same length as
+// ps.
+func (s *Service) SetBatch(ps []Path, vs [][]byte) (err error) {
+	if len(ps) != len(vs) {
+		return errors.NotValid.Newf("[config] Service.SetBatch requires the same number of paths (%d) and values (%d)", len(ps), len(vs))
+	}
+	if s.config.Log != nil && s.config.Log.IsDebug() {
+		defer log.WhenDone(s.config.Log).Debug("config.Service.SetBatch", log.Int("paths", len(ps)), log.Err(err))
+	}
+	ps = append([]Path(nil), ps...)
+	vs = append([][]byte(nil), vs...)
+	keys := make([]string, len(ps))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for i := range ps {
+		if ps[i].UseEnvSuffix && ps[i].envSuffix != s.envName {
+			ps[i].envSuffix = s.envName
+		}
+		if err := ps[i].IsValid(); err != nil {
+			return errors.WithStack(err)
+		}
+		if keys[i], vs[i], err = s.beforeSet(ps[i], vs[i]); err != nil {
+			return errors.Wrapf(err, "[config] Service.SetBatch with path %q", ps[i].String())
+		}
+	}
+
+	if bs, ok := s.level2.(BatchSetter); ok {
+		err = bs.SetBatch(ps, vs)
+	} else {
+		for i := range ps {
+			if err = s.level2.Set(ps[i], vs[i]); err != nil {
+				break
+			}
+		}
+	}
+	for i := range ps {
+		if _, _, err2 := s.routeConfig.process(keys[i], EventOnAfterSet, ps[i], vs[i], err == nil); err == nil && err2 != nil {
+			err = errors.WithStack(err2)
+		}
+	}
+	if err != nil {
+		return errors.Wrap(err, "[config] Service.SetBatch.level2")
+	}
+	for _, p := range ps {
+		if s.pubSub != nil {
+			s.pubSub.sendMsg(p)
+		}
+		if s.config.Broadcaster != nil {
+			if err := s.config.Broadcaster.Broadcast(p); err != nil {
+				return errors.Wrap(err, "[config] Service.Broadcaster.Broadcast")
+			}
+		}
+	}
+	return nil
+}
+
 // Get returns a configuration value from the Service, ignoring the scope
 // hierarchy/fallback logic using a direct match. Safe for concurrent use.
 // Example usage:
@@ -569,9 +763,18 @@ func (ss Scoped) isAllowedWebsite(restrictUpTo scope.Type) bool {
 // bubbling. For example a path gets stored in all three scopes but argument
 // `restrictUpTo` specifies only website scope, then the store scope will be
 // ignored for querying. If argument `restrictUpTo` has been set to zero aka.
-// scope.Absent, then all three scopes are considered for querying.
-// Returns a guaranteed non-nil Value.
+// scope.Absent, then all three scopes are considered for querying. If
+// Options.EnableInterpolation has been set, the placeholders of the value get
+// expanded. Returns a guaranteed non-nil Value.
 func (ss Scoped) Get(restrictUpTo scope.Type, route string) (v *Value) {
+	v = ss.get(restrictUpTo, route)
+	if s, ok := ss.rootSrv.(*Service); ok && s.interpolator != nil {
+		v = s.interpolator.interpolate(ss, route, v)
+	}
+	return v
+}
+
+func (ss Scoped) get(restrictUpTo scope.Type, route string) (v *Value) {
 	// fallback to next parent scope if value does not exists
 	p := Path{
 		route: Route(route),
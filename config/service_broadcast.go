@@ -0,0 +1,123 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+)
+
+// Broadcaster transports the paths of changed values between all processes
+// of a cluster which share the same level 2 storage. A Set on one process
+// evicts the level 1 caches and notifies the MessageReceivers of all other
+// processes. Implementations can be found in package config/storage, e.g.
+// Redis pub/sub or MySQL polling.
+type Broadcaster interface {
+	// Broadcast announces the path of a locally changed value.
+	Broadcast(p Path) error
+	// Listen blocks and calls fn for each path changed by another process
+	// until Close gets called. Listen returns nil after Close or the first
+	// error of fn. The paths of the own process should be skipped, if the
+	// transport allows it. When Listen returns a non-nil error, e.g. a broken
+	// connection, the Service calls Listen again after a backoff.
+	Listen(fn func(Path) error) error
+	// Close terminates Listen.
+	Close() error
+}
+
+// Deleter gets optionally implemented by a level 1 Storager to remove a cached
+// value. If the level 1 Storager does not implement it, the value gets
+// reloaded from level 2.
+type Deleter interface {
+	Delete(p Path) error
+}
+
+// listenBroadcast calls Broadcaster.Listen until it returns nil. Errors get
+// logged and Listen gets called again after an exponential backoff, starting at
+// 100ms and capped at Options.BroadcastMaxBackoff. Paths changed while the
+// transport has been down, are not received.
+func (s *Service) listenBroadcast() {
+	defer close(s.broadcastDone)
+	const minBackoff = 100 * time.Millisecond
+	backoff := minBackoff
+	for {
+		started := time.Now()
+		err := s.config.Broadcaster.Listen(func(p Path) error {
+			// a failing path must not stop the invalidation of other paths
+			if err := s.receiveBroadcast(p); err != nil && s.config.Log != nil && s.config.Log.IsInfo() {
+				s.config.Log.Info("config.Service.receiveBroadcast", log.Stringer("path", p), log.Err(err))
+			}
+			return nil
+		})
+		if err == nil {
+			return
+		}
+		if time.Since(started) > s.config.BroadcastMaxBackoff {
+			backoff = minBackoff // the connection has been healthy for a while
+		}
+		if s.config.Log != nil && s.config.Log.IsInfo() {
+			s.config.Log.Info("config.Service.Broadcaster.Listen", log.Err(err), log.Duration("backoff", backoff))
+		}
+		select {
+		case <-s.broadcastStop:
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > s.config.BroadcastMaxBackoff {
+			backoff = s.config.BroadcastMaxBackoff
+		}
+	}
+}
+
+// receiveBroadcast evicts the level 1 cache and notifies the subscribers for a
+// path which has been changed by another process. A level 1 cache without
+// Deleter gets the new value or gets flushed if the path has been removed.
+func (s *Service) receiveBroadcast(p Path) error {
+	if s.config.Log != nil && s.config.Log.IsDebug() {
+		s.config.Log.Debug("config.Service.receiveBroadcast", log.Stringer("path", p))
+	}
+	if l1 := s.config.Level1; l1 != nil {
+		if d, ok := l1.(Deleter); ok {
+			if err := d.Delete(p); err != nil {
+				return errors.Wrapf(err, "[config] Service.receiveBroadcast.Level1.Delete with path %q", p)
+			}
+		} else {
+			v, ok, err := s.level2.Get(p)
+			if err != nil {
+				return errors.Wrapf(err, "[config] Service.receiveBroadcast.level2.Get with path %q", p)
+			}
+			switch f, canFlush := l1.(flusher); {
+			case ok:
+				if err := l1.Set(p, v); err != nil {
+					return errors.Wrapf(err, "[config] Service.receiveBroadcast.Level1.Set with path %q", p)
+				}
+			case canFlush:
+				// the path has been removed, hence the stale value can only
+				// be evicted together with all other values.
+				if err := f.Flush(); err != nil {
+					return errors.Wrapf(err, "[config] Service.receiveBroadcast.Level1.Flush with path %q", p)
+				}
+			default:
+				return errors.NotImplemented.Newf("[config] Service.receiveBroadcast Level1 %T can't evict the removed path %q, it must implement config.Deleter", l1, p)
+			}
+		}
+	}
+	if s.pubSub != nil {
+		s.pubSub.sendMsg(p)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+// flakyBroadcaster fails Listen failures times and afterwards blocks until
+// Close gets called.
+type flakyBroadcaster struct {
+	failures int32
+	listens  int32
+	closed   chan struct{}
+}
+
+func (fb *flakyBroadcaster) Broadcast(config.Path) error { return nil }
+
+func (fb *flakyBroadcaster) Listen(func(config.Path) error) error {
+	if atomic.AddInt32(&fb.listens, 1) <= fb.failures {
+		return errors.ConnectionFailed.Newf("connection lost")
+	}
+	<-fb.closed
+	return nil
+}
+
+func (fb *flakyBroadcaster) Close() error {
+	close(fb.closed)
+	return nil
+}
+
+func TestService_Broadcaster_Reconnect(t *testing.T) {
+	t.Run("listens again after failures", func(t *testing.T) {
+		fb := &flakyBroadcaster{failures: 3, closed: make(chan struct{})}
+		s, err := config.NewService(storage.NewMap(), config.Options{
+			Broadcaster:         fb,
+			BroadcastMaxBackoff: 150 * time.Millisecond,
+		})
+		assert.NoError(t, err)
+
+		deadline := time.Now().Add(5 * time.Second)
+		for atomic.LoadInt32(&fb.listens) < 4 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.NoError(t, s.Close())
+		assert.Exactly(t, int32(4), atomic.LoadInt32(&fb.listens))
+	})
+
+	t.Run("Close terminates the backoff", func(t *testing.T) {
+		fb := &flakyBroadcaster{failures: 1 << 30, closed: make(chan struct{})}
+		s, err := config.NewService(storage.NewMap(), config.Options{
+			Broadcaster:         fb,
+			BroadcastMaxBackoff: time.Hour,
+		})
+		assert.NoError(t, err)
+
+		for atomic.LoadInt32(&fb.listens) < 1 {
+			time.Sleep(time.Millisecond)
+		}
+		done := make(chan error)
+		go func() { done <- s.Close() }()
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Close blocked in the backoff")
+		}
+	})
+}
+
+// pathBroadcaster delivers the paths of another process to the Service.
+type pathBroadcaster struct {
+	paths  chan config.Path
+	closed chan struct{}
+}
+
+func (pb *pathBroadcaster) Broadcast(config.Path) error { return nil }
+
+func (pb *pathBroadcaster) Listen(fn func(config.Path) error) error {
+	for {
+		select {
+		case p := <-pb.paths:
+			if err := fn(p); err != nil {
+				return err
+			}
+		case <-pb.closed:
+			return nil
+		}
+	}
+}
+
+func (pb *pathBroadcaster) Close() error {
+	close(pb.closed)
+	return nil
+}
+
+// flushOnlyLevel1 is a level 1 cache without config.Deleter.
+type flushOnlyLevel1 struct {
+	mu sync.Mutex
+	config.Storager
+	flushed int
+}
+
+func (fl *flushOnlyLevel1) Flush() error {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+	fl.flushed++
+	return fl.Storager.(interface{ Flush() error }).Flush()
+}
+
+func (fl *flushOnlyLevel1) flushCount() int {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+	return fl.flushed
+}
+
+func TestService_Broadcaster_Level1WithoutDeleter(t *testing.T) {
+	p := config.MustMakePathWithScope(scope.Store.WithID(2), "general/locale/timezone")
+	level2 := storage.NewMap()
+	l1 := &flushOnlyLevel1{Storager: storage.NewMap()}
+	pb := &pathBroadcaster{paths: make(chan config.Path), closed: make(chan struct{})}
+	s, err := config.NewService(level2, config.Options{Level1: l1, Broadcaster: pb})
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	t.Run("changed path overwrites level 1", func(t *testing.T) {
+		assert.NoError(t, l1.Set(p, []byte(`Europe/Berlin`)))
+		assert.NoError(t, level2.Set(p, []byte(`Europe/London`)))
+		pb.paths <- p
+		pb.paths <- p // the first path has been processed when the second gets received
+
+		v, found, err := l1.Get(p)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Exactly(t, []byte(`Europe/London`), v)
+		assert.Exactly(t, 0, l1.flushCount())
+	})
+
+	t.Run("removed path flushes level 1", func(t *testing.T) {
+		assert.NoError(t, level2.(config.Deleter).Delete(p))
+		pb.paths <- p
+		pb.paths <- p
+
+		v, found, err := l1.Get(p)
+		assert.NoError(t, err)
+		assert.False(t, found, "stale value must be removed")
+		assert.Nil(t, v)
+		assert.True(t, l1.flushCount() > 0)
+		assert.False(t, s.Get(p).IsValid())
+	})
+}
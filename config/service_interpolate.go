@@ -0,0 +1,185 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/store/scope"
+)
+
+// InterpolateMaxDepth limits the nesting of references between values.
+const InterpolateMaxDepth = 10
+
+// interpolateRegexp matches the placeholder {{config "web/secure/base_url"}}.
+var interpolateRegexp = regexp.MustCompile(`\{\{\s*config\s+"([^"{}]*)"\s*\}\}`)
+
+var (
+	interpolateOpen = []byte("{{")
+	envPlaceHolder  = []byte(EnvNamePlaceHolder)
+)
+
+type interpolateKey struct {
+	websiteID uint32
+	storeID   uint32
+	route     string
+}
+
+type interpolateEntry struct {
+	raw      string
+	expanded []byte
+	deps     map[string]struct{} // all routes the expanded value depends on
+}
+
+// interpolator expands placeholders in values read via Scoped.Get and caches
+// the results. It implements MessageReceiver to evict all cached values which
+// depend on a changed route.
+type interpolator struct {
+	envName []byte
+
+	mu sync.RWMutex
+	// gen increases with each eviction. A value which has been expanded
+	// during an eviction does not get cached because it might be stale.
+	gen   uint64
+	cache map[interpolateKey]interpolateEntry
+}
+
+// setupInterpolation creates the interpolator and subscribes it to the changes
+// of all scopes.
+func (s *Service) setupInterpolation() error {
+	if s.pubSub == nil {
+		return errors.NotValid.Newf("[config] EnableInterpolation requires EnablePubSub to invalidate the expanded values")
+	}
+	ip := &interpolator{
+		envName: []byte(s.envName),
+		cache:   map[interpolateKey]interpolateEntry{},
+	}
+	for _, t := range [...]scope.Type{scope.Default, scope.Website, scope.Store} {
+		if _, err := s.pubSub.Subscribe(t.StrType(), ip); err != nil {
+			return errors.Wrapf(err, "[config] Service.setupInterpolation.Subscribe to scope %q", t.StrType())
+		}
+	}
+	s.interpolator = ip
+	return nil
+}
+
+// interpolate returns a copy of v with expanded placeholders. References get
+// resolved through the scope hierarchy of ss. Errors get assigned to the
+// returned Value.
+func (ip *interpolator) interpolate(ss Scoped, route string, v *Value) *Value {
+	if v.lastErr != nil || v.found == valFoundNo ||
+		(!bytes.Contains(v.data, interpolateOpen) && !bytes.Contains(v.data, envPlaceHolder)) {
+		return v
+	}
+	key := interpolateKey{websiteID: ss.websiteID, storeID: ss.storeID, route: route}
+	v2 := *v
+
+	ip.mu.RLock()
+	e, ok := ip.cache[key]
+	gen := ip.gen
+	ip.mu.RUnlock()
+	if ok && e.raw == string(v.data) {
+		v2.data = append([]byte(nil), e.expanded...)
+		return &v2
+	}
+
+	e = interpolateEntry{raw: string(v.data), deps: map[string]struct{}{}}
+	var err error
+	if e.expanded, err = ip.expand(ss, v.data, []string{route}, e.deps); err != nil {
+		v2.data = nil
+		v2.lastErr = errors.Wrapf(err, "[config] Interpolation of route %q", route)
+		return &v2
+	}
+
+	ip.mu.Lock()
+	if gen == ip.gen {
+		ip.cache[key] = e
+	}
+	ip.mu.Unlock()
+	v2.data = append([]byte(nil), e.expanded...)
+	return &v2
+}
+
+// expand replaces all placeholders in raw. Argument visiting contains the
+// chain of routes currently being expanded to detect cycles.
+func (ip *interpolator) expand(ss Scoped, raw []byte, visiting []string, deps map[string]struct{}) ([]byte, error) {
+	raw = bytes.Replace(raw, envPlaceHolder, ip.envName, -1)
+	matches := interpolateRegexp.FindAllSubmatchIndex(raw, -1)
+	if len(matches) == 0 {
+		return raw, nil
+	}
+	if len(visiting) > InterpolateMaxDepth {
+		return nil, errors.Exceeded.Newf("[config] Interpolation exceeds the max depth of %d references: %s", InterpolateMaxDepth, strings.Join(visiting, " -> "))
+	}
+
+	buf := make([]byte, 0, len(raw))
+	last := 0
+	for _, m := range matches {
+		ref := string(raw[m[2]:m[3]])
+		for _, r := range visiting {
+			if r == ref {
+				return nil, errors.NotAcceptable.Newf("[config] Interpolation cycle detected: %s -> %s", strings.Join(visiting, " -> "), ref)
+			}
+		}
+		if err := Route(ref).IsValid(); err != nil {
+			return nil, errors.NotValid.New(err, "[config] Interpolation reference %q is not a valid route", ref)
+		}
+		deps[ref] = struct{}{}
+
+		rv := ss.get(scope.Absent, ref)
+		val, ok, err := rv.Str()
+		switch {
+		case err != nil:
+			return nil, errors.Wrapf(err, "[config] Interpolation reference %q", ref)
+		case !ok:
+			return nil, errors.NotFound.Newf("[config] Interpolation reference %q not found in scope %s", ref, ss.ScopeID())
+		}
+
+		exp, err := ip.expand(ss, []byte(val), append(visiting[:len(visiting):len(visiting)], ref), deps)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		buf = append(buf, raw[last:m[0]]...)
+		buf = append(buf, exp...)
+		last = m[1]
+	}
+	return append(buf, raw[last:]...), nil
+}
+
+// MessageConfig implements MessageReceiver and removes all cached values which
+// depend on the route of the changed path, regardless of the scope.
+func (ip *interpolator) MessageConfig(p Path) error {
+	route := string(p.route)
+	ip.mu.Lock()
+	defer ip.mu.Unlock()
+	ip.gen++
+	for k, e := range ip.cache {
+		if _, ok := e.deps[route]; ok || k.route == route {
+			delete(ip.cache, k)
+		}
+	}
+	return nil
+}
+
+func (ip *interpolator) flush() {
+	ip.mu.Lock()
+	ip.gen++
+	ip.cache = map[interpolateKey]interpolateEntry{}
+	ip.mu.Unlock()
+}
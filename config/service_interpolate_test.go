@@ -0,0 +1,108 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func newInterpolationService(t *testing.T) *config.Service {
+	s, err := config.NewService(storage.NewMap(
+		"default/0/web/secure/base_url", "https://shop.dev/",
+		"websites/1/web/secure/base_url", "https://ch.shop.dev/",
+		"stores/2/web/secure/base_url", "https://de.shop.dev/",
+		"default/0/payment/stripe/return_url", `{{config "web/secure/base_url"}}checkout/{CS_ENV}`,
+		"default/0/payment/stripe/cancel_url", `{{ config "payment/stripe/return_url" }}?cancel=1`,
+		"default/0/aa/bb/cc", `{{config "aa/bb/dd"}}`,
+		"default/0/aa/bb/dd", `x{{config "aa/bb/cc"}}`,
+		"default/0/aa/bb/missing", `{{config "aa/bb/not_stored"}}`,
+		"default/0/aa/bb/invalid", `{{config "aa/b"}}`,
+		"default/0/aa/bb/plain", `{{ no placeholder }}`,
+	), config.Options{
+		EnvName:             "STAGING",
+		EnablePubSub:        true,
+		EnableInterpolation: true,
+	})
+	assert.NoError(t, err)
+	return s
+}
+
+func scopedStr(t *testing.T, ss config.Scoped, route string) string {
+	v, ok, err := ss.Get(scope.Absent, route).Str()
+	assert.NoError(t, err, "Route %q", route)
+	assert.True(t, ok, "Route %q", route)
+	return v
+}
+
+func TestService_Interpolation(t *testing.T) {
+	t.Run("requires pubsub", func(t *testing.T) {
+		_, err := config.NewService(storage.NewMap(), config.Options{EnableInterpolation: true})
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+
+	s := newInterpolationService(t)
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	t.Run("scope hierarchy", func(t *testing.T) {
+		assert.Exactly(t, "https://shop.dev/checkout/STAGING", scopedStr(t, s.Scoped(0, 0), "payment/stripe/return_url"))
+		assert.Exactly(t, "https://ch.shop.dev/checkout/STAGING", scopedStr(t, s.Scoped(1, 0), "payment/stripe/return_url"))
+		assert.Exactly(t, "https://de.shop.dev/checkout/STAGING", scopedStr(t, s.Scoped(1, 2), "payment/stripe/return_url"))
+		assert.Exactly(t, "https://ch.shop.dev/checkout/STAGING", scopedStr(t, s.Scoped(1, 3), "payment/stripe/return_url"))
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		assert.Exactly(t, "https://de.shop.dev/checkout/STAGING?cancel=1", scopedStr(t, s.Scoped(1, 2), "payment/stripe/cancel_url"))
+		assert.Exactly(t, "{{ no placeholder }}", scopedStr(t, s.Scoped(1, 2), "aa/bb/plain"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, _, err := s.Scoped(1, 2).Get(scope.Absent, "aa/bb/cc").Str()
+		assert.ErrorIsKind(t, errors.NotAcceptable, err)
+		assert.Contains(t, err.Error(), "aa/bb/cc -> aa/bb/dd -> aa/bb/cc")
+
+		_, _, err = s.Scoped(1, 2).Get(scope.Absent, "aa/bb/missing").Str()
+		assert.ErrorIsKind(t, errors.NotFound, err)
+
+		_, _, err = s.Scoped(1, 2).Get(scope.Absent, "aa/bb/invalid").Str()
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+
+	t.Run("Service.Get does not interpolate", func(t *testing.T) {
+		v, _, err := s.Get(config.MustMakePath("payment/stripe/return_url")).Str()
+		assert.NoError(t, err)
+		assert.Exactly(t, `{{config "web/secure/base_url"}}checkout/{CS_ENV}`, v)
+	})
+
+	t.Run("invalidation via pubsub", func(t *testing.T) {
+		ss := s.Scoped(1, 2)
+		assert.Exactly(t, "https://de.shop.dev/checkout/STAGING?cancel=1", scopedStr(t, ss, "payment/stripe/cancel_url"))
+		assert.NoError(t, s.Set(config.MustMakePath("web/secure/base_url").BindStore(2), []byte("https://www.shop.de/")))
+
+		want := "https://www.shop.de/checkout/STAGING?cancel=1"
+		deadline := time.Now().Add(2 * time.Second)
+		for scopedStr(t, ss, "payment/stripe/cancel_url") != want && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		assert.Exactly(t, want, scopedStr(t, ss, "payment/stripe/cancel_url"))
+		assert.Exactly(t, "https://ch.shop.dev/checkout/STAGING?cancel=1", scopedStr(t, s.Scoped(1, 0), "payment/stripe/cancel_url"))
+	})
+}
@@ -144,7 +144,10 @@ func (s *pubSub) publish() {
 				return
 			}
 
-			if len(s.subMap) == 0 {
+			s.mu.RLock()
+			subCount := len(s.subMap)
+			s.mu.RUnlock()
+			if subCount == 0 {
 				break
 			}
 			var evict []int
@@ -0,0 +1,166 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/store/scope"
+)
+
+type schemaField struct {
+	*Field
+	perm scope.Perm
+}
+
+// schema validates the values of the strict mode, see Options.StrictSections.
+type schema struct {
+	fields map[string]schemaField // key: route
+}
+
+func newSchema(secs Sections) (*schema, error) {
+	if err := secs.Validate(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	sc := &schema{
+		fields: make(map[string]schemaField, secs.TotalFields()),
+	}
+	for _, s := range secs {
+		for _, g := range s.Groups {
+			for _, f := range g.Fields {
+				route := f.ConfigRoute
+				if route == "" {
+					route = s.ID + sPathSeparator + g.ID + sPathSeparator + f.ID
+				}
+				sf := schemaField{Field: f, perm: f.Scopes}
+				switch {
+				case sf.perm > 0:
+				case g.Scopes > 0:
+					sf.perm = g.Scopes
+				case s.Scopes > 0:
+					sf.perm = s.Scopes
+				default:
+					sf.perm = scope.PermDefault
+				}
+				sc.fields[route] = sf
+			}
+		}
+	}
+	return sc, nil
+}
+
+// validate checks that the route of the path exists, that its scope is
+// allowed and that the value matches the type of the field. TypeTime and
+// TypeDuration must parse, TypeButton and TypeLabel do not store any value,
+// TypeMultiselect must not contain empty entries and all other types must be
+// valid UTF-8. The options of TypeSelect and TypeMultiselect cannot be checked
+// because a Field does not define its allowed values.
+func (sc *schema) validate(p Path, v []byte) error {
+	f, ok := sc.fields[string(p.route)]
+	if !ok {
+		return errors.NotFound.Newf("[config] Route %q does not exist in the Sections", p.route)
+	}
+	if !f.perm.Has(p.ScopeID.Type()) {
+		return errors.NotAllowed.Newf("[config] Route %q is not allowed in scope %q, allowed up to scope %q", p.route, p.ScopeID.Type().StrType(), f.perm)
+	}
+	if len(v) == 0 {
+		if f.Type == TypeMultiselect && !f.CanBeEmpty {
+			return errors.Empty.Newf("[config] Route %q requires at least one selected value", p.route)
+		}
+		return nil
+	}
+	var err error
+	switch f.Type {
+	case TypeTime:
+		_, err = parseDateTime(string(v), time.UTC)
+	case TypeDuration:
+		_, err = time.ParseDuration(string(v))
+	case TypeButton, TypeLabel:
+		return errors.NotAllowed.Newf("[config] Route %q of type %s cannot store a value", p.route, f.Type)
+	case TypeMultiselect:
+		if !utf8.Valid(v) {
+			err = errors.BadEncoding.Newf("invalid UTF-8")
+			break
+		}
+		for _, sel := range bytes.Split(v, []byte(string(CSVColumnSeparator))) {
+			if len(bytes.TrimSpace(sel)) == 0 {
+				err = errors.Empty.Newf("empty selection in %q", v)
+				break
+			}
+		}
+	default:
+		if !utf8.Valid(v) {
+			err = errors.BadEncoding.Newf("invalid UTF-8")
+		}
+	}
+	if err != nil {
+		return errors.NotValid.New(err, "[config] Route %q contains an invalid value for type %s", p.route, f.Type)
+	}
+	return nil
+}
+
+// schemaReport collects the invalid values of one loadData run. It gets only
+// attached to the copy of the Service which the LoadDataOptions receive, so
+// concurrent calls to Service.Set always return their error.
+type schemaReport struct {
+	mu     sync.Mutex
+	closed bool
+	errs   map[string]string // key: fully qualified path
+}
+
+func newSchemaReport() *schemaReport {
+	return &schemaReport{errs: map[string]string{}}
+}
+
+// add collects the error of the path and returns true as long as the loading
+// has not yet finished.
+func (sr *schemaReport) add(p Path, err error) bool {
+	if sr == nil {
+		return false
+	}
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	if sr.closed {
+		return false
+	}
+	sr.errs[p.String()] = err.Error()
+	return true
+}
+
+// close finishes the loading and returns one error which contains all
+// collected errors sorted by path.
+func (sr *schemaReport) close() error {
+	if sr == nil {
+		return nil
+	}
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.closed = true
+	if len(sr.errs) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(sr.errs))
+	for fq, msg := range sr.errs {
+		lines = append(lines, fq+": "+msg)
+	}
+	sort.Strings(lines)
+	return errors.NotValid.Newf("[config] Strict mode rejected %d values:\n%s", len(lines), strings.Join(lines, "\n"))
+}
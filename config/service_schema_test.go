@@ -0,0 +1,198 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config_test
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+var strictSections = config.MustMakeSectionsValidate(
+	&config.Section{
+		ID: "web",
+		Groups: config.MakeGroups(
+			&config.Group{
+				ID: "secure",
+				Fields: config.MakeFields(
+					&config.Field{ID: "base_url", Type: config.TypeText, Scopes: scope.PermStore},
+					&config.Field{ID: "country", Type: config.TypeText},
+				),
+			},
+			&config.Group{
+				ID:     "cookie",
+				Scopes: scope.PermWebsite,
+				Fields: config.MakeFields(
+					&config.Field{ID: "lifetime", Type: config.TypeDuration},
+					&config.Field{ID: "domain", Type: config.TypeText},
+					&config.Field{ID: "since", Type: config.TypeTime},
+					&config.Field{ID: "methods", Type: config.TypeMultiselect},
+				),
+			},
+			&config.Group{
+				ID: "misc",
+				Fields: config.MakeFields(
+					&config.Field{ID: "hint", Type: config.TypeLabel},
+					&config.Field{ID: "name", Type: config.TypeText},
+				),
+			},
+		),
+	},
+)
+
+// withCountryValidator registers an observer which only accepts the country
+// CH.
+func withCountryValidator() config.LoadDataOption {
+	return config.MakeLoadDataOption(func(s *config.Service) error {
+		return s.RegisterObserver(config.EventOnBeforeSet, "web/secure/country", testObserver{
+			observe: func(p config.Path, rawData []byte, found bool) ([]byte, error) {
+				if string(rawData) != "CH" {
+					return nil, errors.NotValid.Newf("country %q not allowed", rawData)
+				}
+				return rawData, nil
+			},
+		})
+	}).WithSortOrder(-1)
+}
+
+func TestService_StrictSections(t *testing.T) {
+	t.Run("aggregated report", func(t *testing.T) {
+		_, err := config.NewService(storage.NewMap(), config.Options{StrictSections: strictSections},
+			storage.WithLoadStrings(
+				"default/0/web/secure/base_url", "https://shop.dev/",
+				"stores/2/web/secure/base_url", "https://de.shop.dev/",
+				"default/0/web/secure/base_ulr", "https://typo.dev/",
+				"stores/2/web/cookie/lifetime", "1h",
+				"websites/1/web/cookie/lifetime", "1 hour",
+				"stores/1/web/cookie/domain", "shop.dev",
+				"default/0/web/cookie/since", "yesterday",
+				"default/0/web/cookie/methods", "",
+				"default/0/web/secure/country", "XX",
+			),
+			withCountryValidator(),
+		)
+		assert.ErrorIsKind(t, errors.NotValid, err)
+
+		msg := err.Error()
+		assert.Contains(t, msg, "Strict mode rejected 7 values")
+		for _, want := range []string{
+			`default/0/web/secure/base_ulr: [config] Route "web/secure/base_ulr" does not exist`,
+			`stores/2/web/cookie/lifetime: [config] Route "web/cookie/lifetime" is not allowed in scope "stores"`,
+			`websites/1/web/cookie/lifetime: [config] Route "web/cookie/lifetime" contains an invalid value for type TypeDuration`,
+			`stores/1/web/cookie/domain: [config] Route "web/cookie/domain" is not allowed in scope "stores"`,
+			`default/0/web/cookie/since: [config] Route "web/cookie/since" contains an invalid value`,
+			`default/0/web/cookie/methods: [config] Route "web/cookie/methods" requires at least one selected value`,
+			`default/0/web/secure/country: country "XX" not allowed`,
+		} {
+			assert.Contains(t, msg, want)
+		}
+		assert.False(t, strings.Contains(msg, "https://shop.dev/"), "values must not be reported")
+	})
+
+	s, err := config.NewService(storage.NewMap(), config.Options{StrictSections: strictSections},
+		storage.WithLoadStrings(
+			"default/0/web/secure/base_url", "https://shop.dev/",
+			"websites/1/web/cookie/lifetime", "1h",
+			"default/0/web/cookie/since", "2026-10-19 12:00:00",
+			"default/0/web/secure/country", "CH",
+		),
+		withCountryValidator(),
+	)
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	t.Run("loaded", func(t *testing.T) {
+		d, ok, err := s.Get(config.MustMakePath("web/cookie/lifetime").BindWebsite(1)).Duration()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Exactly(t, "1h0m0s", d.String())
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		err := s.Set(config.MustMakePath("web/secure/base_ulr"), []byte("https://typo.dev/"))
+		assert.ErrorIsKind(t, errors.NotFound, err)
+
+		err = s.Set(config.MustMakePath("web/cookie/domain").BindStore(1), []byte("shop.dev"))
+		assert.ErrorIsKind(t, errors.NotAllowed, err)
+
+		err = s.Set(config.MustMakePath("web/secure/country"), []byte("DE"))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+
+		assert.NoError(t, s.Set(config.MustMakePath("web/secure/base_url").BindStore(2), []byte("https://de.shop.dev/")))
+	})
+
+	t.Run("Set field types", func(t *testing.T) {
+		err := s.Set(config.MustMakePath("web/misc/hint"), []byte("text"))
+		assert.ErrorIsKind(t, errors.NotAllowed, err)
+
+		err = s.Set(config.MustMakePath("web/misc/name"), []byte("\xff\xfe"))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		assert.NoError(t, s.Set(config.MustMakePath("web/misc/name"), []byte("Zürich")))
+
+		err = s.Set(config.MustMakePath("web/cookie/methods"), []byte("dhl,,ups"))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		assert.NoError(t, s.Set(config.MustMakePath("web/cookie/methods"), []byte("dhl,ups")))
+	})
+
+	t.Run("invalid Sections", func(t *testing.T) {
+		_, err := config.NewService(storage.NewMap(), config.Options{
+			StrictSections: config.Sections{
+				&config.Section{ID: "web", Groups: config.MakeGroups(&config.Group{ID: "secure", Fields: config.MakeFields(&config.Field{ID: "url"}, &config.Field{ID: "url"})})},
+			},
+		})
+		assert.ErrorIsKind(t, errors.Duplicated, err)
+	})
+}
+
+func TestService_StrictSections_SetDuringReload(t *testing.T) {
+	loading := make(chan struct{})
+	release := make(chan struct{})
+	var loads int32
+	s, err := config.NewService(storage.NewMap(), config.Options{
+		StrictSections:   strictSections,
+		EnableHotReload:  true,
+		HotReloadSignals: []os.Signal{syscall.SIGUSR2},
+	}, config.MakeLoadDataOption(func(s *config.Service) error {
+		if atomic.AddInt32(&loads, 1) > 1 {
+			close(loading)
+			<-release
+		}
+		return s.Set(config.MustMakePath("web/secure/base_url"), []byte("https://shop.dev/"))
+	}))
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR2))
+	select {
+	case <-loading:
+	case <-time.After(time.Second):
+		t.Fatal("hot reload did not start")
+	}
+	// the reload is in progress, an invalid value must not be collected.
+	p := config.MustMakePath("web/cookie/lifetime").BindWebsite(1)
+	err = s.Set(p, []byte("1 hour"))
+	close(release)
+	assert.ErrorIsKind(t, errors.NotValid, err)
+	assert.False(t, s.Get(p).IsValid(), "invalid value must not be written")
+}
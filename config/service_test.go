@@ -862,3 +862,117 @@ func TestService_FieldMetaData_Fallbacks(t *testing.T) {
 		assert.Exactly(t, "\"Bitte wählen;Frau;Herr\"", str)
 	})
 }
+
+func TestWithSharedSections(t *testing.T) {
+	shared, err := config.WithSharedSections(&config.Section{
+		ID: "carrier",
+		Groups: config.MakeGroups(&config.Group{
+			ID: "dhl",
+			Fields: config.MakeFields(
+				&config.Field{ID: "timeout", Default: "3600s", Scopes: scope.PermDefault},
+				&config.Field{ID: "username", Default: "prdUser0", Scopes: scope.PermWebsite},
+			),
+		}),
+	})
+	assert.NoError(t, err)
+
+	srv1 := config.MustNewService(storage.NewMap(), config.Options{}, shared)
+	defer func() { assert.NoError(t, srv1.Close()) }()
+	srv2 := config.MustNewService(storage.NewMap(), config.Options{}, shared,
+		config.WithFieldMeta(&config.FieldMeta{Route: "carrier/dhl/timeout", Default: "60s"}),
+	)
+	defer func() { assert.NoError(t, srv2.Close()) }()
+
+	dhlTimeout := config.MustMakePath("carrier/dhl/timeout")
+
+	t.Run("shared default value", func(t *testing.T) {
+		str, ok, err := srv1.Get(dhlTimeout).Str()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Exactly(t, `3600s`, str)
+	})
+	t.Run("own default value takes precedence", func(t *testing.T) {
+		str, ok, err := srv2.Get(dhlTimeout).Str()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Exactly(t, `60s`, str)
+	})
+	t.Run("shared scope permission", func(t *testing.T) {
+		err := srv1.Set(dhlTimeout.BindWebsite(1), []byte(`1m`))
+		assert.ErrorIsKind(t, errors.NotAllowed, err)
+		assert.NoError(t, srv1.Set(config.MustMakePath("carrier/dhl/username").BindWebsite(1), []byte(`guest`)))
+	})
+	t.Run("value of one service does not leak", func(t *testing.T) {
+		assert.NoError(t, srv1.Set(dhlTimeout, []byte(`1m`)))
+		str, _, err := srv2.Get(dhlTimeout).Str()
+		assert.NoError(t, err)
+		assert.Exactly(t, `60s`, str)
+	})
+	t.Run("invalid sections", func(t *testing.T) {
+		sec := &config.Section{
+			ID:     "carrier",
+			Groups: config.MakeGroups(&config.Group{ID: "dhl", Fields: config.MakeFields(&config.Field{ID: "timeout"})}),
+		}
+		_, err := config.WithSharedSections(sec, sec)
+		assert.ErrorIsKind(t, errors.Duplicated, err)
+	})
+}
+
+// batchStorage counts the calls to SetBatch of a level 2 storage.
+type batchStorage struct {
+	config.Storager
+	batches int
+}
+
+func (bs *batchStorage) SetBatch(ps []config.Path, vs [][]byte) error {
+	bs.batches++
+	for i, p := range ps {
+		if err := bs.Storager.Set(p, vs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestService_SetBatch(t *testing.T) {
+	level2 := &batchStorage{Storager: storage.NewMap()}
+	srv := config.MustNewService(level2, config.Options{},
+		config.WithFieldMeta(&config.FieldMeta{Route: "carrier/dhl/timeout", WriteScopePerm: scope.PermDefault}),
+	)
+	defer func() { assert.NoError(t, srv.Close()) }()
+
+	pUser := config.MustMakePath("carrier/dhl/username")
+	pTimeout := config.MustMakePath("carrier/dhl/timeout")
+
+	t.Run("Validate", func(t *testing.T) {
+		assert.NoError(t, srv.Validate(pTimeout, []byte(`1m`)))
+		assert.ErrorIsKind(t, errors.NotAllowed, srv.Validate(pTimeout.BindWebsite(1), []byte(`1m`)))
+		assert.False(t, srv.Get(pTimeout).IsValid(), "Validate must not write")
+	})
+
+	t.Run("invalid value writes nothing", func(t *testing.T) {
+		err := srv.SetBatch(
+			[]config.Path{pUser, pTimeout.BindWebsite(1)},
+			[][]byte{[]byte(`guest`), []byte(`1m`)},
+		)
+		assert.ErrorIsKind(t, errors.NotAllowed, err)
+		_, ok, err := srv.Get(pUser).Str()
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.Exactly(t, 0, level2.batches)
+	})
+
+	t.Run("one batch", func(t *testing.T) {
+		assert.NoError(t, srv.SetBatch(
+			[]config.Path{pUser, pTimeout},
+			[][]byte{[]byte(`guest`), []byte(`1m`)},
+		))
+		assert.Exactly(t, 1, level2.batches)
+		assert.Exactly(t, `"guest"`, srv.Get(pUser).String())
+		assert.Exactly(t, `"1m"`, srv.Get(pTimeout).String())
+	})
+
+	t.Run("length mismatch", func(t *testing.T) {
+		assert.ErrorIsKind(t, errors.NotValid, srv.SetBatch([]config.Path{pUser}, nil))
+	})
+}
@@ -0,0 +1,248 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build csall || (db && json && yaml)
+// +build csall db,json,yaml
+
+// Command cfgexport exports, imports and compares the configuration values
+// stored in the table core_configuration. A source is either a YAML or JSON
+// file in the format of storage.WithLoadYAML and storage.WithLoadJSON or a
+// MySQL/MariaDB DSN.
+//
+// Example usage:
+//
+//	cfgexport export -src 'user:pass@tcp(localhost:3306)/magento' -out config.yaml
+//	cfgexport import -dst 'user:pass@tcp(localhost:3306)/magento' -file config.yaml
+//	cfgexport diff -left "$STAGING_DSN" -left-env STAGING -right "$PRD_DSN" -right-env PRD \
+//		-mask payment/stripe/secret_key,carriers/dhl/password
+//
+// Values of the routes in -mask get replaced, e.g. the routes encrypted with
+// observer.NewAESGCM, which an application can list via
+// config.Service.ObservedRoutes(config.EventOnAfterGet, observer.IsAESGCM).
+// The diff exits with status 1 if both sources differ.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
+)
+
+const usage = `Usage: %s export|import|diff [flags]
+Run a sub command with -h to see its flags.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintf(os.Stderr, usage, os.Args[0])
+		os.Exit(2)
+	}
+	var (
+		differs bool
+		err     error
+	)
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "export":
+		err = runExport(args)
+	case "import":
+		err = runImport(args)
+	case "diff":
+		differs, err = runDiff(args)
+	default:
+		fmt.Fprintf(os.Stderr, usage, os.Args[0])
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %+v\n", err)
+		os.Exit(1)
+	}
+	if differs {
+		os.Exit(1)
+	}
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	src := fs.String("src", "", "DSN or YAML/JSON file to export")
+	out := fs.String("out", "", "output file, the extension .json selects JSON, default: YAML to stdout")
+	format := fs.String("format", "yaml", "output format if -out is empty: yaml or json")
+	env := fs.String("env", "", "exports only the environment suffixes of this environment name")
+	strip := fs.Bool("strip-env", false, "removes the environment suffix, the output cannot be imported anymore")
+	mask := fs.String("mask", "", "comma separated list of routes or route prefixes whose values get masked")
+	maskValue := fs.String("mask-value", storage.DefaultMaskValue, "replaces the values of the masked routes")
+	timeout := fs.Duration("timeout", time.Minute, "maximum duration of the database queries")
+	_ = fs.Parse(args)
+
+	ed, err := export(*src, *timeout, storage.ExportOptions{
+		MaskRoutes:     splitRoutes(*mask),
+		MaskValue:      *maskValue,
+		EnvName:        *env,
+		StripEnvSuffix: *strip,
+	})
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		defer f.Close()
+		w = f
+		if filepath.Ext(*out) == ".json" {
+			*format = "json"
+		}
+	}
+	if *format == "json" {
+		return ed.WriteJSON(w)
+	}
+	return ed.WriteYAML(w)
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	dst := fs.String("dst", "", "DSN of the target database")
+	file := fs.String("file", "", "YAML or JSON file to import")
+	mask := fs.String("mask", "", "comma separated list of routes or route prefixes which have been masked during the export")
+	maskValue := fs.String("mask-value", storage.DefaultMaskValue, "mask value used during the export")
+	timeout := fs.Duration("timeout", time.Minute, "maximum duration of the import transaction")
+	_ = fs.Parse(args)
+
+	ed, err := decodeFile(*file)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	tbls, err := newTables(ctx, *dst)
+	if err != nil {
+		return err
+	}
+	defer tbls.ConnPool.Close()
+	return storage.ImportDB(ctx, tbls, storage.DBOptions{SkipSchemaValidation: true}, ed, storage.ExportOptions{
+		MaskRoutes: splitRoutes(*mask),
+		MaskValue:  *maskValue,
+	})
+}
+
+func runDiff(args []string) (bool, error) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	left := fs.String("left", "", "DSN or YAML/JSON file of the left side, e.g. staging")
+	right := fs.String("right", "", "DSN or YAML/JSON file of the right side, e.g. production")
+	leftEnv := fs.String("left-env", "", "environment name of the left side, gets removed from the routes")
+	rightEnv := fs.String("right-env", "", "environment name of the right side, gets removed from the routes")
+	mask := fs.String("mask", "", "comma separated list of routes or route prefixes whose values get masked")
+	timeout := fs.Duration("timeout", time.Minute, "maximum duration of the database queries")
+	_ = fs.Parse(args)
+
+	maskRoutes := splitRoutes(*mask)
+	edLeft, err := export(*left, *timeout, storage.ExportOptions{MaskRoutes: maskRoutes, EnvName: *leftEnv, StripEnvSuffix: true})
+	if err != nil {
+		return false, err
+	}
+	edRight, err := export(*right, *timeout, storage.ExportOptions{MaskRoutes: maskRoutes, EnvName: *rightEnv, StripEnvSuffix: true})
+	if err != nil {
+		return false, err
+	}
+	des := storage.Diff(edLeft, edRight)
+	for _, de := range des {
+		fmt.Println(de.String())
+	}
+	return len(des) > 0, nil
+}
+
+func export(src string, timeout time.Duration, o storage.ExportOptions) (storage.ExportData, error) {
+	var w storage.Walker
+	if isFile(src) {
+		ed, err := decodeFile(src)
+		if err != nil {
+			return nil, err
+		}
+		w = ed
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		tbls, err := newTables(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		defer tbls.ConnPool.Close()
+		if w, err = storage.NewDBWalker(tbls, storage.DBOptions{ContextTimeoutRead: timeout}); err != nil {
+			return nil, err
+		}
+	}
+	return storage.Export(w, o)
+}
+
+func isFile(src string) bool {
+	switch filepath.Ext(src) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+func decodeFile(file string) (storage.ExportData, error) {
+	if !isFile(file) {
+		return nil, errors.NotSupported.Newf("[cfgexport] File %q must have the extension .yaml, .yml or .json", file)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer f.Close()
+	if filepath.Ext(file) == ".json" {
+		return storage.DecodeJSON(f)
+	}
+	return storage.DecodeYAML(f)
+}
+
+func newTables(ctx context.Context, dsn string) (*ddl.Tables, error) {
+	if dsn == "" {
+		return nil, errors.Empty.Newf("[cfgexport] DSN or file name is empty")
+	}
+	db, err := dml.NewConnPool(dml.WithDSN(dsn), dml.WithVerifyConnection(ctx, 10*time.Second))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	tbls, err := storage.NewTables(ctx, ddl.WithConnPool(db))
+	if err != nil {
+		_ = db.Close()
+		return nil, errors.WithStack(err)
+	}
+	return tbls, nil
+}
+
+func splitRoutes(s string) []string {
+	if s == "" {
+		return nil
+	}
+	rs := strings.Split(s, ",")
+	for i, r := range rs {
+		rs[i] = strings.Trim(strings.TrimSpace(r), "/")
+	}
+	return rs
+}
@@ -0,0 +1,208 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build csall || db
+// +build csall db
+
+package storage
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/store/scope"
+)
+
+// DBBroadcastOptions applies options to the DBBroadcast type.
+type DBBroadcastOptions struct {
+	// TableName if set, specifies the alternate table name, default:
+	// `core_configuration` aka constant TableNameCoreConfiguration.
+	TableName string
+	// UpdatedAtColumn defines the timestamp column which changes with each
+	// write, default `version_ts`, the start of the system versioning. Any
+	// other column like `updated_at` with `ON UPDATE CURRENT_TIMESTAMP(6)`
+	// works too.
+	UpdatedAtColumn string
+	// PollInterval default 2s.
+	PollInterval time.Duration
+	// Overlap defines how far each poll reaches back before the newest
+	// timestamp seen so far, default 5s. Transactions commit in a different
+	// order than their timestamps get assigned, so a row can appear with a
+	// timestamp equal to or older than the newest one already seen. Rows
+	// within the Overlap get reported only once. Rows which commit later than
+	// Overlap after their timestamp has been set, get missed.
+	Overlap time.Duration
+	// ContextTimeoutRead default 10s.
+	ContextTimeoutRead time.Duration
+	Log                log.Logger
+}
+
+// DBBroadcast implements config.Broadcaster by polling the table
+// core_configuration for rows with a newer timestamp. Broadcast is a no-op
+// because the DB Storager writes the row itself. Polling cannot distinguish
+// between the own and other processes, hence the origin process gets notified
+// too and the MessageReceivers must be idempotent.
+type DBBroadcast struct {
+	options  DBBroadcastOptions
+	qryLast  *dml.DBR
+	qryPoll  *dml.DBR
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewDBBroadcast creates a new polling broadcaster for the table
+// core_configuration.
+func NewDBBroadcast(tbls *ddl.Tables, o DBBroadcastOptions) (*DBBroadcast, error) {
+	if o.TableName == "" {
+		o.TableName = TableNameCoreConfiguration
+	}
+	if o.UpdatedAtColumn == "" {
+		o.UpdatedAtColumn = "version_ts"
+	}
+	if o.PollInterval == 0 {
+		o.PollInterval = time.Second * 2
+	}
+	if o.ContextTimeoutRead == 0 {
+		o.ContextTimeoutRead = time.Second * 10 // just a guess
+	}
+	if o.Overlap == 0 {
+		o.Overlap = time.Second * 5
+	}
+
+	tbl, err := tbls.Table(o.TableName)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	qryLast := tbl.Select(o.UpdatedAtColumn).OrderByDesc(o.UpdatedAtColumn).Limit(0, 1)
+
+	qryPoll := tbl.Select("scope", "scope_id", "path", o.UpdatedAtColumn).Where(
+		dml.Column(o.UpdatedAtColumn).GreaterOrEqual().PlaceHolder(),
+	).OrderBy(o.UpdatedAtColumn)
+
+	return &DBBroadcast{
+		options: o,
+		qryLast: qryLast.WithDBR(tbls.ConnPool.DB),
+		qryPoll: qryPoll.WithDBR(tbls.ConnPool.DB),
+		stop:    make(chan struct{}),
+	}, nil
+}
+
+// Broadcast does nothing because the changed row gets detected by Listen.
+func (db *DBBroadcast) Broadcast(_ config.Path) error { return nil }
+
+// Listen polls the table and calls fn for each row with a timestamp newer than
+// the newest seen timestamp minus the Overlap. Rows already reported within
+// the Overlap get skipped. Database errors get logged as Info message and the
+// polling continues.
+func (db *DBBroadcast) Listen(fn func(config.Path) error) error {
+	var lastSeen time.Time
+	for lastSeen.IsZero() {
+		ctx, cancel := context.WithTimeout(context.Background(), db.options.ContextTimeoutRead)
+		nt, _, err := db.qryLast.LoadNullTime(ctx)
+		cancel()
+		if err == nil {
+			lastSeen = nt.Time
+			if !nt.Valid {
+				lastSeen = time.Unix(0, 0) // empty table
+			}
+			break
+		}
+		if db.options.Log != nil && db.options.Log.IsInfo() {
+			db.options.Log.Info("config.storage.DBBroadcast.Listen.LoadNullTime", log.Err(err))
+		}
+		select {
+		case <-db.stop:
+			return nil
+		case <-time.After(db.options.PollInterval):
+		}
+	}
+
+	// seen contains the rows reported within the overlap window. key: scope,
+	// scope ID, path and timestamp.
+	seen := map[string]time.Time{}
+	ticker := time.NewTicker(db.options.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-db.stop:
+			return nil
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), db.options.ContextTimeoutRead)
+		var fnErr error
+		err := db.qryPoll.IterateSerial(ctx, func(cm *dml.ColumnMap) error {
+			var scp, path string
+			var scopeID uint32
+			var ts time.Time
+			for cm.Next(4) {
+				switch c := cm.Column(); c {
+				case "scope":
+					cm.String(&scp)
+				case "scope_id":
+					cm.Uint32(&scopeID)
+				case "path":
+					cm.String(&path)
+				default:
+					cm.Time(&ts)
+				}
+			}
+			if err := cm.Err(); err != nil {
+				return errors.Wrapf(err, "[config/storage] DBBroadcast.Listen.IterateSerial at row %d", cm.Count)
+			}
+			key := scp + "/" + strconv.FormatUint(uint64(scopeID), 10) + "/" + path + "@" + strconv.FormatInt(ts.UnixNano(), 10)
+			if _, ok := seen[key]; ok {
+				return nil
+			}
+			seen[key] = ts
+			if ts.After(lastSeen) {
+				lastSeen = ts
+			}
+			p, err := config.MakePathWithScope(scope.FromString(scp).WithID(scopeID), path)
+			if err != nil {
+				return errors.Wrapf(err, "[config/storage] DBBroadcast.Listen.config.MakePathWithScope Path %q Scope: %q ID: %d", path, scp, scopeID)
+			}
+			if fnErr = fn(p); fnErr != nil {
+				return fnErr
+			}
+			return nil
+		}, lastSeen.Add(-db.options.Overlap))
+		cancel()
+		for key, ts := range seen {
+			if ts.Before(lastSeen.Add(-db.options.Overlap)) {
+				delete(seen, key)
+			}
+		}
+		switch {
+		case fnErr != nil:
+			return errors.WithStack(fnErr)
+		case err != nil && db.options.Log != nil && db.options.Log.IsInfo():
+			db.options.Log.Info("config.storage.DBBroadcast.Listen.IterateSerial", log.Time("last_seen", lastSeen), log.Err(err))
+		}
+	}
+}
+
+// Close terminates Listen.
+func (db *DBBroadcast) Close() error {
+	db.stopOnce.Do(func() { close(db.stop) })
+	return nil
+}
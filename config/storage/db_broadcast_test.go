@@ -0,0 +1,85 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build csall || db
+// +build csall db
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+var _ config.Broadcaster = (*storage.DBBroadcast)(nil)
+
+func TestDBBroadcast_Mocked(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	dbMock.ExpectQuery("SELECT.+FROM information_schema.COLUMNS").WithArgs().WillReturnRows(
+		dmltest.MustMockRows(dmltest.WithFile("testdata", "core_configuration_columns.csv")),
+	)
+	const overlap = 2 * time.Second
+	db, err := storage.NewDBBroadcast(mustNewTables(context.TODO(), ddl.WithConnPool(dbc)), storage.DBBroadcastOptions{
+		PollInterval: 5 * time.Millisecond,
+		Overlap:      overlap,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Broadcast(config.Path{}), "Broadcast must be a no-op")
+
+	t0 := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	t1 := t0.Add(time.Second)
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `version_ts` FROM `core_configuration` AS `main_table` ORDER BY `version_ts` DESC LIMIT")).
+		WillReturnRows(sqlmock.NewRows([]string{"version_ts"}).AddRow(t0))
+	const qryPoll = "SELECT `scope`, `scope_id`, `path`, `version_ts` FROM `core_configuration` AS `main_table` WHERE (`version_ts` >= ?) ORDER BY `version_ts`"
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(qryPoll)).
+		WithArgs(t0.Add(-overlap)).
+		WillReturnRows(sqlmock.NewRows([]string{"scope", "scope_id", "path", "version_ts"}).
+			AddRow("websites", 1, "web/cookie/domain", t1).
+			AddRow("default", 0, "general/locale/code", t1))
+	// Two transactions committed late: one with an older and one with the
+	// same timestamp as the newest seen row. Already reported rows get
+	// skipped.
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(qryPoll)).
+		WithArgs(t1.Add(-overlap)).
+		WillReturnRows(sqlmock.NewRows([]string{"scope", "scope_id", "path", "version_ts"}).
+			AddRow("stores", 2, "web/unsecure/base_url", t0.Add(500*time.Millisecond)).
+			AddRow("websites", 1, "web/cookie/domain", t1).
+			AddRow("default", 0, "general/locale/code", t1).
+			AddRow("stores", 3, "web/unsecure/base_url", t1))
+
+	var paths []string
+	err = db.Listen(func(p config.Path) error {
+		paths = append(paths, p.String())
+		if len(paths) == 4 {
+			assert.NoError(t, db.Close())
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{
+		"websites/1/web/cookie/domain", "default/0/general/locale/code",
+		"stores/2/web/unsecure/base_url", "stores/3/web/unsecure/base_url",
+	}, paths)
+	assert.NoError(t, db.Close(), "Close must be idempotent")
+}
@@ -67,7 +67,8 @@ type dbStats struct {
 // Service connects the MySQL/MariaDB with the config.Service type. Implements
 // interface config.Storager.
 type DB struct {
-	cfg DBOptions
+	cfg      DBOptions
+	connPool *dml.ConnPool
 
 	sqlRead  *dml.Select
 	sqlWrite *dml.Insert
@@ -141,6 +142,7 @@ func NewDB(tbls *ddl.Tables, o DBOptions) (*DB, error) {
 
 	dbs := &DB{
 		cfg:              o,
+		connPool:         tbls.ConnPool,
 		tickerDaemonStop: make(chan struct{}),
 		sqlRead:          qryRead,
 		sqlWrite:         qryWrite,
@@ -273,6 +275,23 @@ func (dbs *DB) Set(p config.Path, value []byte) error {
 	return err
 }
 
+// SetBatch writes all values within one transaction. Implements interface
+// config.BatchSetter.
+func (dbs *DB) SetBatch(ps []config.Path, vs [][]byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbs.cfg.ContextTimeoutWrite)
+	defer cancel()
+	return dbs.connPool.Transaction(ctx, nil, func(tx *dml.Tx) error {
+		stmt := tx.WithQueryBuilder(dbs.sqlWrite)
+		for i, p := range ps {
+			scp, path := p.ScopeRoute()
+			if _, err := stmt.ExecContext(ctx, scp.ToUint64(), path, vs[i]); err != nil {
+				return errors.Wrapf(err, "[config/storage] DB.SetBatch with path %q", p.String())
+			}
+		}
+		return nil
+	})
+}
+
 // Get performs a read operation from the database and returns a value from
 // the table. The `ok` return argument can be true even if byte slice `v` is
 // nil, which means that the path and scope are stored in the database table.
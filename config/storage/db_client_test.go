@@ -33,7 +33,10 @@ import (
 	"github.com/fortytw2/leaktest"
 )
 
-var _ config.Storager = (*storage.DB)(nil)
+var (
+	_ config.Storager    = (*storage.DB)(nil)
+	_ config.BatchSetter = (*storage.DB)(nil)
+)
 
 func mustNewTables(ctx context.Context, opts ...ddl.TableOption) (tm *ddl.Tables) {
 	t, err := storage.NewTables(ctx, opts...)
@@ -303,6 +306,59 @@ func TestService_Set(t *testing.T) {
 	})
 }
 
+func TestDB_SetBatch(t *testing.T) {
+	const insert = "INSERT INTO `core_configuration` (`scope`,`scope_id`,`path`,`value`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `value`=VALUES(`value`)"
+
+	newDB := func(t *testing.T) (*storage.DB, sqlmock.Sqlmock, func()) {
+		dbc, dbMock := dmltest.MockDB(t)
+		dbMock.ExpectQuery("SELECT.+FROM information_schema.COLUMNS").WithArgs().WillReturnRows(
+			dmltest.MustMockRows(dmltest.WithFile("testdata", "core_configuration_columns.csv")),
+		)
+		dbs, err := storage.NewDB(mustNewTables(context.TODO(), ddl.WithConnPool(dbc)), storage.DBOptions{
+			SkipSchemaValidation: true,
+		})
+		assert.NoError(t, err)
+		return dbs, dbMock, func() {
+			dmltest.Close(t, dbs)
+			dmltest.MockClose(t, dbc, dbMock)
+		}
+	}
+	ps := []config.Path{
+		config.MustMakePath("web/cors/allow_credentials"),
+		config.MustMakePathWithScope(scope.Store.WithID(2), "web/cors/exposed_headers"),
+	}
+	vs := [][]byte{[]byte(`1`), []byte(`X-Token`)}
+
+	t.Run("commit", func(t *testing.T) {
+		dbs, dbMock, closer := newDB(t)
+		defer closer()
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(insert)).WithArgs(scope.DefaultTypeID, "web/cors/allow_credentials", vs[0]).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(insert)).WithArgs(scope.Store.WithID(2), "web/cors/exposed_headers", vs[1]).
+			WillReturnResult(sqlmock.NewResult(2, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dbs.SetBatch(ps, vs))
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		dbs, dbMock, closer := newDB(t)
+		defer closer()
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(insert)).WithArgs(scope.DefaultTypeID, "web/cors/allow_credentials", vs[0]).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(insert)).WithArgs(scope.Store.WithID(2), "web/cors/exposed_headers", vs[1]).
+			WillReturnError(errors.ConnectionFailed.Newf("gone away"))
+		dbMock.ExpectRollback()
+
+		err := dbs.SetBatch(ps, vs)
+		assert.ErrorIsKind(t, errors.ConnectionFailed, err)
+	})
+}
+
 // Test_WithApplyCoreConfigData reads from the MySQL core_configuration table and applies
 // these value to the underlying storage. tries to get back the values from the
 // underlying storage
@@ -0,0 +1,118 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build csall || db
+// +build csall db
+
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/store/scope"
+)
+
+// NewDBWalker creates a Walker which iterates over all rows of the table
+// core_configuration sorted by scope and path. Use it with function Export.
+func NewDBWalker(tbls *ddl.Tables, o DBOptions) (Walker, error) {
+	if o.TableName == "" {
+		o.TableName = TableNameCoreConfiguration
+	}
+	if o.ContextTimeoutRead == 0 {
+		o.ContextTimeoutRead = time.Second * 10 // just a guess
+	}
+	tbl, err := tbls.Table(o.TableName)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	qryAll := tbl.Select("scope", "scope_id", "path", "value").
+		OrderBy("scope", "scope_id", "path").
+		WithDBR(tbls.ConnPool.DB)
+
+	return WalkerFunc(func(fn func(config.Path, []byte) error) error {
+		ctx, cancel := context.WithTimeout(context.Background(), o.ContextTimeoutRead)
+		defer cancel()
+		return qryAll.IterateSerial(ctx, func(cm *dml.ColumnMap) error {
+			var scp, path string
+			var scopeID uint32
+			var value null.String
+			for cm.Next(4) {
+				switch c := cm.Column(); c {
+				case "scope":
+					cm.String(&scp)
+				case "scope_id":
+					cm.Uint32(&scopeID)
+				case "path":
+					cm.String(&path)
+				default:
+					cm.NullString(&value)
+				}
+			}
+			if err := cm.Err(); err != nil {
+				return errors.Wrapf(err, "[config/storage] DBWalker.IterateSerial at row %d", cm.Count)
+			}
+			p, err := config.MakePathWithScope(scope.FromString(scp).WithID(scopeID), path)
+			if err != nil {
+				return errors.Wrapf(err, "[config/storage] DBWalker.config.MakePathWithScope Path %q Scope: %q ID: %d", path, scp, scopeID)
+			}
+			var v []byte
+			if value.Valid {
+				v = []byte(value.Data)
+			}
+			return fn(p, v)
+		})
+	}), nil
+}
+
+// ImportDB writes all values of ExportData into the table core_configuration
+// within one transaction. Existing values get overwritten. Pass the same
+// ExportOptions which created the export, because masked values cannot be
+// imported.
+func ImportDB(ctx context.Context, tbls *ddl.Tables, o DBOptions, ed ExportData, eo ExportOptions) error {
+	if o.TableName == "" {
+		o.TableName = TableNameCoreConfiguration
+	}
+	tbl, err := tbls.Table(o.TableName)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	paths, err := ed.paths()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := eo.checkMasked("ImportDB", paths); err != nil {
+		return errors.WithStack(err)
+	}
+
+	ins := dml.NewInsert(tbl.Name).AddColumns("scope", "scope_id", "path", "value").
+		AddOnDuplicateKeyExclude("scope", "scope_id", "path").OnDuplicateKey()
+
+	return tbls.ConnPool.Transaction(ctx, nil, func(tx *dml.Tx) error {
+		stmt := tx.WithQueryBuilder(ins)
+		for _, pv := range paths {
+			scp, route := pv.p.ScopeRoute()
+			scpType, scpID := scp.Unpack()
+			if _, err := stmt.ExecContext(ctx, scpType.StrType(), scpID, route, pv.v); err != nil {
+				return errors.Wrapf(err, "[config/storage] ImportDB with path %q", pv.p.String())
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,338 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/store/scope"
+)
+
+// Walker gets implemented by a config.Storager which can iterate over all of
+// its stored values, e.g. the map storage, ExportData or the database storage
+// via NewDBWalker.
+type Walker interface {
+	// Walk calls fn for each stored path and its raw value. Returning an error
+	// from fn stops the iteration.
+	Walk(fn func(p config.Path, v []byte) error) error
+}
+
+// WalkerFunc adapts a function to the Walker interface.
+type WalkerFunc func(fn func(p config.Path, v []byte) error) error
+
+// Walk calls wf(fn).
+func (wf WalkerFunc) Walk(fn func(p config.Path, v []byte) error) error {
+	return wf(fn)
+}
+
+// DefaultMaskValue replaces the values of masked routes during an export.
+const DefaultMaskValue = "********"
+
+// ExportOptions applies options to function Export.
+type ExportOptions struct {
+	// MaskRoutes contains routes or route prefixes whose values get replaced
+	// by MaskValue. Use config.Service.ObservedRoutes together with
+	// observer.IsAESGCM to mask all encrypted values.
+	MaskRoutes []string
+	// MaskValue replaces the values of masked routes. Defaults to
+	// DefaultMaskValue.
+	MaskValue string
+	// EnvName if set, skips all routes with an environment suffix which
+	// differs from EnvName. See config.Path.UseEnvSuffix.
+	EnvName string
+	// StripEnvSuffix removes the suffix EnvName from the routes. A value with
+	// suffix overwrites the value without suffix. Use it to compare two
+	// environments with Diff, but do not import the result because the
+	// environment awareness gets lost.
+	StripEnvSuffix bool
+}
+
+func (o ExportOptions) isMasked(route string) bool {
+	for _, mr := range o.MaskRoutes {
+		if route == mr || strings.HasPrefix(route, mr+sPathSeparator) {
+			return true
+		}
+	}
+	return false
+}
+
+const sPathSeparator = string(config.PathSeparator)
+
+// envSuffix returns the fourth segment of a route, which contains the
+// environment name.
+func envSuffix(route string) (prefix, suffix string) {
+	if strings.Count(route, sPathSeparator) < config.PathLevels {
+		return route, ""
+	}
+	pos := strings.LastIndexByte(route, config.PathSeparator)
+	return route[:pos], route[pos+1:]
+}
+
+// ExportData contains configuration values in the format of the files loaded
+// by WithLoadYAML and WithLoadJSON: route -> scope -> scope ID -> value. For
+// example:
+//
+//	web/unsecure/base_url:
+//	  default:
+//	    0: "http://eshop.dev/"
+//	  stores:
+//	    2: "http://eshop.dev/de-de/"
+//
+// ExportData implements config.Setter and Walker.
+type ExportData map[string]map[string]map[string]string
+
+// Export reads all paths of all scopes from the Walker, which is usually a
+// config.Storager, into ExportData.
+func Export(w Walker, o ExportOptions) (ExportData, error) {
+	if o.MaskValue == "" {
+		o.MaskValue = DefaultMaskValue
+	}
+	ed := ExportData{}
+	fromSuffix := map[cacheKey]bool{}
+	err := w.Walk(func(p config.Path, v []byte) error {
+		scp, route := p.ScopeRoute()
+		if o.EnvName != "" {
+			prefix, suffix := envSuffix(route)
+			if suffix != "" && suffix != o.EnvName {
+				return nil
+			}
+			if o.StripEnvSuffix {
+				key := makeCacheKey(scp, prefix)
+				if suffix == "" && fromSuffix[key] {
+					return nil // suffixed value has precedence
+				}
+				fromSuffix[key] = suffix != ""
+				route = prefix
+			}
+		}
+		val := string(v)
+		if o.isMasked(route) {
+			val = o.MaskValue
+		}
+		ed.set(scp, route, val)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "[config/storage] Export.Walk")
+	}
+	return ed, nil
+}
+
+func (ed ExportData) set(scp scope.TypeID, route, val string) {
+	scpType, scpID := scp.Unpack()
+	scopes := ed[route]
+	if scopes == nil {
+		scopes = map[string]map[string]string{}
+		ed[route] = scopes
+	}
+	ids := scopes[scpType.StrType()]
+	if ids == nil {
+		ids = map[string]string{}
+		scopes[scpType.StrType()] = ids
+	}
+	ids[strconv.FormatUint(uint64(scpID), 10)] = val
+}
+
+// Set implements config.Setter and adds the value to ExportData.
+func (ed ExportData) Set(p config.Path, v []byte) error {
+	if err := p.IsValid(); err != nil {
+		return errors.WithStack(err)
+	}
+	scp, route := p.ScopeRoute()
+	ed.set(scp, route, string(v))
+	return nil
+}
+
+// Walk implements Walker and iterates over all values sorted by scope and
+// route.
+func (ed ExportData) Walk(fn func(p config.Path, v []byte) error) error {
+	paths, err := ed.paths()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, pv := range paths {
+		if err := fn(pv.p, []byte(pv.v)); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+type pathValue struct {
+	p config.Path
+	v string
+}
+
+// paths parses and validates all entries and returns them sorted.
+func (ed ExportData) paths() ([]pathValue, error) {
+	pvs := make([]pathValue, 0, len(ed))
+	for route, scopes := range ed {
+		for scp, ids := range scopes {
+			for id, v := range ids {
+				var p config.Path
+				if err := p.ParseStrings(scp, id, route); err != nil {
+					return nil, errors.Wrapf(err, "[config/storage] ExportData with route %q scope %q and ID %q", route, scp, id)
+				}
+				pvs = append(pvs, pathValue{p: p, v: v})
+			}
+		}
+	}
+	sort.Slice(pvs, func(i, j int) bool {
+		return pvs[i].p.String() < pvs[j].p.String()
+	})
+	return pvs, nil
+}
+
+// checkMasked returns an error if a value equals the MaskValue or belongs to
+// one of the MaskRoutes, because importing it would overwrite a secret with
+// its placeholder.
+func (o ExportOptions) checkMasked(fnName string, paths []pathValue) error {
+	mv := o.MaskValue
+	if mv == "" {
+		mv = DefaultMaskValue
+	}
+	for _, pv := range paths {
+		if _, route := pv.p.ScopeRoute(); pv.v == mv || o.isMasked(route) {
+			return errors.NotAcceptable.Newf("[config/storage] %s: path %q contains a masked value", fnName, pv.p.String())
+		}
+	}
+	return nil
+}
+
+// Import writes all values of ExportData into the Storager. Pass the same
+// ExportOptions which created the export, because values equal to its
+// MaskValue or belonging to its MaskRoutes cannot be imported. All paths get
+// validated before the first write. If a write fails, the already written
+// paths get restored to their previous values in reverse order. Paths which
+// did not exist before get removed if the Storager implements config.Deleter.
+//
+// Import is not atomic: concurrent readers can see a partial import and a
+// failing restore leaves the partial import behind; the error lists the
+// affected paths. For the database storage use ImportDB, which runs in a
+// transaction.
+func Import(s config.Storager, ed ExportData, o ExportOptions) (err error) {
+	paths, err := ed.paths()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := o.checkMasked("Import", paths); err != nil {
+		return errors.WithStack(err)
+	}
+
+	type previous struct {
+		p     config.Path
+		v     []byte
+		found bool
+	}
+	undo := make([]previous, 0, len(paths))
+	defer func() {
+		if err == nil {
+			return
+		}
+		for i := len(undo) - 1; i >= 0; i-- {
+			u := undo[i]
+			var errU error
+			switch d, ok := s.(config.Deleter); {
+			case u.found:
+				errU = s.Set(u.p, u.v)
+			case ok:
+				errU = d.Delete(u.p)
+			}
+			if errU != nil {
+				err = errors.Wrapf(err, "[config/storage] Import rollback of path %q failed: %s", u.p.String(), errU)
+			}
+		}
+	}()
+
+	for _, pv := range paths {
+		v, found, errG := s.Get(pv.p)
+		if errG != nil {
+			return errors.Wrapf(errG, "[config/storage] Import.Get with path %q", pv.p.String())
+		}
+		undo = append(undo, previous{p: pv.p, v: v, found: found})
+		if err = s.Set(pv.p, []byte(pv.v)); err != nil {
+			return errors.Wrapf(err, "[config/storage] Import.Set with path %q", pv.p.String())
+		}
+	}
+	return nil
+}
+
+// DiffKind defines the kind of a difference between two ExportData.
+type DiffKind uint8
+
+// Available kinds of differences.
+const (
+	DiffAdded DiffKind = iota + 1
+	DiffRemoved
+	DiffChanged
+)
+
+// DiffEntry describes the difference of a single path.
+type DiffEntry struct {
+	Kind DiffKind
+	// Path contains the fully qualified path, e.g. stores/2/web/unsecure/base_url
+	Path  string
+	Left  string
+	Right string
+}
+
+// String returns a line in the format of a unified diff.
+func (de DiffEntry) String() string {
+	switch de.Kind {
+	case DiffAdded:
+		return "+ " + de.Path + ": " + strconv.Quote(de.Right)
+	case DiffRemoved:
+		return "- " + de.Path + ": " + strconv.Quote(de.Left)
+	}
+	return "~ " + de.Path + ": " + strconv.Quote(de.Left) + " => " + strconv.Quote(de.Right)
+}
+
+// Diff compares two ExportData and returns the differences sorted by path.
+// Paths only present in right are added, paths only present in left are
+// removed. Export both sides with the same MaskRoutes and StripEnvSuffix to
+// compare two environments, e.g. staging and production. Masked values only
+// show up as added or removed.
+func Diff(left, right ExportData) []DiffEntry {
+	var des []DiffEntry
+	walk := func(a, b ExportData, fn func(fq, va, vb string, ok bool)) {
+		for route, scopes := range a {
+			for scp, ids := range scopes {
+				for id, va := range ids {
+					vb, ok := b[route][scp][id]
+					fn(scp+sPathSeparator+id+sPathSeparator+route, va, vb, ok)
+				}
+			}
+		}
+	}
+	walk(left, right, func(fq, vl, vr string, ok bool) {
+		switch {
+		case !ok:
+			des = append(des, DiffEntry{Kind: DiffRemoved, Path: fq, Left: vl})
+		case vl != vr:
+			des = append(des, DiffEntry{Kind: DiffChanged, Path: fq, Left: vl, Right: vr})
+		}
+	})
+	walk(right, left, func(fq, vr, _ string, ok bool) {
+		if !ok {
+			des = append(des, DiffEntry{Kind: DiffAdded, Path: fq, Right: vr})
+		}
+	})
+	sort.Slice(des, func(i, j int) bool { return des[i].Path < des[j].Path })
+	return des
+}
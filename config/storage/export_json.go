@@ -0,0 +1,42 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build csall || json
+// +build csall json
+
+package storage
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/corestoreio/errors"
+)
+
+// WriteJSON writes the indented JSON representation of ExportData to w. The
+// output can be loaded with WithLoadJSON.
+func (ed ExportData) WriteJSON(w io.Writer) error {
+	je := json.NewEncoder(w)
+	je.SetIndent("", "  ")
+	return errors.WithStack(je.Encode(ed))
+}
+
+// DecodeJSON reads a JSON file in the format of WithLoadJSON into ExportData.
+func DecodeJSON(r io.Reader) (ExportData, error) {
+	ed := ExportData{}
+	if err := loadJSON(ed, r); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ed, nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+var _ storage.Walker = storage.NewMap().(storage.Walker)
+
+func newExportMap() config.Storager {
+	return storage.NewMap(
+		"default/0/web/unsecure/base_url", "http://eshop.dev/",
+		"stores/2/web/unsecure/base_url", "http://eshop.dev/de-de/",
+		"default/0/payment/stripe/secret_key", "cipher0",
+		"default/0/payment/stripe/secret_key/PRD", "cipherPRD",
+		"default/0/payment/stripe/secret_key/STAGING", "cipherSTAGING",
+		"websites/1/carriers/dhl/url/STAGING", "https://sandbox.dhl.com",
+	)
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all", func(t *testing.T) {
+		ed, err := storage.Export(newExportMap().(storage.Walker), storage.ExportOptions{})
+		assert.NoError(t, err)
+		assert.Exactly(t, storage.ExportData{
+			"web/unsecure/base_url": {
+				"default": {"0": "http://eshop.dev/"},
+				"stores":  {"2": "http://eshop.dev/de-de/"},
+			},
+			"payment/stripe/secret_key":         {"default": {"0": "cipher0"}},
+			"payment/stripe/secret_key/PRD":     {"default": {"0": "cipherPRD"}},
+			"payment/stripe/secret_key/STAGING": {"default": {"0": "cipherSTAGING"}},
+			"carriers/dhl/url/STAGING":          {"websites": {"1": "https://sandbox.dhl.com"}},
+		}, ed)
+	})
+
+	t.Run("masked and env PRD", func(t *testing.T) {
+		ed, err := storage.Export(newExportMap().(storage.Walker), storage.ExportOptions{
+			MaskRoutes: []string{"payment/stripe"},
+			EnvName:    "PRD",
+		})
+		assert.NoError(t, err)
+		assert.Exactly(t, storage.ExportData{
+			"web/unsecure/base_url": {
+				"default": {"0": "http://eshop.dev/"},
+				"stores":  {"2": "http://eshop.dev/de-de/"},
+			},
+			"payment/stripe/secret_key":     {"default": {"0": storage.DefaultMaskValue}},
+			"payment/stripe/secret_key/PRD": {"default": {"0": storage.DefaultMaskValue}},
+		}, ed)
+	})
+
+	t.Run("strip env STAGING", func(t *testing.T) {
+		ed, err := storage.Export(newExportMap().(storage.Walker), storage.ExportOptions{
+			EnvName:        "STAGING",
+			StripEnvSuffix: true,
+		})
+		assert.NoError(t, err)
+		assert.Exactly(t, storage.ExportData{
+			"web/unsecure/base_url": {
+				"default": {"0": "http://eshop.dev/"},
+				"stores":  {"2": "http://eshop.dev/de-de/"},
+			},
+			"payment/stripe/secret_key": {"default": {"0": "cipherSTAGING"}},
+			"carriers/dhl/url":          {"websites": {"1": "https://sandbox.dhl.com"}},
+		}, ed)
+	})
+}
+
+// failingStorage fails to write the path failAt.
+type failingStorage struct {
+	config.Storager
+	failAt string
+}
+
+func (fs failingStorage) Set(p config.Path, v []byte) error {
+	if _, route := p.ScopeRoute(); route == fs.failAt {
+		return errors.WriteFailed.Newf("write failed: %q", route)
+	}
+	return fs.Storager.Set(p, v)
+}
+
+func (fs failingStorage) Delete(p config.Path) error {
+	return fs.Storager.(config.Deleter).Delete(p)
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	ed := storage.ExportData{
+		"aa/bb/cc": {"default": {"0": "new-aa"}, "stores": {"3": "new-aa-3"}},
+		"xx/yy/zz": {"websites": {"1": "new-xx"}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		s := storage.NewMap("default/0/aa/bb/cc", "old-aa")
+		assert.NoError(t, storage.Import(s, ed, storage.ExportOptions{}))
+		validateFoundGet(t, s, scope.DefaultTypeID, "aa/bb/cc", "new-aa")
+		validateFoundGet(t, s, scope.Store.WithID(3), "aa/bb/cc", "new-aa-3")
+		validateFoundGet(t, s, scope.Website.WithID(1), "xx/yy/zz", "new-xx")
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		s := storage.NewMap("default/0/aa/bb/cc", "old-aa")
+		err := storage.Import(failingStorage{Storager: s, failAt: "xx/yy/zz"}, ed, storage.ExportOptions{})
+		assert.ErrorIsKind(t, errors.WriteFailed, err)
+		validateFoundGet(t, s, scope.DefaultTypeID, "aa/bb/cc", "old-aa")
+		validateNotFoundGet(t, s, scope.Store.WithID(3), "aa/bb/cc")
+		validateNotFoundGet(t, s, scope.Website.WithID(1), "xx/yy/zz")
+	})
+
+	t.Run("invalid path writes nothing", func(t *testing.T) {
+		s := storage.NewMap()
+		err := storage.Import(s, storage.ExportData{
+			"aa/bb/cc": {"default": {"0": "new-aa"}},
+			"xx":       {"default": {"0": "invalid"}},
+		}, storage.ExportOptions{})
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		validateNotFoundGet(t, s, scope.DefaultTypeID, "aa/bb/cc")
+	})
+
+	t.Run("masked value", func(t *testing.T) {
+		err := storage.Import(storage.NewMap(), storage.ExportData{
+			"aa/bb/cc": {"default": {"0": storage.DefaultMaskValue}},
+		}, storage.ExportOptions{})
+		assert.ErrorIsKind(t, errors.NotAcceptable, err)
+	})
+
+	t.Run("custom mask value", func(t *testing.T) {
+		eo := storage.ExportOptions{MaskRoutes: []string{"payment/stripe"}, MaskValue: "<redacted>"}
+		ed, err := storage.Export(storage.NewMap(
+			"default/0/payment/stripe/secret_key", "sk_live_4711",
+			"default/0/web/unsecure/base_url", "https://shop.com/",
+		).(storage.Walker), eo)
+		assert.NoError(t, err)
+		assert.Exactly(t, "<redacted>", ed["payment/stripe/secret_key"]["default"]["0"])
+
+		s := storage.NewMap("default/0/payment/stripe/secret_key", "sk_live_4711")
+		err = storage.Import(s, ed, eo)
+		assert.ErrorIsKind(t, errors.NotAcceptable, err)
+		validateFoundGet(t, s, scope.DefaultTypeID, "payment/stripe/secret_key", "sk_live_4711")
+
+		// the placeholder gets detected even without the MaskRoutes.
+		err = storage.Import(s, ed, storage.ExportOptions{MaskValue: "<redacted>"})
+		assert.ErrorIsKind(t, errors.NotAcceptable, err)
+		validateFoundGet(t, s, scope.DefaultTypeID, "payment/stripe/secret_key", "sk_live_4711")
+	})
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	staging, err := storage.Export(newExportMap().(storage.Walker), storage.ExportOptions{
+		MaskRoutes: []string{"payment/stripe/secret_key"}, EnvName: "STAGING", StripEnvSuffix: true,
+	})
+	assert.NoError(t, err)
+
+	production, err := storage.Export(storage.NewMap(
+		"default/0/web/unsecure/base_url", "https://shop.com/",
+		"default/0/payment/stripe/secret_key/PRD", "cipherPRD",
+		"stores/5/general/locale/code", "de_CH",
+	).(storage.Walker), storage.ExportOptions{
+		MaskRoutes: []string{"payment/stripe/secret_key"}, EnvName: "PRD", StripEnvSuffix: true,
+	})
+	assert.NoError(t, err)
+
+	des := storage.Diff(staging, production)
+	assert.Exactly(t, []storage.DiffEntry{
+		{Kind: storage.DiffChanged, Path: "default/0/web/unsecure/base_url", Left: "http://eshop.dev/", Right: "https://shop.com/"},
+		{Kind: storage.DiffRemoved, Path: "stores/2/web/unsecure/base_url", Left: "http://eshop.dev/de-de/"},
+		{Kind: storage.DiffAdded, Path: "stores/5/general/locale/code", Right: "de_CH"},
+		{Kind: storage.DiffRemoved, Path: "websites/1/carriers/dhl/url", Left: "https://sandbox.dhl.com"},
+	}, des)
+	assert.Exactly(t, `~ default/0/web/unsecure/base_url: "http://eshop.dev/" => "https://shop.com/"`, des[0].String())
+	assert.Exactly(t, `- stores/2/web/unsecure/base_url: "http://eshop.dev/de-de/"`, des[1].String())
+	assert.Exactly(t, `+ stores/5/general/locale/code: "de_CH"`, des[2].String())
+
+	assert.Len(t, storage.Diff(production, production), 0)
+}
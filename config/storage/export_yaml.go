@@ -0,0 +1,44 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build csall || yaml
+// +build csall yaml
+
+package storage
+
+import (
+	"io"
+
+	"github.com/corestoreio/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// WriteYAML writes the YAML representation of ExportData to w. The output can
+// be loaded with WithLoadYAML.
+func (ed ExportData) WriteYAML(w io.Writer) error {
+	ye := yaml.NewEncoder(w)
+	if err := ye.Encode(ed); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(ye.Close())
+}
+
+// DecodeYAML reads a YAML file in the format of WithLoadYAML into ExportData.
+func DecodeYAML(r io.Reader) (ExportData, error) {
+	ed := ExportData{}
+	if err := loadYAML(ed, r); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ed, nil
+}
@@ -17,6 +17,8 @@
 package storage_test
 
 import (
+	"bytes"
+	"os"
 	"testing"
 
 	"github.com/corestoreio/errors"
@@ -74,3 +76,24 @@ func TestWithLoadJSON(t *testing.T) {
 	t.Run("malformed_v2t_dataIF", runner("malformed_v2t_dataIF.json", errors.CorruptData,
 		`WithLoadJSON unexpected data in []interface {}{}`))
 }
+
+func TestExportData_WriteJSON(t *testing.T) {
+	f, err := os.Open("testdata/example.json")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	ed, err := storage.DecodeJSON(f)
+	assert.NoError(t, err)
+	assert.Exactly(t, "AUserName", ed["payment/stripe/user_name"]["default"]["0"])
+
+	var buf bytes.Buffer
+	assert.NoError(t, ed.WriteJSON(&buf))
+
+	ed2, err := storage.DecodeJSON(&buf)
+	assert.NoError(t, err)
+	assert.Exactly(t, ed, ed2)
+
+	sm := storage.NewMap()
+	assert.NoError(t, storage.Import(sm, ed2, storage.ExportOptions{}))
+	validateFoundGet(t, sm, scope.Store.WithID(11), "payment/stripe/user_name", "SO11Username")
+}
@@ -92,6 +92,17 @@ func (c *lruCache) Get(p config.Path) (v []byte, found bool, err error) {
 	return
 }
 
+// Delete removes a key from the cache. Implements config.Deleter.
+func (c *lruCache) Delete(p config.Path) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ele, hit := c.cache[makeCacheKey(p.ScopeRoute())]; hit {
+		c.removeElement(ele)
+	}
+	return nil
+}
+
 func (c *lruCache) removeOldest() {
 	ele := c.ll.Back()
 	if ele == nil {
@@ -141,3 +141,16 @@ func TestLRUNew_Parallel(t *testing.T) {
 		})
 	})
 }
+
+func TestLRUDelete(t *testing.T) {
+	p := config.MustMakePath("aa/bb/cc").BindStore(3)
+	lru := storage.NewLRU(2)
+	assert.NoError(t, lru.Set(p, testLRUData))
+	assert.NoError(t, lru.(config.Deleter).Delete(p))
+	assert.NoError(t, lru.(config.Deleter).Delete(p), "deleting a missing key")
+
+	val, ok, err := lru.Get(p)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, val)
+}
@@ -91,3 +91,23 @@ func (sp *kvmap) Keys(ret ...string) []string {
 	}
 	return ret
 }
+
+// Delete implements config.Deleter and removes a path.
+func (sp *kvmap) Delete(p config.Path) error {
+	sp.Lock()
+	delete(sp.kv, makeCacheKey(p.ScopeRoute()))
+	sp.Unlock()
+	return nil
+}
+
+// Walk implements Walker and iterates over all stored values sorted by scope
+// and route.
+func (sp *kvmap) Walk(fn func(p config.Path, v []byte) error) error {
+	sp.RLock()
+	ed := make(ExportData, len(sp.kv))
+	for k, v := range sp.kv {
+		ed.set(k.scp, k.route, v)
+	}
+	sp.RUnlock()
+	return ed.Walk(fn)
+}
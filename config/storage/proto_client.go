@@ -0,0 +1,153 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build csall || proto
+// +build csall proto
+
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"google.golang.org/grpc"
+)
+
+// ProtoClientOptions applies optional settings to the ProtoClient.
+type ProtoClientOptions struct {
+	// RequestTimeout limits the duration of Set, Get and GetBatch. Default 5s.
+	RequestTimeout time.Duration
+	// CallOptions get applied to each request.
+	CallOptions []grpc.CallOption
+}
+
+// ProtoClient implements config.Storager and uses a central configuration
+// service via gRPC as storage. It gets mostly used as level 2 storage of
+// config.Service, together with a local LRU as level 1:
+//
+//	pc := storage.NewProtoClient(config.NewProtoServiceClient(conn), storage.ProtoClientOptions{})
+//	lru := storage.NewLRU(2048)
+//	srv, err := config.NewService(pc, config.Options{Level1: lru})
+//	go pc.Watch(ctx, lru, "general", "web") // keeps the LRU up to date
+type ProtoClient struct {
+	client  config.ProtoServiceClient
+	options ProtoClientOptions
+}
+
+// NewProtoClient creates a new gRPC backed storage.
+func NewProtoClient(c config.ProtoServiceClient, o ProtoClientOptions) *ProtoClient {
+	if o.RequestTimeout == 0 {
+		o.RequestTimeout = 5 * time.Second
+	}
+	return &ProtoClient{
+		client:  c,
+		options: o,
+	}
+}
+
+// Set writes the value on the configuration server.
+func (pc *ProtoClient) Set(p config.Path, value []byte) error {
+	fq, err := p.FQ()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pc.options.RequestTimeout)
+	defer cancel()
+	if _, err := pc.client.Set(ctx, &config.ProtoValue{Path: fq, Data: value}, pc.options.CallOptions...); err != nil {
+		return errors.Wrapf(err, "[config/storage] ProtoClient.Set with path %q", fq)
+	}
+	return nil
+}
+
+// Get reads the value from the configuration server.
+func (pc *ProtoClient) Get(p config.Path) (v []byte, found bool, err error) {
+	fq, err := p.FQ()
+	if err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pc.options.RequestTimeout)
+	defer cancel()
+	pv, err := pc.client.Get(ctx, &config.ProtoPath{Path: fq}, pc.options.CallOptions...)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "[config/storage] ProtoClient.Get with path %q", fq)
+	}
+	return pv.Data, pv.Found, nil
+}
+
+// GetBatch reads several values with one request. The returned values have the
+// same order as the paths.
+func (pc *ProtoClient) GetBatch(ps ...config.Path) ([]*config.ProtoValue, error) {
+	req := &config.ProtoPaths{Paths: make([]string, len(ps))}
+	for i, p := range ps {
+		fq, err := p.FQ()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		req.Paths[i] = fq
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pc.options.RequestTimeout)
+	defer cancel()
+	pvs, err := pc.client.GetBatch(ctx, req, pc.options.CallOptions...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[config/storage] ProtoClient.GetBatch with paths %q", req.Paths)
+	}
+	return pvs.Values, nil
+}
+
+// Watch subscribes to the changes of the routes on the configuration server
+// and writes the new values into `level1`, e.g. a local LRU cache. Values which
+// do not exist anymore on the server get removed from `level1`, hence it must
+// implement config.Deleter. A route can be a prefix like `general` or a fully
+// qualified path. Watch blocks until the context gets canceled or the stream
+// fails.
+func (pc *ProtoClient) Watch(ctx context.Context, level1 config.Setter, routes ...string) error {
+	d, ok := level1.(config.Deleter)
+	if !ok {
+		return errors.NotImplemented.Newf("[config/storage] ProtoClient.Watch level1 %T must implement config.Deleter", level1)
+	}
+	stream, err := pc.client.Watch(ctx, &config.ProtoPaths{Paths: routes}, pc.options.CallOptions...)
+	if err != nil {
+		return errors.Wrapf(err, "[config/storage] ProtoClient.Watch with routes %q", routes)
+	}
+	for {
+		pp, err := stream.Recv()
+		switch {
+		case err == io.EOF || ctx.Err() != nil:
+			return nil
+		case err != nil:
+			return errors.Wrapf(err, "[config/storage] ProtoClient.Watch with routes %q", routes)
+		}
+
+		var p config.Path
+		if err := p.Parse(pp.Path); err != nil {
+			return errors.WithStack(err)
+		}
+		v, found, err := pc.Get(p)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if !found {
+			if err := d.Delete(p); err != nil {
+				return errors.Wrapf(err, "[config/storage] ProtoClient.Watch.level1.Delete with path %q", pp.Path)
+			}
+			continue
+		}
+		if err := level1.Set(p, v); err != nil {
+			return errors.Wrapf(err, "[config/storage] ProtoClient.Watch.level1.Set with path %q", pp.Path)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build csall || proto
+// +build csall proto
+
+package storage_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var _ config.Storager = (*storage.ProtoClient)(nil)
+
+// testToken sends the bearer token with each request.
+type testToken string
+
+func (tt testToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "bearer " + string(tt)}, nil
+}
+
+func (testToken) RequireTransportSecurity() bool { return false }
+
+func testAuthFunc(ctx context.Context, _ string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if a := md.Get("authorization"); len(a) != 1 || a[0] != "bearer secret" {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return ctx, nil
+}
+
+func newTestProtoClient(t *testing.T) (*config.Service, *storage.ProtoClient, func()) {
+	srv, err := config.NewService(storage.NewMap(), config.Options{EnablePubSub: true})
+	assert.NoError(t, err)
+	pc, closer := newTestProtoServer(t, srv, config.ProtoServerOptions{AuthFunc: testAuthFunc}, testToken("secret"))
+	return srv, pc, func() {
+		closer()
+		assert.NoError(t, srv.Close())
+	}
+}
+
+func newTestProtoServer(t *testing.T, srv *config.Service, o config.ProtoServerOptions, tt testToken) (*storage.ProtoClient, func()) {
+	lis := bufconn.Listen(1 << 16)
+	gs := grpc.NewServer()
+	config.RegisterProtoServiceServer(gs, config.NewProtoServiceServer(srv, o))
+	go func() { _ = gs.Serve(lis) }()
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	assert.NoError(t, err)
+
+	return storage.NewProtoClient(config.NewProtoServiceClient(conn), storage.ProtoClientOptions{
+			CallOptions: []grpc.CallOption{grpc.PerRPCCredentials(tt)},
+		}), func() {
+			assert.NoError(t, conn.Close())
+			gs.Stop()
+		}
+}
+
+func TestProtoClient(t *testing.T) {
+	srv, pc, closer := newTestProtoClient(t)
+	defer closer()
+
+	pStore := config.MustMakePathWithScope(scope.Store.WithID(2), "general/locale/timezone")
+	pWebsite := config.MustMakePathWithScope(scope.Website.WithID(1), "general/locale/timezone")
+
+	t.Run("Set and Get", func(t *testing.T) {
+		assert.NoError(t, pc.Set(pStore, []byte(`Europe/Berlin`)))
+
+		v, found, err := pc.Get(pStore)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Exactly(t, []byte(`Europe/Berlin`), v)
+
+		v, found, err = pc.Get(pWebsite)
+		assert.NoError(t, err)
+		assert.False(t, found, "scope must be taken into account")
+		assert.Nil(t, v)
+
+		assert.Exactly(t, `Europe/Berlin`, srv.Get(pStore).UnsafeStr())
+	})
+
+	t.Run("GetBatch", func(t *testing.T) {
+		vs, err := pc.GetBatch(pStore, pWebsite)
+		assert.NoError(t, err)
+		assert.Len(t, vs, 2)
+		assert.Exactly(t, "stores/2/general/locale/timezone", vs[0].Path)
+		assert.True(t, vs[0].Found)
+		assert.False(t, vs[1].Found)
+	})
+
+	t.Run("Level 2 storage of a Service", func(t *testing.T) {
+		edge, err := config.NewService(pc, config.Options{Level1: storage.NewLRU(10)})
+		assert.NoError(t, err)
+		defer func() { assert.NoError(t, edge.Close()) }()
+		assert.Exactly(t, `Europe/Berlin`, edge.Get(pStore).UnsafeStr())
+	})
+
+	t.Run("Watch updates level 1", func(t *testing.T) {
+		lru := storage.NewLRU(10)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- pc.Watch(ctx, lru, "stores/2/general") }()
+
+		pCountry := config.MustMakePathWithScope(scope.Store.WithID(2), "general/country/default")
+		deadline := time.Now().Add(2 * time.Second)
+		var v []byte
+		var found bool
+		var err error
+		for !found && time.Now().Before(deadline) {
+			// the subscription gets registered asynchronously
+			assert.NoError(t, srv.Set(pCountry, []byte(`DE`)))
+			time.Sleep(20 * time.Millisecond)
+			v, found, err = lru.Get(pCountry)
+			assert.NoError(t, err)
+		}
+		assert.True(t, found)
+		assert.Exactly(t, []byte(`DE`), v)
+
+		cancel()
+		assert.NoError(t, <-done)
+	})
+}
+
+// unreachableStorage returns for each request an error which contains internal
+// details of the backend.
+type unreachableStorage struct{}
+
+func (unreachableStorage) Set(config.Path, []byte) error {
+	return errors.New("dial tcp 10.0.0.7:3306: password=s3cr3t")
+}
+
+func (unreachableStorage) Get(config.Path) ([]byte, bool, error) {
+	return nil, false, errors.New("dial tcp 10.0.0.7:3306: password=s3cr3t")
+}
+
+func TestProtoServer_Errors(t *testing.T) {
+	p := config.MustMakePathWithScope(scope.Store.WithID(2), "general/locale/timezone")
+
+	t.Run("AuthFunc missing", func(t *testing.T) {
+		srv, err := config.NewService(storage.NewMap(), config.Options{})
+		assert.NoError(t, err)
+		defer func() { assert.NoError(t, srv.Close()) }()
+		pc, closer := newTestProtoServer(t, srv, config.ProtoServerOptions{}, testToken("secret"))
+		defer closer()
+
+		_, _, err = pc.Get(p)
+		assert.Exactly(t, codes.Unauthenticated, status.Code(errors.Cause(err)), "%+v", err)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		srv, err := config.NewService(storage.NewMap(), config.Options{})
+		assert.NoError(t, err)
+		defer func() { assert.NoError(t, srv.Close()) }()
+		pc, closer := newTestProtoServer(t, srv, config.ProtoServerOptions{AuthFunc: testAuthFunc}, testToken("guessed"))
+		defer closer()
+
+		_, err = pc.GetBatch(p)
+		assert.Exactly(t, codes.Unauthenticated, status.Code(errors.Cause(err)), "%+v", err)
+		err = pc.Set(p, []byte(`UTC`))
+		assert.Exactly(t, codes.Unauthenticated, status.Code(errors.Cause(err)), "%+v", err)
+	})
+
+	t.Run("internal error hides details", func(t *testing.T) {
+		srv, err := config.NewService(unreachableStorage{}, config.Options{})
+		assert.NoError(t, err)
+		defer func() { assert.NoError(t, srv.Close()) }()
+		pc, closer := newTestProtoServer(t, srv, config.ProtoServerOptions{InsecureSkipAuth: true}, "")
+		defer closer()
+
+		_, _, err = pc.Get(p)
+		st := status.Convert(errors.Cause(err))
+		assert.Exactly(t, codes.Internal, st.Code(), "%+v", err)
+		assert.Exactly(t, "[config] internal error", st.Message())
+	})
+}
+
+// removedValueClient reports one changed path via Watch whose value does not
+// exist anymore on the server.
+type removedValueClient struct {
+	config.ProtoServiceClient
+	path string
+}
+
+func (rc removedValueClient) Get(context.Context, *config.ProtoPath, ...grpc.CallOption) (*config.ProtoValue, error) {
+	return &config.ProtoValue{Path: rc.path}, nil
+}
+
+func (rc removedValueClient) Watch(context.Context, *config.ProtoPaths, ...grpc.CallOption) (config.ProtoService_WatchClient, error) {
+	return &removedValueStream{paths: []string{rc.path}}, nil
+}
+
+type removedValueStream struct {
+	grpc.ClientStream
+	paths []string
+}
+
+func (rs *removedValueStream) Recv() (*config.ProtoPath, error) {
+	if len(rs.paths) == 0 {
+		return nil, io.EOF
+	}
+	p := rs.paths[0]
+	rs.paths = rs.paths[1:]
+	return &config.ProtoPath{Path: p}, nil
+}
+
+func TestProtoClient_Watch_RemovedValue(t *testing.T) {
+	p := config.MustMakePathWithScope(scope.Store.WithID(2), "general/country/default")
+	fq, err := p.FQ()
+	assert.NoError(t, err)
+	pc := storage.NewProtoClient(removedValueClient{path: fq}, storage.ProtoClientOptions{})
+
+	t.Run("evicts level 1", func(t *testing.T) {
+		lru := storage.NewLRU(10)
+		assert.NoError(t, lru.Set(p, []byte(`DE`)))
+
+		assert.NoError(t, pc.Watch(context.Background(), lru, "stores/2/general"))
+
+		v, found, err := lru.Get(p)
+		assert.NoError(t, err)
+		assert.False(t, found, "stale value must be removed")
+		assert.Nil(t, v)
+	})
+
+	t.Run("level 1 without Deleter", func(t *testing.T) {
+		err := pc.Watch(context.Background(), struct{ config.Setter }{}, "stores/2/general")
+		assert.ErrorIsKind(t, errors.NotImplemented, err)
+	})
+}
@@ -0,0 +1,156 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build csall || redis
+// +build csall redis
+
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/gomodule/redigo/redis"
+)
+
+// RedisBroadcastDefaultChannel defines the default Redis pub/sub channel.
+const RedisBroadcastDefaultChannel = "csconfig"
+
+// RedisBroadcastOptions applies options to the RedisBroadcast type.
+type RedisBroadcastOptions struct {
+	// Channel defines the name of the Redis pub/sub channel, default
+	// RedisBroadcastDefaultChannel.
+	Channel string
+	// NodeID identifies the current process to skip its own messages. Default
+	// a random ID.
+	NodeID string
+}
+
+// RedisBroadcast implements config.Broadcaster via Redis pub/sub. A message
+// contains the node ID and the fully qualified path, separated by a space.
+type RedisBroadcast struct {
+	pool    *redis.Pool
+	options RedisBroadcastOptions
+
+	mu     sync.Mutex
+	psc    *redis.PubSubConn
+	closed bool
+}
+
+// NewRedisBroadcast creates a new Redis backed broadcaster. The pool gets used
+// for publishing and one of its connections for listening.
+func NewRedisBroadcast(pool *redis.Pool, o RedisBroadcastOptions) (*RedisBroadcast, error) {
+	if o.Channel == "" {
+		o.Channel = RedisBroadcastDefaultChannel
+	}
+	if o.NodeID == "" {
+		var id [8]byte
+		if _, err := rand.Read(id[:]); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		o.NodeID = hex.EncodeToString(id[:])
+	}
+	if strings.ContainsRune(o.NodeID, ' ') {
+		return nil, errors.NotValid.Newf("[config/storage] RedisBroadcast NodeID %q must not contain a space", o.NodeID)
+	}
+	return &RedisBroadcast{
+		pool:    pool,
+		options: o,
+	}, nil
+}
+
+// Broadcast publishes the path to all other processes.
+func (rb *RedisBroadcast) Broadcast(p config.Path) error {
+	fq, err := p.FQ()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	conn := rb.pool.Get()
+	defer conn.Close()
+	if _, err := conn.Do("PUBLISH", rb.options.Channel, rb.options.NodeID+" "+fq); err != nil {
+		return errors.Wrapf(err, "[config/storage] RedisBroadcast.Broadcast with path %q", fq)
+	}
+	return nil
+}
+
+// Listen subscribes to the channel and calls fn for each path changed by
+// another process.
+func (rb *RedisBroadcast) Listen(fn func(config.Path) error) error {
+	rb.mu.Lock()
+	if rb.closed {
+		rb.mu.Unlock()
+		return nil
+	}
+	psc := &redis.PubSubConn{Conn: rb.pool.Get()}
+	if err := psc.Subscribe(rb.options.Channel); err != nil {
+		rb.mu.Unlock()
+		_ = psc.Close()
+		return errors.Wrapf(err, "[config/storage] RedisBroadcast.Listen.Subscribe channel %q", rb.options.Channel)
+	}
+	rb.psc = psc
+	rb.mu.Unlock()
+	defer func() {
+		rb.mu.Lock() // Close might write concurrently to the connection
+		_ = psc.Close()
+		rb.psc = nil
+		rb.mu.Unlock()
+	}()
+
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			node, fq, ok := strings.Cut(string(v.Data), " ")
+			if !ok || node == rb.options.NodeID {
+				continue
+			}
+			var p config.Path
+			if err := p.Parse(fq); err != nil {
+				return errors.WithStack(err)
+			}
+			if err := fn(p); err != nil {
+				return errors.WithStack(err)
+			}
+		case redis.Subscription:
+			if v.Count == 0 {
+				return nil // unsubscribed via Close
+			}
+		case error:
+			rb.mu.Lock()
+			closed := rb.closed
+			rb.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return errors.Wrapf(v, "[config/storage] RedisBroadcast.Listen.Receive channel %q", rb.options.Channel)
+		}
+	}
+}
+
+// Close terminates Listen.
+func (rb *RedisBroadcast) Close() error {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	if rb.closed {
+		return nil
+	}
+	rb.closed = true
+	if rb.psc == nil {
+		return nil
+	}
+	return errors.WithStack(rb.psc.Unsubscribe())
+}
@@ -0,0 +1,94 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build csall || redis
+// +build csall redis
+
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/gomodule/redigo/redis"
+)
+
+var _ config.Broadcaster = (*storage.RedisBroadcast)(nil)
+
+type testMessageReceiver chan config.Path
+
+func (tr testMessageReceiver) MessageConfig(p config.Path) error {
+	tr <- p
+	return nil
+}
+
+func TestRedisBroadcast(t *testing.T) {
+	mr := miniredis.RunT(t)
+	pool := &redis.Pool{
+		Dial: func() (redis.Conn, error) { return redis.Dial("tcp", mr.Addr()) },
+	}
+	defer pool.Close()
+
+	level2 := storage.NewMap() // shared by both processes, like the DB
+	newPod := func(nodeID string) (*config.Service, config.Storager) {
+		rb, err := storage.NewRedisBroadcast(pool, storage.RedisBroadcastOptions{NodeID: nodeID})
+		assert.NoError(t, err)
+		lru := storage.NewLRU(10)
+		srv, err := config.NewService(level2, config.Options{
+			Level1:       lru,
+			EnablePubSub: true,
+			Broadcaster:  rb,
+		})
+		assert.NoError(t, err)
+		return srv, lru
+	}
+	podA, _ := newPod("a")
+	podB, lruB := newPod("b")
+	defer func() {
+		assert.NoError(t, podA.Close())
+		assert.NoError(t, podB.Close())
+	}()
+
+	p := config.MustMakePathWithScope(scope.Website.WithID(1), "web/cookie/domain")
+	assert.NoError(t, podA.Set(p, []byte(`a.example.com`)))
+	assert.Exactly(t, `a.example.com`, podB.Get(p).UnsafeStr()) // fills B's LRU
+
+	changed := make(testMessageReceiver, 10)
+	_, err := podB.Subscribe("websites/1/web/cookie", changed)
+	assert.NoError(t, err)
+
+	// wait until both listeners have subscribed to the channel
+	deadline := time.Now().Add(2 * time.Second)
+	for mr.PubSubNumSub(storage.RedisBroadcastDefaultChannel)[storage.RedisBroadcastDefaultChannel] < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	assert.NoError(t, podA.Set(p, []byte(`b.example.com`)))
+
+	select {
+	case cp := <-changed:
+		assert.Exactly(t, "websites/1/web/cookie/domain", cp.String())
+	case <-time.After(2 * time.Second):
+		t.Fatal("pod B did not receive the change")
+	}
+	_, found, err := lruB.Get(p)
+	assert.NoError(t, err)
+	assert.False(t, found, "level 1 of pod B must be evicted")
+	assert.Exactly(t, `b.example.com`, podB.Get(p).UnsafeStr())
+}
@@ -17,6 +17,8 @@
 package storage_test
 
 import (
+	"bytes"
+	"os"
 	"testing"
 
 	"github.com/corestoreio/errors"
@@ -115,3 +117,24 @@ func TestWithLoadFieldMetaYAML(t *testing.T) {
 		assert.True(t, errors.NotFound.Match(err), "%+v", err)
 	})
 }
+
+func TestExportData_WriteYAML(t *testing.T) {
+	f, err := os.Open("testdata/example.yaml")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	ed, err := storage.DecodeYAML(f)
+	assert.NoError(t, err)
+	assert.Exactly(t, "http://eshop.dev/de-de/", ed["web/unsecure/base_url"]["stores"]["2"])
+
+	var buf bytes.Buffer
+	assert.NoError(t, ed.WriteYAML(&buf))
+
+	ed2, err := storage.DecodeYAML(&buf)
+	assert.NoError(t, err)
+	assert.Exactly(t, ed, ed2)
+
+	sm := storage.NewMap()
+	assert.NoError(t, storage.Import(sm, ed2, storage.ExportOptions{}))
+	validateFoundGet(t, sm, scope.Store.WithID(2), "web/unsecure/base_url", "http://eshop.dev/de-de/")
+}
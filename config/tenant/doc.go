@@ -0,0 +1,40 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tenant hosts several independent shops, called tenants, within one
+// process.
+//
+// Each tenant gets its own config.Service with its own level 1 and level 2
+// storage and its own config.Options, e.g. the EnvName. The default values and
+// scope permissions of the config.Sections get shared by all tenants. The
+// Registry creates the services lazily on first access and limits the number
+// of tenants and the number of paths each tenant can write.
+//
+// The tenant ID travels within the context.Context, see WithContext and the
+// middleware Registry.WithTenant. Registry.Scoped combines the tenant with the
+// website and store ID of the scope package.
+//
+// The scoped services of the net/* packages cache their configuration per
+// scope ID and hence must not be shared between tenants. Create one instance
+// per tenant with the config.Service of the tenant as config.Scoper via
+// Registry.PerTenant:
+//
+//	h := reg.WithTenant(reg.PerTenant(func(tenantID string, cfg *config.Service) (http.Handler, error) {
+//		authSrv, err := auth.New(cfg)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return authSrv.WithAuthentication(next), nil
+//	}))
+package tenant
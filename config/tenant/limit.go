@@ -0,0 +1,58 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"sync"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+)
+
+// limitedStorage limits the number of distinct paths which can be written to
+// the wrapped Storager. Paths stored before the creation of the
+// limitedStorage do not get counted.
+type limitedStorage struct {
+	config.Storager
+	max int
+
+	mu    sync.Mutex
+	paths map[string]struct{}
+}
+
+func newLimitedStorage(s config.Storager, max int) *limitedStorage {
+	return &limitedStorage{
+		Storager: s,
+		max:      max,
+		paths:    map[string]struct{}{},
+	}
+}
+
+// Set writes the value if the path has already been written or the limit has
+// not been reached.
+func (ls *limitedStorage) Set(p config.Path, v []byte) error {
+	key := p.String()
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	_, ok := ls.paths[key]
+	if !ok && len(ls.paths) >= ls.max {
+		return errors.Exceeded.Newf("[tenant] Path %q cannot be written: maximum of %d paths reached", key, ls.max)
+	}
+	if err := ls.Storager.Set(p, v); err != nil {
+		return errors.WithStack(err)
+	}
+	ls.paths[key] = struct{}{}
+	return nil
+}
@@ -0,0 +1,322 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/net/mw"
+	"github.com/corestoreio/pkg/store/scope"
+)
+
+// MaxIDLength defines the maximum length of a tenant ID.
+const MaxIDLength = 64
+
+// Options applies options to the Registry.
+type Options struct {
+	// Sections provides the default values and scope permissions for all
+	// tenants. The Sections get converted once and all config.Services share
+	// the result read-only.
+	Sections config.Sections
+	// Storage returns the optional level 1 and the required level 2 storage of
+	// a tenant. It should return a NotFound error for unknown tenants.
+	// Required.
+	Storage func(tenantID string) (level1, level2 config.Storager, err error)
+	// ServiceOptions returns the optional config.Options of a tenant, e.g. its
+	// EnvName. Field Level1 gets set by Storage.
+	ServiceOptions func(tenantID string) config.Options
+	// LoadData returns the optional LoadDataOptions of a tenant, e.g. to load
+	// its configuration files.
+	LoadData func(tenantID string) []config.LoadDataOption
+	// MaxTenants limits the number of tenants. Zero means unlimited.
+	MaxTenants int
+	// MaxPaths limits the number of distinct paths a tenant can write to its
+	// level 2 storage, including the paths written by LoadData. Zero means
+	// unlimited.
+	MaxPaths int
+	// TenantFunc extracts the tenant ID from a request in middleware
+	// WithTenant, e.g. from the host name. If nil, the tenant ID must already
+	// be set in the request context via WithContext.
+	TenantFunc func(r *http.Request) (string, error)
+	// ErrorHandler gets called by the middlewares if the tenant cannot be
+	// resolved. Defaults to a handler which maps the error kind to a status
+	// code without printing the error.
+	ErrorHandler mw.ErrorHandler
+	// Log if set, gets passed to the config.Service of each tenant with the
+	// field "tenant", unless ServiceOptions provides a logger.
+	Log log.Logger
+}
+
+// Registry manages the config.Service of each tenant. Safe for concurrent
+// use.
+type Registry struct {
+	o Options
+	// sections contains the default values of Options.Sections, shared
+	// read-only by all tenants.
+	sections config.LoadDataOption
+
+	mu       sync.RWMutex
+	closed   bool
+	services map[string]*tenant
+	handlers []*perTenant
+}
+
+// tenant gets created once per tenant ID. Its config.Service gets created
+// without holding the lock of the Registry. Fields s and err must only be read
+// after ready has been closed.
+type tenant struct {
+	ready chan struct{}
+	s     *config.Service
+	err   error
+}
+
+// service waits until the config.Service has been created.
+func (t *tenant) service() (*config.Service, error) {
+	<-t.ready
+	return t.s, t.err
+}
+
+// NewRegistry creates a new Registry. The Sections get validated.
+func NewRegistry(o Options) (*Registry, error) {
+	if o.Storage == nil {
+		return nil, errors.NotValid.Newf("[tenant] NewRegistry: Storage function is required")
+	}
+	sections, err := config.WithSharedSections(o.Sections...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if o.ErrorHandler == nil {
+		o.ErrorHandler = errorWithKind
+	}
+	return &Registry{
+		o:        o,
+		sections: sections,
+		services: map[string]*tenant{},
+	}, nil
+}
+
+// MustNewRegistry same as NewRegistry but panics on error.
+func MustNewRegistry(o Options) *Registry {
+	r, err := NewRegistry(o)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// validateID allows only [a-zA-Z0-9_-] characters.
+func validateID(tenantID string) error {
+	if tenantID == "" || len(tenantID) > MaxIDLength {
+		return errors.NotValid.Newf("[tenant] ID %q must have a length between 1 and %d", tenantID, MaxIDLength)
+	}
+	for _, r := range tenantID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return errors.NotValid.Newf("[tenant] ID %q contains invalid character %q", tenantID, r)
+		}
+	}
+	return nil
+}
+
+// Service returns the config.Service of a tenant and creates it on first
+// access. Use it as config.Scoper for the net/* scoped services of the
+// tenant. Concurrent first accesses of the same tenant wait for one creation;
+// other tenants are not blocked. A failed creation gets retried on the next
+// access.
+func (r *Registry) Service(tenantID string) (*config.Service, error) {
+	r.mu.RLock()
+	t, ok := r.services[tenantID]
+	r.mu.RUnlock()
+	if ok {
+		return t.service()
+	}
+	if err := validateID(tenantID); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	r.mu.Lock()
+	switch t, ok := r.services[tenantID]; {
+	case ok:
+		r.mu.Unlock()
+		return t.service()
+	case r.closed:
+		r.mu.Unlock()
+		return nil, errors.AlreadyClosed.Newf("[tenant] Registry has been closed")
+	case r.o.MaxTenants > 0 && len(r.services) >= r.o.MaxTenants:
+		r.mu.Unlock()
+		return nil, errors.Exceeded.Newf("[tenant] Registry cannot create tenant %q: maximum of %d tenants reached", tenantID, r.o.MaxTenants)
+	}
+	t = &tenant{ready: make(chan struct{})}
+	r.services[tenantID] = t
+	r.mu.Unlock()
+
+	// Remove and Close wait for ready and close the service, if they have
+	// already taken t out of the map.
+	defer close(t.ready)
+	if t.s, t.err = r.newService(tenantID); t.err != nil {
+		t.err = errors.Wrapf(t.err, "[tenant] Failed to create the config.Service of tenant %q", tenantID)
+		r.mu.Lock()
+		if r.services[tenantID] == t {
+			delete(r.services, tenantID)
+		}
+		r.mu.Unlock()
+	}
+	return t.s, t.err
+}
+
+// MustService same as Service but panics on error.
+func (r *Registry) MustService(tenantID string) *config.Service {
+	s, err := r.Service(tenantID)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (r *Registry) newService(tenantID string) (*config.Service, error) {
+	level1, level2, err := r.o.Storage(tenantID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if level2 == nil {
+		return nil, errors.NotValid.Newf("[tenant] Storage returned an empty level 2 storage")
+	}
+	if r.o.MaxPaths > 0 {
+		level2 = newLimitedStorage(level2, r.o.MaxPaths)
+	}
+
+	var co config.Options
+	if r.o.ServiceOptions != nil {
+		co = r.o.ServiceOptions(tenantID)
+	}
+	co.Level1 = level1
+	if co.Log == nil && r.o.Log != nil {
+		co.Log = r.o.Log.With(log.String("tenant", tenantID))
+	}
+
+	fns := []config.LoadDataOption{r.sections}
+	if r.o.LoadData != nil {
+		fns = append(fns, r.o.LoadData(tenantID)...)
+	}
+	return config.NewService(level2, co, fns...)
+}
+
+// Tenants returns the sorted IDs of all created tenants.
+func (r *Registry) Tenants() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, 0, len(r.services))
+	for id, t := range r.services {
+		select {
+		case <-t.ready:
+			if t.err == nil {
+				ids = append(ids, id)
+			}
+		default: // still in creation
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Remove closes and removes the config.Service and the handlers of a tenant.
+// The next access creates them again.
+func (r *Registry) Remove(tenantID string) error {
+	r.mu.Lock()
+	t, ok := r.services[tenantID]
+	delete(r.services, tenantID)
+	handlers := r.handlers
+	r.mu.Unlock()
+	if !ok {
+		return errors.NotFound.Newf("[tenant] Tenant %q not found", tenantID)
+	}
+	for _, pt := range handlers {
+		pt.remove(tenantID)
+	}
+	s, err := t.service()
+	if err != nil {
+		return errors.NotFound.Newf("[tenant] Tenant %q not found", tenantID)
+	}
+	return errors.WithStack(s.Close())
+}
+
+// Close closes all config.Services. The Registry cannot be used anymore.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	services := r.services
+	r.services = map[string]*tenant{}
+	handlers := r.handlers
+	r.mu.Unlock()
+
+	for _, pt := range handlers {
+		pt.reset()
+	}
+	var firstErr error
+	for id, t := range services {
+		s, err := t.service()
+		if err != nil {
+			continue // creation failed, nothing to close
+		}
+		if err := s.Close(); err != nil && firstErr == nil {
+			firstErr = errors.Wrapf(err, "[tenant] Failed to close tenant %q", id)
+		}
+	}
+	return firstErr
+}
+
+// ServiceFromContext returns the config.Service of the tenant found in the
+// context.
+func (r *Registry) ServiceFromContext(ctx context.Context) (*config.Service, error) {
+	tenantID, ok := FromContext(ctx)
+	if !ok {
+		return nil, errors.NotFound.Newf("[tenant] Tenant ID not found in context")
+	}
+	return r.Service(tenantID)
+}
+
+// Scoped returns the scoped configuration of the tenant, website and store
+// found in the context. See scope.WithContext.
+func (r *Registry) Scoped(ctx context.Context) (config.Scoped, error) {
+	s, err := r.ServiceFromContext(ctx)
+	if err != nil {
+		return config.Scoped{}, errors.WithStack(err)
+	}
+	websiteID, storeID, ok := scope.FromContext(ctx)
+	if !ok {
+		return config.Scoped{}, errors.NotFound.Newf("[tenant] Scope not found in context")
+	}
+	return s.Scoped(websiteID, storeID), nil
+}
+
+type ctxKey struct{}
+
+// WithContext adds the tenant ID to the context.
+func WithContext(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, tenantID)
+}
+
+// FromContext returns the tenant ID set via WithContext.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(ctxKey{}).(string)
+	return id, ok && id != ""
+}
@@ -0,0 +1,152 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+)
+
+// WithTenant is an HTTP middleware which resolves the tenant ID via
+// Options.TenantFunc, creates the config.Service of the tenant if needed and
+// adds the tenant ID to the request context.
+func (r *Registry) WithTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		tenantID, ok := FromContext(ctx)
+		if r.o.TenantFunc != nil {
+			var err error
+			if tenantID, err = r.o.TenantFunc(req); err != nil {
+				r.o.ErrorHandler(errors.Wrapf(err, "[tenant] TenantFunc")).ServeHTTP(w, req)
+				return
+			}
+			ok = tenantID != ""
+		}
+		if !ok {
+			r.o.ErrorHandler(errors.NotFound.Newf("[tenant] Tenant ID not found in request")).ServeHTTP(w, req)
+			return
+		}
+		if _, err := r.Service(tenantID); err != nil {
+			r.o.ErrorHandler(err).ServeHTTP(w, req)
+			return
+		}
+		next.ServeHTTP(w, req.WithContext(WithContext(ctx, tenantID)))
+	})
+}
+
+// PerTenant returns a handler which dispatches each request to the handler of
+// the tenant found in the request context. The handler of a tenant gets
+// created once via newHandler, which usually creates the net/* scoped services
+// of the tenant. Wrap it with WithTenant.
+func (r *Registry) PerTenant(newHandler func(tenantID string, cfg *config.Service) (http.Handler, error)) http.Handler {
+	pt := &perTenant{
+		reg:        r,
+		newHandler: newHandler,
+		handlers:   map[string]tenantHandler{},
+	}
+	r.mu.Lock()
+	r.handlers = append(r.handlers, pt)
+	r.mu.Unlock()
+	return pt
+}
+
+type perTenant struct {
+	reg        *Registry
+	newHandler func(tenantID string, cfg *config.Service) (http.Handler, error)
+
+	mu       sync.RWMutex
+	handlers map[string]tenantHandler
+}
+
+// tenantHandler stores the config.Service a handler has been created with. A
+// handler whose tenant has been removed and created again, gets replaced.
+type tenantHandler struct {
+	cfg *config.Service
+	h   http.Handler
+}
+
+func (pt *perTenant) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h, err := pt.handler(r)
+	if err != nil {
+		pt.reg.o.ErrorHandler(err).ServeHTTP(w, r)
+		return
+	}
+	h.ServeHTTP(w, r)
+}
+
+func (pt *perTenant) handler(r *http.Request) (http.Handler, error) {
+	tenantID, ok := FromContext(r.Context())
+	if !ok {
+		return nil, errors.NotFound.Newf("[tenant] Tenant ID not found in context")
+	}
+	// The Registry must not get called while holding the lock, see
+	// Registry.Close.
+	cfg, err := pt.reg.Service(tenantID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	pt.mu.RLock()
+	th, ok := pt.handlers[tenantID]
+	pt.mu.RUnlock()
+	if ok && th.cfg == cfg {
+		return th.h, nil
+	}
+
+	h, err := pt.newHandler(tenantID, cfg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "[tenant] Failed to create the handler of tenant %q", tenantID)
+	}
+
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if th, ok := pt.handlers[tenantID]; ok && th.cfg == cfg {
+		return th.h, nil // created by a concurrent request
+	}
+	pt.handlers[tenantID] = tenantHandler{cfg: cfg, h: h}
+	return h, nil
+}
+
+func (pt *perTenant) remove(tenantID string) {
+	pt.mu.Lock()
+	delete(pt.handlers, tenantID)
+	pt.mu.Unlock()
+}
+
+func (pt *perTenant) reset() {
+	pt.mu.Lock()
+	pt.handlers = map[string]tenantHandler{}
+	pt.mu.Unlock()
+}
+
+// errorWithKind writes the status text of a status code depending on the error
+// kind. The error message does not get printed because it might contain
+// internal details.
+func errorWithKind(err error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		code := http.StatusInternalServerError
+		switch {
+		case errors.NotFound.Match(err):
+			code = http.StatusNotFound
+		case errors.NotValid.Match(err):
+			code = http.StatusBadRequest
+		case errors.Exceeded.Match(err), errors.AlreadyClosed.Match(err):
+			code = http.StatusServiceUnavailable
+		}
+		http.Error(w, http.StatusText(code), code)
+	})
+}
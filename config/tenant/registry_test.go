@@ -0,0 +1,297 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/config"
+	"github.com/corestoreio/pkg/config/storage"
+	"github.com/corestoreio/pkg/config/tenant"
+	"github.com/corestoreio/pkg/store/scope"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+var testSections = config.MustMakeSectionsValidate(
+	&config.Section{
+		ID: "web",
+		Groups: config.MakeGroups(
+			&config.Group{
+				ID: "secure",
+				Fields: config.MakeFields(
+					&config.Field{ID: "base_url", Default: "https://default.dev/"},
+					&config.Field{ID: "title", Default: "Shop"},
+				),
+			},
+		),
+	},
+)
+
+func newRegistry(t *testing.T, o tenant.Options) *tenant.Registry {
+	o.Sections = testSections
+	o.Storage = func(tenantID string) (config.Storager, config.Storager, error) {
+		switch tenantID {
+		case "shop_a":
+			return nil, storage.NewMap("default/0/web/secure/base_url", "https://a.dev/", "stores/2/web/secure/title", "Shop A DE"), nil
+		case "shop_b":
+			return nil, storage.NewMap("default/0/web/secure/base_url", "https://b.dev/"), nil
+		case "shop_c":
+			return nil, storage.NewMap(), nil
+		}
+		return nil, nil, errors.NotFound.Newf("tenant %q not found", tenantID)
+	}
+	o.ServiceOptions = func(tenantID string) config.Options {
+		return config.Options{EnvName: strings.ToUpper(tenantID[len(tenantID)-1:])}
+	}
+	r, err := tenant.NewRegistry(o)
+	assert.NoError(t, err)
+	return r
+}
+
+func str(t *testing.T, v *config.Value) string {
+	s, ok, err := v.Str()
+	assert.NoError(t, err)
+	assert.True(t, ok, "Path %q", v.Path.String())
+	return s
+}
+
+func scopedStr(t *testing.T, ss config.Scoped, route string) string {
+	return str(t, ss.Get(scope.Absent, route))
+}
+
+func TestNewRegistry(t *testing.T) {
+	_, err := tenant.NewRegistry(tenant.Options{})
+	assert.ErrorIsKind(t, errors.NotValid, err)
+}
+
+func TestRegistry_Service(t *testing.T) {
+	r := newRegistry(t, tenant.Options{MaxTenants: 2})
+	defer func() { assert.NoError(t, r.Close()) }()
+
+	a, err := r.Service("shop_a")
+	assert.NoError(t, err)
+	b, err := r.Service("shop_b")
+	assert.NoError(t, err)
+	a2, err := r.Service("shop_a")
+	assert.NoError(t, err)
+	assert.True(t, a == a2, "Service must be created only once")
+
+	t.Run("isolation", func(t *testing.T) {
+		p := config.MustMakePath("web/secure/base_url")
+		assert.Exactly(t, "https://a.dev/", str(t, a.Get(p)))
+		assert.Exactly(t, "https://b.dev/", str(t, b.Get(p)))
+		assert.Exactly(t, "A", a.EnvName())
+		assert.Exactly(t, "B", b.EnvName())
+
+		assert.NoError(t, a.Set(config.MustMakePath("web/secure/title").BindWebsite(1), []byte("Shop A CH")))
+		assert.Exactly(t, "Shop A CH", scopedStr(t, a.Scoped(1, 0), "web/secure/title"))
+		assert.Exactly(t, "Shop", scopedStr(t, b.Scoped(1, 0), "web/secure/title"), "default of the shared Sections")
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		_, err := r.Service("shop_c")
+		assert.ErrorIsKind(t, errors.Exceeded, err)
+		_, err = r.Service("shop/a")
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		assert.Exactly(t, []string{"shop_a", "shop_b"}, r.Tenants())
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		assert.NoError(t, r.Remove("shop_b"))
+		assert.ErrorIsKind(t, errors.NotFound, r.Remove("shop_b"))
+		_, err := r.Service("shop_x")
+		assert.ErrorIsKind(t, errors.NotFound, err)
+		_, err = r.Service("shop_c")
+		assert.NoError(t, err)
+	})
+}
+
+func TestRegistry_MaxPaths(t *testing.T) {
+	r := newRegistry(t, tenant.Options{MaxPaths: 2})
+	defer func() { assert.NoError(t, r.Close()) }()
+
+	s := r.MustService("shop_c")
+	p := config.MustMakePath("web/secure/title")
+	assert.NoError(t, s.Set(p, []byte("a")))
+	assert.NoError(t, s.Set(p.BindStore(1), []byte("b")))
+	assert.NoError(t, s.Set(p, []byte("c")), "overwriting does not count")
+	assert.ErrorIsKind(t, errors.Exceeded, s.Set(p.BindStore(2), []byte("d")))
+
+	assert.NoError(t, r.MustService("shop_b").Set(p.BindStore(2), []byte("d")), "limit applies per tenant")
+}
+
+func TestRegistry_Scoped(t *testing.T) {
+	r := newRegistry(t, tenant.Options{})
+	defer func() { assert.NoError(t, r.Close()) }()
+
+	_, err := r.Scoped(context.Background())
+	assert.ErrorIsKind(t, errors.NotFound, err)
+
+	ctx := tenant.WithContext(context.Background(), "shop_a")
+	_, err = r.Scoped(ctx)
+	assert.ErrorIsKind(t, errors.NotFound, err)
+
+	ss, err := r.Scoped(scope.WithContext(ctx, 1, 2))
+	assert.NoError(t, err)
+	assert.Exactly(t, "Shop A DE", scopedStr(t, ss, "web/secure/title"))
+}
+
+func TestRegistry_WithTenant(t *testing.T) {
+	r := newRegistry(t, tenant.Options{
+		TenantFunc: func(r *http.Request) (string, error) {
+			return strings.TrimSuffix(r.Host, ".example.com"), nil
+		},
+	})
+	defer func() { assert.NoError(t, r.Close()) }()
+
+	var created int32
+	h := r.WithTenant(r.PerTenant(func(tenantID string, cfg *config.Service) (http.Handler, error) {
+		atomic.AddInt32(&created, 1)
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ss, err := r.Scoped(req.Context())
+			assert.NoError(t, err)
+			_, _ = w.Write([]byte(tenantID + ":" + scopedStr(t, ss, "web/secure/title")))
+		}), nil
+	}))
+
+	serve := func(host string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "http://"+host+"/", nil)
+		req = req.WithContext(scope.WithContext(req.Context(), 1, 2))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("shop_a.example.com")
+	assert.Exactly(t, http.StatusOK, w.Code)
+	assert.Exactly(t, "shop_a:Shop A DE", w.Body.String())
+	assert.Exactly(t, "shop_b:Shop", serve("shop_b.example.com").Body.String())
+	assert.Exactly(t, "shop_a:Shop A DE", serve("shop_a.example.com").Body.String())
+	assert.Exactly(t, int32(2), atomic.LoadInt32(&created))
+
+	w = serve("unknown.example.com")
+	assert.Exactly(t, http.StatusNotFound, w.Code)
+	assert.NotContains(t, w.Body.String(), "unknown")
+
+	assert.NoError(t, r.Remove("shop_a"))
+	assert.Exactly(t, "shop_a:Shop A DE", serve("shop_a.example.com").Body.String())
+	assert.Exactly(t, int32(3), atomic.LoadInt32(&created))
+
+	assert.NoError(t, r.Close())
+	assert.Exactly(t, http.StatusServiceUnavailable, serve("shop_a.example.com").Code)
+}
+
+func TestRegistry_ServiceCreation(t *testing.T) {
+	release := make(chan struct{})
+	var calls, failures int32
+	r, err := tenant.NewRegistry(tenant.Options{
+		Sections: testSections,
+		Storage: func(tenantID string) (config.Storager, config.Storager, error) {
+			switch tenantID {
+			case "slow":
+				atomic.AddInt32(&calls, 1)
+				<-release
+			case "flaky":
+				if atomic.AddInt32(&failures, 1) == 1 {
+					return nil, nil, errors.ConnectionFailed.Newf("database down")
+				}
+			}
+			return nil, storage.NewMap(), nil
+		},
+	})
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, r.Close()) }()
+
+	t.Run("a slow tenant does not block other tenants", func(t *testing.T) {
+		var wg sync.WaitGroup
+		services := make([]*config.Service, 5)
+		for i := range services {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				services[i] = r.MustService("slow")
+			}(i)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			r.MustService("fast")
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("tenant fast has been blocked by tenant slow")
+		}
+		assert.Exactly(t, []string{"fast"}, r.Tenants(), "slow is still in creation")
+
+		close(release)
+		wg.Wait()
+		assert.Exactly(t, int32(1), atomic.LoadInt32(&calls))
+		for _, s := range services {
+			assert.True(t, s == services[0], "Service must be created only once")
+		}
+		assert.Exactly(t, "Shop", str(t, services[0].Get(config.MustMakePath("web/secure/title"))))
+	})
+
+	t.Run("failed creation gets retried", func(t *testing.T) {
+		_, err := r.Service("flaky")
+		assert.ErrorIsKind(t, errors.ConnectionFailed, err)
+		_, err = r.Service("flaky")
+		assert.NoError(t, err)
+	})
+}
+
+func TestRegistry_PerTenant_RemoveDuringCreation(t *testing.T) {
+	r := newRegistry(t, tenant.Options{})
+	defer func() { assert.NoError(t, r.Close()) }()
+
+	var created int32
+	h := r.PerTenant(func(tenantID string, cfg *config.Service) (http.Handler, error) {
+		if atomic.AddInt32(&created, 1) == 1 {
+			// Remove races with the first request and closes cfg before the
+			// handler gets stored.
+			assert.NoError(t, r.Remove(tenantID))
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			cur, err := r.Service(tenantID)
+			assert.NoError(t, err)
+			if cur != cfg {
+				w.WriteHeader(http.StatusGone)
+			}
+		}), nil
+	})
+
+	serve := func() int {
+		req := httptest.NewRequest("GET", "http://shop_a.example.com/", nil)
+		req = req.WithContext(tenant.WithContext(req.Context(), "shop_a"))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Exactly(t, http.StatusGone, serve(), "first request uses the removed config.Service")
+	assert.Exactly(t, http.StatusOK, serve(), "stale handler must be replaced")
+	assert.Exactly(t, http.StatusOK, serve())
+	assert.Exactly(t, int32(2), atomic.LoadInt32(&created))
+}
@@ -553,6 +553,12 @@ func (v *Value) IsValid() bool {
 	return v.lastErr == nil && v.found > valFoundNo
 }
 
+// IsDefault returns true if the value has not been found in a storage but
+// has been provided by the default value of a FieldMeta.
+func (v *Value) IsDefault() bool {
+	return v.lastErr == nil && v.found == valFoundDefaults
+}
+
 // Equal compares if current object is fully equal to v2 object. Path and data
 // must be equal. Nil safe.
 func (v *Value) Equal(v2 *Value) bool {
@@ -146,7 +146,7 @@ func (s *Service) DebugCache(w io.Writer) error {
 // contains only the website->default scope despite setting a store scope. If an
 // OptionFactory is set the configuration gets loaded from the backend. A nil
 // root config causes a panic.
-func (s *Service) ConfigByScope(websiteID, storeID int64) (ScopedConfig, error) {
+func (s *Service) ConfigByScope(websiteID, storeID uint32) (ScopedConfig, error) {
 	cfg := s.config.Scoped(websiteID, storeID)
 	if s.useWebsite {
 		cfg = s.config.Scoped(websiteID, 0)
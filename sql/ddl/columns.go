@@ -591,6 +591,45 @@ func (c *Column) IsBlobDataType() bool {
 		strings.Contains(dt, "binary") || strings.Contains(dt, "json")
 }
 
+// EnumValues returns the allowed values of an enum or set column, parsed from
+// field ColumnType. For all other columns it returns nil. Quotes in a value
+// get unescaped.
+func (c *Column) EnumValues() []string {
+	var ct string
+	switch {
+	case c.DataType == "enum" && strings.HasPrefix(c.ColumnType, "enum("):
+		ct = c.ColumnType[len("enum("):]
+	case c.DataType == "set" && strings.HasPrefix(c.ColumnType, "set("):
+		ct = c.ColumnType[len("set("):]
+	default:
+		return nil
+	}
+	ct = strings.TrimSuffix(ct, ")")
+
+	var values []string
+	var buf strings.Builder
+	inQuote := false
+	for i := 0; i < len(ct); i++ {
+		switch ch := ct[i]; {
+		case ch == '\\' && inQuote && i+1 < len(ct):
+			i++
+			buf.WriteByte(ct[i])
+		case ch == '\'' && inQuote && i+1 < len(ct) && ct[i+1] == '\'':
+			i++ // escaped quote: ''
+			buf.WriteByte('\'')
+		case ch == '\'':
+			if inQuote {
+				values = append(values, buf.String())
+				buf.Reset()
+			}
+			inQuote = !inQuote
+		case inQuote:
+			buf.WriteByte(ch)
+		}
+	}
+	return values
+}
+
 // HasEqualType returns true if the type matches and nullable.
 func (c *Column) HasEqualType(c2 *Column) bool {
 	return c != nil && c2 != nil && c.ColumnType != "" && c.ColumnType == c2.ColumnType && c.Null == c2.Null
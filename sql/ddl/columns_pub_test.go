@@ -281,6 +281,14 @@ func TestColumn_IsTime(t *testing.T) {
 	assert.True(t, adminUserColumns.ByField("created").IsTime())
 }
 
+func TestColumn_EnumValues(t *testing.T) {
+	assert.Exactly(t, []string{"a", "b c", "it's", ""},
+		(&ddl.Column{DataType: "enum", ColumnType: "enum('a','b c','it''s','')"}).EnumValues())
+	assert.Exactly(t, []string{"x", "y,z"},
+		(&ddl.Column{DataType: "set", ColumnType: "set('x','y,z')"}).EnumValues())
+	assert.Nil(t, (&ddl.Column{DataType: "varchar", ColumnType: "varchar(255)"}).EnumValues())
+}
+
 func TestColumn_IsBlobDataType(t *testing.T) {
 	assert.False(t, adminUserColumns.ByField("version_ts").IsBlobDataType(), "version_ts")
 	assert.False(t, adminUserColumns.ByField("firstname").IsBlobDataType(), "firstname")
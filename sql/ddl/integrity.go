@@ -0,0 +1,335 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/storage/null"
+)
+
+// Relation describes a reference from the columns of a child table to the
+// columns of a parent table. A composite foreign key has several columns.
+type Relation struct {
+	Table             string   // child table
+	Columns           []string // child columns, in the order of the key
+	ReferencedTable   string   // parent table
+	ReferencedColumns []string // parent columns, in the order of the key
+	// Implicit is true if the relation has not been declared as foreign key.
+	Implicit bool
+	// ManyToOne is true if many child rows can reference the same parent
+	// row, otherwise it is a one to one relation.
+	ManyToOne bool
+}
+
+// String returns child.column => parent.column. The columns of a composite
+// key are enclosed in brackets: child.(c1, c2) => parent.(p1, p2).
+func (r Relation) String() string {
+	return r.Table + "." + joinColumns(r.Columns) + " => " + r.ReferencedTable + "." + joinColumns(r.ReferencedColumns)
+}
+
+func joinColumns(cols []string) string {
+	if len(cols) == 1 {
+		return cols[0]
+	}
+	return "(" + strings.Join(cols, ", ") + ")"
+}
+
+// IntegrityOptions configures the IntegrityChecker.
+type IntegrityOptions struct {
+	// Tables restricts the loading of the declared foreign keys to those
+	// referenced tables. Empty loads all foreign keys of the current database.
+	Tables []string
+	// SkipForeignKeys does not load the declared foreign keys, only the
+	// ImplicitRelations get checked.
+	SkipForeignKeys bool
+	// ImplicitRelations defines relations without a foreign key, similar to
+	// the ColumnAliases in dmlgen. The key contains the referenced parent
+	// "table.column" and the value the list of child "table.column" which
+	// point to the parent. For example:
+	//		"customer_entity.entity_id": {"sales_order.customer_id", "quote.customer_id"}
+	ImplicitRelations map[string][]string
+	// SampleSize defines the maximum number of distinct orphaned values to
+	// collect per relation. Defaults to 10.
+	SampleSize int
+}
+
+// Orphans contains the result of the check of one relation.
+type Orphans struct {
+	Relation
+	// Count of child rows whose values do not exist in the parent table.
+	Count int64
+	// Samples contains some distinct orphaned values of the child columns.
+	// The values of a composite key are enclosed in brackets.
+	Samples []string
+}
+
+// DeleteSQL returns the DELETE statement which removes the orphaned rows of
+// the child table. The statement uses the same anti-join as the check.
+func (o Orphans) DeleteSQL() string {
+	var buf strings.Builder
+	buf.WriteString("DELETE `c` ")
+	o.writeAntiJoin(&buf)
+	return buf.String()
+}
+
+// writeAntiJoin writes the FROM and WHERE part of the anti-join: all child
+// rows with non-NULL values which do not exist in the parent table. Like the
+// foreign key check, a composite key with a NULL column is not an orphan.
+func (r Relation) writeAntiJoin(w *strings.Builder) {
+	w.WriteString("FROM ")
+	w.WriteString(dml.Quoter.QualifierName("", r.Table))
+	w.WriteString(" AS `c` LEFT JOIN ")
+	w.WriteString(dml.Quoter.QualifierName("", r.ReferencedTable))
+	w.WriteString(" AS `p` ON ")
+	for i, c := range r.Columns {
+		if i > 0 {
+			w.WriteString(" AND ")
+		}
+		w.WriteString(dml.Quoter.QualifierName("c", c))
+		w.WriteString(" = ")
+		w.WriteString(dml.Quoter.QualifierName("p", r.ReferencedColumns[i]))
+	}
+	w.WriteString(" WHERE ")
+	for _, c := range r.Columns {
+		w.WriteString(dml.Quoter.QualifierName("c", c))
+		w.WriteString(" IS NOT NULL AND ")
+	}
+	w.WriteString(dml.Quoter.QualifierName("p", r.ReferencedColumns[0]))
+	w.WriteString(" IS NULL")
+}
+
+// IntegrityChecker finds orphaned rows in tables whose foreign keys are
+// missing or have been disabled, e.g. via DisableForeignKeys during an
+// import.
+type IntegrityChecker struct {
+	db        dml.Querier
+	o         IntegrityOptions
+	relations []Relation
+}
+
+// NewIntegrityChecker loads the declared foreign keys via LoadKeyColumnUsage,
+// merges them with the implicit relations and determines the type of each
+// relation via GenerateKeyRelationships.
+func NewIntegrityChecker(ctx context.Context, db dml.Querier, o IntegrityOptions) (*IntegrityChecker, error) {
+	if o.SampleSize <= 0 {
+		o.SampleSize = 10
+	}
+	kcu := map[string]KeyColumnUsageCollection{}
+	if !o.SkipForeignKeys {
+		var err error
+		if kcu, err = LoadKeyColumnUsage(ctx, db, o.Tables...); err != nil {
+			return nil, fmt.Errorf("[ddl] 1792363611977 NewIntegrityChecker: %w", err)
+		}
+	}
+
+	relations := make([]Relation, 0, len(kcu)+len(o.ImplicitRelations))
+	for _, kcuc := range kcu {
+		// the columns of a composite foreign key form one relation
+		keys := make([]*KeyColumnUsage, len(kcuc.Data))
+		copy(keys, kcuc.Data)
+		sort.SliceStable(keys, func(i, j int) bool {
+			if keys[i].ConstraintName != keys[j].ConstraintName {
+				return keys[i].ConstraintName < keys[j].ConstraintName
+			}
+			return keys[i].OrdinalPosition < keys[j].OrdinalPosition
+		})
+		for i, k := range keys {
+			if i == 0 || k.ConstraintName != keys[i-1].ConstraintName {
+				relations = append(relations, Relation{
+					Table:           k.TableName,
+					ReferencedTable: k.ReferencedTableName.Data,
+				})
+			}
+			r := &relations[len(relations)-1]
+			r.Columns = append(r.Columns, k.ColumnName)
+			r.ReferencedColumns = append(r.ReferencedColumns, k.ReferencedColumnName.Data)
+		}
+	}
+	for parent, children := range o.ImplicitRelations {
+		pt, pc, err := splitTableColumn(parent)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			ct, cc, err := splitTableColumn(child)
+			if err != nil {
+				return nil, err
+			}
+			relations = append(relations, Relation{
+				Table: ct, Columns: []string{cc}, ReferencedTable: pt, ReferencedColumns: []string{pc}, Implicit: true,
+			})
+			// the implicit relations take part in the relationship analysis
+			kcuc := kcu[ct]
+			kcuc.Data = append(kcuc.Data, &KeyColumnUsage{
+				ConstraintName:       "implicit",
+				TableName:            ct,
+				ColumnName:           cc,
+				ReferencedTableName:  null.MakeString(pt),
+				ReferencedColumnName: null.MakeString(pc),
+			})
+			kcu[ct] = kcuc
+		}
+	}
+	if len(relations) == 0 {
+		return &IntegrityChecker{db: db, o: o}, nil
+	}
+
+	krs, err := GenerateKeyRelationships(ctx, db, kcu)
+	if err != nil {
+		return nil, fmt.Errorf("[ddl] 1792363648108 NewIntegrityChecker: %w", err)
+	}
+	for i, r := range relations {
+		// the type depends on the child table, so the first column decides.
+		relations[i].ManyToOne = krs.IsOneToMany(r.ReferencedTable, r.ReferencedColumns[0], r.Table, r.Columns[0])
+	}
+	sort.Slice(relations, func(i, j int) bool {
+		return relations[i].String() < relations[j].String()
+	})
+
+	return &IntegrityChecker{
+		db:        db,
+		o:         o,
+		relations: relations,
+	}, nil
+}
+
+func splitTableColumn(tc string) (table, column string, _ error) {
+	pos := strings.IndexByte(tc, '.')
+	if pos < 1 || pos == len(tc)-1 {
+		return "", "", fmt.Errorf("[ddl] 1792363671440 Implicit relation %q must be in the form table.column", tc)
+	}
+	table, column = tc[:pos], tc[pos+1:]
+	if err := dml.IsValidIdentifier(table); err != nil {
+		return "", "", err
+	}
+	if err := dml.IsValidIdentifier(column); err != nil {
+		return "", "", err
+	}
+	return table, column, nil
+}
+
+// Relations returns the sorted relations which get checked.
+func (ic *IntegrityChecker) Relations() []Relation {
+	return ic.relations
+}
+
+// Check runs for each relation an anti-join query and returns the relations
+// which have orphaned child rows.
+func (ic *IntegrityChecker) Check(ctx context.Context) ([]Orphans, error) {
+	var ret []Orphans
+	for _, r := range ic.relations {
+		o, err := ic.checkRelation(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		if o.Count > 0 {
+			ret = append(ret, o)
+		}
+	}
+	return ret, nil
+}
+
+func (ic *IntegrityChecker) checkRelation(ctx context.Context, r Relation) (_ Orphans, err error) {
+	o := Orphans{Relation: r}
+
+	var buf strings.Builder
+	buf.WriteString("SELECT COUNT(*) ")
+	r.writeAntiJoin(&buf)
+	rows, err := ic.db.QueryContext(ctx, buf.String())
+	if err != nil {
+		return o, fmt.Errorf("[ddl] 1792363702551 IntegrityChecker count query for %s failed: %w", r, err)
+	}
+	for rows.Next() {
+		if err = rows.Scan(&o.Count); err != nil {
+			_ = rows.Close()
+			return o, fmt.Errorf("[ddl] 1792363713338 IntegrityChecker scan count for %s failed: %w", r, err)
+		}
+	}
+	if err = rows.Close(); err != nil {
+		return o, fmt.Errorf("[ddl] 1792363725016 IntegrityChecker close rows for %s failed: %w", r, err)
+	}
+	if o.Count == 0 {
+		return o, nil
+	}
+
+	buf.Reset()
+	buf.WriteString("SELECT DISTINCT ")
+	for i, c := range r.Columns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(dml.Quoter.QualifierName("c", c))
+	}
+	buf.WriteByte(' ')
+	r.writeAntiJoin(&buf)
+	buf.WriteString(" LIMIT ")
+	buf.WriteString(strconv.Itoa(ic.o.SampleSize))
+	if rows, err = ic.db.QueryContext(ctx, buf.String()); err != nil {
+		return o, fmt.Errorf("[ddl] 1792363740822 IntegrityChecker sample query for %s failed: %w", r, err)
+	}
+	defer func() {
+		if err2 := rows.Close(); err2 != nil && err == nil {
+			err = fmt.Errorf("[ddl] 1792363752197 IntegrityChecker close rows for %s failed: %w", r, err2)
+		}
+	}()
+	vals := make([]sql.RawBytes, len(r.Columns))
+	dest := make([]interface{}, len(vals))
+	for i := range vals {
+		dest[i] = &vals[i]
+	}
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			return o, fmt.Errorf("[ddl] 1792363763925 IntegrityChecker scan sample for %s failed: %w", r, err)
+		}
+		sample := make([]string, len(vals))
+		for i, v := range vals {
+			sample[i] = string(v)
+		}
+		o.Samples = append(o.Samples, joinColumns(sample))
+	}
+	return o, rows.Err()
+}
+
+// WriteIntegrityReport writes a human readable report of the orphans to w. If
+// withDelete is true, the cleanup DELETE statements get appended as SQL
+// comments, so they must be reviewed before execution.
+func WriteIntegrityReport(w io.Writer, orphans []Orphans, withDelete bool) error {
+	for _, o := range orphans {
+		typ := "one-to-one"
+		if o.ManyToOne {
+			typ = "many-to-one"
+		}
+		if o.Implicit {
+			typ += ", implicit"
+		}
+		if _, err := fmt.Fprintf(w, "%s (%s): %d orphaned rows, samples: %s\n", o.Relation, typ, o.Count, strings.Join(o.Samples, ", ")); err != nil {
+			return err
+		}
+		if withDelete {
+			if _, err := fmt.Fprintf(w, "-- %s;\n", o.DeleteSQL()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
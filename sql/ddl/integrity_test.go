@@ -0,0 +1,135 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestIntegrityChecker(t *testing.T) {
+	db, mock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, db, mock)
+
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT CONSTRAINT_CATALOG")).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"CONSTRAINT_CATALOG", "CONSTRAINT_SCHEMA", "CONSTRAINT_NAME", "TABLE_CATALOG", "TABLE_SCHEMA",
+			"TABLE_NAME", "COLUMN_NAME", "ORDINAL_POSITION", "POSITION_IN_UNIQUE_CONSTRAINT",
+			"REFERENCED_TABLE_SCHEMA", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME",
+		}).AddRow("def", "shop", "FK_ORDER_STORE", "def", "shop", "sales_order", "store_id", 1, 1, "shop", "store", "store_id"))
+
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT TABLE_NAME, COLUMN_KEY, COUNT(*) AS FIELD_COUNT")).
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_KEY", "FIELD_COUNT"}).
+			AddRow("sales_order", "PRI", 1).AddRow("sales_order", "MUL", 2).AddRow("sales_order", "", 5).
+			AddRow("store", "PRI", 1).AddRow("store", "", 3).
+			AddRow("customer_entity", "PRI", 1).AddRow("customer_entity", "", 8))
+
+	ic, err := NewIntegrityChecker(context.Background(), db.DB, IntegrityOptions{
+		ImplicitRelations: map[string][]string{
+			"customer_entity.entity_id": {"sales_order.customer_id"},
+		},
+		SampleSize: 2,
+	})
+	assert.NoError(t, err)
+	assert.Exactly(t, []Relation{
+		{Table: "sales_order", Columns: []string{"customer_id"}, ReferencedTable: "customer_entity", ReferencedColumns: []string{"entity_id"}, Implicit: true, ManyToOne: true},
+		{Table: "sales_order", Columns: []string{"store_id"}, ReferencedTable: "store", ReferencedColumns: []string{"store_id"}, ManyToOne: true},
+	}, ic.Relations())
+
+	const antiJoinCustomer = "FROM `sales_order` AS `c` LEFT JOIN `customer_entity` AS `p` ON `c`.`customer_id` = `p`.`entity_id` WHERE `c`.`customer_id` IS NOT NULL AND `p`.`entity_id` IS NULL"
+	const antiJoinStore = "FROM `sales_order` AS `c` LEFT JOIN `store` AS `p` ON `c`.`store_id` = `p`.`store_id` WHERE `c`.`store_id` IS NOT NULL AND `p`.`store_id` IS NULL"
+
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT COUNT(*) " + antiJoinCustomer)).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(3))
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT DISTINCT `c`.`customer_id` " + antiJoinCustomer + " LIMIT 2")).
+		WillReturnRows(sqlmock.NewRows([]string{"customer_id"}).AddRow("17").AddRow("42"))
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT COUNT(*) " + antiJoinStore)).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+
+	orphans, err := ic.Check(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, orphans, 1)
+	assert.Exactly(t, int64(3), orphans[0].Count)
+	assert.Exactly(t, []string{"17", "42"}, orphans[0].Samples)
+	assert.Exactly(t, "DELETE `c` "+antiJoinCustomer, orphans[0].DeleteSQL())
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteIntegrityReport(&buf, orphans, true))
+	assert.Exactly(t, "sales_order.customer_id => customer_entity.entity_id (many-to-one, implicit): 3 orphaned rows, samples: 17, 42\n"+
+		"-- DELETE `c` "+antiJoinCustomer+";\n", buf.String())
+}
+
+func TestIntegrityChecker_CompositeForeignKey(t *testing.T) {
+	db, mock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, db, mock)
+
+	// ORDER BY TABLE_NAME, ORDINAL_POSITION interleaves the constraints.
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT CONSTRAINT_CATALOG")).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"CONSTRAINT_CATALOG", "CONSTRAINT_SCHEMA", "CONSTRAINT_NAME", "TABLE_CATALOG", "TABLE_SCHEMA",
+			"TABLE_NAME", "COLUMN_NAME", "ORDINAL_POSITION", "POSITION_IN_UNIQUE_CONSTRAINT",
+			"REFERENCED_TABLE_SCHEMA", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME",
+		}).
+			AddRow("def", "shop", "FK_PRICE_PRODUCT_STORE", "def", "shop", "product_price", "product_id", 1, 1, "shop", "product_store", "product_id").
+			AddRow("def", "shop", "FK_PRICE_CURRENCY", "def", "shop", "product_price", "currency", 1, 1, "shop", "currency", "code").
+			AddRow("def", "shop", "FK_PRICE_PRODUCT_STORE", "def", "shop", "product_price", "store_id", 2, 2, "shop", "product_store", "store_id"))
+
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT TABLE_NAME, COLUMN_KEY, COUNT(*) AS FIELD_COUNT")).
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_KEY", "FIELD_COUNT"}).
+			AddRow("product_price", "PRI", 1).AddRow("product_price", "MUL", 2).AddRow("product_price", "", 2).
+			AddRow("product_store", "PRI", 2).
+			AddRow("currency", "PRI", 1).AddRow("currency", "", 1))
+
+	ic, err := NewIntegrityChecker(context.Background(), db.DB, IntegrityOptions{})
+	assert.NoError(t, err)
+	assert.Exactly(t, []Relation{
+		{Table: "product_price", Columns: []string{"product_id", "store_id"}, ReferencedTable: "product_store", ReferencedColumns: []string{"product_id", "store_id"}, ManyToOne: true},
+		{Table: "product_price", Columns: []string{"currency"}, ReferencedTable: "currency", ReferencedColumns: []string{"code"}, ManyToOne: true},
+	}, ic.Relations())
+
+	const antiJoinProductStore = "FROM `product_price` AS `c` LEFT JOIN `product_store` AS `p` ON `c`.`product_id` = `p`.`product_id` AND `c`.`store_id` = `p`.`store_id` " +
+		"WHERE `c`.`product_id` IS NOT NULL AND `c`.`store_id` IS NOT NULL AND `p`.`product_id` IS NULL"
+	const antiJoinCurrency = "FROM `product_price` AS `c` LEFT JOIN `currency` AS `p` ON `c`.`currency` = `p`.`code` WHERE `c`.`currency` IS NOT NULL AND `p`.`code` IS NULL"
+
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT COUNT(*) " + antiJoinProductStore)).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(2))
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT DISTINCT `c`.`product_id`, `c`.`store_id` " + antiJoinProductStore + " LIMIT 10")).
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "store_id"}).AddRow("5", "1").AddRow("5", "2"))
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT COUNT(*) " + antiJoinCurrency)).
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+
+	orphans, err := ic.Check(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, orphans, 1)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteIntegrityReport(&buf, orphans, true))
+	assert.Exactly(t, "product_price.(product_id, store_id) => product_store.(product_id, store_id) (many-to-one): 2 orphaned rows, samples: (5, 1), (5, 2)\n"+
+		"-- DELETE `c` "+antiJoinProductStore+";\n", buf.String())
+}
+
+func TestNewIntegrityChecker_InvalidImplicitRelation(t *testing.T) {
+	ic, err := NewIntegrityChecker(context.Background(), nil, IntegrityOptions{
+		SkipForeignKeys:   true,
+		ImplicitRelations: map[string][]string{"customer_entity": {"sales_order.customer_id"}},
+	})
+	assert.Error(t, err)
+	assert.Nil(t, ic)
+}
@@ -0,0 +1,442 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/pkg/sql/dml"
+)
+
+// Partition represents a row of information_schema.PARTITIONS.
+type Partition struct {
+	TableName       string
+	Name            string // PARTITION_NAME
+	OrdinalPosition int64
+	Method          string // RANGE, RANGE COLUMNS, LIST, HASH ...
+	Expression      string // PARTITION_EXPRESSION, e.g. to_days(`created_at`)
+	Description     string // PARTITION_DESCRIPTION, the VALUES LESS THAN value
+	TableRows       int64
+	DataLength      int64
+}
+
+// IsMaxValue returns true if the partition is the catch-all partition defined
+// with VALUES LESS THAN (MAXVALUE).
+func (p Partition) IsMaxValue() bool {
+	return strings.EqualFold(p.Description, "MAXVALUE")
+}
+
+// Partitions a list of partitions of a table, ordered by the ordinal
+// position.
+type Partitions []*Partition
+
+// ByName returns the partition or nil if not found.
+func (ps Partitions) ByName(name string) *Partition {
+	for _, p := range ps {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// MaxValue returns the catch-all MAXVALUE partition or nil.
+func (ps Partitions) MaxValue() *Partition {
+	for _, p := range ps {
+		if p.IsMaxValue() {
+			return p
+		}
+	}
+	return nil
+}
+
+// LoadPartitions loads the partitions of the tables in the current database.
+// An empty tables argument loads all partitioned tables. The key of the map
+// is the table name. Non-partitioned tables are not part of the result.
+func LoadPartitions(ctx context.Context, db dml.Querier, tables ...string) (_ map[string]Partitions, err error) {
+	const selPartitions = `SELECT TABLE_NAME, PARTITION_NAME, PARTITION_ORDINAL_POSITION, PARTITION_METHOD,
+	IFNULL(PARTITION_EXPRESSION,''), IFNULL(PARTITION_DESCRIPTION,''), TABLE_ROWS, DATA_LENGTH
+	FROM information_schema.PARTITIONS WHERE TABLE_SCHEMA = DATABASE() AND PARTITION_NAME IS NOT NULL`
+	const selOrderBy = ` ORDER BY TABLE_NAME, PARTITION_ORDINAL_POSITION`
+
+	qry := selPartitions + selOrderBy
+	if len(tables) > 0 {
+		qry, _, err = dml.Interpolate(selPartitions + ` AND TABLE_NAME IN ?` + selOrderBy).Strs(tables...).ToSQL()
+		if err != nil {
+			return nil, fmt.Errorf("[ddl] 1792364215518 LoadPartitions dml.Interpolate for tables %v with: %w", tables, err)
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, qry)
+	if err != nil {
+		return nil, fmt.Errorf("[ddl] 1792364229062 LoadPartitions QueryContext for tables %v with: %w", tables, err)
+	}
+	defer func() {
+		if err2 := rows.Close(); err2 != nil && err == nil {
+			err = fmt.Errorf("[ddl] 1792364240370 LoadPartitions.Rows.Close: %w", err2)
+		}
+	}()
+
+	ret := map[string]Partitions{}
+	for rows.Next() {
+		p := new(Partition)
+		var tableRows, dataLength sql.NullInt64
+		if err = rows.Scan(&p.TableName, &p.Name, &p.OrdinalPosition, &p.Method, &p.Expression, &p.Description, &tableRows, &dataLength); err != nil {
+			return nil, fmt.Errorf("[ddl] 1792364253815 LoadPartitions Scan for tables %v with: %w", tables, err)
+		}
+		p.TableRows, p.DataLength = tableRows.Int64, dataLength.Int64
+		ret[p.TableName] = append(ret[p.TableName], p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("[ddl] 1792364266147 LoadPartitions rows.Err for tables %v with: %w", tables, err)
+	}
+	return ret, nil
+}
+
+// PartitionDefinition defines a new range partition.
+type PartitionDefinition struct {
+	Name string
+	// LessThan contains the raw SQL expression of VALUES LESS THAN, e.g.
+	// `TO_DAYS('2024-02-01')`, `'2024-02-01'` or `MAXVALUE`.
+	LessThan string
+}
+
+func writePartitionDefinitions(buf *strings.Builder, defs []PartitionDefinition) error {
+	buf.WriteByte('(')
+	for i, d := range defs {
+		if err := dml.IsValidIdentifier(d.Name); err != nil {
+			return err
+		}
+		if d.LessThan == "" {
+			return fmt.Errorf("[ddl] 1792364281207 Partition %q requires a LessThan value", d.Name)
+		}
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString("PARTITION ")
+		buf.WriteString(dml.Quoter.Name(d.Name))
+		buf.WriteString(" VALUES LESS THAN (")
+		buf.WriteString(d.LessThan)
+		buf.WriteByte(')')
+	}
+	buf.WriteByte(')')
+	return nil
+}
+
+func writePartitionNames(buf *strings.Builder, names []string) error {
+	for i, n := range names {
+		if err := dml.IsValidIdentifier(n); err != nil {
+			return err
+		}
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(dml.Quoter.Name(n))
+	}
+	return nil
+}
+
+func (t *Table) alterPartitionPrefix(buf *strings.Builder, o Options) error {
+	if err := dml.IsValidIdentifier(t.Name); err != nil {
+		return err
+	}
+	buf.WriteString("ALTER TABLE ")
+	buf.WriteString(dml.Quoter.QualifierName(t.Schema, t.Name))
+	o.sqlAddShouldWait(buf)
+	buf.WriteByte(' ')
+	return nil
+}
+
+// AddPartitions appends new range partitions to the table. MySQL/MariaDB
+// only allow adding partitions above the highest existing one, so if a
+// MAXVALUE partition exists, use ReorganizePartitions. To use a custom
+// connection, set the Execer field in Options.
+func (t *Table) AddPartitions(ctx context.Context, o Options, defs ...PartitionDefinition) error {
+	if len(defs) == 0 {
+		return nil
+	}
+	var buf strings.Builder
+	if err := t.alterPartitionPrefix(&buf, o); err != nil {
+		return err
+	}
+	buf.WriteString("ADD PARTITION ")
+	if err := writePartitionDefinitions(&buf, defs); err != nil {
+		return err
+	}
+	return t.runExec(ctx, o, buf.String())
+}
+
+// DropPartitions drops the partitions and all their data.
+func (t *Table) DropPartitions(ctx context.Context, o Options, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	var buf strings.Builder
+	if err := t.alterPartitionPrefix(&buf, o); err != nil {
+		return err
+	}
+	buf.WriteString("DROP PARTITION ")
+	if err := writePartitionNames(&buf, names); err != nil {
+		return err
+	}
+	return t.runExec(ctx, o, buf.String())
+}
+
+// ExchangePartition swaps the data of the partition with the data of the
+// non-partitioned table `withTable`, which must have the same structure. Used
+// to archive a partition: exchange it into an empty archive table and then
+// drop the now empty partition.
+func (t *Table) ExchangePartition(ctx context.Context, o Options, name, withTable string) error {
+	if err := dml.IsValidIdentifier(name); err != nil {
+		return err
+	}
+	if err := dml.IsValidIdentifier(withTable); err != nil {
+		return err
+	}
+	var buf strings.Builder
+	if err := t.alterPartitionPrefix(&buf, o); err != nil {
+		return err
+	}
+	buf.WriteString("EXCHANGE PARTITION ")
+	buf.WriteString(dml.Quoter.Name(name))
+	buf.WriteString(" WITH TABLE ")
+	buf.WriteString(dml.Quoter.QualifierName(t.Schema, withTable))
+	return t.runExec(ctx, o, buf.String())
+}
+
+// ReorganizePartitions splits or merges the partitions `names` into the new
+// definitions. Commonly used to split the MAXVALUE partition.
+func (t *Table) ReorganizePartitions(ctx context.Context, o Options, names []string, defs ...PartitionDefinition) error {
+	if len(names) == 0 || len(defs) == 0 {
+		return fmt.Errorf("[ddl] 1792364296440 ReorganizePartitions of table %q requires partition names and definitions", t.Name)
+	}
+	var buf strings.Builder
+	if err := t.alterPartitionPrefix(&buf, o); err != nil {
+		return err
+	}
+	buf.WriteString("REORGANIZE PARTITION ")
+	if err := writePartitionNames(&buf, names); err != nil {
+		return err
+	}
+	buf.WriteString(" INTO ")
+	if err := writePartitionDefinitions(&buf, defs); err != nil {
+		return err
+	}
+	return t.runExec(ctx, o, buf.String())
+}
+
+// PartitionPolicy defines the time based range partitioning of a table. The
+// partitions are named p<YYYYMM> for PartitionMonthly and p<YYYYMMDD> for
+// PartitionDaily. Partition p202401 contains all rows before 2024-02-01.
+type PartitionPolicy struct {
+	Table string
+	// Daily switches from monthly to daily partitions.
+	Daily bool
+	// Future defines the number of partitions to create ahead of the current
+	// period. The current period is always created.
+	Future int
+	// Retention defines how many past partitions, excluding the current one,
+	// are kept. Older partitions get dropped or archived. Zero keeps all.
+	Retention int
+	// ArchiveTable if set, returns the name of the archive table for an
+	// expired partition. The archive table gets created via CREATE TABLE LIKE,
+	// the partitioning removed and then the partition gets exchanged into it
+	// before dropping. If nil, expired partitions are dropped with their data.
+	ArchiveTable func(table, partition string) string
+	// LessThan formats the upper bound of a partition. Defaults to
+	// TO_DAYS('2006-01-02'). For RANGE COLUMNS partitioning on a DATE column
+	// return the quoted date.
+	LessThan func(upperBound time.Time) string
+	// Now returns the current time, defaults to time.Now.
+	Now func() time.Time
+}
+
+// PartitionChanges reports the changes made by EnsurePartitions.
+type PartitionChanges struct {
+	Added    []string
+	Archived []string // partition names which have been exchanged
+	Dropped  []string
+}
+
+func (pp PartitionPolicy) periodStart(t time.Time) time.Time {
+	if pp.Daily {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func (pp PartitionPolicy) addPeriods(t time.Time, n int) time.Time {
+	if pp.Daily {
+		return t.AddDate(0, 0, n)
+	}
+	return t.AddDate(0, n, 0)
+}
+
+func (pp PartitionPolicy) layout() string {
+	if pp.Daily {
+		return "p20060102"
+	}
+	return "p200601"
+}
+
+func (pp PartitionPolicy) definition(start time.Time) PartitionDefinition {
+	upper := pp.addPeriods(start, 1)
+	lt := "TO_DAYS('" + upper.Format("2006-01-02") + "')"
+	if pp.LessThan != nil {
+		lt = pp.LessThan(upper)
+	}
+	return PartitionDefinition{Name: start.Format(pp.layout()), LessThan: lt}
+}
+
+// EnsurePartitions applies the policy to the table: it creates the missing
+// partitions for the current and the future periods and drops or archives
+// the expired ones. Partitions whose names do not match the naming scheme of
+// the policy, except MAXVALUE, are left untouched. The function is idempotent
+// and designed to be called periodically, e.g. by a daily cron job. The
+// table must already be partitioned by RANGE.
+func (tm *Tables) EnsurePartitions(ctx context.Context, pp PartitionPolicy) (pc PartitionChanges, err error) {
+	t, err := tm.Table(pp.Table)
+	if err != nil {
+		return pc, fmt.Errorf("[ddl] 1792364310872 EnsurePartitions: %w", err)
+	}
+	if tm.ConnPool == nil {
+		return pc, fmt.Errorf("[ddl] 1792364323507 EnsurePartitions for table %q requires a connection pool", pp.Table)
+	}
+	nowFn := pp.Now
+	if nowFn == nil {
+		nowFn = time.Now
+	}
+	pm, err := LoadPartitions(ctx, tm.ConnPool.DB, pp.Table)
+	if err != nil {
+		return pc, fmt.Errorf("[ddl] 1792364335170 EnsurePartitions: %w", err)
+	}
+	existing := pm[pp.Table]
+	if len(existing) == 0 {
+		return pc, fmt.Errorf("[ddl] 1792364347733 EnsurePartitions table %q is not partitioned", pp.Table)
+	}
+
+	current := pp.periodStart(nowFn())
+
+	// Future partitions, only periods above the highest existing partition
+	// can be added.
+	var highest time.Time
+	for _, p := range existing {
+		if start, err := time.ParseInLocation(pp.layout(), p.Name, current.Location()); err == nil && start.After(highest) {
+			highest = start
+		}
+	}
+	var defs []PartitionDefinition
+	for i := 0; i <= pp.Future; i++ {
+		start := pp.addPeriods(current, i)
+		if start.After(highest) {
+			defs = append(defs, pp.definition(start))
+		}
+	}
+	if len(defs) > 0 {
+		if maxP := existing.MaxValue(); maxP != nil {
+			err = t.ReorganizePartitions(ctx, Options{}, []string{maxP.Name},
+				append(defs, PartitionDefinition{Name: maxP.Name, LessThan: "MAXVALUE"})...)
+		} else {
+			err = t.AddPartitions(ctx, Options{}, defs...)
+		}
+		if err != nil {
+			return pc, fmt.Errorf("[ddl] 1792364360218 EnsurePartitions add partitions to table %q: %w", pp.Table, err)
+		}
+		for _, d := range defs {
+			pc.Added = append(pc.Added, d.Name)
+		}
+	}
+
+	if pp.Retention <= 0 {
+		return pc, nil
+	}
+	oldest := pp.addPeriods(current, -pp.Retention)
+	var expired []string
+	for _, p := range existing {
+		start, err := time.ParseInLocation(pp.layout(), p.Name, current.Location())
+		if err == nil && start.Before(oldest) {
+			expired = append(expired, p.Name)
+		}
+	}
+	if len(expired) == 0 {
+		return pc, nil
+	}
+	if pp.ArchiveTable != nil {
+		for _, pName := range expired {
+			if err := tm.archivePartition(ctx, t, pName, pp.ArchiveTable(t.Name, pName)); err != nil {
+				return pc, err
+			}
+			pc.Archived = append(pc.Archived, pName)
+		}
+	}
+	if err := t.DropPartitions(ctx, Options{}, expired...); err != nil {
+		return pc, fmt.Errorf("[ddl] 1792364372913 EnsurePartitions drop partitions of table %q: %w", pp.Table, err)
+	}
+	pc.Dropped = expired
+	return pc, nil
+}
+
+// archivePartition creates the archive table with the structure of t, if it
+// does not exist, and moves the data of the partition into it. A failed run
+// can be repeated: an existing archive table gets reused and the exchange gets
+// skipped when the archive table already contains the data of the emptied
+// partition, because a second exchange would move the data back.
+func (tm *Tables) archivePartition(ctx context.Context, t *Table, partition, archive string) error {
+	if err := dml.IsValidIdentifier(archive); err != nil {
+		return err
+	}
+	db := tm.ConnPool.DB
+	qTable := dml.Quoter.QualifierName(t.Schema, t.Name)
+	qArchive := dml.Quoter.QualifierName(t.Schema, archive)
+
+	qry := "CREATE TABLE IF NOT EXISTS " + qArchive + " LIKE " + qTable
+	if _, err := db.ExecContext(ctx, qry); err != nil {
+		return fmt.Errorf("[ddl] 1792364385260 EnsurePartitions archive partition %q with query %q: %w", partition, qry, err)
+	}
+	pm, err := LoadPartitions(ctx, db, archive)
+	if err != nil {
+		return fmt.Errorf("[ddl] 1792364386374 EnsurePartitions archive partition %q: %w", partition, err)
+	}
+	if len(pm[archive]) > 0 {
+		qry = "ALTER TABLE " + qArchive + " REMOVE PARTITIONING"
+		if _, err := db.ExecContext(ctx, qry); err != nil {
+			return fmt.Errorf("[ddl] 1792364387119 EnsurePartitions archive partition %q with query %q: %w", partition, qry, err)
+		}
+	}
+
+	var archiveHasRows, partitionHasRows bool
+	qry = "SELECT EXISTS(SELECT 1 FROM " + qArchive + ")"
+	if err := db.QueryRowContext(ctx, qry).Scan(&archiveHasRows); err != nil {
+		return fmt.Errorf("[ddl] 1792364388402 EnsurePartitions archive partition %q with query %q: %w", partition, qry, err)
+	}
+	if archiveHasRows {
+		qry = "SELECT EXISTS(SELECT 1 FROM " + qTable + " PARTITION (" + dml.Quoter.Name(partition) + "))"
+		if err := db.QueryRowContext(ctx, qry).Scan(&partitionHasRows); err != nil {
+			return fmt.Errorf("[ddl] 1792364389677 EnsurePartitions archive partition %q with query %q: %w", partition, qry, err)
+		}
+		if partitionHasRows {
+			return fmt.Errorf("[ddl] 1792364390958 EnsurePartitions archive table %q and partition %q both contain rows", archive, partition)
+		}
+		return nil // exchanged by a previous run
+	}
+	if err := t.ExchangePartition(ctx, Options{}, partition, archive); err != nil {
+		return fmt.Errorf("[ddl] 1792364397815 EnsurePartitions exchange partition %q into %q: %w", partition, archive, err)
+	}
+	return nil
+}
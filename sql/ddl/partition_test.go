@@ -0,0 +1,242 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+var partitionColumns = []string{
+	"TABLE_NAME", "PARTITION_NAME", "PARTITION_ORDINAL_POSITION", "PARTITION_METHOD",
+	"PARTITION_EXPRESSION", "PARTITION_DESCRIPTION", "TABLE_ROWS", "DATA_LENGTH",
+}
+
+func TestTable_PartitionStatements(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	tbls, err := NewTables(WithConnPool(dbc), WithTable("sales_order"))
+	assert.NoError(t, err)
+	tbl := tbls.MustTable("sales_order")
+	ctx := context.Background()
+
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("ALTER TABLE `sales_order` ADD PARTITION (PARTITION `p202402` VALUES LESS THAN (TO_DAYS('2024-03-01')))")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.NoError(t, tbl.AddPartitions(ctx, Options{}, PartitionDefinition{Name: "p202402", LessThan: "TO_DAYS('2024-03-01')"}))
+
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("ALTER TABLE `sales_order` DROP PARTITION `p202301`, `p202302`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.NoError(t, tbl.DropPartitions(ctx, Options{}, "p202301", "p202302"))
+
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("ALTER TABLE `sales_order` EXCHANGE PARTITION `p202301` WITH TABLE `sales_order_archive`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.NoError(t, tbl.ExchangePartition(ctx, Options{}, "p202301", "sales_order_archive"))
+
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("ALTER TABLE `sales_order`  WAIT 2  REORGANIZE PARTITION `pmax` INTO (PARTITION `p202403` VALUES LESS THAN (TO_DAYS('2024-04-01')), PARTITION `pmax` VALUES LESS THAN (MAXVALUE))")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.NoError(t, tbl.ReorganizePartitions(ctx, Options{Wait: 2 * time.Second}, []string{"pmax"},
+		PartitionDefinition{Name: "p202403", LessThan: "TO_DAYS('2024-04-01')"},
+		PartitionDefinition{Name: "pmax", LessThan: "MAXVALUE"},
+	))
+
+	assert.Error(t, tbl.ReorganizePartitions(ctx, Options{}, nil))
+	assert.Error(t, tbl.AddPartitions(ctx, Options{}, PartitionDefinition{Name: "p202404"}))
+}
+
+func TestLoadPartitions(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("FROM information_schema.PARTITIONS WHERE TABLE_SCHEMA = DATABASE() AND PARTITION_NAME IS NOT NULL AND TABLE_NAME IN ('sales_order') ORDER BY")).
+		WillReturnRows(sqlmock.NewRows(partitionColumns).
+			AddRow("sales_order", "p202401", 1, "RANGE", "to_days(`created_at`)", "739282", 10, 16384).
+			AddRow("sales_order", "pmax", 2, "RANGE", "to_days(`created_at`)", "MAXVALUE", nil, nil))
+
+	pm, err := LoadPartitions(context.Background(), dbc.DB, "sales_order")
+	assert.NoError(t, err)
+	ps := pm["sales_order"]
+	assert.Len(t, ps, 2)
+	assert.Exactly(t, int64(10), ps.ByName("p202401").TableRows)
+	assert.Exactly(t, "pmax", ps.MaxValue().Name)
+	assert.Nil(t, ps.ByName("p202312"))
+}
+
+func TestTables_EnsurePartitions(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	tbls, err := NewTables(WithConnPool(dbc), WithTable("sales_order"))
+	assert.NoError(t, err)
+
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("FROM information_schema.PARTITIONS")).
+		WillReturnRows(sqlmock.NewRows(partitionColumns).
+			AddRow("sales_order", "p202311", 1, "RANGE", "to_days(`created_at`)", "739221", 5, 16384).
+			AddRow("sales_order", "p202312", 2, "RANGE", "to_days(`created_at`)", "739252", 5, 16384).
+			AddRow("sales_order", "p202401", 3, "RANGE", "to_days(`created_at`)", "739283", 5, 16384).
+			AddRow("sales_order", "p202402", 4, "RANGE", "to_days(`created_at`)", "739312", 5, 16384).
+			AddRow("sales_order", "pmax", 5, "RANGE", "to_days(`created_at`)", "MAXVALUE", 0, 16384))
+
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("ALTER TABLE `sales_order` REORGANIZE PARTITION `pmax` INTO (" +
+		"PARTITION `p202403` VALUES LESS THAN (TO_DAYS('2024-04-01')), " +
+		"PARTITION `p202404` VALUES LESS THAN (TO_DAYS('2024-05-01')), " +
+		"PARTITION `pmax` VALUES LESS THAN (MAXVALUE))")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	for _, p := range []string{"p202311", "p202312"} {
+		expectArchiveTable(dbMock, p, true)
+		expectArchiveRows(dbMock, p, false)
+		expectExchange(dbMock, p).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("ALTER TABLE `sales_order` DROP PARTITION `p202311`, `p202312`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	pc, err := tbls.EnsurePartitions(context.Background(), PartitionPolicy{
+		Table:     "sales_order",
+		Future:    2,
+		Retention: 1,
+		ArchiveTable: func(table, partition string) string {
+			return table + "_" + partition
+		},
+		Now: func() time.Time { return time.Date(2024, 2, 15, 10, 0, 0, 0, time.UTC) },
+	})
+	assert.NoError(t, err)
+	assert.Exactly(t, PartitionChanges{
+		Added:    []string{"p202403", "p202404"},
+		Archived: []string{"p202311", "p202312"},
+		Dropped:  []string{"p202311", "p202312"},
+	}, pc)
+}
+
+func expectArchiveTable(dbMock sqlmock.Sqlmock, p string, partitioned bool) {
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("CREATE TABLE IF NOT EXISTS `sales_order_" + p + "` LIKE `sales_order`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows(partitionColumns)
+	if partitioned {
+		rows.AddRow("sales_order_"+p, p, 1, "RANGE", "to_days(`created_at`)", "739221", 5, 16384)
+	}
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("FROM information_schema.PARTITIONS WHERE TABLE_SCHEMA = DATABASE() AND PARTITION_NAME IS NOT NULL AND TABLE_NAME IN ('sales_order_" + p + "')")).
+		WillReturnRows(rows)
+	if partitioned {
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("ALTER TABLE `sales_order_" + p + "` REMOVE PARTITIONING")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+}
+
+func expectArchiveRows(dbMock sqlmock.Sqlmock, p string, hasRows bool) {
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT EXISTS(SELECT 1 FROM `sales_order_" + p + "`)")).
+		WillReturnRows(sqlmock.NewRows([]string{"EXISTS"}).AddRow(hasRows))
+}
+
+func expectExchange(dbMock sqlmock.Sqlmock, p string) *sqlmock.ExpectedExec {
+	return dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("ALTER TABLE `sales_order` EXCHANGE PARTITION `" + p + "` WITH TABLE `sales_order_" + p + "`"))
+}
+
+func TestTables_EnsurePartitions_ArchiveRerun(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	tbls, err := NewTables(WithConnPool(dbc), WithTable("sales_order"))
+	assert.NoError(t, err)
+
+	pp := PartitionPolicy{
+		Table:     "sales_order",
+		Future:    0,
+		Retention: 1,
+		ArchiveTable: func(table, partition string) string {
+			return table + "_" + partition
+		},
+		Now: func() time.Time { return time.Date(2024, 2, 15, 10, 0, 0, 0, time.UTC) },
+	}
+	expectPartitions := func() {
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("FROM information_schema.PARTITIONS WHERE TABLE_SCHEMA = DATABASE() AND PARTITION_NAME IS NOT NULL AND TABLE_NAME IN ('sales_order')")).
+			WillReturnRows(sqlmock.NewRows(partitionColumns).
+				AddRow("sales_order", "p202311", 1, "RANGE", "to_days(`created_at`)", "739221", 5, 16384).
+				AddRow("sales_order", "p202312", 2, "RANGE", "to_days(`created_at`)", "739252", 5, 16384).
+				AddRow("sales_order", "p202401", 3, "RANGE", "to_days(`created_at`)", "739283", 5, 16384).
+				AddRow("sales_order", "p202402", 4, "RANGE", "to_days(`created_at`)", "739312", 5, 16384))
+	}
+
+	// First run: p202311 gets archived, the exchange of p202312 fails after
+	// its archive table has been created.
+	expectPartitions()
+	expectArchiveTable(dbMock, "p202311", true)
+	expectArchiveRows(dbMock, "p202311", false)
+	expectExchange(dbMock, "p202311").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectArchiveTable(dbMock, "p202312", true)
+	expectArchiveRows(dbMock, "p202312", false)
+	expectExchange(dbMock, "p202312").WillReturnError(errors.New("Lock wait timeout exceeded"))
+
+	pc, err := tbls.EnsurePartitions(context.Background(), pp)
+	assert.Error(t, err)
+	assert.Exactly(t, []string{"p202311"}, pc.Archived)
+	assert.Nil(t, pc.Dropped)
+
+	// Second run: both archive tables exist without partitioning. The data of
+	// p202311 already sits in the archive table and must not get exchanged
+	// back.
+	expectPartitions()
+	expectArchiveTable(dbMock, "p202311", false)
+	expectArchiveRows(dbMock, "p202311", true)
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT EXISTS(SELECT 1 FROM `sales_order` PARTITION (`p202311`))")).
+		WillReturnRows(sqlmock.NewRows([]string{"EXISTS"}).AddRow(false))
+	expectArchiveTable(dbMock, "p202312", false)
+	expectArchiveRows(dbMock, "p202312", false)
+	expectExchange(dbMock, "p202312").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("ALTER TABLE `sales_order` DROP PARTITION `p202311`, `p202312`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	pc, err = tbls.EnsurePartitions(context.Background(), pp)
+	assert.NoError(t, err)
+	assert.Exactly(t, PartitionChanges{
+		Archived: []string{"p202311", "p202312"},
+		Dropped:  []string{"p202311", "p202312"},
+	}, pc)
+}
+
+func TestTables_EnsurePartitions_ArchiveConflict(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	tbls, err := NewTables(WithConnPool(dbc), WithTable("sales_order"))
+	assert.NoError(t, err)
+
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("FROM information_schema.PARTITIONS WHERE TABLE_SCHEMA = DATABASE() AND PARTITION_NAME IS NOT NULL AND TABLE_NAME IN ('sales_order')")).
+		WillReturnRows(sqlmock.NewRows(partitionColumns).
+			AddRow("sales_order", "p202311", 1, "RANGE", "to_days(`created_at`)", "739221", 5, 16384).
+			AddRow("sales_order", "p202402", 2, "RANGE", "to_days(`created_at`)", "739312", 5, 16384))
+	expectArchiveTable(dbMock, "p202311", false)
+	expectArchiveRows(dbMock, "p202311", true)
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT EXISTS(SELECT 1 FROM `sales_order` PARTITION (`p202311`))")).
+		WillReturnRows(sqlmock.NewRows([]string{"EXISTS"}).AddRow(true))
+
+	pc, err := tbls.EnsurePartitions(context.Background(), PartitionPolicy{
+		Table:     "sales_order",
+		Retention: 1,
+		ArchiveTable: func(table, partition string) string {
+			return table + "_" + partition
+		},
+		Now: func() time.Time { return time.Date(2024, 2, 15, 10, 0, 0, 0, time.UTC) },
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "both contain rows")
+	assert.Nil(t, pc.Archived)
+}
@@ -260,6 +260,39 @@ func (t *Table) Update() *dml.Update {
 	return dml.NewUpdate(t.Name).AddColumns(t.columnsUpsert...)
 }
 
+// UpdateVersioned creates a new UPDATE statement without a WHERE clause for
+// optimistic locking. The SET clause increments the column versionColumn by
+// one, based on the current version of the record, `version`=? + 1. Add a
+// WHERE condition `version` = ? to detect concurrent modifications.
+func (t *Table) UpdateVersioned(versionColumn string) *dml.Update {
+	u := dml.NewUpdate(t.Name)
+	found := false
+	for _, c := range t.columnsUpsert {
+		if c == versionColumn {
+			u.AddClauses(dml.Column(c).Expr("? + 1"))
+			found = true
+			continue
+		}
+		u.AddColumns(c)
+	}
+	if !found {
+		u.AddClauses(dml.Column(versionColumn).Expr("? + 1"))
+	}
+	return u
+}
+
+// SoftDelete creates a new UPDATE statement without a WHERE clause which sets
+// the column deletedAtColumn to NOW() instead of deleting the row.
+func (t *Table) SoftDelete(deletedAtColumn string) *dml.Update {
+	return dml.NewUpdate(t.Name).AddClauses(dml.Column(deletedAtColumn).Expr("NOW()"))
+}
+
+// Restore creates a new UPDATE statement without a WHERE clause which sets the
+// column deletedAtColumn to NULL to restore a soft deleted row.
+func (t *Table) Restore(deletedAtColumn string) *dml.Update {
+	return dml.NewUpdate(t.Name).AddClauses(dml.Column(deletedAtColumn).Expr("NULL"))
+}
+
 // WhereByPK puts the primary keys as WHERE clauses into a condition.
 func (t *Table) WhereByPK(op dml.Op) dml.Conditions {
 	cnds := make(dml.Conditions, 0, 1)
@@ -21,6 +21,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
 	"github.com/corestoreio/pkg/sql/dmltest"
 	"github.com/corestoreio/pkg/storage/null"
 	"github.com/corestoreio/pkg/util/assert"
@@ -529,6 +530,60 @@ func TestTable_Artisan_Methods(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Exactly(t, int64(1), id)
 	})
+
+	t.Run("UpdateVersioned", func(t *testing.T) {
+		tblBlock := ddl.NewTable("cms_block",
+			&ddl.Column{Field: "block_id", Pos: 1, DataType: "int", Null: "NO", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "title", Pos: 2, DataType: "varchar", Null: "NO"},
+			&ddl.Column{Field: "version", Pos: 3, DataType: "int", Null: "NO"},
+		)
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `cms_block` SET `title`=?, `version`=? + 1 WHERE (`block_id` = ?) AND (`version` = ?)")).
+			WithArgs("Footer", int64(4), int64(3), int64(4)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		upd := tblBlock.UpdateVersioned("version").Where(
+			dml.Column("block_id").Equal().PlaceHolder(),
+			dml.Column("version").Equal().PlaceHolder(),
+		)
+		res, err := upd.WithDBR(dbc.DB).ExecContext(context.Background(),
+			"Footer", 4, 3, 4,
+		)
+		assert.NoError(t, err)
+		id, err := res.RowsAffected()
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(1), id)
+	})
+
+	t.Run("SoftDelete", func(t *testing.T) {
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `admin_user` SET `lognum`=NOW() WHERE (`user_id` = ?) AND (`lognum` IS NULL)")).
+			WithArgs(int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		upd := tblAdmUser.SoftDelete("lognum").Where(
+			dml.Column("user_id").Equal().PlaceHolder(),
+			dml.Column("lognum").Null(),
+		)
+		res, err := upd.WithDBR(dbc.DB).ExecContext(context.Background(), 3)
+		assert.NoError(t, err)
+		id, err := res.RowsAffected()
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(1), id)
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `admin_user` SET `lognum`=NULL WHERE (`user_id` = ?)")).
+			WithArgs(int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		upd := tblAdmUser.Restore("lognum").Where(
+			dml.Column("user_id").Equal().PlaceHolder(),
+		)
+		res, err := upd.WithDBR(dbc.DB).ExecContext(context.Background(), 3)
+		assert.NoError(t, err)
+		id, err := res.RowsAffected()
+		assert.NoError(t, err)
+		assert.Exactly(t, int64(1), id)
+	})
 }
 
 func TestTable_GeneratedColumns(t *testing.T) {
@@ -0,0 +1,328 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"time"
+
+	"github.com/corestoreio/pkg/storage/null"
+)
+
+// The typed column descriptors get generated by dmlgen for each table and
+// create conditions with the Go type of the column. Renaming a column or
+// changing its type breaks the build instead of failing at runtime. For
+// example:
+//
+//	dml.NewSelect("*").From("customer_entity").Where(
+//		CustomerEntityColumns.Email.Like("%@example.com"),
+//		CustomerEntityColumns.EntityID.In(1, 2, 3),
+//	)
+//
+// The value of a descriptor is the name of the column. Qualify prefixes it
+// with a table name or an alias.
+
+// Signed defines the types of a ColumnInt.
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned defines the types of a ColumnUint.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+func toInt64s[T Signed](v []T) []int64 {
+	r := make([]int64, len(v))
+	for i, vv := range v {
+		r[i] = int64(vv)
+	}
+	return r
+}
+
+func toUint64s[T Unsigned](v []T) []uint64 {
+	r := make([]uint64, len(v))
+	for i, vv := range v {
+		r[i] = uint64(vv)
+	}
+	return r
+}
+
+// ColumnInt describes a signed integer column.
+type ColumnInt[T Signed] string
+
+// Name returns the name of the column.
+func (c ColumnInt[T]) Name() string { return string(c) }
+
+// Qualify prefixes the column with a table name or alias.
+func (c ColumnInt[T]) Qualify(qualifier string) ColumnInt[T] {
+	return ColumnInt[T](qualifier + "." + string(c))
+}
+
+func (c ColumnInt[T]) Equal(v T) *Condition { return Column(string(c)).Equal().Int64(int64(v)) }
+func (c ColumnInt[T]) NotEqual(v T) *Condition {
+	return Column(string(c)).NotEqual().Int64(int64(v))
+}
+func (c ColumnInt[T]) Less(v T) *Condition    { return Column(string(c)).Less().Int64(int64(v)) }
+func (c ColumnInt[T]) Greater(v T) *Condition { return Column(string(c)).Greater().Int64(int64(v)) }
+func (c ColumnInt[T]) LessOrEqual(v T) *Condition {
+	return Column(string(c)).LessOrEqual().Int64(int64(v))
+}
+
+func (c ColumnInt[T]) GreaterOrEqual(v T) *Condition {
+	return Column(string(c)).GreaterOrEqual().Int64(int64(v))
+}
+
+func (c ColumnInt[T]) In(v ...T) *Condition { return Column(string(c)).In().Int64s(toInt64s(v)...) }
+func (c ColumnInt[T]) NotIn(v ...T) *Condition {
+	return Column(string(c)).NotIn().Int64s(toInt64s(v)...)
+}
+
+func (c ColumnInt[T]) Between(from, to T) *Condition {
+	return Column(string(c)).Between().Int64s(int64(from), int64(to))
+}
+func (c ColumnInt[T]) Null() *Condition        { return Column(string(c)).Null() }
+func (c ColumnInt[T]) NotNull() *Condition     { return Column(string(c)).NotNull() }
+func (c ColumnInt[T]) PlaceHolder() *Condition { return Column(string(c)).PlaceHolder() }
+
+// ColumnUint describes an unsigned integer column.
+type ColumnUint[T Unsigned] string
+
+// Name returns the name of the column.
+func (c ColumnUint[T]) Name() string { return string(c) }
+
+// Qualify prefixes the column with a table name or alias.
+func (c ColumnUint[T]) Qualify(qualifier string) ColumnUint[T] {
+	return ColumnUint[T](qualifier + "." + string(c))
+}
+
+func (c ColumnUint[T]) Equal(v T) *Condition { return Column(string(c)).Equal().Uint64(uint64(v)) }
+func (c ColumnUint[T]) NotEqual(v T) *Condition {
+	return Column(string(c)).NotEqual().Uint64(uint64(v))
+}
+func (c ColumnUint[T]) Less(v T) *Condition { return Column(string(c)).Less().Uint64(uint64(v)) }
+func (c ColumnUint[T]) Greater(v T) *Condition {
+	return Column(string(c)).Greater().Uint64(uint64(v))
+}
+
+func (c ColumnUint[T]) LessOrEqual(v T) *Condition {
+	return Column(string(c)).LessOrEqual().Uint64(uint64(v))
+}
+
+func (c ColumnUint[T]) GreaterOrEqual(v T) *Condition {
+	return Column(string(c)).GreaterOrEqual().Uint64(uint64(v))
+}
+
+func (c ColumnUint[T]) In(v ...T) *Condition {
+	return Column(string(c)).In().Uint64s(toUint64s(v)...)
+}
+
+func (c ColumnUint[T]) NotIn(v ...T) *Condition {
+	return Column(string(c)).NotIn().Uint64s(toUint64s(v)...)
+}
+
+func (c ColumnUint[T]) Between(from, to T) *Condition {
+	return Column(string(c)).Between().Uint64s(uint64(from), uint64(to))
+}
+func (c ColumnUint[T]) Null() *Condition        { return Column(string(c)).Null() }
+func (c ColumnUint[T]) NotNull() *Condition     { return Column(string(c)).NotNull() }
+func (c ColumnUint[T]) PlaceHolder() *Condition { return Column(string(c)).PlaceHolder() }
+
+// ColumnFloat64 describes a float or double column.
+type ColumnFloat64 string
+
+// Name returns the name of the column.
+func (c ColumnFloat64) Name() string { return string(c) }
+
+// Qualify prefixes the column with a table name or alias.
+func (c ColumnFloat64) Qualify(qualifier string) ColumnFloat64 {
+	return ColumnFloat64(qualifier + "." + string(c))
+}
+func (c ColumnFloat64) Equal(v float64) *Condition    { return Column(string(c)).Equal().Float64(v) }
+func (c ColumnFloat64) NotEqual(v float64) *Condition { return Column(string(c)).NotEqual().Float64(v) }
+func (c ColumnFloat64) Less(v float64) *Condition     { return Column(string(c)).Less().Float64(v) }
+func (c ColumnFloat64) Greater(v float64) *Condition  { return Column(string(c)).Greater().Float64(v) }
+func (c ColumnFloat64) LessOrEqual(v float64) *Condition {
+	return Column(string(c)).LessOrEqual().Float64(v)
+}
+
+func (c ColumnFloat64) GreaterOrEqual(v float64) *Condition {
+	return Column(string(c)).GreaterOrEqual().Float64(v)
+}
+func (c ColumnFloat64) In(v ...float64) *Condition { return Column(string(c)).In().Float64s(v...) }
+func (c ColumnFloat64) NotIn(v ...float64) *Condition {
+	return Column(string(c)).NotIn().Float64s(v...)
+}
+func (c ColumnFloat64) Between(from, to float64) *Condition {
+	return Column(string(c)).Between().Float64s(from, to)
+}
+func (c ColumnFloat64) Null() *Condition        { return Column(string(c)).Null() }
+func (c ColumnFloat64) NotNull() *Condition     { return Column(string(c)).NotNull() }
+func (c ColumnFloat64) PlaceHolder() *Condition { return Column(string(c)).PlaceHolder() }
+
+// ColumnDecimal describes a decimal column.
+type ColumnDecimal string
+
+func decimalStrings(v []null.Decimal) []string {
+	r := make([]string, len(v))
+	for i, d := range v {
+		r[i] = d.String()
+	}
+	return r
+}
+
+// Name returns the name of the column.
+func (c ColumnDecimal) Name() string { return string(c) }
+
+// Qualify prefixes the column with a table name or alias.
+func (c ColumnDecimal) Qualify(qualifier string) ColumnDecimal {
+	return ColumnDecimal(qualifier + "." + string(c))
+}
+
+func (c ColumnDecimal) Equal(v null.Decimal) *Condition {
+	return Column(string(c)).Equal().Decimal(v)
+}
+
+func (c ColumnDecimal) NotEqual(v null.Decimal) *Condition {
+	return Column(string(c)).NotEqual().Decimal(v)
+}
+func (c ColumnDecimal) Less(v null.Decimal) *Condition { return Column(string(c)).Less().Decimal(v) }
+func (c ColumnDecimal) Greater(v null.Decimal) *Condition {
+	return Column(string(c)).Greater().Decimal(v)
+}
+
+func (c ColumnDecimal) LessOrEqual(v null.Decimal) *Condition {
+	return Column(string(c)).LessOrEqual().Decimal(v)
+}
+
+func (c ColumnDecimal) GreaterOrEqual(v null.Decimal) *Condition {
+	return Column(string(c)).GreaterOrEqual().Decimal(v)
+}
+
+func (c ColumnDecimal) In(v ...null.Decimal) *Condition {
+	return Column(string(c)).In().Strs(decimalStrings(v)...)
+}
+
+func (c ColumnDecimal) NotIn(v ...null.Decimal) *Condition {
+	return Column(string(c)).NotIn().Strs(decimalStrings(v)...)
+}
+
+func (c ColumnDecimal) Between(from, to null.Decimal) *Condition {
+	return Column(string(c)).Between().Strs(from.String(), to.String())
+}
+func (c ColumnDecimal) Null() *Condition        { return Column(string(c)).Null() }
+func (c ColumnDecimal) NotNull() *Condition     { return Column(string(c)).NotNull() }
+func (c ColumnDecimal) PlaceHolder() *Condition { return Column(string(c)).PlaceHolder() }
+
+// ColumnStr describes a character, text, enum or set column.
+type ColumnStr string
+
+// Name returns the name of the column.
+func (c ColumnStr) Name() string { return string(c) }
+
+// Qualify prefixes the column with a table name or alias.
+func (c ColumnStr) Qualify(qualifier string) ColumnStr {
+	return ColumnStr(qualifier + "." + string(c))
+}
+func (c ColumnStr) Equal(v string) *Condition       { return Column(string(c)).Equal().Str(v) }
+func (c ColumnStr) NotEqual(v string) *Condition    { return Column(string(c)).NotEqual().Str(v) }
+func (c ColumnStr) Less(v string) *Condition        { return Column(string(c)).Less().Str(v) }
+func (c ColumnStr) Greater(v string) *Condition     { return Column(string(c)).Greater().Str(v) }
+func (c ColumnStr) LessOrEqual(v string) *Condition { return Column(string(c)).LessOrEqual().Str(v) }
+func (c ColumnStr) GreaterOrEqual(v string) *Condition {
+	return Column(string(c)).GreaterOrEqual().Str(v)
+}
+func (c ColumnStr) In(v ...string) *Condition      { return Column(string(c)).In().Strs(v...) }
+func (c ColumnStr) NotIn(v ...string) *Condition   { return Column(string(c)).NotIn().Strs(v...) }
+func (c ColumnStr) Like(pattern string) *Condition { return Column(string(c)).Like().Str(pattern) }
+func (c ColumnStr) NotLike(pattern string) *Condition {
+	return Column(string(c)).NotLike().Str(pattern)
+}
+
+func (c ColumnStr) Regexp(pattern string) *Condition {
+	return Column(string(c)).Regexp().Str(pattern)
+}
+
+func (c ColumnStr) Between(from, to string) *Condition {
+	return Column(string(c)).Between().Strs(from, to)
+}
+func (c ColumnStr) Null() *Condition        { return Column(string(c)).Null() }
+func (c ColumnStr) NotNull() *Condition     { return Column(string(c)).NotNull() }
+func (c ColumnStr) PlaceHolder() *Condition { return Column(string(c)).PlaceHolder() }
+
+// ColumnBool describes a boolean column, for example tinyint(1).
+type ColumnBool string
+
+// Name returns the name of the column.
+func (c ColumnBool) Name() string { return string(c) }
+
+// Qualify prefixes the column with a table name or alias.
+func (c ColumnBool) Qualify(qualifier string) ColumnBool {
+	return ColumnBool(qualifier + "." + string(c))
+}
+func (c ColumnBool) Equal(v bool) *Condition    { return Column(string(c)).Equal().Bool(v) }
+func (c ColumnBool) NotEqual(v bool) *Condition { return Column(string(c)).NotEqual().Bool(v) }
+func (c ColumnBool) Null() *Condition           { return Column(string(c)).Null() }
+func (c ColumnBool) NotNull() *Condition        { return Column(string(c)).NotNull() }
+func (c ColumnBool) PlaceHolder() *Condition    { return Column(string(c)).PlaceHolder() }
+
+// ColumnTime describes a date, datetime, timestamp or time column.
+type ColumnTime string
+
+// Name returns the name of the column.
+func (c ColumnTime) Name() string { return string(c) }
+
+// Qualify prefixes the column with a table name or alias.
+func (c ColumnTime) Qualify(qualifier string) ColumnTime {
+	return ColumnTime(qualifier + "." + string(c))
+}
+func (c ColumnTime) Equal(v time.Time) *Condition    { return Column(string(c)).Equal().Time(v) }
+func (c ColumnTime) NotEqual(v time.Time) *Condition { return Column(string(c)).NotEqual().Time(v) }
+func (c ColumnTime) Less(v time.Time) *Condition     { return Column(string(c)).Less().Time(v) }
+func (c ColumnTime) Greater(v time.Time) *Condition  { return Column(string(c)).Greater().Time(v) }
+func (c ColumnTime) LessOrEqual(v time.Time) *Condition {
+	return Column(string(c)).LessOrEqual().Time(v)
+}
+
+func (c ColumnTime) GreaterOrEqual(v time.Time) *Condition {
+	return Column(string(c)).GreaterOrEqual().Time(v)
+}
+func (c ColumnTime) In(v ...time.Time) *Condition    { return Column(string(c)).In().Times(v...) }
+func (c ColumnTime) NotIn(v ...time.Time) *Condition { return Column(string(c)).NotIn().Times(v...) }
+func (c ColumnTime) Between(from, to time.Time) *Condition {
+	return Column(string(c)).Between().Times(from, to)
+}
+func (c ColumnTime) Null() *Condition        { return Column(string(c)).Null() }
+func (c ColumnTime) NotNull() *Condition     { return Column(string(c)).NotNull() }
+func (c ColumnTime) PlaceHolder() *Condition { return Column(string(c)).PlaceHolder() }
+
+// ColumnBytes describes a binary or blob column.
+type ColumnBytes string
+
+// Name returns the name of the column.
+func (c ColumnBytes) Name() string { return string(c) }
+
+// Qualify prefixes the column with a table name or alias.
+func (c ColumnBytes) Qualify(qualifier string) ColumnBytes {
+	return ColumnBytes(qualifier + "." + string(c))
+}
+func (c ColumnBytes) Equal(v []byte) *Condition    { return Column(string(c)).Equal().Bytes(v) }
+func (c ColumnBytes) NotEqual(v []byte) *Condition { return Column(string(c)).NotEqual().Bytes(v) }
+func (c ColumnBytes) In(v ...[]byte) *Condition    { return Column(string(c)).In().BytesSlice(v...) }
+func (c ColumnBytes) NotIn(v ...[]byte) *Condition {
+	return Column(string(c)).NotIn().BytesSlice(v...)
+}
+func (c ColumnBytes) Null() *Condition        { return Column(string(c)).Null() }
+func (c ColumnBytes) NotNull() *Condition     { return Column(string(c)).NotNull() }
+func (c ColumnBytes) PlaceHolder() *Condition { return Column(string(c)).PlaceHolder() }
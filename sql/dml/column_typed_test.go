@@ -0,0 +1,89 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+type testEntityID uint32
+
+var testColumns = struct {
+	EntityID  ColumnUint[testEntityID]
+	StoreID   ColumnInt[int16]
+	Email     ColumnStr
+	Price     ColumnDecimal
+	Weight    ColumnFloat64
+	IsActive  ColumnBool
+	CreatedAt ColumnTime
+	Hash      ColumnBytes
+}{
+	EntityID:  "entity_id",
+	StoreID:   "store_id",
+	Email:     "email",
+	Price:     "price",
+	Weight:    "weight",
+	IsActive:  "is_active",
+	CreatedAt: "created_at",
+	Hash:      "hash",
+}
+
+func TestColumnTyped(t *testing.T) {
+	t.Run("name and qualify", func(t *testing.T) {
+		assert.Exactly(t, "entity_id", testColumns.EntityID.Name())
+		assert.Exactly(t, "ce.entity_id", testColumns.EntityID.Qualify("ce").Name())
+	})
+
+	t.Run("integers", func(t *testing.T) {
+		s := NewSelect("email").From("customer_entity").Where(
+			testColumns.EntityID.In(1, 2, 3),
+			testColumns.StoreID.Between(-1, 5),
+			testColumns.EntityID.Qualify("customer_entity").NotEqual(4),
+			testColumns.StoreID.GreaterOrEqual(0),
+		)
+		compareToSQL2(t, s, false, "SELECT `email` FROM `customer_entity` WHERE (`entity_id` IN (1,2,3)) AND (`store_id` BETWEEN -1 AND 5) AND (`customer_entity`.`entity_id` != 4) AND (`store_id` >= 0)")
+	})
+
+	t.Run("strings and decimals", func(t *testing.T) {
+		s := NewSelect("email").From("customer_entity").Where(
+			testColumns.Email.Like("%@example.com"),
+			testColumns.Email.NotIn("a", "b"),
+			testColumns.Price.Less(null.MakeDecimalInt64(1299, 2)),
+			testColumns.Weight.Null(),
+		)
+		compareToSQL2(t, s, false, "SELECT `email` FROM `customer_entity` WHERE (`email` LIKE '%@example.com') AND (`email` NOT IN ('a','b')) AND (`price` < '12.99') AND (`weight` IS NULL)")
+	})
+
+	t.Run("bool time bytes", func(t *testing.T) {
+		now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		s := NewSelect("email").From("customer_entity").Where(
+			testColumns.IsActive.Equal(true),
+			testColumns.CreatedAt.Greater(now),
+			testColumns.Hash.NotNull(),
+		)
+		compareToSQL2(t, s, false, "SELECT `email` FROM `customer_entity` WHERE (`is_active` = 1) AND (`created_at` > '2020-01-02 03:04:05') AND (`hash` IS NOT NULL)")
+	})
+
+	t.Run("placeholder", func(t *testing.T) {
+		s := NewSelect("email").From("customer_entity").Where(
+			testColumns.EntityID.PlaceHolder(),
+		)
+		compareToSQL2(t, s, false, "SELECT `email` FROM `customer_entity` WHERE (`entity_id` = ?)")
+	})
+}
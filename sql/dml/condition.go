@@ -1097,7 +1097,10 @@ func (cs Conditions) writeSetClauses(w *bytes.Buffer, placeHolders []string) ([]
 			if _, err := writeExpression(w, cnd.Right.Column, cnd.Right.args); err != nil {
 				return nil, errors.WithStack(err)
 			}
-			placeHolders = append(placeHolders, cnd.Left)
+			// expressions like NOW() or NULL don't need a value from a record.
+			if strings.IndexByte(cnd.Right.Column, placeHolderRune) >= 0 {
+				placeHolders = append(placeHolders, cnd.Left)
+			}
 		case cnd.Right.Sub != nil:
 			w.WriteByte('(')
 			var err error
@@ -46,10 +46,11 @@
 // Database locks should not be used by the average developer. Understand
 // optimistic concurrency and use serializable isolation.
 //
-// TODO(CyS) Soft deletion with system versioned tables (MariaDB only) see here
-//
-//	 where they discuss various concepts about soft deletion:
-//		https://news.ycombinator.com/item?id=34202606 and here https://news.ycombinator.com/item?id=32156009
+// Soft deletion gets supported via a deleted_at column or via system
+// versioned tables (MariaDB only), see QueryOptions.WithDeleted,
+// QueryOptions.OnlyDeleted and Select.ForSystemTimeAsOf. Various concepts about
+// soft deletion are discussed here:
+// https://news.ycombinator.com/item?id=34202606 and here https://news.ycombinator.com/item?id=32156009
 //
 // TODO(CyS) refactor some parts of the code once Go implements generics ;-)
 //
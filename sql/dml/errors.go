@@ -40,6 +40,24 @@ func (e *Error) Unwrap() error {
 	return e.Err
 }
 
+// ConflictError gets returned by generated code when an UPDATE statement with
+// an optimistic locking condition did not affect any row. The row has been
+// modified or deleted by someone else since it has been loaded.
+type ConflictError struct {
+	Table   string
+	Version any // the version the UPDATE statement expected
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("[dml] optimistic locking conflict in table %q with version %v", e.Table, e.Version)
+}
+
+// IsConflictError reports whether err contains a *ConflictError.
+func IsConflictError(err error) bool {
+	var ce *ConflictError
+	return errors.As(err, &ce)
+}
+
 // MySQLNumberFromError returns the error code number from an error. The error
 // has been generated by the driver go-sql-driver/mysql. A list of error codes
 // can be accessed here: https://mariadb.com/kb/en/mariadb-error-codes/ Returns
@@ -8,7 +8,10 @@ import (
 	"github.com/go-sql-driver/mysql"
 )
 
-var _ error = (*Error)(nil)
+var (
+	_ error = (*Error)(nil)
+	_ error = (*ConflictError)(nil)
+)
 
 func TestMySQLFromError(t *testing.T) {
 	myErr := &mysql.MySQLError{
@@ -21,3 +24,10 @@ func TestMySQLFromError(t *testing.T) {
 	haveN := MySQLNumberFromError(fmt.Errorf("outer fatal error: %w", myErr))
 	assert.Exactly(t, uint16(1062), haveN)
 }
+
+func TestIsConflictError(t *testing.T) {
+	err := fmt.Errorf("outer: %w", &ConflictError{Table: "customer_entity", Version: uint32(3)})
+	assert.True(t, IsConflictError(err))
+	assert.EqualError(t, err, `outer: [dml] optimistic locking conflict in table "customer_entity" with version 3`)
+	assert.False(t, IsConflictError(fmt.Errorf("outer")))
+}
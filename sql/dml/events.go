@@ -26,8 +26,24 @@ type ctxKeyQueryOption uint8
 // QueryOptions provides different options while executing code for SQL queries.
 type QueryOptions struct {
 	SkipEvents     bool // skips above defined EventFlag
-	SkipTimestamps bool // skips generating timestamps (TODO)
+	SkipTimestamps bool // skips setting the created and updated timestamps
 	SkipRelations  bool // skips executing relation based SQL code
+	SkipVersion    bool // skips the optimistic locking: no check and no increment of the version column
+	WithDeleted    bool // includes soft deleted rows in SELECT queries
+	OnlyDeleted    bool // selects only soft deleted rows, has precedence over WithDeleted
+}
+
+// SoftDeleteCacheKey appends the suffix "OnlyDeleted" or "WithDeleted" to the
+// cacheKey depending on the soft deletion options. Used in generated code to
+// find the correct SELECT query.
+func (qo QueryOptions) SoftDeleteCacheKey(cacheKey string) string {
+	switch {
+	case qo.OnlyDeleted:
+		return cacheKey + "OnlyDeleted"
+	case qo.WithDeleted:
+		return cacheKey + "WithDeleted"
+	}
+	return cacheKey
 }
 
 // WithContextQueryOptions adds options for executing queries, mostly in generated code.
@@ -29,3 +29,10 @@ func TestQueryOptions(t *testing.T) {
 	})
 	assert.True(t, FromContextQueryOptions(ctx).SkipEvents)
 }
+
+func TestQueryOptions_SoftDeleteCacheKey(t *testing.T) {
+	assert.Exactly(t, "CustomerSelectByPK", QueryOptions{}.SoftDeleteCacheKey("CustomerSelectByPK"))
+	assert.Exactly(t, "CustomerSelectByPKWithDeleted", QueryOptions{WithDeleted: true}.SoftDeleteCacheKey("CustomerSelectByPK"))
+	assert.Exactly(t, "CustomerSelectByPKOnlyDeleted", QueryOptions{OnlyDeleted: true}.SoftDeleteCacheKey("CustomerSelectByPK"))
+	assert.Exactly(t, "CustomerSelectByPKOnlyDeleted", QueryOptions{WithDeleted: true, OnlyDeleted: true}.SoftDeleteCacheKey("CustomerSelectByPK"))
+}
@@ -17,8 +17,10 @@ package dml
 import (
 	"bytes"
 	"fmt"
+	"time"
 
 	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/util/bufferpool"
 )
 
 // Select contains the clauses for a SELECT statement. Wildcard `SELECT *`
@@ -49,6 +51,9 @@ type Select struct {
 	IsOrderByDeactivated bool // See OrderByDeactivated()
 	IsOrderByRand        bool // enables the original slow ORDER BY RAND() clause
 	OffsetCount          uint64
+	// SystemTime contains the rendered FOR SYSTEM_TIME clause of MariaDB
+	// system-versioned tables. See ForSystemTimeAsOf() and ForSystemTimeAll().
+	SystemTime string
 }
 
 // NewSelect creates a new Select object.
@@ -127,6 +132,27 @@ func (b *Select) ForUpdate() *Select {
 	return b
 }
 
+// ForSystemTimeAsOf queries a MariaDB system-versioned table at the point in
+// time `t` by adding the clause FOR SYSTEM_TIME AS OF TIMESTAMP to the table
+// in the FROM part. The time gets formatted in the location of `t`.
+// https://mariadb.com/kb/en/system-versioned-tables/
+func (b *Select) ForSystemTimeAsOf(t time.Time) *Select {
+	buf := bufferpool.Get()
+	defer bufferpool.Put(buf)
+	buf.WriteString("AS OF TIMESTAMP ")
+	dialect.EscapeTime(buf, t)
+	b.SystemTime = buf.String()
+	return b
+}
+
+// ForSystemTimeAll queries all current and historical rows of a MariaDB
+// system-versioned table by adding the clause FOR SYSTEM_TIME ALL to the table
+// in the FROM part.
+func (b *Select) ForSystemTimeAll() *Select {
+	b.SystemTime = "ALL"
+	return b
+}
+
 // LockInShareMode sets a shared mode lock on any rows that are read. Other
 // sessions can read the rows, but cannot modify them until your transaction
 // commits. If any of these rows were changed by another transaction that has
@@ -396,7 +422,20 @@ func (b *Select) toSQL(w *bytes.Buffer, placeHolders []string) (_placeHolders []
 
 	if !b.Table.isEmpty() {
 		w.WriteString(" FROM ")
-		if placeHolders, err = b.Table.writeQuoted(w, placeHolders); err != nil {
+		if b.SystemTime != "" && b.Table.DerivedTable == nil {
+			// The FOR SYSTEM_TIME clause must be placed before the alias.
+			tbl := b.Table
+			tbl.Aliased = ""
+			if placeHolders, err = tbl.writeQuoted(w, placeHolders); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			w.WriteString(" FOR SYSTEM_TIME ")
+			w.WriteString(b.SystemTime)
+			if b.Table.Aliased != "" {
+				w.WriteString(" AS ")
+				Quoter.quote(w, b.Table.Aliased)
+			}
+		} else if placeHolders, err = b.Table.writeQuoted(w, placeHolders); err != nil {
 			return nil, errors.WithStack(err)
 		}
 	}
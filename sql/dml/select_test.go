@@ -19,6 +19,7 @@ import (
 	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/corestoreio/pkg/storage/null"
 	"github.com/corestoreio/pkg/util/assert"
@@ -877,6 +878,25 @@ func TestSelect_Locks(t *testing.T) {
 	})
 }
 
+func TestSelect_ForSystemTime(t *testing.T) {
+	t.Run("AS OF with alias", func(t *testing.T) {
+		s := NewSelect("p1.*").
+			FromAlias("dml_people", "p1").
+			Where(Column("p1.id").Int(3)).
+			ForSystemTimeAsOf(time.Date(2016, 10, 9, 8, 7, 6, 0, time.UTC))
+		compareToSQL2(t, s, false,
+			"SELECT `p1`.* FROM `dml_people` FOR SYSTEM_TIME AS OF TIMESTAMP '2016-10-09 08:07:06' AS `p1` WHERE (`p1`.`id` = 3)",
+		)
+	})
+	t.Run("ALL", func(t *testing.T) {
+		s := NewSelect().Star().From("dml_people").ForSystemTimeAll()
+		compareToSQL2(t, s, false,
+			"SELECT * FROM `dml_people` FOR SYSTEM_TIME ALL",
+		)
+		assert.Exactly(t, "ALL", s.Clone().SystemTime)
+	})
+}
+
 func TestSelect_Columns(t *testing.T) {
 	t.Run("AddColumns, multiple args", func(t *testing.T) {
 		s := NewSelect("a", "b")
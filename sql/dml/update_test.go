@@ -95,6 +95,20 @@ func TestUpdate_SetExprToSQL(t *testing.T) {
 		)
 	})
 
+	t.Run("expression without placeholder and record", func(t *testing.T) {
+		u := NewUpdate("a").
+			AddClauses(
+				Column("deleted_at").Expr("NOW()"),
+			).
+			Where(Column("id").PlaceHolder()).
+			WithDBR(dbMock{})
+		compareToSQL(t, u.TestWithArgs(uint(99)), false,
+			"UPDATE `a` SET `deleted_at`=NOW() WHERE (`id` = ?)",
+			"",
+			int64(99))
+		assert.Exactly(t, []string{"id"}, u.cachedSQL.qualifiedColumns)
+	})
+
 	t.Run("with placeholder", func(t *testing.T) {
 		u := NewUpdate("a").
 			AddClauses(
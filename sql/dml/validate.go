@@ -0,0 +1,161 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/storage/null"
+)
+
+// Validation rules used in FieldError.Rule by the Validate functions generated
+// via dmlgen.
+const (
+	RuleRequired  = "required"   // NOT NULL column without a default value
+	RuleMaxLength = "max_length" // characters of (var)char or bytes of (var)binary
+	RuleRange     = "range"      // minimum and maximum of a numeric column
+	RulePrecision = "precision"  // digits of a decimal column
+	RuleEnum      = "enum"       // value of an ENUM column
+	RuleSet       = "set"        // values of a SET column
+	RuleDateRange = "date_range" // supported range of a date or time column
+)
+
+// FieldError describes a value of a struct field which violates the
+// constraint of its table column.
+type FieldError struct {
+	Column  string // name of the table column
+	Field   string // name of the Go struct field
+	Rule    string // see the Rule* constants
+	Message string
+}
+
+func (fe *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", fe.Field, fe.Message)
+}
+
+// FieldErrors gets returned by the generated Validate functions and contains
+// all field errors of an entity. The kind of the error is errors.NotValid.
+type FieldErrors []*FieldError
+
+// Add appends a new FieldError and returns the slice.
+func (fes FieldErrors) Add(column, field, rule, format string, args ...any) FieldErrors {
+	return append(fes, &FieldError{
+		Column:  column,
+		Field:   field,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (fes FieldErrors) Error() string {
+	var buf strings.Builder
+	buf.WriteString("[dml] validation failed: ")
+	for i, fe := range fes {
+		if i > 0 {
+			buf.WriteString("; ")
+		}
+		buf.WriteString(fe.Error())
+	}
+	return buf.String()
+}
+
+// ErrorKind returns errors.NotValid.
+func (fes FieldErrors) ErrorKind() errors.Kind {
+	return errors.NotValid
+}
+
+// ErrorOrNil returns nil if the slice is empty, otherwise the FieldErrors.
+func (fes FieldErrors) ErrorOrNil() error {
+	if len(fes) == 0 {
+		return nil
+	}
+	return fes
+}
+
+// Join merges the FieldErrors contained in err with fes and returns the
+// result of ErrorOrNil. Any other non-nil error gets returned unchanged. It
+// merges the result of a custom validation function with the derived
+// validation.
+func (fes FieldErrors) Join(err error) error {
+	if err == nil {
+		return fes.ErrorOrNil()
+	}
+	if other, ok := err.(FieldErrors); ok {
+		return append(fes, other...).ErrorOrNil()
+	}
+	return err
+}
+
+// ValidEnum reports whether v is one of the values of an ENUM column.
+func ValidEnum(v string, values ...string) bool {
+	for _, ev := range values {
+		if v == ev {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidSet reports whether all comma separated parts of v are values of a SET
+// column. An empty string is a valid set.
+func ValidSet(v string, values ...string) bool {
+	if v == "" {
+		return true
+	}
+	for _, p := range strings.Split(v, ",") {
+		if !ValidEnum(p, values...) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidDecimal reports whether d fits into a DECIMAL(precision,scale) column.
+// Only the digits before the decimal point count, MySQL rounds the fractional
+// part to the scale.
+func ValidDecimal(d null.Decimal, precision, scale int) bool {
+	if !d.Valid {
+		return true
+	}
+	digits := len(d.PrecisionStr)
+	if digits == 0 {
+		digits = len(strconv.FormatUint(d.Precision, 10))
+	}
+	return digits-int(d.Scale) <= precision-scale
+}
+
+var (
+	dateMin      = time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC)
+	dateMax      = time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC)
+	timestampMin = time.Date(1970, 1, 1, 0, 0, 1, 0, time.UTC)
+	timestampMax = time.Date(2038, 1, 19, 3, 14, 7, 999999999, time.UTC)
+)
+
+// ValidDateRange reports whether t lies within the supported range of the
+// MySQL data type date, datetime or timestamp. Other data types return
+// true.
+func ValidDateRange(dataType string, t time.Time) bool {
+	switch dataType {
+	case "date", "datetime":
+		return !t.Before(dateMin) && !t.After(dateMax)
+	case "timestamp":
+		return !t.Before(timestampMin) && !t.After(timestampMax)
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package dml
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+var _ error = (FieldErrors)(nil)
+
+func TestFieldErrors(t *testing.T) {
+	var fe FieldErrors
+	assert.NoError(t, fe.ErrorOrNil())
+	assert.NoError(t, fe.Join(nil))
+
+	fe = fe.Add("email", "Email", RuleMaxLength, "must not exceed %d characters", 5)
+	err := fe.Join(FieldErrors(nil).Add("email", "Email", "custom", "already registered"))
+	assert.EqualError(t, err, "[dml] validation failed: Email: must not exceed 5 characters; Email: already registered")
+	assert.Len(t, err.(FieldErrors), 2)
+	assert.Exactly(t, RuleMaxLength, err.(FieldErrors)[0].Rule)
+
+	other := errors.New("database gone")
+	assert.Exactly(t, other, fe.Join(other))
+}
+
+func TestValidEnumSet(t *testing.T) {
+	assert.True(t, ValidEnum("m", "f", "m"))
+	assert.False(t, ValidEnum("", "f", "m"))
+	assert.True(t, ValidSet("", "a", "b"))
+	assert.True(t, ValidSet("a,b", "a", "b"))
+	assert.False(t, ValidSet("a,c", "a", "b"))
+}
+
+func TestValidDecimal(t *testing.T) {
+	assert.True(t, ValidDecimal(null.Decimal{}, 4, 2))
+	assert.True(t, ValidDecimal(null.Decimal{Precision: 9999, Scale: 2, Valid: true}, 4, 2))   // 99.99
+	assert.True(t, ValidDecimal(null.Decimal{Precision: 99999, Scale: 3, Valid: true}, 4, 2))  // 99.999 gets rounded
+	assert.False(t, ValidDecimal(null.Decimal{Precision: 10000, Scale: 2, Valid: true}, 4, 2)) // 100.00
+	assert.False(t, ValidDecimal(null.Decimal{PrecisionStr: "123456789012345678901234", Valid: true}, 20, 0))
+}
+
+func TestValidDateRange(t *testing.T) {
+	assert.True(t, ValidDateRange("date", time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, ValidDateRange("datetime", time.Date(999, 12, 31, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, ValidDateRange("timestamp", time.Date(2038, 1, 19, 3, 14, 8, 0, time.UTC)))
+	assert.False(t, ValidDateRange("timestamp", time.Unix(0, 0)))
+	assert.True(t, ValidDateRange("time", time.Time{}))
+}
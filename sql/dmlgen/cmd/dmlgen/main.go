@@ -0,0 +1,100 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command dmlgen generates the Go, protobuf and test files of the tables
+// described in a YAML or TOML configuration file. The database connection gets
+// established via the environment variable CS_DSN. A configuration which
+// defines all tables in its schema section and does not load foreign keys runs
+// without a database connection.
+//
+// Example usage:
+//
+//	CS_DSN='user:pass@tcp(localhost:3306)/magento' dmlgen -config dmlgen.yaml
+//	CS_DSN='user:pass@tcp(localhost:3306)/magento' dmlgen -config dmlgen.yaml -check
+//
+// The check mode does not write any files and exits with status code 1 if at
+// least one generated file differs from the file on disk. Use it in CI to
+// detect stale generated code. See testdata/example.yaml and
+// testdata/example.toml for an example configuration.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmlgen"
+)
+
+var (
+	flagConfig  = flag.String("config", "dmlgen.yaml", "path to the YAML or TOML configuration file")
+	flagDir     = flag.String("dir", "", "output directory, defaults to the directory of the configuration file")
+	flagCheck   = flag.Bool("check", false, "check if the generated files are up to date, does not write any files")
+	flagTimeout = flag.Duration("timeout", 2*time.Minute, "maximum duration of the code generation")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  The environment variable %s must contain the database DSN if tables get loaded from the database.\n", dml.EnvDSN)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func start() error {
+	cfg, err := dmlgen.LoadConfigFile(*flagConfig)
+	if err != nil {
+		return err
+	}
+	dir := *flagDir
+	if dir == "" {
+		dir = filepath.Dir(*flagConfig)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *flagTimeout)
+	defer cancel()
+
+	var db *dml.ConnPool
+	if cfg.NeedsDB() {
+		if db, err = dml.NewConnPool(
+			dml.WithDSNFromEnv(""),
+			dml.WithVerifyConnection(ctx, 10*time.Second),
+		); err != nil {
+			return err
+		}
+		defer db.Close()
+	}
+
+	staleFiles, err := cfg.Run(ctx, db, dir, *flagCheck)
+	if err != nil {
+		return err
+	}
+	if len(staleFiles) > 0 {
+		for _, f := range staleFiles {
+			fmt.Fprintf(os.Stderr, "Stale: %s\n", f)
+		}
+		return fmt.Errorf("%d generated files are not up to date, please run %s", len(staleFiles), filepath.Base(os.Args[0]))
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/codegen"
+	"github.com/corestoreio/pkg/util/strs"
+)
+
+// columnDescriptorType returns the typed column descriptor of package dml for
+// the Go type of a column. Nullable columns use the same descriptor as the
+// NOT NULL columns because NULL gets checked via Null and NotNull.
+func (g *Generator) columnDescriptorType(c *ddl.Column) string {
+	switch goType := g.goType(c); {
+	case goType == "string":
+		return "dml.ColumnStr"
+	case goType == "bool":
+		return "dml.ColumnBool"
+	case goType == "float64":
+		return "dml.ColumnFloat64"
+	case goType == "null.Decimal":
+		return "dml.ColumnDecimal"
+	case goType == "time.Time":
+		return "dml.ColumnTime"
+	case goType == "[]byte":
+		return "dml.ColumnBytes"
+	case strings.HasPrefix(goType, "uint"):
+		return "dml.ColumnUint[" + goType + "]"
+	case strings.HasPrefix(goType, "int"):
+		return "dml.ColumnInt[" + goType + "]"
+	default:
+		return "dml.ColumnStr"
+	}
+}
+
+// fnEntityColumns writes the typed column descriptors of a table. Each
+// descriptor creates dml.Condition with the Go type of the column, e.g.
+// CustomerEntityColumns.EntityID.In(1, 2). Renamed or retyped columns break
+// the build of the queries instead of failing at runtime.
+func (t *Table) fnEntityColumns(mainGen *codegen.Go, g *Generator) {
+	if !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureDBColumns) {
+		return
+	}
+	varName := t.EntityName() + `Columns`
+	mainGen.C(varName, `contains the typed columns of table`, t.Table.Name+`.`,
+		`They create the conditions of queries, e.g. `+varName+`.`+strs.ToGoCamelCase(t.Table.Columns.First().Field)+`.Equal(v).`)
+	mainGen.Pln(`var`, varName, `= struct {`)
+	{
+		mainGen.In()
+		t.Table.Columns.Each(func(c *ddl.Column) {
+			mainGen.Pln(strs.ToGoCamelCase(c.Field), g.columnDescriptorType(c))
+		})
+		mainGen.Out()
+	}
+	mainGen.Pln(`}{`)
+	{
+		mainGen.In()
+		t.Table.Columns.Each(func(c *ddl.Column) {
+			mainGen.Pln(strs.ToGoCamelCase(c.Field)+`:`, fmt.Sprintf("%q", c.Field)+`,`)
+		})
+		mainGen.Out()
+	}
+	mainGen.Pln(`}`)
+}
@@ -0,0 +1,36 @@
+package dmlgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestGenerator_EntityColumns(t *testing.T) {
+	g := newFixturesTestGenerator(t)
+	var bufMain, bufTest bytes.Buffer
+	assert.NoError(t, g.GenerateGo(&bufMain, &bufTest))
+	code := bufMain.String()
+
+	assert.Contains(t, code, "var CustomerEntityColumns = struct {")
+	assert.Contains(t, code, "\tEntityID   dml.ColumnUint[uint32]\n")
+	assert.Contains(t, code, "\tEmail      dml.ColumnStr\n")
+	assert.Contains(t, code, "\tGender     dml.ColumnStr\n")
+	assert.Contains(t, code, "\tLevel      dml.ColumnUint[uint8]\n")
+	assert.Contains(t, code, "\tGrandTotal dml.ColumnDecimal\n")
+	assert.Contains(t, code, "\tDob        dml.ColumnTime\n")
+	assert.Contains(t, code, "\tNickname   dml.ColumnStr\n")
+	assert.Contains(t, code, "\tEntityID:   \"entity_id\",\n")
+	assert.Contains(t, code, "var StoreWebsiteColumns = struct {\n\tWebsiteID dml.ColumnUint[uint16]\n\tCode      dml.ColumnStr\n}{")
+}
+
+func TestGenerator_EntityColumns_FeatureExclude(t *testing.T) {
+	g := newFixturesTestGenerator(t)
+	assert.NoError(t, WithTableConfig("store_website", &TableConfig{FeaturesExclude: FeatureDBColumns}).fn(g))
+
+	var bufMain, bufTest bytes.Buffer
+	assert.NoError(t, g.GenerateGo(&bufMain, &bufTest))
+	assert.NotContains(t, bufMain.String(), "StoreWebsiteColumns")
+	assert.Contains(t, bufMain.String(), "CustomerEntityColumns")
+}
@@ -0,0 +1,449 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlgen
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/storage/null"
+	"gopkg.in/yaml.v2"
+)
+
+// Config describes declaratively the code generation of one or more
+// packages. It replaces the hand written main functions calling NewGenerator.
+// The files cmd/dmlgen/testdata/example.yaml and example.toml provide an
+// example.
+type Config struct {
+	Generators []*GeneratorConfig `yaml:"generators" toml:"generators"`
+}
+
+// GeneratorConfig describes the options of one Generator and the files to
+// write.
+type GeneratorConfig struct {
+	// Package defines the full import path of the generated Go package.
+	Package string `yaml:"package" toml:"package"`
+	// Tables to load from the database.
+	Tables []string `yaml:"tables" toml:"tables"`
+	// Schema defines the columns of tables without querying the database.
+	// The map key contains the table name.
+	Schema              map[string][]*ColumnYAML    `yaml:"schema" toml:"schema"`
+	BuildTags           []string                    `yaml:"build_tags" toml:"build_tags"`
+	TestSQLDumpGlobPath string                      `yaml:"test_sql_dump_glob_path" toml:"test_sql_dump_glob_path"`
+	TableConfigDefault  *TableConfigYAML            `yaml:"table_config_default" toml:"table_config_default"`
+	TableConfigs        map[string]*TableConfigYAML `yaml:"table_configs" toml:"table_configs"`
+	Serializer          *SerializerConfigYAML       `yaml:"serializer" toml:"serializer"`
+	GRPCService         *GRPCServiceConfigYAML      `yaml:"grpc_service" toml:"grpc_service"`
+	ForeignKeys         *ForeignKeyOptionsYAML      `yaml:"foreign_keys" toml:"foreign_keys"`
+	Output              OutputConfig                `yaml:"output" toml:"output"`
+}
+
+// ColumnYAML represents a column of a table defined in Schema. The fields
+// match the columns of information_schema.COLUMNS.
+type ColumnYAML struct {
+	Field         string `yaml:"field" toml:"field"`
+	DataType      string `yaml:"data_type" toml:"data_type"`
+	ColumnType    string `yaml:"column_type" toml:"column_type"`
+	Nullable      bool   `yaml:"nullable" toml:"nullable"`
+	Default       string `yaml:"default" toml:"default"`
+	CharMaxLength int64  `yaml:"char_max_length" toml:"char_max_length"`
+	Precision     int64  `yaml:"precision" toml:"precision"`
+	Scale         int64  `yaml:"scale" toml:"scale"`
+	Key           string `yaml:"key" toml:"key"`
+	Extra         string `yaml:"extra" toml:"extra"`
+	Comment       string `yaml:"comment" toml:"comment"`
+}
+
+func (cy *ColumnYAML) toColumn(pos int) *ddl.Column {
+	c := &ddl.Column{
+		Field:      cy.Field,
+		Pos:        uint64(pos),
+		Null:       "NO",
+		DataType:   strings.ToLower(cy.DataType),
+		ColumnType: cy.ColumnType,
+		Key:        cy.Key,
+		Extra:      cy.Extra,
+		Comment:    cy.Comment,
+	}
+	if cy.Nullable {
+		c.Null = "YES"
+	}
+	if cy.Default != "" {
+		c.Default = null.MakeString(cy.Default)
+	}
+	if cy.CharMaxLength > 0 {
+		c.CharMaxLength = null.MakeInt64(cy.CharMaxLength)
+	}
+	if cy.Precision > 0 {
+		c.Precision = null.MakeInt64(cy.Precision)
+		c.Scale = null.MakeInt64(cy.Scale)
+	}
+	return c
+}
+
+// TableConfigYAML represents a TableConfig in a configuration file. Features
+// are a list of feature names, see ParseFeatureToggle.
+type TableConfigYAML struct {
+	Encoders          []string            `yaml:"encoders" toml:"encoders"`
+	StructTags        []string            `yaml:"struct_tags" toml:"struct_tags"`
+	CustomStructTags  []string            `yaml:"custom_struct_tags" toml:"custom_struct_tags"`
+	Comment           string              `yaml:"comment" toml:"comment"`
+	ColumnAliases     map[string][]string `yaml:"column_aliases" toml:"column_aliases"`
+	UniquifiedColumns []string            `yaml:"uniquified_columns" toml:"uniquified_columns"`
+	PrivateFields     []string            `yaml:"private_fields" toml:"private_fields"`
+	FeaturesInclude   []string            `yaml:"features_include" toml:"features_include"`
+	FeaturesExclude   []string            `yaml:"features_exclude" toml:"features_exclude"`
+	VersionColumn     string              `yaml:"version_column" toml:"version_column"`
+	CreatedAtColumn   string              `yaml:"created_at_column" toml:"created_at_column"`
+	UpdatedAtColumn   string              `yaml:"updated_at_column" toml:"updated_at_column"`
+	SoftDeleteColumn  string              `yaml:"soft_delete_column" toml:"soft_delete_column"`
+	SystemVersioned   bool                `yaml:"system_versioned" toml:"system_versioned"`
+}
+
+// SerializerConfigYAML represents WithProtobuf or WithFlatbuffers.
+type SerializerConfigYAML struct {
+	// Type can be protobuf or fbs.
+	Type              string   `yaml:"type" toml:"type"`
+	PackageImportPath string   `yaml:"package_import_path" toml:"package_import_path"`
+	AdditionalHeaders []string `yaml:"additional_headers" toml:"additional_headers"`
+}
+
+// GRPCServiceConfigYAML represents WithGRPCService.
+type GRPCServiceConfigYAML struct {
+	HTTPPathPrefix   string `yaml:"http_path_prefix" toml:"http_path_prefix"`
+	DefaultListLimit uint64 `yaml:"default_list_limit" toml:"default_list_limit"`
+	MaxListLimit     uint64 `yaml:"max_list_limit" toml:"max_list_limit"`
+}
+
+// ForeignKeyOptionsYAML represents WithForeignKeyRelationships.
+type ForeignKeyOptionsYAML struct {
+	IncludeRelationShips []string `yaml:"include_relationships" toml:"include_relationships"`
+	ExcludeRelationships []string `yaml:"exclude_relationships" toml:"exclude_relationships"`
+}
+
+// OutputConfig defines the file names, relative to the directory of the
+// configuration file, of the generated code. An empty file name skips the
+// generation. The test file of Go defaults to the Go file name with suffix
+// _test.go. The test file of the serializer defaults to the serializer file
+// name without extension and suffix _test.go, it only gets written if the
+// serializer generates tests.
+type OutputConfig struct {
+	Go             string `yaml:"go" toml:"go"`
+	GoTest         string `yaml:"go_test" toml:"go_test"`
+	Serializer     string `yaml:"serializer" toml:"serializer"`
+	SerializerTest string `yaml:"serializer_test" toml:"serializer_test"`
+	GRPCServer     string `yaml:"grpc_server" toml:"grpc_server"`
+	JSONSchema     string `yaml:"json_schema" toml:"json_schema"`
+	OpenAPI        string `yaml:"openapi" toml:"openapi"`
+	Fixtures       string `yaml:"fixtures" toml:"fixtures"`
+}
+
+// LoadConfig decodes a YAML configuration. Unknown keys return an error to
+// catch typos.
+func LoadConfig(r io.Reader) (*Config, error) {
+	var c Config
+	d := yaml.NewDecoder(r)
+	d.SetStrict(true)
+	if err := d.Decode(&c); err != nil {
+		return nil, errors.NotValid.New(err, "[dmlgen] LoadConfig: Failed to decode YAML")
+	}
+	if err := c.validate(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &c, nil
+}
+
+// LoadConfigTOML decodes a TOML configuration. Unknown keys return an error
+// to catch typos.
+func LoadConfigTOML(r io.Reader) (*Config, error) {
+	var c Config
+	md, err := toml.DecodeReader(r, &c)
+	if err != nil {
+		return nil, errors.NotValid.New(err, "[dmlgen] LoadConfigTOML: Failed to decode TOML")
+	}
+	if keys := md.Undecoded(); len(keys) > 0 {
+		return nil, errors.NotValid.Newf("[dmlgen] LoadConfigTOML: Unknown keys %q", keys)
+	}
+	if err := c.validate(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &c, nil
+}
+
+func (c *Config) validate() error {
+	for i, gc := range c.Generators {
+		if gc.Package == "" {
+			return errors.NotValid.Newf("[dmlgen] LoadConfig: Generator %d has an empty package", i)
+		}
+		for tn, cols := range gc.Schema {
+			for ci, col := range cols {
+				if col.Field == "" || col.DataType == "" {
+					return errors.NotValid.Newf("[dmlgen] LoadConfig: Generator %d schema %q column %d requires field and data_type", i, tn, ci)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// LoadConfigFile loads a YAML or TOML configuration file depending on the
+// file extension.
+func LoadConfigFile(fileName string) (*Config, error) {
+	var load func(io.Reader) (*Config, error)
+	switch ext := filepath.Ext(fileName); ext {
+	case ".yaml", ".yml", ".json":
+		load = LoadConfig
+	case ".toml":
+		load = LoadConfigTOML
+	default:
+		return nil, errors.NotSupported.Newf("[dmlgen] LoadConfigFile: File extension %q not supported", ext)
+	}
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer f.Close()
+	return load(f)
+}
+
+// NeedsDB reports whether at least one generator loads tables or foreign
+// keys from the database. Configurations which define all tables in Schema
+// can run without a database connection.
+func (c *Config) NeedsDB() bool {
+	for _, gc := range c.Generators {
+		if len(gc.Tables) > 0 || gc.ForeignKeys != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (tc *TableConfigYAML) toTableConfig() (*TableConfig, error) {
+	fi, err := ParseFeatureToggle(tc.FeaturesInclude...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	fe, err := ParseFeatureToggle(tc.FeaturesExclude...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(tc.CustomStructTags)%2 == 1 {
+		return nil, errors.NotValid.Newf("[dmlgen] TableConfig: custom_struct_tags must be a balanced slice")
+	}
+	return &TableConfig{
+		Encoders:          tc.Encoders,
+		StructTags:        tc.StructTags,
+		CustomStructTags:  tc.CustomStructTags,
+		Comment:           tc.Comment,
+		ColumnAliases:     tc.ColumnAliases,
+		UniquifiedColumns: tc.UniquifiedColumns,
+		PrivateFields:     tc.PrivateFields,
+		FeaturesInclude:   fi,
+		FeaturesExclude:   fe,
+		VersionColumn:     tc.VersionColumn,
+		CreatedAtColumn:   tc.CreatedAtColumn,
+		UpdatedAtColumn:   tc.UpdatedAtColumn,
+		SoftDeleteColumn:  tc.SoftDeleteColumn,
+		SystemVersioned:   tc.SystemVersioned,
+	}, nil
+}
+
+// Options converts the configuration into the options for NewGenerator. If db
+// is nil, the tables and foreign keys do not get loaded from the database.
+func (gc *GeneratorConfig) Options(ctx context.Context, db *dml.ConnPool) ([]Option, error) {
+	opts := make([]Option, 0, len(gc.Schema)+len(gc.TableConfigs)+6)
+	if db != nil && len(gc.Tables) > 0 {
+		opts = append(opts, WithTablesFromDB(ctx, db, gc.Tables...))
+	}
+	schemaTables := make([]string, 0, len(gc.Schema))
+	for tn := range gc.Schema {
+		schemaTables = append(schemaTables, tn)
+	}
+	sort.Strings(schemaTables)
+	for _, tn := range schemaTables {
+		cols := make(ddl.Columns, len(gc.Schema[tn]))
+		for i, cy := range gc.Schema[tn] {
+			cols[i] = cy.toColumn(i + 1)
+		}
+		opts = append(opts, WithTable(tn, cols))
+	}
+	if len(gc.BuildTags) > 0 {
+		opts = append(opts, WithBuildTags(gc.BuildTags...))
+	}
+	if gc.TableConfigDefault != nil {
+		tc, err := gc.TableConfigDefault.toTableConfig()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		opts = append(opts, WithTableConfigDefault(*tc))
+	}
+
+	tableNames := make([]string, 0, len(gc.TableConfigs))
+	for tn := range gc.TableConfigs {
+		tableNames = append(tableNames, tn)
+	}
+	sort.Strings(tableNames)
+	for _, tn := range tableNames {
+		tc, err := gc.TableConfigs[tn].toTableConfig()
+		if err != nil {
+			return nil, errors.Wrapf(err, "[dmlgen] Table %q", tn)
+		}
+		opts = append(opts, WithTableConfig(tn, tc))
+	}
+
+	if s := gc.Serializer; s != nil {
+		sc := &SerializerConfig{PackageImportPath: s.PackageImportPath, AdditionalHeaders: s.AdditionalHeaders}
+		switch s.Type {
+		case "protobuf":
+			opts = append(opts, WithProtobuf(sc))
+		case "fbs":
+			opts = append(opts, WithFlatbuffers(sc))
+		default:
+			return nil, errors.NotSupported.Newf("[dmlgen] Serializer %q not supported", s.Type)
+		}
+	}
+	if s := gc.GRPCService; s != nil {
+		opts = append(opts, WithGRPCService(&GRPCServiceConfig{
+			HTTPPathPrefix:   s.HTTPPathPrefix,
+			DefaultListLimit: s.DefaultListLimit,
+			MaxListLimit:     s.MaxListLimit,
+		}))
+	}
+	if fk := gc.ForeignKeys; fk != nil && db != nil {
+		opts = append(opts, WithForeignKeyRelationships(ctx, db.DB, ForeignKeyOptions{
+			IncludeRelationShips: fk.IncludeRelationShips,
+			ExcludeRelationships: fk.ExcludeRelationships,
+		}))
+	}
+	return opts, nil
+}
+
+// NewGenerator creates a new Generator from the configuration. Argument
+// additional gets applied after the options of the configuration, for
+// example to add tables without a database connection.
+func (gc *GeneratorConfig) NewGenerator(ctx context.Context, db *dml.ConnPool, additional ...Option) (*Generator, error) {
+	opts, err := gc.Options(ctx, db)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	g, err := NewGenerator(gc.Package, append(opts, additional...)...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	g.TestSQLDumpGlobPath = gc.TestSQLDumpGlobPath
+	return g, nil
+}
+
+// GenerateFiles generates the code and returns the content of the files
+// defined in Output. The map key contains the file name.
+func (gc *GeneratorConfig) GenerateFiles(g *Generator) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	o := gc.Output
+	if o.Go != "" {
+		var main, test bytes.Buffer
+		if err := g.GenerateGo(&main, &test); err != nil {
+			return nil, errors.Wrapf(err, "[dmlgen] Failed to generate %q", o.Go)
+		}
+		files[o.Go] = main.Bytes()
+		testFile := o.GoTest
+		if testFile == "" {
+			testFile = strings.TrimSuffix(o.Go, ".go") + "_test.go"
+		}
+		files[testFile] = test.Bytes()
+	}
+	if o.Serializer != "" {
+		var main, test bytes.Buffer
+		if err := g.GenerateSerializer(&main, &test); err != nil {
+			return nil, errors.Wrapf(err, "[dmlgen] Failed to generate %q", o.Serializer)
+		}
+		files[o.Serializer] = main.Bytes()
+		if test.Len() > 0 {
+			testFile := o.SerializerTest
+			if testFile == "" {
+				testFile = strings.TrimSuffix(o.Serializer, filepath.Ext(o.Serializer)) + "_test.go"
+			}
+			files[testFile] = test.Bytes()
+		}
+	}
+	for _, f := range []struct {
+		name string
+		fn   func(io.Writer) error
+	}{
+		{o.GRPCServer, g.GenerateGRPCServer},
+		{o.JSONSchema, g.GenerateJSONSchema},
+		{o.OpenAPI, g.GenerateOpenAPI},
+		{o.Fixtures, g.GenerateFixtures},
+	} {
+		if f.name == "" {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := f.fn(&buf); err != nil {
+			return nil, errors.Wrapf(err, "[dmlgen] Failed to generate %q", f.name)
+		}
+		files[f.name] = buf.Bytes()
+	}
+	return files, nil
+}
+
+// Run generates the files of all generators and writes them into directory
+// dir. If check is true, no files get written, instead the names of the files
+// which differ from the generated content get returned. Argument db can be
+// nil, see Options.
+func (c *Config) Run(ctx context.Context, db *dml.ConnPool, dir string, check bool) (staleFiles []string, err error) {
+	for _, gc := range c.Generators {
+		g, err := gc.NewGenerator(ctx, db)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[dmlgen] Package %q", gc.Package)
+		}
+		files, err := gc.GenerateFiles(g)
+		if err != nil {
+			return nil, errors.Wrapf(err, "[dmlgen] Package %q", gc.Package)
+		}
+		fileNames := make([]string, 0, len(files))
+		for fn := range files {
+			fileNames = append(fileNames, fn)
+		}
+		sort.Strings(fileNames)
+
+		for _, fn := range fileNames {
+			path := filepath.Join(dir, fn)
+			if check {
+				current, err := ioutil.ReadFile(path)
+				if err != nil && !os.IsNotExist(err) {
+					return nil, errors.WithStack(err)
+				}
+				if !bytes.Equal(current, files[fn]) {
+					staleFiles = append(staleFiles, path)
+				}
+				continue
+			}
+			if err := ioutil.WriteFile(path, files[fn], 0o644); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+	}
+	return staleFiles, nil
+}
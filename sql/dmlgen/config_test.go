@@ -0,0 +1,199 @@
+package dmlgen
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestParseFeatureToggle(t *testing.T) {
+	ft, err := ParseFeatureToggle("FeatureDB", "CollectionFilter", "FeatureGRPCService")
+	assert.NoError(t, err)
+	assert.Exactly(t, FeatureDB|FeatureCollectionFilter|FeatureGRPCService, ft)
+
+	ft, err = ParseFeatureToggle()
+	assert.NoError(t, err)
+	assert.Exactly(t, FeatureToggle(0), ft)
+
+	_, err = ParseFeatureToggle("DBSelekt")
+	assert.ErrorIsKind(t, errors.NotFound, err)
+}
+
+func TestLoadConfig(t *testing.T) {
+	for _, fileName := range []string{"cmd/dmlgen/testdata/example.yaml", "cmd/dmlgen/testdata/example.toml"} {
+		t.Run(fileName, func(t *testing.T) {
+			c, err := LoadConfigFile(fileName)
+			assert.NoError(t, err)
+			assert.True(t, c.NeedsDB())
+			assert.Len(t, c.Generators, 1)
+			gc := c.Generators[0]
+			assert.Exactly(t, "github.com/corestoreio/pkg/store", gc.Package)
+			assert.Exactly(t, []string{"store_website", "store_group", "store"}, gc.Tables)
+			assert.Exactly(t, "protobuf", gc.Serializer.Type)
+			assert.Exactly(t, uint64(500), gc.GRPCService.MaxListLimit)
+			assert.Exactly(t, []string{"entity_id"}, gc.TableConfigs["store"].ColumnAliases["store_id"])
+			assert.Exactly(t, []string{"store_website.website_id", "customer_entity.website_id"}, gc.ForeignKeys.ExcludeRelationships)
+			assert.Exactly(t, "storepb/entities.proto", gc.Output.Serializer)
+
+			tc, err := gc.TableConfigDefault.toTableConfig()
+			assert.NoError(t, err)
+			assert.Exactly(t, FeatureDBSelect|FeatureEntityStruct|FeatureCollectionStruct|FeatureCollectionFilter, tc.FeaturesInclude)
+		})
+	}
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := LoadConfig(strings.NewReader("generators:\n  - package: a/b\n    tabels: [x]\n"))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+	t.Run("empty package", func(t *testing.T) {
+		_, err := LoadConfig(strings.NewReader("generators:\n  - tables: [x]\n"))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+	t.Run("unknown TOML key", func(t *testing.T) {
+		_, err := LoadConfigTOML(strings.NewReader("[[generators]]\npackage = \"a/b\"\ntabels = [\"x\"]\n"))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+	t.Run("empty TOML package", func(t *testing.T) {
+		_, err := LoadConfigTOML(strings.NewReader("[[generators]]\ntables = [\"x\"]\n"))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+	t.Run("ini not supported", func(t *testing.T) {
+		_, err := LoadConfigFile("dmlgen.ini")
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+	t.Run("schema column without data_type", func(t *testing.T) {
+		_, err := LoadConfig(strings.NewReader("generators:\n  - package: a/b\n    schema:\n      x:\n        - field: id\n"))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+	t.Run("unknown feature", func(t *testing.T) {
+		c, err := LoadConfig(strings.NewReader("generators:\n  - package: a/b\n    table_configs:\n      x:\n        features_include: [Foo]\n"))
+		assert.NoError(t, err)
+		_, err = c.Generators[0].Options(context.TODO(), nil)
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+	t.Run("unknown serializer", func(t *testing.T) {
+		c, err := LoadConfig(strings.NewReader("generators:\n  - package: a/b\n    serializer:\n      type: thrift\n"))
+		assert.NoError(t, err)
+		_, err = c.Generators[0].Options(context.TODO(), nil)
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+}
+
+const testConfigYAML = `generators:
+  - package: github.com/corestoreio/pkg/sql/dmlgen/dmltestconfig
+    build_tags: ["!ignore"]
+    table_configs:
+      core_config_data:
+        encoders: [easyjson]
+        features_include: [EntityStruct, CollectionStruct, DBSelect]
+    output:
+      go: entities_gen.go
+      json_schema: entities.schema.json
+`
+
+func TestConfig_Run(t *testing.T) {
+	c, err := LoadConfig(strings.NewReader(testConfigYAML))
+	assert.NoError(t, err)
+	gc := c.Generators[0]
+
+	g, err := gc.NewGenerator(context.TODO(), nil, WithTable("core_config_data", ddl.Columns{
+		&ddl.Column{Field: "config_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+		&ddl.Column{Field: "path", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(255)"},
+	}))
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"!ignore"}, g.BuildTags)
+
+	files, err := gc.GenerateFiles(g)
+	assert.NoError(t, err)
+	assert.Len(t, files, 3)
+	assert.Contains(t, string(files["entities_gen.go"]), "type CoreConfigData struct {")
+	assert.Contains(t, string(files["entities_gen.go"]), "// +build !ignore")
+	assert.NotNil(t, files["entities_gen_test.go"])
+	assert.Contains(t, string(files["entities.schema.json"]), `"CoreConfigData": {`)
+
+	dir, err := ioutil.TempDir("", "dmlgen_config")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Run does not load any tables without a DB connection, so write the
+	// files of the generator above and compare them.
+	for fn, data := range files {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, fn), data, 0o644))
+	}
+	c.Generators[0].TableConfigs = nil
+	c.Generators[0].Output = OutputConfig{JSONSchema: "entities.schema.json"}
+	stale, err := c.Run(context.TODO(), nil, dir, true)
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{filepath.Join(dir, "entities.schema.json")}, stale, "schema without tables differs")
+
+	stale, err = c.Run(context.TODO(), nil, dir, false)
+	assert.NoError(t, err)
+	assert.Nil(t, stale)
+
+	stale, err = c.Run(context.TODO(), nil, dir, true)
+	assert.NoError(t, err)
+	assert.Nil(t, stale)
+}
+
+const testConfigSchemaYAML = `generators:
+  - package: github.com/corestoreio/pkg/sql/dmlgen/dmltestconfig
+    schema:
+      core_config_data:
+        - {field: config_id, data_type: int, column_type: int(10) unsigned, key: PRI, extra: auto_increment}
+        - {field: path, data_type: varchar, column_type: varchar(255), char_max_length: 255}
+        - {field: value, data_type: text, column_type: text, nullable: true}
+    table_configs:
+      core_config_data:
+        features_include: [EntityStruct, CollectionStruct, DBSelect]
+    serializer:
+      type: protobuf
+    output:
+      go: entities_gen.go
+      serializer: entities.proto
+      json_schema: entities.schema.json
+`
+
+func TestConfig_Run_Schema(t *testing.T) {
+	c, err := LoadConfig(strings.NewReader(testConfigSchemaYAML))
+	assert.NoError(t, err)
+	assert.False(t, c.NeedsDB())
+
+	g, err := c.Generators[0].NewGenerator(context.TODO(), nil)
+	assert.NoError(t, err)
+	tbl := g.Tables["core_config_data"]
+	assert.NotNil(t, tbl)
+	assert.Exactly(t, []string{"config_id", "path", "value"}, tbl.Table.Columns.FieldNames())
+	assert.True(t, tbl.Table.Columns.ByField("config_id").IsAutoIncrement())
+	assert.True(t, tbl.Table.Columns.ByField("value").IsNull())
+	assert.Exactly(t, int64(255), tbl.Table.Columns.ByField("path").CharMaxLength.Int64)
+
+	files, err := c.Generators[0].GenerateFiles(g)
+	assert.NoError(t, err)
+	assert.Contains(t, string(files["entities_gen.go"]), "type CoreConfigData struct {")
+	assert.Contains(t, string(files["entities.proto"]), "message CoreConfigData {")
+	_, ok := files["entities_test.go"]
+	assert.False(t, ok, "protobuf serializer does not generate tests")
+
+	dir, err := ioutil.TempDir("", "dmlgen_config_schema")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	stale, err := c.Run(context.TODO(), nil, dir, false)
+	assert.NoError(t, err)
+	assert.Nil(t, stale)
+
+	stale, err = c.Run(context.TODO(), nil, dir, true)
+	assert.NoError(t, err)
+	assert.Nil(t, stale)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "entities.proto"), []byte("syntax"), 0o644))
+	stale, err = c.Run(context.TODO(), nil, dir, true)
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{filepath.Join(dir, "entities.proto")}, stale)
+}
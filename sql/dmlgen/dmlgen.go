@@ -72,6 +72,9 @@ type Generator struct {
 	// customCode injects custom code to manipulate testing and other generate
 	// code blocks. A few implementations now but more can be added later.
 	customCode map[string]func(*Generator, *Table, io.Writer)
+	// grpcService enables the generation of the gRPC services, see
+	// WithGRPCService.
+	grpcService *GRPCServiceConfig
 
 	kcu    map[string]ddl.KeyColumnUsageCollection
 	kcuRev map[string]ddl.KeyColumnUsageCollection // rev = reversed relationship to find OneToMany
@@ -98,7 +101,9 @@ func NewGenerator(packageImportPath string, opts ...Option) (*Generator, error)
 			"fmt",
 			"io",
 			"sort",
+			"strings",
 			"time",
+			"unicode/utf8",
 
 			"github.com/corestoreio/errors",
 			"github.com/corestoreio/pkg/sql/ddl",
@@ -236,6 +241,7 @@ func (g *Generator) GenerateGo(wMain, wTest io.Writer) error {
 	for _, t := range tables {
 		t.fnEntityRelationStruct(mainGen, g) // this must go first because to check if a table has relations
 		t.fnEntityStruct(mainGen, g)
+		t.fnEntityColumns(mainGen, g)
 	}
 	g.fnCreateDBM(mainGen, tables)
 	g.fnTestMainOther(testGen, tables)
@@ -256,6 +262,7 @@ func (g *Generator) GenerateGo(wMain, wTest io.Writer) error {
 		t.fnEntityGetSetPrivateFields(mainGen, g)
 		t.fnEntityValidate(mainGen, g)
 		t.fnEntityWriteTo(mainGen, g)
+		t.fnEntityFlatbuffers(mainGen, g)
 
 		t.fnCollectionStruct(mainGen, g)
 		t.fnCollectionAppend(mainGen, g)
@@ -273,6 +280,8 @@ func (g *Generator) GenerateGo(wMain, wTest io.Writer) error {
 		t.fnCollectionUniquifiedGetters(mainGen, g)
 		t.fnCollectionValidate(mainGen, g)
 		t.fnCollectionWriteTo(mainGen, g)
+		t.fnCollectionFlatbuffers(mainGen, g)
+		t.fnCollectionPreload(mainGen, g)
 	}
 
 	// now figure out all used package names in the buffer.
@@ -282,6 +291,13 @@ func (g *Generator) GenerateGo(wMain, wTest io.Writer) error {
 		return errors.WithStack(err)
 	}
 	mainGen.AddImports(pkgs...)
+	for _, t := range tables {
+		if g.hasFlatbuffers(t) {
+			// the package name differs from the last path element
+			mainGen.AddImport(importPathFlatbuffers, "flatbuffers")
+			break
+		}
+	}
 
 	if err := mainGen.GenerateFile(wMain); err != nil {
 		return errors.WithStack(err)
@@ -22,6 +22,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/pkg/sql/ddl"
 	"github.com/corestoreio/pkg/sql/dmlgen"
@@ -383,6 +384,126 @@ func TestNewGenerator_NoDB(t *testing.T) {
 	writeFile(t, "dmltestgenerated2/no_db_gen.go", ts.GenerateGo)
 }
 
+// TestNewGenerator_Flatbuffers writes the Go and FlatBuffers files to the
+// dmltestgeneratedfbs directory. The generated tests run the round trips.
+func TestNewGenerator_Flatbuffers(t *testing.T) {
+	ts, err := dmlgen.NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgeneratedfbs",
+		dmlgen.WithFlatbuffers(&dmlgen.SerializerConfig{}),
+		dmlgen.WithTable("fbs_types", ddl.Columns{
+			&ddl.Column{Field: "id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "col_bigint", Pos: 2, Null: "NO", DataType: "bigint", ColumnType: "bigint(20)"},
+			&ddl.Column{Field: "col_bigint_null", Pos: 3, Null: "YES", DataType: "bigint", ColumnType: "bigint(20) unsigned"},
+			&ddl.Column{Field: "col_smallint", Pos: 4, Null: "NO", DataType: "smallint", ColumnType: "smallint(5)"},
+			&ddl.Column{Field: "col_smallint_null", Pos: 5, Null: "YES", DataType: "smallint", ColumnType: "smallint(5)"},
+			&ddl.Column{Field: "col_tinyint", Pos: 6, Null: "NO", DataType: "tinyint", ColumnType: "tinyint(3) unsigned"},
+			&ddl.Column{Field: "is_active", Pos: 7, Null: "YES", DataType: "tinyint", ColumnType: "tinyint(1)"},
+			&ddl.Column{Field: "col_float", Pos: 8, Null: "YES", DataType: "double", ColumnType: "double"},
+			&ddl.Column{Field: "price", Pos: 9, Null: "YES", DataType: "decimal", ColumnType: "decimal(12,4)", Precision: null.MakeInt64(12), Scale: null.MakeInt64(4)},
+			&ddl.Column{Field: "col_varchar", Pos: 10, Null: "NO", DataType: "varchar", ColumnType: "varchar(100)", CharMaxLength: null.MakeInt64(100)},
+			&ddl.Column{Field: "col_text", Pos: 11, Null: "YES", DataType: "text", ColumnType: "text", CharMaxLength: null.MakeInt64(65535)},
+			&ddl.Column{Field: "col_blob", Pos: 12, Null: "YES", DataType: "blob", ColumnType: "blob"},
+			&ddl.Column{Field: "created_at", Pos: 13, Null: "NO", DataType: "datetime", ColumnType: "datetime"},
+			&ddl.Column{Field: "updated_at", Pos: 14, Null: "YES", DataType: "timestamp", ColumnType: "timestamp"},
+		}),
+		dmlgen.WithTableConfig("fbs_types", &dmlgen.TableConfig{
+			Encoders:        []string{"fbs"},
+			FeaturesExclude: dmlgen.FeatureDB | dmlgen.FeatureCollectionUniquifiedGetters | dmlgen.FeatureCollectionUniqueGetters,
+		}),
+	)
+	assert.NoError(t, err)
+
+	writeFile(t, "dmltestgeneratedfbs/fbs_gen.go", ts.GenerateGo)
+	writeFile(t, "dmltestgeneratedfbs/fbs_gen.fbs", ts.GenerateSerializer)
+}
+
+// TestNewGenerator_Features writes the Go files to the
+// dmltestgeneratedfeatures directory with preloading, optimistic locking,
+// timestamps, soft deletion, validation, typed columns and fixtures enabled.
+// The foreign keys get loaded from a mocked information_schema, the generated
+// code gets tested with a DB mock in dmltestgeneratedfeatures/manual_test.go.
+func TestNewGenerator_Features(t *testing.T) {
+	db, mock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, db, mock)
+
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT CONSTRAINT_CATALOG")).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"CONSTRAINT_CATALOG", "CONSTRAINT_SCHEMA", "CONSTRAINT_NAME", "TABLE_CATALOG", "TABLE_SCHEMA",
+			"TABLE_NAME", "COLUMN_NAME", "ORDINAL_POSITION", "POSITION_IN_UNIQUE_CONSTRAINT",
+			"REFERENCED_TABLE_SCHEMA", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME",
+		}).
+			AddRow("def", "shop", "FK_SHOP_BOOK_AUTHOR", "def", "shop", "shop_book", "author_id", 1, 1, "shop", "shop_author", "author_id").
+			AddRow("def", "shop", "FK_SHOP_BOOK_TAG_BOOK", "def", "shop", "shop_book_tag", "book_id", 1, 1, "shop", "shop_book", "book_id").
+			AddRow("def", "shop", "FK_SHOP_BOOK_TAG_TAG", "def", "shop", "shop_book_tag", "tag_id", 1, 1, "shop", "shop_tag", "tag_id"))
+
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT TABLE_NAME, COLUMN_KEY, COUNT(*) AS FIELD_COUNT")).
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_KEY", "FIELD_COUNT"}).
+			AddRow("shop_author", "PRI", 1).AddRow("shop_author", "UNI", 1).AddRow("shop_author", "", 4).
+			AddRow("shop_book", "PRI", 1).AddRow("shop_book", "MUL", 1).AddRow("shop_book", "", 6).
+			AddRow("shop_book_tag", "PRI", 2).
+			AddRow("shop_tag", "PRI", 1).AddRow("shop_tag", "UNI", 1))
+
+	ts, err := dmlgen.NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgeneratedfeatures",
+		dmlgen.WithTable("shop_author", ddl.Columns{
+			&ddl.Column{Field: "author_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "name", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(64)", CharMaxLength: null.MakeInt64(64)},
+			&ddl.Column{Field: "email", Pos: 3, Null: "NO", DataType: "varchar", ColumnType: "varchar(128)", CharMaxLength: null.MakeInt64(128), Key: "UNI"},
+			&ddl.Column{Field: "deleted_at", Pos: 4, Null: "YES", DataType: "datetime", ColumnType: "datetime"},
+			&ddl.Column{Field: "created_at", Pos: 5, Null: "NO", DataType: "timestamp", ColumnType: "timestamp"},
+			&ddl.Column{Field: "updated_at", Pos: 6, Null: "YES", DataType: "datetime", ColumnType: "datetime"},
+		}),
+		dmlgen.WithTable("shop_book", ddl.Columns{
+			&ddl.Column{Field: "book_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "author_id", Pos: 2, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "MUL"},
+			&ddl.Column{Field: "title", Pos: 3, Null: "NO", DataType: "varchar", ColumnType: "varchar(100)", CharMaxLength: null.MakeInt64(100)},
+			&ddl.Column{Field: "status", Pos: 4, Null: "NO", DataType: "enum", ColumnType: "enum('draft','published')"},
+			&ddl.Column{Field: "price", Pos: 5, Null: "NO", DataType: "decimal", ColumnType: "decimal(10,2)", Precision: null.MakeInt64(10), Scale: null.MakeInt64(2)},
+			&ddl.Column{Field: "version", Pos: 6, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned"},
+			&ddl.Column{Field: "created_at", Pos: 7, Null: "NO", DataType: "timestamp", ColumnType: "timestamp"},
+			&ddl.Column{Field: "updated_at", Pos: 8, Null: "YES", DataType: "datetime", ColumnType: "datetime"},
+		}),
+		dmlgen.WithTable("shop_tag", ddl.Columns{
+			&ddl.Column{Field: "tag_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "name", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(32)", CharMaxLength: null.MakeInt64(32), Key: "UNI"},
+		}),
+		dmlgen.WithTable("shop_book_tag", ddl.Columns{
+			&ddl.Column{Field: "book_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI"},
+			&ddl.Column{Field: "tag_id", Pos: 2, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI"},
+		}),
+
+		dmlgen.WithTableConfigDefault(dmlgen.TableConfig{
+			StructTags: []string{"json"},
+		}),
+		dmlgen.WithTableConfig("shop_author", &dmlgen.TableConfig{
+			SoftDeleteColumn: "deleted_at",
+			CreatedAtColumn:  "created_at",
+			UpdatedAtColumn:  "updated_at",
+		}),
+		dmlgen.WithTableConfig("shop_book", &dmlgen.TableConfig{
+			VersionColumn:   "version",
+			CreatedAtColumn: "created_at",
+			UpdatedAtColumn: "updated_at",
+		}),
+
+		dmlgen.WithForeignKeyRelationships(context.Background(), db.DB, dmlgen.ForeignKeyOptions{
+			ExcludeRelationships: []string{
+				"shop_book.author_id", "shop_author.author_id",
+				"shop_book_tag.book_id", "shop_book.book_id",
+				"shop_book_tag.tag_id", "shop_tag.tag_id",
+				"shop_tag.tag_id", "shop_book_tag.tag_id",
+				"shop_tag.tag_id", "shop_book.book_id",
+			},
+		}),
+	)
+	assert.NoError(t, err)
+
+	writeFile(t, "dmltestgeneratedfeatures/features_gen.go", ts.GenerateGo)
+
+	f, err := os.Create("dmltestgeneratedfeatures/fixtures_gen.go")
+	assert.NoError(t, err)
+	defer dmltest.Close(t, f)
+	assert.NoError(t, ts.GenerateFixtures(f))
+}
+
 func TestNewGenerator_ReversedForeignKeys(t *testing.T) {
 	db := dmltest.MustConnectDB(t)
 	defer dmltest.Close(t, db)
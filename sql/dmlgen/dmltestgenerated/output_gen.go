@@ -10,7 +10,9 @@ import (
 	"database/sql"
 	"fmt"
 	"io"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/pkg/sql/ddl"
@@ -30,6 +32,23 @@ type CatalogProductIndexEAVDecimalIDX struct {
 	Value       null.Decimal // value decimal(12,4) NOT NULL MUL   "Value"
 }
 
+// CatalogProductIndexEAVDecimalIDXColumns contains the typed columns of table
+// catalog_product_index_eav_decimal_idx. They create the conditions of queries,
+// e.g. CatalogProductIndexEAVDecimalIDXColumns.EntityID.Equal(v).
+var CatalogProductIndexEAVDecimalIDXColumns = struct {
+	EntityID    dml.ColumnUint[uint32]
+	AttributeID dml.ColumnUint[uint32]
+	StoreID     dml.ColumnUint[uint32]
+	SourceID    dml.ColumnUint[uint32]
+	Value       dml.ColumnDecimal
+}{
+	EntityID:    "entity_id",
+	AttributeID: "attribute_id",
+	StoreID:     "store_id",
+	SourceID:    "source_id",
+	Value:       "value",
+}
+
 // CoreConfiguration represents a single row for DB table core_configuration.
 // Auto generated.
 // Table comment: Config Data
@@ -45,6 +64,29 @@ type CoreConfiguration struct {
 	VersionTe time.Time   `json:"version_te,omitempty" `             // version_te timestamp(6) NOT NULL PRI  STORED GENERATED "Timestamp End Versioning"
 }
 
+// CoreConfigurationColumns contains the typed columns of table
+// core_configuration. They create the conditions of queries, e.g.
+// CoreConfigurationColumns.ConfigID.Equal(v).
+var CoreConfigurationColumns = struct {
+	ConfigID  dml.ColumnUint[uint32]
+	Scope     dml.ColumnStr
+	ScopeID   dml.ColumnInt[int32]
+	Expires   dml.ColumnTime
+	Path      dml.ColumnStr
+	Value     dml.ColumnStr
+	VersionTs dml.ColumnTime
+	VersionTe dml.ColumnTime
+}{
+	ConfigID:  "config_id",
+	Scope:     "scope",
+	ScopeID:   "scope_id",
+	Expires:   "expires",
+	Path:      "path",
+	Value:     "value",
+	VersionTs: "version_ts",
+	VersionTe: "version_te",
+}
+
 // CustomerAddressEntity represents a single row for DB table
 // customer_address_entity. Auto generated.
 // Table comment: Customer Address Entity
@@ -66,6 +108,41 @@ type CustomerAddressEntity struct {
 	Street      string      `max_len:"65535"` // street text NOT NULL    "Street Address"
 }
 
+// CustomerAddressEntityColumns contains the typed columns of table
+// customer_address_entity. They create the conditions of queries, e.g.
+// CustomerAddressEntityColumns.EntityID.Equal(v).
+var CustomerAddressEntityColumns = struct {
+	EntityID    dml.ColumnUint[uint32]
+	IncrementID dml.ColumnStr
+	ParentID    dml.ColumnUint[uint32]
+	CreatedAt   dml.ColumnTime
+	UpdatedAt   dml.ColumnTime
+	IsActive    dml.ColumnBool
+	City        dml.ColumnStr
+	Company     dml.ColumnStr
+	CountryID   dml.ColumnStr
+	Firstname   dml.ColumnStr
+	Lastname    dml.ColumnStr
+	Postcode    dml.ColumnStr
+	Region      dml.ColumnStr
+	Street      dml.ColumnStr
+}{
+	EntityID:    "entity_id",
+	IncrementID: "increment_id",
+	ParentID:    "parent_id",
+	CreatedAt:   "created_at",
+	UpdatedAt:   "updated_at",
+	IsActive:    "is_active",
+	City:        "city",
+	Company:     "company",
+	CountryID:   "country_id",
+	Firstname:   "firstname",
+	Lastname:    "lastname",
+	Postcode:    "postcode",
+	Region:      "region",
+	Street:      "street",
+}
+
 type customerEntityRelations struct {
 	parent                  *CustomerEntity
 	CustomerAddressEntities *CustomerAddressEntities // Reversed 1:M customer_entity.entity_id => customer_address_entity.parent_id
@@ -108,6 +185,49 @@ type CustomerEntity struct {
 	Relations        *customerEntityRelations
 }
 
+// CustomerEntityColumns contains the typed columns of table customer_entity.
+// They create the conditions of queries, e.g.
+// CustomerEntityColumns.EntityID.Equal(v).
+var CustomerEntityColumns = struct {
+	EntityID         dml.ColumnUint[uint32]
+	WebsiteID        dml.ColumnUint[uint32]
+	Email            dml.ColumnStr
+	GroupID          dml.ColumnUint[uint32]
+	StoreID          dml.ColumnUint[uint32]
+	CreatedAt        dml.ColumnTime
+	UpdatedAt        dml.ColumnTime
+	IsActive         dml.ColumnBool
+	CreatedIn        dml.ColumnStr
+	Firstname        dml.ColumnStr
+	Lastname         dml.ColumnStr
+	Dob              dml.ColumnTime
+	PasswordHash     dml.ColumnStr
+	RpToken          dml.ColumnStr
+	RpTokenCreatedAt dml.ColumnTime
+	DefaultBilling   dml.ColumnUint[uint32]
+	DefaultShipping  dml.ColumnUint[uint32]
+	Gender           dml.ColumnUint[uint32]
+}{
+	EntityID:         "entity_id",
+	WebsiteID:        "website_id",
+	Email:            "email",
+	GroupID:          "group_id",
+	StoreID:          "store_id",
+	CreatedAt:        "created_at",
+	UpdatedAt:        "updated_at",
+	IsActive:         "is_active",
+	CreatedIn:        "created_in",
+	Firstname:        "firstname",
+	Lastname:         "lastname",
+	Dob:              "dob",
+	PasswordHash:     "password_hash",
+	RpToken:          "rp_token",
+	RpTokenCreatedAt: "rp_token_created_at",
+	DefaultBilling:   "default_billing",
+	DefaultShipping:  "default_shipping",
+	Gender:           "gender",
+}
+
 // DmlgenTypes represents a single row for DB table dmlgen_types. Auto generated.
 // // Just another comment.
 //easyjson:json
@@ -155,6 +275,94 @@ type DmlgenTypes struct {
 	ColChar2       string       `json:"col_char_2,omitempty"  max_len:"17"`             // col_char_2 char(17) NOT NULL  DEFAULT ''xchar''  ""
 }
 
+// DmlgenTypesColumns contains the typed columns of table dmlgen_types. They
+// create the conditions of queries, e.g. DmlgenTypesColumns.ID.Equal(v).
+var DmlgenTypesColumns = struct {
+	ID             dml.ColumnInt[int32]
+	ColBigint1     dml.ColumnInt[int64]
+	ColBigint2     dml.ColumnInt[int64]
+	ColBigint3     dml.ColumnUint[uint64]
+	ColBigint4     dml.ColumnUint[uint64]
+	ColBlob        dml.ColumnBytes
+	ColDate1       dml.ColumnTime
+	ColDate2       dml.ColumnTime
+	ColDatetime1   dml.ColumnTime
+	ColDatetime2   dml.ColumnTime
+	ColDecimal101  dml.ColumnDecimal
+	ColDecimal124  dml.ColumnDecimal
+	PriceA124      dml.ColumnDecimal
+	PriceB124      dml.ColumnDecimal
+	ColDecimal123  dml.ColumnDecimal
+	ColDecimal206  dml.ColumnDecimal
+	ColDecimal2412 dml.ColumnDecimal
+	ColInt1        dml.ColumnInt[int32]
+	ColInt2        dml.ColumnInt[int32]
+	ColInt3        dml.ColumnUint[uint32]
+	ColInt4        dml.ColumnUint[uint32]
+	ColLongtext1   dml.ColumnStr
+	ColLongtext2   dml.ColumnStr
+	ColMediumblob  dml.ColumnBytes
+	ColMediumtext1 dml.ColumnStr
+	ColMediumtext2 dml.ColumnStr
+	ColSmallint1   dml.ColumnInt[int32]
+	ColSmallint2   dml.ColumnInt[int32]
+	ColSmallint3   dml.ColumnUint[uint32]
+	ColSmallint4   dml.ColumnUint[uint32]
+	HasSmallint5   dml.ColumnBool
+	IsSmallint5    dml.ColumnBool
+	ColText        dml.ColumnStr
+	ColTimestamp1  dml.ColumnTime
+	ColTimestamp2  dml.ColumnTime
+	ColTinyint1    dml.ColumnInt[int32]
+	ColVarchar1    dml.ColumnStr
+	ColVarchar100  dml.ColumnStr
+	ColVarchar16   dml.ColumnStr
+	ColChar1       dml.ColumnStr
+	ColChar2       dml.ColumnStr
+}{
+	ID:             "id",
+	ColBigint1:     "col_bigint_1",
+	ColBigint2:     "col_bigint_2",
+	ColBigint3:     "col_bigint_3",
+	ColBigint4:     "col_bigint_4",
+	ColBlob:        "col_blob",
+	ColDate1:       "col_date_1",
+	ColDate2:       "col_date_2",
+	ColDatetime1:   "col_datetime_1",
+	ColDatetime2:   "col_datetime_2",
+	ColDecimal101:  "col_decimal_10_1",
+	ColDecimal124:  "col_decimal_12_4",
+	PriceA124:      "price_a_12_4",
+	PriceB124:      "price_b_12_4",
+	ColDecimal123:  "col_decimal_12_3",
+	ColDecimal206:  "col_decimal_20_6",
+	ColDecimal2412: "col_decimal_24_12",
+	ColInt1:        "col_int_1",
+	ColInt2:        "col_int_2",
+	ColInt3:        "col_int_3",
+	ColInt4:        "col_int_4",
+	ColLongtext1:   "col_longtext_1",
+	ColLongtext2:   "col_longtext_2",
+	ColMediumblob:  "col_mediumblob",
+	ColMediumtext1: "col_mediumtext_1",
+	ColMediumtext2: "col_mediumtext_2",
+	ColSmallint1:   "col_smallint_1",
+	ColSmallint2:   "col_smallint_2",
+	ColSmallint3:   "col_smallint_3",
+	ColSmallint4:   "col_smallint_4",
+	HasSmallint5:   "has_smallint_5",
+	IsSmallint5:    "is_smallint_5",
+	ColText:        "col_text",
+	ColTimestamp1:  "col_timestamp_1",
+	ColTimestamp2:  "col_timestamp_2",
+	ColTinyint1:    "col_tinyint_1",
+	ColVarchar1:    "col_varchar_1",
+	ColVarchar100:  "col_varchar_100",
+	ColVarchar16:   "col_varchar_16",
+	ColChar1:       "col_char_1",
+	ColChar2:       "col_char_2",
+}
+
 // SalesOrderStatusState represents a single row for DB table
 // sales_order_status_state. Auto generated.
 // Table comment: Sales Order Status Table
@@ -166,6 +374,21 @@ type SalesOrderStatusState struct {
 	VisibleOnFront uint32 `max_len:"5"`  // visible_on_front smallint(5) unsigned NOT NULL  DEFAULT '0'  "Visible on front"
 }
 
+// SalesOrderStatusStateColumns contains the typed columns of table
+// sales_order_status_state. They create the conditions of queries, e.g.
+// SalesOrderStatusStateColumns.Status.Equal(v).
+var SalesOrderStatusStateColumns = struct {
+	Status         dml.ColumnStr
+	State          dml.ColumnStr
+	IsDefault      dml.ColumnBool
+	VisibleOnFront dml.ColumnUint[uint32]
+}{
+	Status:         "status",
+	State:          "state",
+	IsDefault:      "is_default",
+	VisibleOnFront: "visible_on_front",
+}
+
 // ViewCustomerAutoIncrement represents a single row for DB table
 // view_customer_auto_increment. Auto generated.
 // Table comment: VIEW
@@ -178,6 +401,23 @@ type ViewCustomerAutoIncrement struct {
 	City       string      `max_len:"255"` // city varchar(255) NOT NULL    "City"
 }
 
+// ViewCustomerAutoIncrementColumns contains the typed columns of table
+// view_customer_auto_increment. They create the conditions of queries, e.g.
+// ViewCustomerAutoIncrementColumns.CeEntityID.Equal(v).
+var ViewCustomerAutoIncrementColumns = struct {
+	CeEntityID dml.ColumnUint[uint32]
+	Email      dml.ColumnStr
+	Firstname  dml.ColumnStr
+	Lastname   dml.ColumnStr
+	City       dml.ColumnStr
+}{
+	CeEntityID: "ce_entity_id",
+	Email:      "email",
+	Firstname:  "firstname",
+	Lastname:   "lastname",
+	City:       "city",
+}
+
 // ViewCustomerNoAutoIncrement represents a single row for DB table
 // view_customer_no_auto_increment. Auto generated.
 // Table comment: VIEW
@@ -189,6 +429,21 @@ type ViewCustomerNoAutoIncrement struct {
 	City      string      `max_len:"255"` // city varchar(255) NOT NULL    "City"
 }
 
+// ViewCustomerNoAutoIncrementColumns contains the typed columns of table
+// view_customer_no_auto_increment. They create the conditions of queries, e.g.
+// ViewCustomerNoAutoIncrementColumns.Email.Equal(v).
+var ViewCustomerNoAutoIncrementColumns = struct {
+	Email     dml.ColumnStr
+	Firstname dml.ColumnStr
+	Lastname  dml.ColumnStr
+	City      dml.ColumnStr
+}{
+	Email:     "email",
+	Firstname: "firstname",
+	Lastname:  "lastname",
+	City:      "city",
+}
+
 // TableName constants define the names of all tables.
 const (
 	TableNameCatalogProductIndexEAVDecimalIDX = "catalog_product_index_eav_decimal_idx"
@@ -835,6 +1090,9 @@ func NewDBManager(ctx context.Context, dbmo *DBMOption) (*DBM, error) {
 			"CustomerAddressEntitiesSelectByFK": dbmo.InitSelectFn(tbls.MustTable(TableNameCustomerAddressEntity).Select("*").Where(
 				dml.Column(`parent_id`).Equal().PlaceHolder(),
 			)),
+			"CustomerAddressEntitiesSelectByFKs": dbmo.InitSelectFn(tbls.MustTable(TableNameCustomerAddressEntity).Select("*").Where(
+				dml.Column(`parent_id`).In().PlaceHolder(),
+			)),
 			// </FOREIGN_KEY_QUERIES customer_entity >
 			"DmlgenTypesCollectionSelectAll": dbmo.InitSelectFn(tbls.MustTable(TableNameDmlgenTypes).Select("*")),
 			"DmlgenTypesCollectionSelectByPK": dbmo.InitSelectFn(tbls.MustTable(TableNameDmlgenTypes).Select("*")).Where(
@@ -1034,17 +1292,33 @@ func (e *CatalogProductIndexEAVDecimalIDX) IsSet() bool {
 }
 
 // This variable can be set in another file to provide a custom validator.
+// A returned dml.FieldErrors gets merged with the errors of the column
+// constraints.
 var validateCatalogProductIndexEAVDecimalIDX func(*CatalogProductIndexEAVDecimalIDX) error
 
-// Validate runs internal consistency tests.
+// Validate runs internal consistency tests and checks the values against the
+// constraints of the table columns. It returns dml.FieldErrors if a constraint
+// has been violated.
 func (e *CatalogProductIndexEAVDecimalIDX) Validate() error {
 	if e == nil {
 		return errors.NotValid.Newf("Type %T cannot be nil", e)
 	}
+	var fe dml.FieldErrors
+	if e.AttributeID > 65535 {
+		fe = fe.Add("attribute_id", "AttributeID", dml.RuleRange, "value %d exceeds the maximum of 65535", e.AttributeID)
+	}
+	if e.StoreID > 65535 {
+		fe = fe.Add("store_id", "StoreID", dml.RuleRange, "value %d exceeds the maximum of 65535", e.StoreID)
+	}
+	if !e.Value.Valid {
+		fe = fe.Add("value", "Value", dml.RuleRequired, "must not be NULL")
+	} else if e.Value.Valid && !dml.ValidDecimal(e.Value, 12, 4) {
+		fe = fe.Add("value", "Value", dml.RulePrecision, "value %s exceeds DECIMAL(12,4)", e.Value)
+	}
 	if validateCatalogProductIndexEAVDecimalIDX != nil {
-		return validateCatalogProductIndexEAVDecimalIDX(e)
+		return fe.Join(validateCatalogProductIndexEAVDecimalIDX(e))
 	}
-	return nil
+	return fe.ErrorOrNil()
 }
 
 // WriteTo implements io.WriterTo and writes the field names and their values to
@@ -1575,17 +1849,31 @@ func (e *CoreConfiguration) Empty() *CoreConfiguration { *e = CoreConfiguration{
 func (e *CoreConfiguration) IsSet() bool { return e.ConfigID > 0 }
 
 // This variable can be set in another file to provide a custom validator.
+// A returned dml.FieldErrors gets merged with the errors of the column
+// constraints.
 var validateCoreConfiguration func(*CoreConfiguration) error
 
-// Validate runs internal consistency tests.
+// Validate runs internal consistency tests and checks the values against the
+// constraints of the table columns. It returns dml.FieldErrors if a constraint
+// has been violated.
 func (e *CoreConfiguration) Validate() error {
 	if e == nil {
 		return errors.NotValid.Newf("Type %T cannot be nil", e)
 	}
+	var fe dml.FieldErrors
+	if utf8.RuneCountInString(e.Scope) > 8 {
+		fe = fe.Add("scope", "Scope", dml.RuleMaxLength, "must not exceed 8 characters")
+	}
+	if e.Expires.Valid && !dml.ValidDateRange("datetime", e.Expires.Time) {
+		fe = fe.Add("expires", "Expires", dml.RuleDateRange, "%s is out of the datetime range", e.Expires.Time)
+	}
+	if utf8.RuneCountInString(e.Path) > 255 {
+		fe = fe.Add("path", "Path", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
 	if validateCoreConfiguration != nil {
-		return validateCoreConfiguration(e)
+		return fe.Join(validateCoreConfiguration(e))
 	}
-	return nil
+	return fe.ErrorOrNil()
 }
 
 // WriteTo implements io.WriterTo and writes the field names and their values to
@@ -2095,17 +2383,63 @@ func (e *CustomerAddressEntity) Empty() *CustomerAddressEntity {
 func (e *CustomerAddressEntity) IsSet() bool { return e.EntityID > 0 }
 
 // This variable can be set in another file to provide a custom validator.
+// A returned dml.FieldErrors gets merged with the errors of the column
+// constraints.
 var validateCustomerAddressEntity func(*CustomerAddressEntity) error
 
-// Validate runs internal consistency tests.
+// Validate runs internal consistency tests and checks the values against the
+// constraints of the table columns. It returns dml.FieldErrors if a constraint
+// has been violated.
 func (e *CustomerAddressEntity) Validate() error {
 	if e == nil {
 		return errors.NotValid.Newf("Type %T cannot be nil", e)
 	}
+	var fe dml.FieldErrors
+	if e.IncrementID.Valid && utf8.RuneCountInString(e.IncrementID.Data) > 50 {
+		fe = fe.Add("increment_id", "IncrementID", dml.RuleMaxLength, "must not exceed 50 characters")
+	}
+	if !e.CreatedAt.IsZero() && !dml.ValidDateRange("timestamp", e.CreatedAt) {
+		fe = fe.Add("created_at", "CreatedAt", dml.RuleDateRange, "%s is out of the timestamp range", e.CreatedAt)
+	}
+	if !e.UpdatedAt.IsZero() && !dml.ValidDateRange("timestamp", e.UpdatedAt) {
+		fe = fe.Add("updated_at", "UpdatedAt", dml.RuleDateRange, "%s is out of the timestamp range", e.UpdatedAt)
+	}
+	if e.City == "" {
+		fe = fe.Add("city", "City", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.City) > 255 {
+		fe = fe.Add("city", "City", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.Company.Valid && utf8.RuneCountInString(e.Company.Data) > 255 {
+		fe = fe.Add("company", "Company", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.CountryID == "" {
+		fe = fe.Add("country_id", "CountryID", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.CountryID) > 255 {
+		fe = fe.Add("country_id", "CountryID", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.Firstname == "" {
+		fe = fe.Add("firstname", "Firstname", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.Firstname) > 255 {
+		fe = fe.Add("firstname", "Firstname", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.Lastname == "" {
+		fe = fe.Add("lastname", "Lastname", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.Lastname) > 255 {
+		fe = fe.Add("lastname", "Lastname", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.Postcode.Valid && utf8.RuneCountInString(e.Postcode.Data) > 255 {
+		fe = fe.Add("postcode", "Postcode", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.Region.Valid && utf8.RuneCountInString(e.Region.Data) > 255 {
+		fe = fe.Add("region", "Region", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.Street == "" {
+		fe = fe.Add("street", "Street", dml.RuleRequired, "must not be empty")
+	}
 	if validateCustomerAddressEntity != nil {
-		return validateCustomerAddressEntity(e)
+		return fe.Join(validateCustomerAddressEntity(e))
 	}
-	return nil
+	return fe.ErrorOrNil()
 }
 
 // WriteTo implements io.WriterTo and writes the field names and their values to
@@ -2694,17 +3028,64 @@ func (e *CustomerEntity) GetPasswordHash() null.String {
 }
 
 // This variable can be set in another file to provide a custom validator.
+// A returned dml.FieldErrors gets merged with the errors of the column
+// constraints.
 var validateCustomerEntity func(*CustomerEntity) error
 
-// Validate runs internal consistency tests.
+// Validate runs internal consistency tests and checks the values against the
+// constraints of the table columns. It returns dml.FieldErrors if a constraint
+// has been violated.
 func (e *CustomerEntity) Validate() error {
 	if e == nil {
 		return errors.NotValid.Newf("Type %T cannot be nil", e)
 	}
+	var fe dml.FieldErrors
+	if e.WebsiteID.Valid && e.WebsiteID.Uint32 > 65535 {
+		fe = fe.Add("website_id", "WebsiteID", dml.RuleRange, "value %d exceeds the maximum of 65535", e.WebsiteID.Uint32)
+	}
+	if e.Email.Valid && utf8.RuneCountInString(e.Email.Data) > 255 {
+		fe = fe.Add("email", "Email", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.GroupID > 65535 {
+		fe = fe.Add("group_id", "GroupID", dml.RuleRange, "value %d exceeds the maximum of 65535", e.GroupID)
+	}
+	if e.StoreID.Valid && e.StoreID.Uint32 > 65535 {
+		fe = fe.Add("store_id", "StoreID", dml.RuleRange, "value %d exceeds the maximum of 65535", e.StoreID.Uint32)
+	}
+	if !e.CreatedAt.IsZero() && !dml.ValidDateRange("timestamp", e.CreatedAt) {
+		fe = fe.Add("created_at", "CreatedAt", dml.RuleDateRange, "%s is out of the timestamp range", e.CreatedAt)
+	}
+	if !e.UpdatedAt.IsZero() && !dml.ValidDateRange("timestamp", e.UpdatedAt) {
+		fe = fe.Add("updated_at", "UpdatedAt", dml.RuleDateRange, "%s is out of the timestamp range", e.UpdatedAt)
+	}
+	if e.CreatedIn.Valid && utf8.RuneCountInString(e.CreatedIn.Data) > 255 {
+		fe = fe.Add("created_in", "CreatedIn", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.Firstname.Valid && utf8.RuneCountInString(e.Firstname.Data) > 255 {
+		fe = fe.Add("firstname", "Firstname", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.Lastname.Valid && utf8.RuneCountInString(e.Lastname.Data) > 255 {
+		fe = fe.Add("lastname", "Lastname", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.Dob.Valid && !dml.ValidDateRange("date", e.Dob.Time) {
+		fe = fe.Add("dob", "Dob", dml.RuleDateRange, "%s is out of the date range", e.Dob.Time)
+	}
+	if e.passwordHash.Valid && utf8.RuneCountInString(e.passwordHash.Data) > 128 {
+		fe = fe.Add("password_hash", "passwordHash", dml.RuleMaxLength, "must not exceed 128 characters")
+	}
+	if e.RpToken.Valid && utf8.RuneCountInString(e.RpToken.Data) > 128 {
+		fe = fe.Add("rp_token", "RpToken", dml.RuleMaxLength, "must not exceed 128 characters")
+	}
+	if e.RpTokenCreatedAt.Valid && !dml.ValidDateRange("datetime", e.RpTokenCreatedAt.Time) {
+		fe = fe.Add("rp_token_created_at", "RpTokenCreatedAt", dml.RuleDateRange, "%s is out of the datetime range", e.RpTokenCreatedAt.Time)
+	}
+	if e.Gender.Valid && e.Gender.Uint32 > 65535 {
+		fe = fe.Add("gender", "Gender", dml.RuleRange, "value %d exceeds the maximum of 65535", e.Gender.Uint32)
+	}
 	if validateCustomerEntity != nil {
-		return validateCustomerEntity(e)
+		return fe.Join(validateCustomerEntity(e))
 	}
-	return nil
+	return fe.ErrorOrNil()
 }
 
 // WriteTo implements io.WriterTo and writes the field names and their values to
@@ -3041,6 +3422,82 @@ func (cc *CustomerEntities) WriteTo(w io.Writer) (n int64, err error) {
 	return n, nil
 }
 
+// PreloadCustomerAddressEntities loads the relation CustomerAddressEntities for
+// all entities of the collection with one query and assigns the rows to the
+// Relations field of each entity. It returns all loaded rows to allow further
+// preloading. Auto generated.
+func (cc *CustomerEntities) PreloadCustomerAddressEntities(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (_ *CustomerAddressEntities, err error) {
+	if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil, nil
+	}
+	parents := make(map[uint32][]*CustomerEntity, len(cc.Data))
+	keys := make([]uint32, 0, len(cc.Data))
+	for _, e := range cc.Data {
+		if e.Relations == nil {
+			e.NewRelations()
+		}
+		e.setRelationParent()
+		e.Relations.CustomerAddressEntities = &CustomerAddressEntities{}
+		k := e.EntityID
+		if _, ok := parents[k]; !ok {
+			keys = append(keys, k)
+		}
+		parents[k] = append(parents[k], e)
+	}
+	children := &CustomerAddressEntities{}
+	if len(keys) == 0 {
+		return children, nil
+	}
+	if _, err = dbm.ConnPool.WithCacheKey("CustomerAddressEntitiesSelectByFKs", opts...).Load(ctx, children, keys); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, c := range children.Data {
+		if !c.ParentID.Valid {
+			continue
+		}
+		for _, e := range parents[c.ParentID.Uint32] {
+			e.Relations.CustomerAddressEntities.Data = append(e.Relations.CustomerAddressEntities.Data, c)
+		}
+	}
+	return children, nil
+}
+
+// Preload loads the relations named in paths for all entities of the collection
+// with one query per relation. A path can address nested relations separated by
+// a dot, e.g. "CustomerAddressEntities.Name". Preloading gets skipped if
+// dml.QueryOptions.SkipRelations has been set. Auto generated.
+func (cc *CustomerEntities) Preload(ctx context.Context, dbm *DBM, paths []string, opts ...dml.DBRFunc) error {
+	if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil
+	}
+	var names []string
+	nested := make(map[string][]string, len(paths))
+	for _, p := range paths {
+		name, rest, _ := strings.Cut(p, ".")
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = nil
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}
+	for _, name := range names {
+		switch name {
+		case "CustomerAddressEntities":
+			if len(nested[name]) > 0 {
+				return errors.NotSupported.Newf("[dmltestgenerated] CustomerEntities.Preload: relation %q has no nested relations", name)
+			}
+			if _, err := cc.PreloadCustomerAddressEntities(ctx, dbm, opts...); err != nil {
+				return errors.WithStack(err)
+			}
+		default:
+			return errors.NotFound.Newf("[dmltestgenerated] CustomerEntities.Preload: relation %q not found", name)
+		}
+	}
+	return nil
+}
+
 // Copy copies the struct and returns a new pointer. TODO use deepcopy tool to
 // generate code afterwards
 func (e *DmlgenTypes) Copy() *DmlgenTypes {
@@ -3253,17 +3710,93 @@ func (e *DmlgenTypes) Empty() *DmlgenTypes { *e = DmlgenTypes{}; return e }
 func (e *DmlgenTypes) IsSet() bool { return e.ID != 0 }
 
 // This variable can be set in another file to provide a custom validator.
+// A returned dml.FieldErrors gets merged with the errors of the column
+// constraints.
 var validateDmlgenTypes func(*DmlgenTypes) error
 
-// Validate runs internal consistency tests.
+// Validate runs internal consistency tests and checks the values against the
+// constraints of the table columns. It returns dml.FieldErrors if a constraint
+// has been violated.
 func (e *DmlgenTypes) Validate() error {
 	if e == nil {
 		return errors.NotValid.Newf("Type %T cannot be nil", e)
 	}
+	var fe dml.FieldErrors
+	if e.ColDate1.Valid && !dml.ValidDateRange("date", e.ColDate1.Time) {
+		fe = fe.Add("col_date_1", "ColDate1", dml.RuleDateRange, "%s is out of the date range", e.ColDate1.Time)
+	}
+	if !e.ColDate2.IsZero() && !dml.ValidDateRange("date", e.ColDate2) {
+		fe = fe.Add("col_date_2", "ColDate2", dml.RuleDateRange, "%s is out of the date range", e.ColDate2)
+	}
+	if e.ColDatetime1.Valid && !dml.ValidDateRange("datetime", e.ColDatetime1.Time) {
+		fe = fe.Add("col_datetime_1", "ColDatetime1", dml.RuleDateRange, "%s is out of the datetime range", e.ColDatetime1.Time)
+	}
+	if !e.ColDatetime2.IsZero() && !dml.ValidDateRange("datetime", e.ColDatetime2) {
+		fe = fe.Add("col_datetime_2", "ColDatetime2", dml.RuleDateRange, "%s is out of the datetime range", e.ColDatetime2)
+	}
+	if e.ColDecimal101.Valid && e.ColDecimal101.Negative {
+		fe = fe.Add("col_decimal_10_1", "ColDecimal101", dml.RuleRange, "value %s must not be negative", e.ColDecimal101)
+	} else if e.ColDecimal101.Valid && !dml.ValidDecimal(e.ColDecimal101, 10, 1) {
+		fe = fe.Add("col_decimal_10_1", "ColDecimal101", dml.RulePrecision, "value %s exceeds DECIMAL(10,1)", e.ColDecimal101)
+	}
+	if e.ColDecimal124.Valid && !dml.ValidDecimal(e.ColDecimal124, 12, 4) {
+		fe = fe.Add("col_decimal_12_4", "ColDecimal124", dml.RulePrecision, "value %s exceeds DECIMAL(12,4)", e.ColDecimal124)
+	}
+	if e.PriceA124.Valid && !dml.ValidDecimal(e.PriceA124, 12, 4) {
+		fe = fe.Add("price_a_12_4", "PriceA124", dml.RulePrecision, "value %s exceeds DECIMAL(12,4)", e.PriceA124)
+	}
+	if e.PriceB124.Valid && !dml.ValidDecimal(e.PriceB124, 12, 4) {
+		fe = fe.Add("price_b_12_4", "PriceB124", dml.RulePrecision, "value %s exceeds DECIMAL(12,4)", e.PriceB124)
+	}
+	if e.ColDecimal123.Valid && !dml.ValidDecimal(e.ColDecimal123, 12, 3) {
+		fe = fe.Add("col_decimal_12_3", "ColDecimal123", dml.RulePrecision, "value %s exceeds DECIMAL(12,3)", e.ColDecimal123)
+	}
+	if e.ColDecimal206.Valid && !dml.ValidDecimal(e.ColDecimal206, 20, 6) {
+		fe = fe.Add("col_decimal_20_6", "ColDecimal206", dml.RulePrecision, "value %s exceeds DECIMAL(20,6)", e.ColDecimal206)
+	}
+	if e.ColDecimal2412.Valid && !dml.ValidDecimal(e.ColDecimal2412, 24, 12) {
+		fe = fe.Add("col_decimal_24_12", "ColDecimal2412", dml.RulePrecision, "value %s exceeds DECIMAL(24,12)", e.ColDecimal2412)
+	}
+	if e.ColSmallint1.Valid && (e.ColSmallint1.Int32 < -32768 || e.ColSmallint1.Int32 > 32767) {
+		fe = fe.Add("col_smallint_1", "ColSmallint1", dml.RuleRange, "value %d out of range [-32768, 32767]", e.ColSmallint1.Int32)
+	}
+	if e.ColSmallint2 < -32768 || e.ColSmallint2 > 32767 {
+		fe = fe.Add("col_smallint_2", "ColSmallint2", dml.RuleRange, "value %d out of range [-32768, 32767]", e.ColSmallint2)
+	}
+	if e.ColSmallint3.Valid && e.ColSmallint3.Uint32 > 65535 {
+		fe = fe.Add("col_smallint_3", "ColSmallint3", dml.RuleRange, "value %d exceeds the maximum of 65535", e.ColSmallint3.Uint32)
+	}
+	if e.ColSmallint4 > 65535 {
+		fe = fe.Add("col_smallint_4", "ColSmallint4", dml.RuleRange, "value %d exceeds the maximum of 65535", e.ColSmallint4)
+	}
+	if !e.ColTimestamp1.IsZero() && !dml.ValidDateRange("timestamp", e.ColTimestamp1) {
+		fe = fe.Add("col_timestamp_1", "ColTimestamp1", dml.RuleDateRange, "%s is out of the timestamp range", e.ColTimestamp1)
+	}
+	if e.ColTimestamp2.Valid && !dml.ValidDateRange("timestamp", e.ColTimestamp2.Time) {
+		fe = fe.Add("col_timestamp_2", "ColTimestamp2", dml.RuleDateRange, "%s is out of the timestamp range", e.ColTimestamp2.Time)
+	}
+	if e.ColTinyint1 < -128 || e.ColTinyint1 > 127 {
+		fe = fe.Add("col_tinyint_1", "ColTinyint1", dml.RuleRange, "value %d out of range [-128, 127]", e.ColTinyint1)
+	}
+	if utf8.RuneCountInString(e.ColVarchar1) > 1 {
+		fe = fe.Add("col_varchar_1", "ColVarchar1", dml.RuleMaxLength, "must not exceed 1 characters")
+	}
+	if e.ColVarchar100.Valid && utf8.RuneCountInString(e.ColVarchar100.Data) > 100 {
+		fe = fe.Add("col_varchar_100", "ColVarchar100", dml.RuleMaxLength, "must not exceed 100 characters")
+	}
+	if utf8.RuneCountInString(e.ColVarchar16) > 16 {
+		fe = fe.Add("col_varchar_16", "ColVarchar16", dml.RuleMaxLength, "must not exceed 16 characters")
+	}
+	if e.ColChar1.Valid && utf8.RuneCountInString(e.ColChar1.Data) > 21 {
+		fe = fe.Add("col_char_1", "ColChar1", dml.RuleMaxLength, "must not exceed 21 characters")
+	}
+	if utf8.RuneCountInString(e.ColChar2) > 17 {
+		fe = fe.Add("col_char_2", "ColChar2", dml.RuleMaxLength, "must not exceed 17 characters")
+	}
 	if validateDmlgenTypes != nil {
-		return validateDmlgenTypes(e)
+		return fe.Join(validateDmlgenTypes(e))
 	}
-	return nil
+	return fe.ErrorOrNil()
 }
 
 // WriteTo implements io.WriterTo and writes the field names and their values to
@@ -3887,17 +4420,35 @@ func (e *SalesOrderStatusState) Empty() *SalesOrderStatusState {
 func (e *SalesOrderStatusState) IsSet() bool { return e.Status != "" && e.State != "" }
 
 // This variable can be set in another file to provide a custom validator.
+// A returned dml.FieldErrors gets merged with the errors of the column
+// constraints.
 var validateSalesOrderStatusState func(*SalesOrderStatusState) error
 
-// Validate runs internal consistency tests.
+// Validate runs internal consistency tests and checks the values against the
+// constraints of the table columns. It returns dml.FieldErrors if a constraint
+// has been violated.
 func (e *SalesOrderStatusState) Validate() error {
 	if e == nil {
 		return errors.NotValid.Newf("Type %T cannot be nil", e)
 	}
+	var fe dml.FieldErrors
+	if e.Status == "" {
+		fe = fe.Add("status", "Status", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.Status) > 32 {
+		fe = fe.Add("status", "Status", dml.RuleMaxLength, "must not exceed 32 characters")
+	}
+	if e.State == "" {
+		fe = fe.Add("state", "State", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.State) > 32 {
+		fe = fe.Add("state", "State", dml.RuleMaxLength, "must not exceed 32 characters")
+	}
+	if e.VisibleOnFront > 65535 {
+		fe = fe.Add("visible_on_front", "VisibleOnFront", dml.RuleRange, "value %d exceeds the maximum of 65535", e.VisibleOnFront)
+	}
 	if validateSalesOrderStatusState != nil {
-		return validateSalesOrderStatusState(e)
+		return fe.Join(validateSalesOrderStatusState(e))
 	}
-	return nil
+	return fe.ErrorOrNil()
 }
 
 // WriteTo implements io.WriterTo and writes the field names and their values to
@@ -4303,17 +4854,40 @@ func (e *ViewCustomerAutoIncrement) Empty() *ViewCustomerAutoIncrement {
 func (e *ViewCustomerAutoIncrement) IsSet() bool { return e.CeEntityID > 0 }
 
 // This variable can be set in another file to provide a custom validator.
+// A returned dml.FieldErrors gets merged with the errors of the column
+// constraints.
 var validateViewCustomerAutoIncrement func(*ViewCustomerAutoIncrement) error
 
-// Validate runs internal consistency tests.
+// Validate runs internal consistency tests and checks the values against the
+// constraints of the table columns. It returns dml.FieldErrors if a constraint
+// has been violated.
 func (e *ViewCustomerAutoIncrement) Validate() error {
 	if e == nil {
 		return errors.NotValid.Newf("Type %T cannot be nil", e)
 	}
+	var fe dml.FieldErrors
+	if e.Email.Valid && utf8.RuneCountInString(e.Email.Data) > 255 {
+		fe = fe.Add("email", "Email", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.Firstname == "" {
+		fe = fe.Add("firstname", "Firstname", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.Firstname) > 255 {
+		fe = fe.Add("firstname", "Firstname", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.Lastname == "" {
+		fe = fe.Add("lastname", "Lastname", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.Lastname) > 255 {
+		fe = fe.Add("lastname", "Lastname", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.City == "" {
+		fe = fe.Add("city", "City", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.City) > 255 {
+		fe = fe.Add("city", "City", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
 	if validateViewCustomerAutoIncrement != nil {
-		return validateViewCustomerAutoIncrement(e)
+		return fe.Join(validateViewCustomerAutoIncrement(e))
 	}
-	return nil
+	return fe.ErrorOrNil()
 }
 
 // WriteTo implements io.WriterTo and writes the field names and their values to
@@ -4568,17 +5142,40 @@ func (e *ViewCustomerNoAutoIncrement) Empty() *ViewCustomerNoAutoIncrement {
 }
 
 // This variable can be set in another file to provide a custom validator.
+// A returned dml.FieldErrors gets merged with the errors of the column
+// constraints.
 var validateViewCustomerNoAutoIncrement func(*ViewCustomerNoAutoIncrement) error
 
-// Validate runs internal consistency tests.
+// Validate runs internal consistency tests and checks the values against the
+// constraints of the table columns. It returns dml.FieldErrors if a constraint
+// has been violated.
 func (e *ViewCustomerNoAutoIncrement) Validate() error {
 	if e == nil {
 		return errors.NotValid.Newf("Type %T cannot be nil", e)
 	}
+	var fe dml.FieldErrors
+	if e.Email.Valid && utf8.RuneCountInString(e.Email.Data) > 255 {
+		fe = fe.Add("email", "Email", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.Firstname == "" {
+		fe = fe.Add("firstname", "Firstname", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.Firstname) > 255 {
+		fe = fe.Add("firstname", "Firstname", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.Lastname == "" {
+		fe = fe.Add("lastname", "Lastname", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.Lastname) > 255 {
+		fe = fe.Add("lastname", "Lastname", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
+	if e.City == "" {
+		fe = fe.Add("city", "City", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.City) > 255 {
+		fe = fe.Add("city", "City", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
 	if validateViewCustomerNoAutoIncrement != nil {
-		return validateViewCustomerNoAutoIncrement(e)
+		return fe.Join(validateViewCustomerNoAutoIncrement(e))
 	}
-	return nil
+	return fe.ErrorOrNil()
 }
 
 // WriteTo implements io.WriterTo and writes the field names and their values to
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"io"
 	"time"
+	"unicode/utf8"
 
 	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/dml"
 	"github.com/corestoreio/pkg/storage/null"
 )
 
@@ -25,6 +27,29 @@ type CoreConfiguration struct {
 	VersionTe time.Time   // version_te timestamp(6) NOT NULL PRI  STORED GENERATED "Timestamp End Versioning"
 }
 
+// CoreConfigurationColumns contains the typed columns of table
+// core_configuration. They create the conditions of queries, e.g.
+// CoreConfigurationColumns.ConfigID.Equal(v).
+var CoreConfigurationColumns = struct {
+	ConfigID  dml.ColumnUint[uint32]
+	Scope     dml.ColumnStr
+	ScopeID   dml.ColumnInt[int32]
+	Expires   dml.ColumnTime
+	Path      dml.ColumnStr
+	Value     dml.ColumnStr
+	VersionTs dml.ColumnTime
+	VersionTe dml.ColumnTime
+}{
+	ConfigID:  "config_id",
+	Scope:     "scope",
+	ScopeID:   "scope_id",
+	Expires:   "expires",
+	Path:      "path",
+	Value:     "value",
+	VersionTs: "version_ts",
+	VersionTe: "version_te",
+}
+
 // SalesOrderStatusState represents a single row for DB table
 // sales_order_status_state. Auto generated.
 // Table comment: Sales Order Status Table
@@ -35,6 +60,21 @@ type SalesOrderStatusState struct {
 	VisibleOnFront uint16 // visible_on_front smallint(5) unsigned NOT NULL  DEFAULT '0'  "Visible on front"
 }
 
+// SalesOrderStatusStateColumns contains the typed columns of table
+// sales_order_status_state. They create the conditions of queries, e.g.
+// SalesOrderStatusStateColumns.Status.Equal(v).
+var SalesOrderStatusStateColumns = struct {
+	Status         dml.ColumnStr
+	State          dml.ColumnStr
+	IsDefault      dml.ColumnBool
+	VisibleOnFront dml.ColumnUint[uint16]
+}{
+	Status:         "status",
+	State:          "state",
+	IsDefault:      "is_default",
+	VisibleOnFront: "visible_on_front",
+}
+
 // Copy copies the struct and returns a new pointer. TODO use deepcopy tool to
 // generate code afterwards
 func (e *CoreConfiguration) Copy() *CoreConfiguration {
@@ -49,17 +89,33 @@ func (e *CoreConfiguration) Copy() *CoreConfiguration {
 func (e *CoreConfiguration) Empty() *CoreConfiguration { *e = CoreConfiguration{}; return e }
 
 // This variable can be set in another file to provide a custom validator.
+// A returned dml.FieldErrors gets merged with the errors of the column
+// constraints.
 var validateCoreConfiguration func(*CoreConfiguration) error
 
-// Validate runs internal consistency tests.
+// Validate runs internal consistency tests and checks the values against the
+// constraints of the table columns. It returns dml.FieldErrors if a constraint
+// has been violated.
 func (e *CoreConfiguration) Validate() error {
 	if e == nil {
 		return errors.NotValid.Newf("Type %T cannot be nil", e)
 	}
+	var fe dml.FieldErrors
+	if utf8.RuneCountInString(e.Scope) > 8 {
+		fe = fe.Add("scope", "Scope", dml.RuleMaxLength, "must not exceed 8 characters")
+	}
+	if e.Expires.Valid && !dml.ValidDateRange("datetime", e.Expires.Time) {
+		fe = fe.Add("expires", "Expires", dml.RuleDateRange, "%s is out of the datetime range", e.Expires.Time)
+	}
+	if e.Path == "" {
+		fe = fe.Add("path", "Path", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.Path) > 255 {
+		fe = fe.Add("path", "Path", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
 	if validateCoreConfiguration != nil {
-		return validateCoreConfiguration(e)
+		return fe.Join(validateCoreConfiguration(e))
 	}
-	return nil
+	return fe.ErrorOrNil()
 }
 
 // WriteTo implements io.WriterTo and writes the field names and their values to
@@ -237,17 +293,32 @@ func (e *SalesOrderStatusState) Empty() *SalesOrderStatusState {
 }
 
 // This variable can be set in another file to provide a custom validator.
+// A returned dml.FieldErrors gets merged with the errors of the column
+// constraints.
 var validateSalesOrderStatusState func(*SalesOrderStatusState) error
 
-// Validate runs internal consistency tests.
+// Validate runs internal consistency tests and checks the values against the
+// constraints of the table columns. It returns dml.FieldErrors if a constraint
+// has been violated.
 func (e *SalesOrderStatusState) Validate() error {
 	if e == nil {
 		return errors.NotValid.Newf("Type %T cannot be nil", e)
 	}
+	var fe dml.FieldErrors
+	if e.Status == "" {
+		fe = fe.Add("status", "Status", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.Status) > 32 {
+		fe = fe.Add("status", "Status", dml.RuleMaxLength, "must not exceed 32 characters")
+	}
+	if e.State == "" {
+		fe = fe.Add("state", "State", dml.RuleRequired, "must not be empty")
+	} else if utf8.RuneCountInString(e.State) > 32 {
+		fe = fe.Add("state", "State", dml.RuleMaxLength, "must not exceed 32 characters")
+	}
 	if validateSalesOrderStatusState != nil {
-		return validateSalesOrderStatusState(e)
+		return fe.Join(validateSalesOrderStatusState(e))
 	}
-	return nil
+	return fe.ErrorOrNil()
 }
 
 // WriteTo implements io.WriterTo and writes the field names and their values to
@@ -232,7 +232,7 @@ func (r *catalogCategoryEntityRelations) InsertSequenceCatalogCategory(ctx conte
 	return errors.WithStack(r.SequenceCatalogCategory.DBInsert(ctx, dbm, opts...))
 }
 
-func (r *customerEntityRelations) UpdateSequenceCatalogCategory(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
+func (r *catalogCategoryEntityRelations) UpdateSequenceCatalogCategory(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
 	if r.SequenceCatalogCategory == nil || len(r.SequenceCatalogCategory.Data) == 0 {
 		dbr := dbm.ConnPool.WithCacheKey("SequenceCatalogCategoryDeleteByFK", opts...)
 		res, err := dbr.ExecContext(ctx, r.parent.RowID)
@@ -250,7 +250,7 @@ func (r *catalogCategoryEntityRelations) LoadSequenceCatalogCategory(ctx context
 		r.SequenceCatalogCategory = &SequenceCatalogCategory{}
 	}
 	r.SequenceCatalogCategory.Clear()
-	rowCount, err = dbm.ConnPool.WithCacheKey("SequenceCatalogCategorySelectByFK", opts...).Load(ctx, r.SequenceCatalogCategory, r.parent.EntityID)
+	rowCount, err = dbm.ConnPool.WithCacheKey("SequenceCatalogCategorySelectByFK", opts...).Load(ctx, r.SequenceCatalogCategory, r.parent.RowID)
 	return rowCount, errors.WithStack(err)
 }
 
@@ -417,7 +417,7 @@ func (r *sequenceCatalogCategoryRelations) InsertCatalogCategoryEntity(ctx conte
 	return errors.WithStack(r.CatalogCategoryEntity.DBInsert(ctx, dbm, opts...))
 }
 
-func (r *customerEntityRelations) UpdateCatalogCategoryEntity(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
+func (r *sequenceCatalogCategoryRelations) UpdateCatalogCategoryEntity(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
 	if r.CatalogCategoryEntity == nil || len(r.CatalogCategoryEntity.Data) == 0 {
 		dbr := dbm.ConnPool.WithCacheKey("CatalogCategoryEntityDeleteByFK", opts...)
 		res, err := dbr.ExecContext(ctx, r.parent.SequenceValue)
@@ -435,7 +435,7 @@ func (r *sequenceCatalogCategoryRelations) LoadCatalogCategoryEntity(ctx context
 		r.CatalogCategoryEntity = &CatalogCategoryEntity{}
 	}
 	r.CatalogCategoryEntity.Clear()
-	rowCount, err = dbm.ConnPool.WithCacheKey("CatalogCategoryEntitySelectByFK", opts...).Load(ctx, r.CatalogCategoryEntity, r.parent.EntityID)
+	rowCount, err = dbm.ConnPool.WithCacheKey("CatalogCategoryEntitySelectByFK", opts...).Load(ctx, r.CatalogCategoryEntity, r.parent.SequenceValue)
 	return rowCount, errors.WithStack(err)
 }
 
@@ -502,7 +502,7 @@ func (r *storeRelations) InsertStoreGroup(ctx context.Context, dbm *DBM, opts ..
 	return errors.WithStack(r.StoreGroup.DBInsert(ctx, dbm, opts...))
 }
 
-func (r *customerEntityRelations) UpdateStoreGroup(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
+func (r *storeRelations) UpdateStoreGroup(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
 	if r.StoreGroup == nil || len(r.StoreGroup.Data) == 0 {
 		dbr := dbm.ConnPool.WithCacheKey("StoreGroupDeleteByFK", opts...)
 		res, err := dbr.ExecContext(ctx, r.parent.StoreID)
@@ -520,7 +520,7 @@ func (r *storeRelations) LoadStoreGroup(ctx context.Context, dbm *DBM, opts ...d
 		r.StoreGroup = &StoreGroup{}
 	}
 	r.StoreGroup.Clear()
-	rowCount, err = dbm.ConnPool.WithCacheKey("StoreGroupSelectByFK", opts...).Load(ctx, r.StoreGroup, r.parent.EntityID)
+	rowCount, err = dbm.ConnPool.WithCacheKey("StoreGroupSelectByFK", opts...).Load(ctx, r.StoreGroup, r.parent.StoreID)
 	return rowCount, errors.WithStack(err)
 }
 
@@ -544,7 +544,7 @@ func (r *storeRelations) InsertStoreWebsite(ctx context.Context, dbm *DBM, opts
 	return errors.WithStack(r.StoreWebsite.DBInsert(ctx, dbm, opts...))
 }
 
-func (r *customerEntityRelations) UpdateStoreWebsite(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
+func (r *storeRelations) UpdateStoreWebsite(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
 	if r.StoreWebsite == nil || len(r.StoreWebsite.Data) == 0 {
 		dbr := dbm.ConnPool.WithCacheKey("StoreWebsiteDeleteByFK", opts...)
 		res, err := dbr.ExecContext(ctx, r.parent.StoreID)
@@ -562,7 +562,7 @@ func (r *storeRelations) LoadStoreWebsite(ctx context.Context, dbm *DBM, opts ..
 		r.StoreWebsite = &StoreWebsite{}
 	}
 	r.StoreWebsite.Clear()
-	rowCount, err = dbm.ConnPool.WithCacheKey("StoreWebsiteSelectByFK", opts...).Load(ctx, r.StoreWebsite, r.parent.EntityID)
+	rowCount, err = dbm.ConnPool.WithCacheKey("StoreWebsiteSelectByFK", opts...).Load(ctx, r.StoreWebsite, r.parent.StoreID)
 	return rowCount, errors.WithStack(err)
 }
 
@@ -640,7 +640,7 @@ func (r *storeGroupRelations) InsertStoreWebsite(ctx context.Context, dbm *DBM,
 	return errors.WithStack(r.StoreWebsite.DBInsert(ctx, dbm, opts...))
 }
 
-func (r *customerEntityRelations) UpdateStoreWebsite(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
+func (r *storeGroupRelations) UpdateStoreWebsite(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
 	if r.StoreWebsite == nil || len(r.StoreWebsite.Data) == 0 {
 		dbr := dbm.ConnPool.WithCacheKey("StoreWebsiteDeleteByFK", opts...)
 		res, err := dbr.ExecContext(ctx, r.parent.GroupID)
@@ -658,7 +658,7 @@ func (r *storeGroupRelations) LoadStoreWebsite(ctx context.Context, dbm *DBM, op
 		r.StoreWebsite = &StoreWebsite{}
 	}
 	r.StoreWebsite.Clear()
-	rowCount, err = dbm.ConnPool.WithCacheKey("StoreWebsiteSelectByFK", opts...).Load(ctx, r.StoreWebsite, r.parent.EntityID)
+	rowCount, err = dbm.ConnPool.WithCacheKey("StoreWebsiteSelectByFK", opts...).Load(ctx, r.StoreWebsite, r.parent.GroupID)
 	return rowCount, errors.WithStack(err)
 }
 
@@ -682,7 +682,7 @@ func (r *storeGroupRelations) InsertStores(ctx context.Context, dbm *DBM, opts .
 	return errors.WithStack(r.Stores.DBInsert(ctx, dbm, opts...))
 }
 
-func (r *customerEntityRelations) UpdateStores(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
+func (r *storeGroupRelations) UpdateStores(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
 	if r.Stores == nil || len(r.Stores.Data) == 0 {
 		dbr := dbm.ConnPool.WithCacheKey("StoresDeleteByFK", opts...)
 		res, err := dbr.ExecContext(ctx, r.parent.GroupID)
@@ -700,7 +700,7 @@ func (r *storeGroupRelations) LoadStores(ctx context.Context, dbm *DBM, opts ...
 		r.Stores = &Stores{}
 	}
 	r.Stores.Clear()
-	rowCount, err = dbm.ConnPool.WithCacheKey("StoresSelectByFK", opts...).Load(ctx, r.Stores, r.parent.EntityID)
+	rowCount, err = dbm.ConnPool.WithCacheKey("StoresSelectByFK", opts...).Load(ctx, r.Stores, r.parent.GroupID)
 	return rowCount, errors.WithStack(err)
 }
 
@@ -777,7 +777,7 @@ func (r *storeWebsiteRelations) InsertStores(ctx context.Context, dbm *DBM, opts
 	return errors.WithStack(r.Stores.DBInsert(ctx, dbm, opts...))
 }
 
-func (r *customerEntityRelations) UpdateStores(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
+func (r *storeWebsiteRelations) UpdateStores(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
 	if r.Stores == nil || len(r.Stores.Data) == 0 {
 		dbr := dbm.ConnPool.WithCacheKey("StoresDeleteByFK", opts...)
 		res, err := dbr.ExecContext(ctx, r.parent.WebsiteID)
@@ -795,7 +795,7 @@ func (r *storeWebsiteRelations) LoadStores(ctx context.Context, dbm *DBM, opts .
 		r.Stores = &Stores{}
 	}
 	r.Stores.Clear()
-	rowCount, err = dbm.ConnPool.WithCacheKey("StoresSelectByFK", opts...).Load(ctx, r.Stores, r.parent.EntityID)
+	rowCount, err = dbm.ConnPool.WithCacheKey("StoresSelectByFK", opts...).Load(ctx, r.Stores, r.parent.WebsiteID)
 	return rowCount, errors.WithStack(err)
 }
 
@@ -819,7 +819,7 @@ func (r *storeWebsiteRelations) InsertStoreGroups(ctx context.Context, dbm *DBM,
 	return errors.WithStack(r.StoreGroups.DBInsert(ctx, dbm, opts...))
 }
 
-func (r *customerEntityRelations) UpdateStoreGroups(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
+func (r *storeWebsiteRelations) UpdateStoreGroups(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
 	if r.StoreGroups == nil || len(r.StoreGroups.Data) == 0 {
 		dbr := dbm.ConnPool.WithCacheKey("StoreGroupsDeleteByFK", opts...)
 		res, err := dbr.ExecContext(ctx, r.parent.WebsiteID)
@@ -837,7 +837,7 @@ func (r *storeWebsiteRelations) LoadStoreGroups(ctx context.Context, dbm *DBM, o
 		r.StoreGroups = &StoreGroups{}
 	}
 	r.StoreGroups.Clear()
-	rowCount, err = dbm.ConnPool.WithCacheKey("StoreGroupsSelectByFK", opts...).Load(ctx, r.StoreGroups, r.parent.EntityID)
+	rowCount, err = dbm.ConnPool.WithCacheKey("StoreGroupsSelectByFK", opts...).Load(ctx, r.StoreGroups, r.parent.WebsiteID)
 	return rowCount, errors.WithStack(err)
 }
 
@@ -7,6 +7,8 @@ import (
 	"database/sql"
 	"fmt"
 	"io"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/corestoreio/errors"
 	"github.com/corestoreio/pkg/sql/ddl"
@@ -65,6 +67,21 @@ type CustomerEntityInt struct {
 	Value       int32  // value int(11) NOT NULL  DEFAULT '0'  "Value"
 }
 
+// CustomerEntityIntColumns contains the typed columns of table
+// customer_entity_int. They create the conditions of queries, e.g.
+// CustomerEntityIntColumns.ValueID.Equal(v).
+var CustomerEntityIntColumns = struct {
+	ValueID     dml.ColumnInt[int32]
+	AttributeID dml.ColumnUint[uint16]
+	EntityID    dml.ColumnUint[uint32]
+	Value       dml.ColumnInt[int32]
+}{
+	ValueID:     "value_id",
+	AttributeID: "attribute_id",
+	EntityID:    "entity_id",
+	Value:       "value",
+}
+
 // CustomerEntityVarchar represents a single row for DB table
 // customer_entity_varchar. Auto generated.
 // Table comment: Customer Entity Varchar
@@ -75,6 +92,21 @@ type CustomerEntityVarchar struct {
 	Value       null.String // value varchar(255) NULL  DEFAULT 'NULL'  "Value"
 }
 
+// CustomerEntityVarcharColumns contains the typed columns of table
+// customer_entity_varchar. They create the conditions of queries, e.g.
+// CustomerEntityVarcharColumns.ValueID.Equal(v).
+var CustomerEntityVarcharColumns = struct {
+	ValueID     dml.ColumnInt[int32]
+	AttributeID dml.ColumnUint[uint16]
+	EntityID    dml.ColumnUint[uint32]
+	Value       dml.ColumnStr
+}{
+	ValueID:     "value_id",
+	AttributeID: "attribute_id",
+	EntityID:    "entity_id",
+	Value:       "value",
+}
+
 // TableName constants define the names of all tables.
 const (
 	TableNameCustomerAddressEntity = "customer_address_entity"
@@ -342,6 +374,15 @@ func NewDBManager(ctx context.Context, dbmo *DBMOption) (*DBM, error) {
 			"CustomerEntityVarcharsSelectByFK": dbmo.InitSelectFn(tbls.MustTable(TableNameCustomerEntityVarchar).Select("*").Where(
 				dml.Column(`entity_id`).Equal().PlaceHolder(),
 			)),
+			"CustomerAddressEntitiesSelectByFKs": dbmo.InitSelectFn(tbls.MustTable(TableNameCustomerAddressEntity).Select("*").Where(
+				dml.Column(`parent_id`).In().PlaceHolder(),
+			)),
+			"CustomerEntityIntsSelectByFKs": dbmo.InitSelectFn(tbls.MustTable(TableNameCustomerEntityInt).Select("*").Where(
+				dml.Column(`entity_id`).In().PlaceHolder(),
+			)),
+			"CustomerEntityVarcharsSelectByFKs": dbmo.InitSelectFn(tbls.MustTable(TableNameCustomerEntityVarchar).Select("*").Where(
+				dml.Column(`entity_id`).In().PlaceHolder(),
+			)),
 			// </FOREIGN_KEY_QUERIES customer_entity >
 			"CustomerEntityIntsSelectAll": dbmo.InitSelectFn(tbls.MustTable(TableNameCustomerEntityInt).Select("*")),
 			"CustomerEntityIntsSelectByPK": dbmo.InitSelectFn(tbls.MustTable(TableNameCustomerEntityInt).Select("*")).Where(
@@ -1200,6 +1241,170 @@ func (cc *CustomerEntities) EntityIDs(ret ...uint32) []uint32 {
 	return ret
 }
 
+// PreloadCustomerAddressEntities loads the relation CustomerAddressEntities for
+// all entities of the collection with one query and assigns the rows to the
+// Relations field of each entity. It returns all loaded rows to allow further
+// preloading. Auto generated.
+func (cc *CustomerEntities) PreloadCustomerAddressEntities(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (_ *CustomerAddressEntities, err error) {
+	if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil, nil
+	}
+	parents := make(map[uint32][]*CustomerEntity, len(cc.Data))
+	keys := make([]uint32, 0, len(cc.Data))
+	for _, e := range cc.Data {
+		if e.Relations == nil {
+			e.NewRelations()
+		}
+		e.setRelationParent()
+		e.Relations.CustomerAddressEntities = &CustomerAddressEntities{}
+		k := e.EntityID
+		if _, ok := parents[k]; !ok {
+			keys = append(keys, k)
+		}
+		parents[k] = append(parents[k], e)
+	}
+	children := &CustomerAddressEntities{}
+	if len(keys) == 0 {
+		return children, nil
+	}
+	if _, err = dbm.ConnPool.WithCacheKey("CustomerAddressEntitiesSelectByFKs", opts...).Load(ctx, children, keys); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, c := range children.Data {
+		if !c.ParentID.Valid {
+			continue
+		}
+		for _, e := range parents[c.ParentID.Uint32] {
+			e.Relations.CustomerAddressEntities.Data = append(e.Relations.CustomerAddressEntities.Data, c)
+		}
+	}
+	return children, nil
+}
+
+// PreloadCustomerEntityInts loads the relation CustomerEntityInts for all
+// entities of the collection with one query and assigns the rows to the
+// Relations field of each entity. It returns all loaded rows to allow further
+// preloading. Auto generated.
+func (cc *CustomerEntities) PreloadCustomerEntityInts(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (_ *CustomerEntityInts, err error) {
+	if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil, nil
+	}
+	parents := make(map[uint32][]*CustomerEntity, len(cc.Data))
+	keys := make([]uint32, 0, len(cc.Data))
+	for _, e := range cc.Data {
+		if e.Relations == nil {
+			e.NewRelations()
+		}
+		e.setRelationParent()
+		e.Relations.CustomerEntityInts = &CustomerEntityInts{}
+		k := e.EntityID
+		if _, ok := parents[k]; !ok {
+			keys = append(keys, k)
+		}
+		parents[k] = append(parents[k], e)
+	}
+	children := &CustomerEntityInts{}
+	if len(keys) == 0 {
+		return children, nil
+	}
+	if _, err = dbm.ConnPool.WithCacheKey("CustomerEntityIntsSelectByFKs", opts...).Load(ctx, children, keys); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, c := range children.Data {
+		for _, e := range parents[c.EntityID] {
+			e.Relations.CustomerEntityInts.Data = append(e.Relations.CustomerEntityInts.Data, c)
+		}
+	}
+	return children, nil
+}
+
+// PreloadCustomerEntityVarchars loads the relation CustomerEntityVarchars for
+// all entities of the collection with one query and assigns the rows to the
+// Relations field of each entity. It returns all loaded rows to allow further
+// preloading. Auto generated.
+func (cc *CustomerEntities) PreloadCustomerEntityVarchars(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (_ *CustomerEntityVarchars, err error) {
+	if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil, nil
+	}
+	parents := make(map[uint32][]*CustomerEntity, len(cc.Data))
+	keys := make([]uint32, 0, len(cc.Data))
+	for _, e := range cc.Data {
+		if e.Relations == nil {
+			e.NewRelations()
+		}
+		e.setRelationParent()
+		e.Relations.CustomerEntityVarchars = &CustomerEntityVarchars{}
+		k := e.EntityID
+		if _, ok := parents[k]; !ok {
+			keys = append(keys, k)
+		}
+		parents[k] = append(parents[k], e)
+	}
+	children := &CustomerEntityVarchars{}
+	if len(keys) == 0 {
+		return children, nil
+	}
+	if _, err = dbm.ConnPool.WithCacheKey("CustomerEntityVarcharsSelectByFKs", opts...).Load(ctx, children, keys); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, c := range children.Data {
+		for _, e := range parents[c.EntityID] {
+			e.Relations.CustomerEntityVarchars.Data = append(e.Relations.CustomerEntityVarchars.Data, c)
+		}
+	}
+	return children, nil
+}
+
+// Preload loads the relations named in paths for all entities of the collection
+// with one query per relation. A path can address nested relations separated by
+// a dot, e.g. "CustomerAddressEntities.Name". Preloading gets skipped if
+// dml.QueryOptions.SkipRelations has been set. Auto generated.
+func (cc *CustomerEntities) Preload(ctx context.Context, dbm *DBM, paths []string, opts ...dml.DBRFunc) error {
+	if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil
+	}
+	var names []string
+	nested := make(map[string][]string, len(paths))
+	for _, p := range paths {
+		name, rest, _ := strings.Cut(p, ".")
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = nil
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}
+	for _, name := range names {
+		switch name {
+		case "CustomerAddressEntities":
+			if len(nested[name]) > 0 {
+				return errors.NotSupported.Newf("[dmltestgenerated5] CustomerEntities.Preload: relation %q has no nested relations", name)
+			}
+			if _, err := cc.PreloadCustomerAddressEntities(ctx, dbm, opts...); err != nil {
+				return errors.WithStack(err)
+			}
+		case "CustomerEntityInts":
+			if len(nested[name]) > 0 {
+				return errors.NotSupported.Newf("[dmltestgenerated5] CustomerEntities.Preload: relation %q has no nested relations", name)
+			}
+			if _, err := cc.PreloadCustomerEntityInts(ctx, dbm, opts...); err != nil {
+				return errors.WithStack(err)
+			}
+		case "CustomerEntityVarchars":
+			if len(nested[name]) > 0 {
+				return errors.NotSupported.Newf("[dmltestgenerated5] CustomerEntities.Preload: relation %q has no nested relations", name)
+			}
+			if _, err := cc.PreloadCustomerEntityVarchars(ctx, dbm, opts...); err != nil {
+				return errors.WithStack(err)
+			}
+		default:
+			return errors.NotFound.Newf("[dmltestgenerated5] CustomerEntities.Preload: relation %q not found", name)
+		}
+	}
+	return nil
+}
+
 // Copy copies the struct and returns a new pointer. TODO use deepcopy tool to
 // generate code afterwards
 func (e *CustomerEntityInt) Copy() *CustomerEntityInt {
@@ -1338,9 +1543,13 @@ func (e *CustomerEntityInt) Empty() *CustomerEntityInt { *e = CustomerEntityInt{
 func (e *CustomerEntityInt) IsSet() bool { return e.ValueID != 0 }
 
 // This variable can be set in another file to provide a custom validator.
+// A returned dml.FieldErrors gets merged with the errors of the column
+// constraints.
 var validateCustomerEntityInt func(*CustomerEntityInt) error
 
-// Validate runs internal consistency tests.
+// Validate runs internal consistency tests and checks the values against the
+// constraints of the table columns. It returns dml.FieldErrors if a constraint
+// has been violated.
 func (e *CustomerEntityInt) Validate() error {
 	if e == nil {
 		return errors.NotValid.Newf("Type %T cannot be nil", e)
@@ -1813,17 +2022,25 @@ func (e *CustomerEntityVarchar) Empty() *CustomerEntityVarchar {
 func (e *CustomerEntityVarchar) IsSet() bool { return e.ValueID != 0 }
 
 // This variable can be set in another file to provide a custom validator.
+// A returned dml.FieldErrors gets merged with the errors of the column
+// constraints.
 var validateCustomerEntityVarchar func(*CustomerEntityVarchar) error
 
-// Validate runs internal consistency tests.
+// Validate runs internal consistency tests and checks the values against the
+// constraints of the table columns. It returns dml.FieldErrors if a constraint
+// has been violated.
 func (e *CustomerEntityVarchar) Validate() error {
 	if e == nil {
 		return errors.NotValid.Newf("Type %T cannot be nil", e)
 	}
+	var fe dml.FieldErrors
+	if e.Value.Valid && utf8.RuneCountInString(e.Value.Data) > 255 {
+		fe = fe.Add("value", "Value", dml.RuleMaxLength, "must not exceed 255 characters")
+	}
 	if validateCustomerEntityVarchar != nil {
-		return validateCustomerEntityVarchar(e)
+		return fe.Join(validateCustomerEntityVarchar(e))
 	}
-	return nil
+	return fe.ErrorOrNil()
 }
 
 // WriteTo implements io.WriterTo and writes the field names and their values to
@@ -4,6 +4,7 @@ package dmltestgeneratedMToM
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/corestoreio/errors"
@@ -289,6 +290,15 @@ func NewDBManager(ctx context.Context, dbmo *DBMOption) (*DBM, error) {
 			"AthleteTeamsSelectByFK": dbmo.InitSelectFn(tbls.MustTable(TableNameAthleteTeamMember).Select("*").Where(
 				dml.Column(`athlete_id`).Equal().PlaceHolder(),
 			)),
+			"AthleteTeamMembersSelectByFKs": dbmo.InitSelectFn(tbls.MustTable(TableNameAthleteTeamMember).Select("*").Where(
+				dml.Column(`athlete_id`).In().PlaceHolder(),
+			)),
+			"AthleteTeamsSelectLinkByFKs": dbmo.InitSelectFn(tbls.MustTable(TableNameAthleteTeamMember).Select("*").Where(
+				dml.Column(`athlete_id`).In().PlaceHolder(),
+			)),
+			"AthleteTeamsSelectByFKs": dbmo.InitSelectFn(tbls.MustTable(TableNameAthleteTeam).Select("*").Where(
+				dml.Column(`team_id`).In().PlaceHolder(),
+			)),
 			// </FOREIGN_KEY_QUERIES athlete >
 			// <FOREIGN_KEY_QUERIES athlete_team >
 			"AthletesDeleteByFK": dbmo.InitDeleteFn(tbls.MustTable(TableNameAthleteTeamMember).Delete().Where(
@@ -297,6 +307,12 @@ func NewDBManager(ctx context.Context, dbmo *DBMOption) (*DBM, error) {
 			"AthletesSelectByFK": dbmo.InitSelectFn(tbls.MustTable(TableNameAthleteTeamMember).Select("*").Where(
 				dml.Column(`team_id`).Equal().PlaceHolder(),
 			)),
+			"AthletesSelectLinkByFKs": dbmo.InitSelectFn(tbls.MustTable(TableNameAthleteTeamMember).Select("*").Where(
+				dml.Column(`team_id`).In().PlaceHolder(),
+			)),
+			"AthletesSelectByFKs": dbmo.InitSelectFn(tbls.MustTable(TableNameAthlete).Select("*").Where(
+				dml.Column(`athlete_id`).In().PlaceHolder(),
+			)),
 			// </FOREIGN_KEY_QUERIES athlete_team >
 			// <FOREIGN_KEY_QUERIES customer_entity >
 			"CustomeraddressentitiesDeleteByFK": dbmo.InitDeleteFn(tbls.MustTable(TableNameCustomerAddressEntity).Delete().Where(
@@ -305,6 +321,9 @@ func NewDBManager(ctx context.Context, dbmo *DBMOption) (*DBM, error) {
 			"CustomeraddressentitiesSelectByFK": dbmo.InitSelectFn(tbls.MustTable(TableNameCustomerAddressEntity).Select("*").Where(
 				dml.Column(`parent_id`).Equal().PlaceHolder(),
 			)),
+			"CustomeraddressentitiesSelectByFKs": dbmo.InitSelectFn(tbls.MustTable(TableNameCustomerAddressEntity).Select("*").Where(
+				dml.Column(`parent_id`).In().PlaceHolder(),
+			)),
 			// </FOREIGN_KEY_QUERIES customer_entity >
 		}),
 	)
@@ -337,7 +356,7 @@ func (r *athleteRelations) InsertAthleteTeamMembers(ctx context.Context, dbm *DB
 	return errors.WithStack(r.AthleteTeamMembers.DBInsert(ctx, dbm, opts...))
 }
 
-func (r *customerEntityRelations) UpdateAthleteTeamMembers(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
+func (r *athleteRelations) UpdateAthleteTeamMembers(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
 	if r.AthleteTeamMembers == nil || len(r.AthleteTeamMembers.Data) == 0 {
 		dbr := dbm.ConnPool.WithCacheKey("AthleteTeamMembersDeleteByFK", opts...)
 		res, err := dbr.ExecContext(ctx, r.parent.AthleteID)
@@ -355,49 +374,7 @@ func (r *athleteRelations) LoadAthleteTeamMembers(ctx context.Context, dbm *DBM,
 		r.AthleteTeamMembers = &AthleteTeamMembers{}
 	}
 	r.AthleteTeamMembers.Clear()
-	rowCount, err = dbm.ConnPool.WithCacheKey("AthleteTeamMembersSelectByFK", opts...).Load(ctx, r.AthleteTeamMembers, r.parent.EntityID)
-	return rowCount, errors.WithStack(err)
-}
-
-func (r *athleteRelations) DeleteAthleteTeams(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) error {
-	dbr := dbm.ConnPool.WithCacheKey("AthleteTeamsDeleteByFK", opts...)
-	res, err := dbr.ExecContext(ctx, r.parent.AthleteID)
-	err = dbr.ResultCheckFn(TableNameAthleteTeamMember, len(r.AthleteTeams.Data), res, err)
-	if err == nil && r.AthleteTeams != nil {
-		r.AthleteTeams.Clear()
-	}
-	return errors.WithStack(err)
-}
-
-func (r *athleteRelations) InsertAthleteTeams(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) error {
-	if r.AthleteTeams == nil || len(r.AthleteTeams.Data) == 0 {
-		return nil
-	}
-	for _, e2 := range r.AthleteTeams.Data {
-		e2.AthleteID = r.parent.AthleteID
-	}
-	return errors.WithStack(r.AthleteTeams.DBInsert(ctx, dbm, opts...))
-}
-
-func (r *customerEntityRelations) UpdateAthleteTeams(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
-	if r.AthleteTeams == nil || len(r.AthleteTeams.Data) == 0 {
-		dbr := dbm.ConnPool.WithCacheKey("AthleteTeamsDeleteByFK", opts...)
-		res, err := dbr.ExecContext(ctx, r.parent.AthleteID)
-		return dbr.ResultCheckFn(TableNameAthleteTeamMember, -1, res, errors.WithStack(err))
-	}
-	for _, e2 := range r.AthleteTeams.Data {
-		e2.AthleteID = r.parent.AthleteID
-	}
-	err = r.AthleteTeams.DBUpdate(ctx, dbm, opts...)
-	return errors.WithStack(err)
-}
-
-func (r *athleteRelations) LoadAthleteTeams(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (rowCount uint64, err error) {
-	if r.AthleteTeams == nil {
-		r.AthleteTeams = &AthleteTeams{}
-	}
-	r.AthleteTeams.Clear()
-	rowCount, err = dbm.ConnPool.WithCacheKey("AthleteTeamsSelectByFK", opts...).Load(ctx, r.AthleteTeams, r.parent.EntityID)
+	rowCount, err = dbm.ConnPool.WithCacheKey("AthleteTeamMembersSelectByFK", opts...).Load(ctx, r.AthleteTeamMembers, r.parent.AthleteID)
 	return rowCount, errors.WithStack(err)
 }
 
@@ -405,9 +382,6 @@ func (r *athleteRelations) InsertAll(ctx context.Context, dbm *DBM, opts ...dml.
 	if err := r.InsertAthleteTeamMembers(ctx, dbm, opts...); err != nil {
 		return errors.WithStack(err)
 	}
-	if err := r.InsertAthleteTeams(ctx, dbm, opts...); err != nil {
-		return errors.WithStack(err)
-	}
 	return nil
 }
 
@@ -415,9 +389,6 @@ func (r *athleteRelations) LoadAll(ctx context.Context, dbm *DBM, opts ...dml.DB
 	if _, err = r.LoadAthleteTeamMembers(ctx, dbm, opts...); err != nil {
 		return errors.WithStack(err)
 	}
-	if _, err = r.LoadAthleteTeams(ctx, dbm, opts...); err != nil {
-		return errors.WithStack(err)
-	}
 	return nil
 }
 
@@ -425,9 +396,6 @@ func (r *athleteRelations) UpdateAll(ctx context.Context, dbm *DBM, opts ...dml.
 	if err := r.UpdateAthleteTeamMembers(ctx, dbm, opts...); err != nil {
 		return errors.WithStack(err)
 	}
-	if err := r.UpdateAthleteTeams(ctx, dbm, opts...); err != nil {
-		return errors.WithStack(err)
-	}
 	return nil
 }
 
@@ -435,9 +403,6 @@ func (r *athleteRelations) DeleteAll(ctx context.Context, dbm *DBM, opts ...dml.
 	if err := r.DeleteAthleteTeamMembers(ctx, dbm, opts...); err != nil {
 		return errors.WithStack(err)
 	}
-	if err := r.DeleteAthleteTeams(ctx, dbm, opts...); err != nil {
-		return errors.WithStack(err)
-	}
 	return nil
 }
 
@@ -544,73 +509,155 @@ func (cc *Athletes) AthleteIDs(ret ...uint32) []uint32 {
 	return ret
 }
 
-func (r *athleteTeamRelations) DeleteAthletes(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) error {
-	dbr := dbm.ConnPool.WithCacheKey("AthletesDeleteByFK", opts...)
-	res, err := dbr.ExecContext(ctx, r.parent.TeamID)
-	err = dbr.ResultCheckFn(TableNameAthleteTeamMember, len(r.Athletes.Data), res, err)
-	if err == nil && r.Athletes != nil {
-		r.Athletes.Clear()
+// PreloadAthleteTeamMembers loads the relation AthleteTeamMembers for all
+// entities of the collection with one query and assigns the rows to the
+// Relations field of each entity. It returns all loaded rows to allow further
+// preloading. Auto generated.
+func (cc *Athletes) PreloadAthleteTeamMembers(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (_ *AthleteTeamMembers, err error) {
+	if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil, nil
 	}
-	return errors.WithStack(err)
-}
-
-func (r *athleteTeamRelations) InsertAthletes(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) error {
-	if r.Athletes == nil || len(r.Athletes.Data) == 0 {
-		return nil
+	parents := make(map[uint32][]*Athlete, len(cc.Data))
+	keys := make([]uint32, 0, len(cc.Data))
+	for _, e := range cc.Data {
+		if e.Relations == nil {
+			e.NewRelations()
+		}
+		e.setRelationParent()
+		e.Relations.AthleteTeamMembers = &AthleteTeamMembers{}
+		k := e.AthleteID
+		if _, ok := parents[k]; !ok {
+			keys = append(keys, k)
+		}
+		parents[k] = append(parents[k], e)
+	}
+	children := &AthleteTeamMembers{}
+	if len(keys) == 0 {
+		return children, nil
+	}
+	if _, err = dbm.ConnPool.WithCacheKey("AthleteTeamMembersSelectByFKs", opts...).Load(ctx, children, keys); err != nil {
+		return nil, errors.WithStack(err)
 	}
-	for _, e2 := range r.Athletes.Data {
-		e2.TeamID = r.parent.TeamID
+	for _, c := range children.Data {
+		for _, e := range parents[c.AthleteID] {
+			e.Relations.AthleteTeamMembers.Data = append(e.Relations.AthleteTeamMembers.Data, c)
+		}
 	}
-	return errors.WithStack(r.Athletes.DBInsert(ctx, dbm, opts...))
+	return children, nil
 }
 
-func (r *customerEntityRelations) UpdateAthletes(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
-	if r.Athletes == nil || len(r.Athletes.Data) == 0 {
-		dbr := dbm.ConnPool.WithCacheKey("AthletesDeleteByFK", opts...)
-		res, err := dbr.ExecContext(ctx, r.parent.TeamID)
-		return dbr.ResultCheckFn(TableNameAthleteTeamMember, -1, res, errors.WithStack(err))
+// PreloadAthleteTeams loads the relation AthleteTeams for all entities of the
+// collection with one query and assigns the rows to the Relations field of each
+// entity. It returns all loaded rows to allow further preloading. Auto
+// generated.
+func (cc *Athletes) PreloadAthleteTeams(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (_ *AthleteTeams, err error) {
+	if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil, nil
 	}
-	for _, e2 := range r.Athletes.Data {
-		e2.TeamID = r.parent.TeamID
+	parents := make(map[uint32][]*Athlete, len(cc.Data))
+	keys := make([]uint32, 0, len(cc.Data))
+	for _, e := range cc.Data {
+		if e.Relations == nil {
+			e.NewRelations()
+		}
+		e.setRelationParent()
+		e.Relations.AthleteTeams = &AthleteTeams{}
+		k := e.AthleteID
+		if _, ok := parents[k]; !ok {
+			keys = append(keys, k)
+		}
+		parents[k] = append(parents[k], e)
 	}
-	err = r.Athletes.DBUpdate(ctx, dbm, opts...)
-	return errors.WithStack(err)
+	children := &AthleteTeams{}
+	if len(keys) == 0 {
+		return children, nil
+	}
+	links := &AthleteTeamMembers{}
+	if _, err = dbm.ConnPool.WithCacheKey("AthleteTeamsSelectLinkByFKs", opts...).Load(ctx, links, keys); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	targetParents := make(map[uint32][]*Athlete, len(links.Data))
+	targetKeys := make([]uint32, 0, len(links.Data))
+	for _, l := range links.Data {
+		k := l.TeamID
+		if _, ok := targetParents[k]; !ok {
+			targetKeys = append(targetKeys, k)
+		}
+		targetParents[k] = append(targetParents[k], parents[l.AthleteID]...)
+	}
+	if len(targetKeys) == 0 {
+		return children, nil
+	}
+	if _, err = dbm.ConnPool.WithCacheKey("AthleteTeamsSelectByFKs", opts...).Load(ctx, children, targetKeys); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, c := range children.Data {
+		for _, e := range targetParents[c.TeamID] {
+			e.Relations.AthleteTeams.Data = append(e.Relations.AthleteTeams.Data, c)
+		}
+	}
+	return children, nil
 }
 
-func (r *athleteTeamRelations) LoadAthletes(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (rowCount uint64, err error) {
-	if r.Athletes == nil {
-		r.Athletes = &Athletes{}
+// Preload loads the relations named in paths for all entities of the collection
+// with one query per relation. A path can address nested relations separated by
+// a dot, e.g. "AthleteTeamMembers.Name". Preloading gets skipped if
+// dml.QueryOptions.SkipRelations has been set. Auto generated.
+func (cc *Athletes) Preload(ctx context.Context, dbm *DBM, paths []string, opts ...dml.DBRFunc) error {
+	if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil
 	}
-	r.Athletes.Clear()
-	rowCount, err = dbm.ConnPool.WithCacheKey("AthletesSelectByFK", opts...).Load(ctx, r.Athletes, r.parent.EntityID)
-	return rowCount, errors.WithStack(err)
+	var names []string
+	nested := make(map[string][]string, len(paths))
+	for _, p := range paths {
+		name, rest, _ := strings.Cut(p, ".")
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = nil
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}
+	for _, name := range names {
+		switch name {
+		case "AthleteTeamMembers":
+			if len(nested[name]) > 0 {
+				return errors.NotSupported.Newf("[dmltestgeneratedMToM] Athletes.Preload: relation %q has no nested relations", name)
+			}
+			if _, err := cc.PreloadAthleteTeamMembers(ctx, dbm, opts...); err != nil {
+				return errors.WithStack(err)
+			}
+		case "AthleteTeams":
+			children, err := cc.PreloadAthleteTeams(ctx, dbm, opts...)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if len(nested[name]) > 0 {
+				if err := children.Preload(ctx, dbm, nested[name], opts...); err != nil {
+					return errors.WithStack(err)
+				}
+			}
+		default:
+			return errors.NotFound.Newf("[dmltestgeneratedMToM] Athletes.Preload: relation %q not found", name)
+		}
+	}
+	return nil
 }
 
 func (r *athleteTeamRelations) InsertAll(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) error {
-	if err := r.InsertAthletes(ctx, dbm, opts...); err != nil {
-		return errors.WithStack(err)
-	}
 	return nil
 }
 
 func (r *athleteTeamRelations) LoadAll(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {
-	if _, err = r.LoadAthletes(ctx, dbm, opts...); err != nil {
-		return errors.WithStack(err)
-	}
 	return nil
 }
 
 func (r *athleteTeamRelations) UpdateAll(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) error {
-	if err := r.UpdateAthletes(ctx, dbm, opts...); err != nil {
-		return errors.WithStack(err)
-	}
 	return nil
 }
 
 func (r *athleteTeamRelations) DeleteAll(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) error {
-	if err := r.DeleteAthletes(ctx, dbm, opts...); err != nil {
-		return errors.WithStack(err)
-	}
 	return nil
 }
 
@@ -715,6 +762,97 @@ func (cc *AthleteTeams) TeamIDs(ret ...uint32) []uint32 {
 	return ret
 }
 
+// PreloadAthletes loads the relation Athletes for all entities of the collection
+// with one query and assigns the rows to the Relations field of each entity. It
+// returns all loaded rows to allow further preloading. Auto generated.
+func (cc *AthleteTeams) PreloadAthletes(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (_ *Athletes, err error) {
+	if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil, nil
+	}
+	parents := make(map[uint32][]*AthleteTeam, len(cc.Data))
+	keys := make([]uint32, 0, len(cc.Data))
+	for _, e := range cc.Data {
+		if e.Relations == nil {
+			e.NewRelations()
+		}
+		e.setRelationParent()
+		e.Relations.Athletes = &Athletes{}
+		k := e.TeamID
+		if _, ok := parents[k]; !ok {
+			keys = append(keys, k)
+		}
+		parents[k] = append(parents[k], e)
+	}
+	children := &Athletes{}
+	if len(keys) == 0 {
+		return children, nil
+	}
+	links := &AthleteTeamMembers{}
+	if _, err = dbm.ConnPool.WithCacheKey("AthletesSelectLinkByFKs", opts...).Load(ctx, links, keys); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	targetParents := make(map[uint32][]*AthleteTeam, len(links.Data))
+	targetKeys := make([]uint32, 0, len(links.Data))
+	for _, l := range links.Data {
+		k := l.AthleteID
+		if _, ok := targetParents[k]; !ok {
+			targetKeys = append(targetKeys, k)
+		}
+		targetParents[k] = append(targetParents[k], parents[l.TeamID]...)
+	}
+	if len(targetKeys) == 0 {
+		return children, nil
+	}
+	if _, err = dbm.ConnPool.WithCacheKey("AthletesSelectByFKs", opts...).Load(ctx, children, targetKeys); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, c := range children.Data {
+		for _, e := range targetParents[c.AthleteID] {
+			e.Relations.Athletes.Data = append(e.Relations.Athletes.Data, c)
+		}
+	}
+	return children, nil
+}
+
+// Preload loads the relations named in paths for all entities of the collection
+// with one query per relation. A path can address nested relations separated by
+// a dot, e.g. "Athletes.Name". Preloading gets skipped if
+// dml.QueryOptions.SkipRelations has been set. Auto generated.
+func (cc *AthleteTeams) Preload(ctx context.Context, dbm *DBM, paths []string, opts ...dml.DBRFunc) error {
+	if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil
+	}
+	var names []string
+	nested := make(map[string][]string, len(paths))
+	for _, p := range paths {
+		name, rest, _ := strings.Cut(p, ".")
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = nil
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}
+	for _, name := range names {
+		switch name {
+		case "Athletes":
+			children, err := cc.PreloadAthletes(ctx, dbm, opts...)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if len(nested[name]) > 0 {
+				if err := children.Preload(ctx, dbm, nested[name], opts...); err != nil {
+					return errors.WithStack(err)
+				}
+			}
+		default:
+			return errors.NotFound.Newf("[dmltestgeneratedMToM] AthleteTeams.Preload: relation %q not found", name)
+		}
+	}
+	return nil
+}
+
 // AssignLastInsertID updates the increment ID field with the last inserted ID
 // from an INSERT operation. Implements dml.InsertIDAssigner. Auto generated.
 func (e *AthleteTeamMember) AssignLastInsertID(id int64) {
@@ -1148,3 +1286,79 @@ func (cc *CustomerEntities) EntityIDs(ret ...uint32) []uint32 {
 	}
 	return ret
 }
+
+// PreloadCustomeraddressentities loads the relation Customeraddressentities for
+// all entities of the collection with one query and assigns the rows to the
+// Relations field of each entity. It returns all loaded rows to allow further
+// preloading. Auto generated.
+func (cc *CustomerEntities) PreloadCustomeraddressentities(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (_ *CustomerAddressEntities, err error) {
+	if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil, nil
+	}
+	parents := make(map[uint32][]*CustomerEntity, len(cc.Data))
+	keys := make([]uint32, 0, len(cc.Data))
+	for _, e := range cc.Data {
+		if e.Relations == nil {
+			e.NewRelations()
+		}
+		e.setRelationParent()
+		e.Relations.Customeraddressentities = &CustomerAddressEntities{}
+		k := e.EntityID
+		if _, ok := parents[k]; !ok {
+			keys = append(keys, k)
+		}
+		parents[k] = append(parents[k], e)
+	}
+	children := &CustomerAddressEntities{}
+	if len(keys) == 0 {
+		return children, nil
+	}
+	if _, err = dbm.ConnPool.WithCacheKey("CustomeraddressentitiesSelectByFKs", opts...).Load(ctx, children, keys); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, c := range children.Data {
+		if !c.ParentID.Valid {
+			continue
+		}
+		for _, e := range parents[c.ParentID.Uint32] {
+			e.Relations.Customeraddressentities.Data = append(e.Relations.Customeraddressentities.Data, c)
+		}
+	}
+	return children, nil
+}
+
+// Preload loads the relations named in paths for all entities of the collection
+// with one query per relation. A path can address nested relations separated by
+// a dot, e.g. "Customeraddressentities.Name". Preloading gets skipped if
+// dml.QueryOptions.SkipRelations has been set. Auto generated.
+func (cc *CustomerEntities) Preload(ctx context.Context, dbm *DBM, paths []string, opts ...dml.DBRFunc) error {
+	if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil
+	}
+	var names []string
+	nested := make(map[string][]string, len(paths))
+	for _, p := range paths {
+		name, rest, _ := strings.Cut(p, ".")
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = nil
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}
+	for _, name := range names {
+		switch name {
+		case "Customeraddressentities":
+			if len(nested[name]) > 0 {
+				return errors.NotSupported.Newf("[dmltestgeneratedMToM] CustomerEntities.Preload: relation %q has no nested relations", name)
+			}
+			if _, err := cc.PreloadCustomeraddressentities(ctx, dbm, opts...); err != nil {
+				return errors.WithStack(err)
+			}
+		default:
+			return errors.NotFound.Newf("[dmltestgeneratedMToM] CustomerEntities.Preload: relation %q not found", name)
+		}
+	}
+	return nil
+}
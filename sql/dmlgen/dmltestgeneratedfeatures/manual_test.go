@@ -0,0 +1,216 @@
+package dmltestgeneratedfeatures
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/pseudo"
+)
+
+func newMockDBM(t *testing.T) (*DBM, sqlmock.Sqlmock, func()) {
+	db, mock := dmltest.MockDB(t)
+	mock.ExpectQuery("SELECT.+FROM information_schema.COLUMNS WHERE").
+		WillReturnRows(dmltest.MustMockRows(dmltest.WithFile("testdata", "shop_columns.csv")))
+
+	dbm, err := NewDBManager(context.Background(), &DBMOption{
+		TableOptions: []ddl.TableOption{ddl.WithConnPool(db)},
+	})
+	assert.NoError(t, err)
+	return dbm, mock, func() { dmltest.MockClose(t, db, mock) }
+}
+
+func TestManual_Preload(t *testing.T) {
+	dbm, mock, closeFn := newMockDBM(t)
+	defer closeFn()
+	ctx := context.Background()
+
+	authors := NewShopAuthors().Append(&ShopAuthor{AuthorID: 1}, &ShopAuthor{AuthorID: 2}, &ShopAuthor{AuthorID: 3})
+
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `book_id`, `author_id`, `title`, `status`, `price`, `version`, `created_at`, `updated_at` FROM `shop_book` AS `main_table` WHERE (`author_id` IN ?)")).
+		WithArgs(1, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id", "title"}).
+			AddRow(10, 1, "Go").AddRow(11, 1, "SQL").AddRow(12, 2, "Rust"))
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `book_id`, `tag_id` FROM `shop_book_tag` AS `main_table` WHERE (`book_id` IN ?)")).
+		WithArgs(10, 11, 12).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "tag_id"}).
+			AddRow(10, 100).AddRow(11, 100).AddRow(11, 101))
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `tag_id`, `name` FROM `shop_tag` AS `main_table` WHERE (`tag_id` IN ?)")).
+		WithArgs(100, 101).
+		WillReturnRows(sqlmock.NewRows([]string{"tag_id", "name"}).
+			AddRow(100, "programming").AddRow(101, "databases"))
+
+	assert.NoError(t, authors.Preload(ctx, dbm, []string{"ShopBooks.ShopTags"}))
+
+	a1, a2, a3 := authors.Data[0], authors.Data[1], authors.Data[2]
+	assert.Exactly(t, []uint32{10, 11}, a1.Relations.ShopBooks.BookIDs())
+	assert.Exactly(t, []uint32{12}, a2.Relations.ShopBooks.BookIDs())
+	assert.Exactly(t, 0, a3.Relations.ShopBooks.Len())
+
+	goBook, sqlBook, rustBook := a1.Relations.ShopBooks.Data[0], a1.Relations.ShopBooks.Data[1], a2.Relations.ShopBooks.Data[0]
+	assert.Exactly(t, []uint32{100}, goBook.Relations.ShopTags.TagIDs())
+	assert.Exactly(t, []uint32{100, 101}, sqlBook.Relations.ShopTags.TagIDs())
+	assert.Exactly(t, 0, rustBook.Relations.ShopTags.Len())
+	assert.Exactly(t, "databases", sqlBook.Relations.ShopTags.Data[1].Name)
+
+	t.Run("relation not found", func(t *testing.T) {
+		err := authors.Preload(ctx, dbm, []string{"ShopTags"})
+		assert.ErrorIsKind(t, errors.NotFound, err)
+	})
+	t.Run("no nested relation", func(t *testing.T) {
+		err := a1.Relations.ShopBooks.Preload(ctx, dbm, []string{"ShopBookTags.ShopTags"})
+		assert.ErrorIsKind(t, errors.NotSupported, err)
+	})
+	t.Run("SkipRelations", func(t *testing.T) {
+		// no query expected
+		ctx := dml.WithContextQueryOptions(ctx, dml.QueryOptions{SkipRelations: true})
+		assert.NoError(t, authors.Preload(ctx, dbm, []string{"ShopBooks"}))
+		assert.Exactly(t, 2, a1.Relations.ShopBooks.Len())
+	})
+}
+
+func TestManual_OptimisticLocking(t *testing.T) {
+	dbm, mock, closeFn := newMockDBM(t)
+	defer closeFn()
+	ctx := context.Background()
+
+	const updateSQL = "UPDATE `shop_book` SET `author_id`=?, `title`=?, `status`=?, `price`=?, `version`=? + 1, `created_at`=?, `updated_at`=? WHERE (`book_id` = ?) AND (`version` = ?)"
+
+	b := &ShopBook{BookID: 10, AuthorID: 1, Title: "Go", Status: "draft", Price: null.MakeDecimalInt64(1999, 2), Version: 3}
+
+	// SET binds the old version which gets incremented by the query, WHERE
+	// binds the primary key and the old version.
+	t.Run("success increments version", func(t *testing.T) {
+		mock.ExpectExec(dmltest.SQLMockQuoteMeta(updateSQL)).
+			WithArgs(1, "Go", "draft", "19.99", 3, sqlmock.AnyArg(), sqlmock.AnyArg(), 10, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		_, err := b.Update(ctx, dbm)
+		assert.NoError(t, err)
+		assert.Exactly(t, uint32(4), b.Version)
+		assert.True(t, b.UpdatedAt.Valid, "UpdatedAt must be set")
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		mock.ExpectExec(dmltest.SQLMockQuoteMeta(updateSQL)).
+			WithArgs(1, "Go", "draft", "19.99", 4, sqlmock.AnyArg(), sqlmock.AnyArg(), 10, 4).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		_, err := b.Update(ctx, dbm)
+		assert.True(t, dml.IsConflictError(err), "%+v", err)
+		assert.Exactly(t, uint32(4), b.Version)
+	})
+
+	t.Run("SkipVersion and SkipTimestamps", func(t *testing.T) {
+		b.UpdatedAt = null.Time{}
+		mock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `shop_book` SET `author_id`=?, `title`=?, `status`=?, `price`=?, `version`=?, `created_at`=?, `updated_at`=? WHERE (`book_id` = ?)")).
+			WithArgs(1, "Go", "draft", "19.99", 4, sqlmock.AnyArg(), nil, 10).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		ctx := dml.WithContextQueryOptions(ctx, dml.QueryOptions{SkipVersion: true, SkipTimestamps: true})
+		_, err := b.Update(ctx, dbm)
+		assert.NoError(t, err)
+		assert.Exactly(t, uint32(4), b.Version)
+		assert.False(t, b.UpdatedAt.Valid, "UpdatedAt must not be set")
+	})
+}
+
+func TestManual_SoftDelete(t *testing.T) {
+	dbm, mock, closeFn := newMockDBM(t)
+	defer closeFn()
+	ctx := context.Background()
+
+	a := &ShopAuthor{AuthorID: 5}
+
+	mock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `shop_author` SET `deleted_at`=NOW() WHERE (`author_id` IN ?) AND (`deleted_at` IS NULL)")).
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err := a.Delete(ctx, dbm)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `author_id`, `name`, `email`, `deleted_at`, `created_at`, `updated_at` FROM `shop_author` AS `main_table` WHERE (`author_id` = ?) AND (`deleted_at` IS NULL)")).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"author_id", "name"}))
+	assert.NoError(t, new(ShopAuthor).Load(ctx, dbm, 5))
+
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `author_id`, `name`, `email`, `deleted_at`, `created_at`, `updated_at` FROM `shop_author` AS `main_table` WHERE (`author_id` = ?) AND (`deleted_at` IS NOT NULL)")).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"author_id", "name", "deleted_at"}).AddRow(5, "Gopher", "2021-03-04 05:06:07"))
+	a2 := new(ShopAuthor)
+	assert.NoError(t, a2.Load(dml.WithContextQueryOptions(ctx, dml.QueryOptions{OnlyDeleted: true}), dbm, 5))
+	assert.Exactly(t, "Gopher", a2.Name)
+	assert.True(t, a2.DeletedAt.Valid, "DeletedAt must be set")
+
+	mock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `author_id`, `name`, `email`, `deleted_at`, `created_at`, `updated_at` FROM `shop_author` AS `main_table` WHERE (`author_id` = ?)")).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"author_id", "name"}).AddRow(5, "Gopher"))
+	assert.NoError(t, new(ShopAuthor).Load(dml.WithContextQueryOptions(ctx, dml.QueryOptions{WithDeleted: true}), dbm, 5))
+
+	mock.ExpectExec(dmltest.SQLMockQuoteMeta("UPDATE `shop_author` SET `deleted_at`=NULL WHERE (`author_id` IN ?)")).
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = a2.Restore(ctx, dbm)
+	assert.NoError(t, err)
+	assert.False(t, a2.DeletedAt.Valid, "DeletedAt must be reset")
+}
+
+func TestManual_Validate(t *testing.T) {
+	b := &ShopBook{Title: "Go", Status: "draft", Price: null.MakeDecimalInt64(1999, 2)}
+	assert.NoError(t, b.Validate())
+
+	b.Status = "archived"
+	b.Price = null.MakeDecimalInt64(12345678901, 2)
+	b.Title = ""
+	err := b.Validate()
+	assert.ErrorIsKind(t, errors.NotValid, err)
+
+	var fes dml.FieldErrors
+	assert.True(t, errors.As(err, &fes), "%+v", err)
+	var rules []string
+	for _, fe := range fes {
+		rules = append(rules, fe.Column+":"+fe.Rule)
+	}
+	assert.Exactly(t, []string{"title:required", "status:enum", "price:precision"}, rules)
+
+	assert.ErrorIsKind(t, errors.NotValid, NewShopBooks().Append(b).Validate())
+}
+
+func TestManual_Columns(t *testing.T) {
+	sqlStr, args, err := dml.NewSelect("*").From(TableNameShopBook).Where(
+		ShopBookColumns.AuthorID.In(1, 2),
+		ShopBookColumns.Status.Equal("published"),
+		ShopBookColumns.Price.PlaceHolder(),
+	).ToSQL()
+	assert.NoError(t, err)
+	assert.Exactly(t, "SELECT * FROM `shop_book` WHERE (`author_id` IN (1,2)) AND (`status` = 'published') AND (`price` = ?)", sqlStr)
+	assert.Nil(t, args)
+}
+
+func TestManual_Fixtures(t *testing.T) {
+	dbm, mock, closeFn := newMockDBM(t)
+	defer closeFn()
+	ctx := context.Background()
+
+	mock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `shop_author` (`name`,`email`,`deleted_at`,`created_at`,`updated_at`) VALUES (?,?,?,?,?)")).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `shop_book` (`author_id`,`title`,`status`,`price`,`version`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?)")).
+		WillReturnResult(sqlmock.NewResult(70, 1))
+
+	f := NewFixtures(dbm, pseudo.MustNewService(1, &pseudo.Options{Lang: "en"}))
+	b, err := f.ShopBook().With(func(b *ShopBook) { b.Status = "published" }).Create(ctx)
+	assert.NoError(t, err)
+	assert.Exactly(t, uint32(7), b.AuthorID)
+	assert.Exactly(t, uint32(70), b.BookID)
+	assert.Exactly(t, "published", b.Status)
+	assert.NoError(t, b.Validate())
+
+	// reverse order of creation
+	mock.ExpectExec(dmltest.SQLMockQuoteMeta("DELETE FROM `shop_book` WHERE (`book_id` = ?)")).
+		WithArgs(70).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(dmltest.SQLMockQuoteMeta("DELETE FROM `shop_author` WHERE (`author_id` = ?)")).
+		WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, f.Cleanup(ctx))
+}
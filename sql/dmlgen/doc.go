@@ -12,7 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package dmlgen generates Go code and protocol buffer files from database tables.
+// Package dmlgen generates Go code and protocol buffer or FlatBuffers files
+// from database tables.
 //
 // TODO check for https://github.com/improbable-eng/ts-protoc-gen and https://github.com/improbable-eng/grpc-web
 //
@@ -0,0 +1,309 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlgen
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/codegen"
+	"github.com/corestoreio/pkg/util/strs"
+)
+
+const importPathFlatbuffers = "github.com/google/flatbuffers/go"
+
+// fbsColumns returns the columns which are part of the FlatBuffers table. The
+// index of a column in the returned slice is its field id in the schema.
+func (t *Table) fbsColumns() ddl.Columns {
+	cols := make(ddl.Columns, 0, len(t.Table.Columns))
+	t.Table.Columns.Each(func(c *ddl.Column) {
+		if t.IsFieldPublic(c.Field) {
+			cols = append(cols, c)
+		}
+	})
+	return cols
+}
+
+// fbsVTableOffset returns the vtable offset of the field id, the same
+// calculation as flatc uses.
+func fbsVTableOffset(fieldID int) string {
+	return strconv.Itoa(4 + 2*fieldID)
+}
+
+// generateFlatbuffers writes the .fbs schema of all tables. Relationships are
+// not yet part of the schema.
+func (g *Generator) generateFlatbuffers(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteString("// Auto generated via github.com/corestoreio/pkg/sql/dmlgen. DO NOT EDIT.\n\n")
+	buf.WriteString("include \"github.com/corestoreio/pkg/storage/null/null.fbs\";\n\n")
+	ns := g.PackageSerializer
+	if ns == "" {
+		ns = g.Package
+	}
+	buf.WriteString("namespace " + strings.TrimPrefix(ns, "./") + ";\n")
+	for _, o := range g.SerializerHeaderOptions {
+		buf.WriteString(o + ";\n")
+	}
+
+	for _, tblname := range g.sortedTableNames() {
+		t := g.Tables[tblname] // must panic if table name not found
+
+		buf.WriteByte('\n')
+		fbsComment(&buf, t.EntityName(), `represents a single row for`, t.Table.Name, `DB table. Auto generated.`)
+		if t.Table.TableComment != "" {
+			fbsComment(&buf, "Table comment:", t.Table.TableComment)
+		}
+		buf.WriteString("table " + t.EntityName() + " {\n")
+		for _, c := range t.fbsColumns() {
+			buf.WriteString("  " + strs.ToGoCamelCase(c.Field) + ":" + g.serializerType(c) + ";")
+			if c.Comment != "" {
+				buf.WriteString(" // " + c.Comment)
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString("}\n\n")
+
+		fbsComment(&buf, t.CollectionName(), `represents multiple rows for the`, t.Table.Name, `DB table. Auto generated.`)
+		buf.WriteString("table " + t.CollectionName() + " {\n  Data:[" + t.EntityName() + "];\n}\n")
+	}
+	_, err := w.Write(buf.Bytes())
+	return errors.WithStack(err)
+}
+
+func fbsComment(buf *bytes.Buffer, comments ...string) {
+	for _, c := range strings.Split(strs.WordWrap(strings.Join(comments, " "), 78), "\n") {
+		buf.WriteString("// " + c + "\n")
+	}
+}
+
+func (g *Generator) hasFlatbuffers(t *Table) bool {
+	return g.Serializer == "fbs" && t.HasSerializer
+}
+
+// fbsScalarFn returns the name suffix of the flatbuffers.Builder Prepend and
+// flatbuffers.Table Get functions, e.g. Int16 or Float64, and whether the Go
+// type is a scalar.
+func fbsScalarFn(goType string) (string, bool) {
+	t := strings.TrimPrefix(goType, "null.")
+	switch t {
+	case "int64", "uint64", "int32", "uint32", "int16", "uint16", "int8", "uint8", "float64", "bool":
+		return strings.ToUpper(t[:1]) + t[1:], true
+	case "Int64", "Uint64", "Int32", "Uint32", "Int16", "Uint16", "Int8", "Uint8", "Float64", "Bool":
+		return t, true
+	}
+	return "", false
+}
+
+func (t *Table) fnEntityFlatbuffers(mainGen *codegen.Go, g *Generator) {
+	if !g.hasFlatbuffers(t) || !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureEntityStruct) {
+		return
+	}
+	cols := t.fbsColumns()
+
+	mainGen.C(`MarshalFBS writes the entity as FlatBuffers table`, t.EntityName(), `into the builder and returns its offset.`)
+	mainGen.Pln(`func (e *`, t.EntityName(), `) MarshalFBS(b *flatbuffers.Builder) flatbuffers.UOffsetT {`)
+	mainGen.In()
+	// offsets of strings, vectors and tables must be created before the object starts.
+	for i, c := range cols {
+		f := `e.` + strs.ToGoCamelCase(c.Field)
+		o := `o` + strconv.Itoa(i)
+		switch gt := g.goTypeNull(c); gt {
+		case "string":
+			mainGen.Pln(o, `:= b.CreateString(`, f, `)`)
+		case "null.String":
+			mainGen.Pln(`var `, o, ` flatbuffers.UOffsetT`)
+			mainGen.Pln(`if `, f, `.Valid {`, o, `= b.CreateString(`, f, `.Data) }`)
+		case "[]byte":
+			mainGen.Pln(`var `, o, ` flatbuffers.UOffsetT`)
+			mainGen.Pln(`if `, f, ` != nil {`, o, `= b.CreateByteVector(`, f, `) }`)
+		case "null.Decimal":
+			mainGen.Pln(o, `:= `, f, `.CreateFBS(b)`)
+		}
+	}
+	mainGen.Pln(`b.StartObject(`, len(cols), `)`)
+	for i, c := range cols {
+		f := `e.` + strs.ToGoCamelCase(c.Field)
+		slot := strconv.Itoa(i)
+		switch gt := g.goTypeNull(c); gt {
+		case "string", "null.String", "[]byte", "null.Decimal":
+			mainGen.Pln(`b.PrependUOffsetTSlot(`, slot, `, o`+slot, `, 0)`)
+		case "time.Time":
+			mainGen.Pln(`null.MakeTime(`, f, `).PrependFBSSlot(b, `, slot, `)`)
+		case "null.Time":
+			mainGen.Pln(f, `.PrependFBSSlot(b, `, slot, `)`)
+		default:
+			fn, ok := fbsScalarFn(gt)
+			if !ok {
+				panic(errors.NotSupported.Newf("[dmlgen] Go type %q of column %q not supported by FlatBuffers", gt, c.Field))
+			}
+			if strings.HasPrefix(gt, "null.") {
+				mainGen.Pln(`if `, f, `.Valid {`)
+				mainGen.Pln(`b.Prepend`+fn, `(`, f+`.`+fn, `)`)
+				mainGen.Pln(`b.Slot(`, slot, `)`)
+				mainGen.Pln(`}`)
+			} else {
+				zero := `0`
+				if gt == "bool" {
+					zero = `false`
+				}
+				mainGen.Pln(`b.Prepend`+fn+`Slot(`, slot, `,`, f, `,`, zero, `)`)
+			}
+		}
+	}
+	mainGen.Pln(`return b.EndObject()`)
+	mainGen.Out()
+	mainGen.Pln(`}`)
+
+	mainGen.C(`UnmarshalFBSTable sets the fields of the entity from the FlatBuffers table`, t.EntityName()+`.`)
+	mainGen.Pln(`func (e *`, t.EntityName(), `) UnmarshalFBSTable(tab *flatbuffers.Table) {`)
+	mainGen.In()
+	for i, c := range cols {
+		f := `e.` + strs.ToGoCamelCase(c.Field)
+		vt := fbsVTableOffset(i)
+		switch gt := g.goTypeNull(c); gt {
+		case "string", "null.String", "[]byte":
+			var zero, val string
+			switch gt {
+			case "string":
+				zero, val = `""`, `string(tab.ByteVector(tab.Pos + o))`
+			case "null.String":
+				zero, val = `null.String{}`, `null.MakeString(string(tab.ByteVector(tab.Pos + o)))`
+			default:
+				zero, val = `nil`, `append([]byte{}, tab.ByteVector(tab.Pos + o)...)`
+			}
+			mainGen.Pln(f, `=`, zero)
+			mainGen.Pln(`if o := flatbuffers.UOffsetT(tab.Offset(`, vt, `)); o != 0 {`, f, `=`, val, `}`)
+		case "null.Decimal":
+			mainGen.Pln(f, `= null.FBSDecimal(tab, `, vt, `)`)
+		case "time.Time":
+			mainGen.Pln(f, `= null.FBSTime(tab, `, vt, `).Time`)
+		case "null.Time":
+			mainGen.Pln(f, `= null.FBSTime(tab, `, vt, `)`)
+		default:
+			fn, _ := fbsScalarFn(gt)
+			if strings.HasPrefix(gt, "null.") {
+				mainGen.Pln(f, `= `, gt, `{}`)
+				mainGen.Pln(`if o := flatbuffers.UOffsetT(tab.Offset(`, vt, `)); o != 0 {`, f, `= null.Make`+fn, `(tab.Get`+fn, `(tab.Pos + o)) }`)
+			} else {
+				zero := `0`
+				if gt == "bool" {
+					zero = `false`
+				}
+				mainGen.Pln(f, `= tab.Get`+fn+`Slot(`, vt, `,`, zero, `)`)
+			}
+		}
+	}
+	mainGen.Out()
+	mainGen.Pln(`}`)
+
+	t.fnFlatbuffersFinished(mainGen, t.EntityName(), `e`)
+}
+
+func (t *Table) fnCollectionFlatbuffers(mainGen *codegen.Go, g *Generator) {
+	if !g.hasFlatbuffers(t) ||
+		!g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureEntityStruct) ||
+		!g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureCollectionStruct) {
+		return
+	}
+
+	mainGen.C(`MarshalFBS writes the collection as FlatBuffers table`, t.CollectionName(), `into the builder and returns its offset.`)
+	mainGen.Pln(`func (cc *`, t.CollectionName(), `) MarshalFBS(b *flatbuffers.Builder) flatbuffers.UOffsetT {
+	offsets := make([]flatbuffers.UOffsetT, len(cc.Data))
+	for i, e := range cc.Data {
+		offsets[i] = e.MarshalFBS(b)
+	}
+	b.StartVector(flatbuffers.SizeUOffsetT, len(offsets), flatbuffers.SizeUOffsetT)
+	for i := len(offsets) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(offsets[i])
+	}
+	data := b.EndVector(len(offsets))
+	b.StartObject(1)
+	b.PrependUOffsetTSlot(0, data, 0)
+	return b.EndObject()
+}`)
+
+	mainGen.C(`UnmarshalFBSTable sets the entities of the collection from the FlatBuffers table`, t.CollectionName()+`.`)
+	mainGen.Pln(`func (cc *`, t.CollectionName(), `) UnmarshalFBSTable(tab *flatbuffers.Table) {
+	cc.Data = cc.Data[:0]
+	o := flatbuffers.UOffsetT(tab.Offset(4))
+	if o == 0 {
+		return
+	}
+	x := tab.Vector(o)
+	for i, n := 0, tab.VectorLen(o); i < n; i++ {
+		et := flatbuffers.Table{Bytes: tab.Bytes, Pos: tab.Indirect(x + flatbuffers.UOffsetT(i)*flatbuffers.SizeUOffsetT)}
+		e := new(`, t.EntityName(), `)
+		e.UnmarshalFBSTable(&et)
+		cc.Data = append(cc.Data, e)
+	}
+}`)
+
+	t.fnFlatbuffersFinished(mainGen, t.CollectionName(), `cc`)
+}
+
+// fnFlatbuffersFinished writes the methods which operate on a finished
+// FlatBuffers byte slice, with the type as root table.
+func (t *Table) fnFlatbuffersFinished(mainGen *codegen.Go, typeName, recv string) {
+	mainGen.C(`MarshalFlatbuffers returns the FlatBuffers encoding with`, typeName, `as root table.`)
+	mainGen.Pln(`func (`, recv, ` *`, typeName, `) MarshalFlatbuffers() []byte {
+	b := flatbuffers.NewBuilder(0)
+	b.Finish(`, recv, `.MarshalFBS(b))
+	return b.FinishedBytes()
+}`)
+
+	mainGen.C(`UnmarshalFlatbuffers decodes the FlatBuffers data with`, typeName, `as root table. Data is not getting verified, so a panic due to corrupt data gets returned as error.`)
+	mainGen.Pln(`func (`, recv, ` *`, typeName, `) UnmarshalFlatbuffers(data []byte) (err error) {
+	if len(data) < flatbuffers.SizeUOffsetT {
+		return errors.CorruptData.Newf("[`+t.Package+`] `+typeName+`.UnmarshalFlatbuffers: data too short")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.CorruptData.Newf("[`+t.Package+`] `+typeName+`.UnmarshalFlatbuffers: %v", r)
+		}
+	}()
+	`, recv, `.UnmarshalFBSTable(&flatbuffers.Table{Bytes: data, Pos: flatbuffers.GetUOffsetT(data)})
+	return nil
+}`)
+}
+
+func (t *Table) generateTestFlatbuffers(testGen *codegen.Go, g *Generator) (codeWritten int) {
+	if !g.hasFlatbuffers(t) || !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureEntityStruct) {
+		return 0
+	}
+	testGen.Pln(`t.Run("` + t.EntityName() + `_Flatbuffers", func(t *testing.T) {`)
+	{
+		testGen.Pln(`e := new(`, t.EntityName(), `)`)
+		testGen.Pln(`assert.NoError(t, ps.FakeData(e))`)
+		testGen.Pln(`data := e.MarshalFlatbuffers()`)
+		testGen.Pln(`e2 := new(`, t.EntityName(), `)`)
+		testGen.Pln(`assert.NoError(t, e2.UnmarshalFlatbuffers(data))`)
+		testGen.Pln(`assert.Exactly(t, data, e2.MarshalFlatbuffers())`)
+		if g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureCollectionStruct) {
+			testGen.Pln(`c := `, t.CollectionName(), `{Data: []*`, t.EntityName(), `{e, e2}}`)
+			testGen.Pln(`data = c.MarshalFlatbuffers()`)
+			testGen.Pln(`var c2 `, t.CollectionName())
+			testGen.Pln(`assert.NoError(t, c2.UnmarshalFlatbuffers(data))`)
+			testGen.Pln(`assert.Len(t, c2.Data, 2)`)
+			testGen.Pln(`assert.Exactly(t, data, c2.MarshalFlatbuffers())`)
+		}
+		testGen.Pln(`assert.True(t, errors.CorruptData.Match(e2.UnmarshalFlatbuffers([]byte{1})))`)
+	}
+	testGen.Pln(`})`) // end t.Run
+	return 1
+}
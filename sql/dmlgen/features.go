@@ -1,6 +1,10 @@
 package dmlgen
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/corestoreio/errors"
+)
 
 // FeatureToggle allows certain generated code blocks to be switched off or on.
 type FeatureToggle uint64
@@ -29,19 +33,23 @@ const (
 	FeatureDBUpdate
 	FeatureDBUpsert
 	FeatureDBTableColumnNames
+	FeatureDBColumns // typed column descriptors, <Entity>Columns
 	FeatureEntityCopy
 	FeatureEntityEmpty
 	FeatureEntityGetSetPrivateFields
 	FeatureEntityIsSet
 	FeatureEntityRelationships
-	FeatureEntityStruct // creates the struct type
-	FeatureEntityValidate
+	FeatureEntityStruct   // creates the struct type
+	FeatureEntityValidate // derives the validation from the column constraints
 	FeatureEntityWriteTo
+	FeatureGRPCService // gRPC CRUD service, requires WithGRPCService
+	FeatureFixtures    // test fixture factories, see GenerateFixtures
 	featureMax
 )
 
 var featureNames = map[FeatureToggle]string{
 	FeatureCollectionAppend:            "FeatureCollectionAppend",
+	FeatureCollectionClear:             "FeatureCollectionClear",
 	FeatureCollectionCut:               "FeatureCollectionCut",
 	FeatureCollectionDelete:            "FeatureCollectionDelete",
 	FeatureCollectionEach:              "FeatureCollectionEach",
@@ -61,6 +69,8 @@ var featureNames = map[FeatureToggle]string{
 	FeatureDBTracing:                   "FeatureDBTracing",
 	FeatureDBUpdate:                    "FeatureDBUpdate",
 	FeatureDBUpsert:                    "FeatureDBUpsert",
+	FeatureDBTableColumnNames:          "FeatureDBTableColumnNames",
+	FeatureDBColumns:                   "FeatureDBColumns",
 	FeatureEntityCopy:                  "FeatureEntityCopy",
 	FeatureEntityEmpty:                 "FeatureEntityEmpty",
 	FeatureEntityGetSetPrivateFields:   "FeatureEntityGetSetPrivateFields",
@@ -69,6 +79,8 @@ var featureNames = map[FeatureToggle]string{
 	FeatureEntityStruct:                "FeatureEntityStruct",
 	FeatureEntityValidate:              "FeatureEntityValidate",
 	FeatureEntityWriteTo:               "FeatureEntityWriteTo",
+	FeatureFixtures:                    "FeatureFixtures",
+	FeatureGRPCService:                 "FeatureGRPCService",
 }
 
 func (f FeatureToggle) String() string {
@@ -86,6 +98,29 @@ func (f FeatureToggle) String() string {
 	return buf.String()
 }
 
+// ParseFeatureToggle converts the feature names into a FeatureToggle. The
+// prefix "Feature" of a name is optional, e.g. "FeatureDB" or "DB".
+func ParseFeatureToggle(names ...string) (FeatureToggle, error) {
+	var ft FeatureToggle
+	for _, name := range names {
+		if !strings.HasPrefix(name, "Feature") {
+			name = "Feature" + name
+		}
+		found := false
+		for f, fName := range featureNames {
+			if fName == name {
+				ft |= f
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, errors.NotFound.Newf("[dmlgen] ParseFeatureToggle: Feature %q not found", name)
+		}
+	}
+	return ft, nil
+}
+
 func hasFeature(include, exclude, features FeatureToggle, mode ...rune) int {
 	featureFlagCount := 0
 	tableIncludeFlagCount := 0
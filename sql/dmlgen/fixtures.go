@@ -0,0 +1,401 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlgen
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/codegen"
+)
+
+// fixtureParent describes a foreign key constraint of a table whose parent
+// entity gets created before the entity itself. A composite foreign key
+// contains several columns.
+type fixtureParent struct {
+	constraint    string
+	parent        *Table
+	columns       []*ddl.Column
+	parentColumns []*ddl.Column
+}
+
+func (g *Generator) hasFixtures(t *Table) bool {
+	return !t.Table.IsView() && t.Table.Columns.PrimaryKeys().Len() > 0 &&
+		t.hasFeature(g, FeatureFixtures|FeatureEntityStruct|FeatureDBInsert)
+}
+
+// fixtureParents returns the NOT NULL foreign key constraints of a table which
+// reference another table with fixtures. Self references and constraints with
+// nullable columns get skipped, the former would create an endless recursion.
+func (t *Table) fixtureParents(g *Generator) []fixtureParent {
+	kcuc, ok := g.kcu[t.Table.Name]
+	if !ok {
+		return nil
+	}
+	var parents []fixtureParent
+	constraintIdx := map[string]int{}
+	skipped := map[string]bool{}
+	for _, kcu := range kcuc.Data {
+		if !kcu.ReferencedTableName.Valid || kcu.ReferencedTableName.Data == t.Table.Name {
+			continue
+		}
+		name := kcu.ConstraintName
+		if name == "" {
+			name = kcu.ColumnName
+		}
+		if skipped[name] {
+			continue
+		}
+		pt, ok := g.Tables[kcu.ReferencedTableName.Data]
+		if !ok || !g.hasFixtures(pt) {
+			skipped[name] = true
+			continue
+		}
+		c := t.Table.Columns.ByField(kcu.ColumnName)
+		pc := pt.Table.Columns.ByField(kcu.ReferencedColumnName.Data)
+		if c.Field == "" || pc.Field == "" || c.IsNull() {
+			skipped[name] = true
+			continue
+		}
+		if _, ok := fixtureConvertible(g.goTypeNull(c), g.goTypeNull(pc)); !ok {
+			skipped[name] = true
+			continue
+		}
+		idx, ok := constraintIdx[name]
+		if !ok {
+			idx = len(parents)
+			constraintIdx[name] = idx
+			parents = append(parents, fixtureParent{constraint: name, parent: pt})
+		}
+		parents[idx].columns = append(parents[idx].columns, c)
+		parents[idx].parentColumns = append(parents[idx].parentColumns, pc)
+	}
+	// a constraint gets dropped completely once one of its columns has been
+	// skipped, otherwise the parent would only be partially referenced.
+	filtered := parents[:0]
+	for _, p := range parents {
+		if !skipped[p.constraint] {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// fixtureConvertible returns the Go conversion from the type of a parent
+// column into the type of the child column.
+func fixtureConvertible(child, parent string) (conversion string, ok bool) {
+	if child == parent {
+		return "", true
+	}
+	isNumber := func(s string) bool {
+		return strings.Contains(s, "int") && !strings.Contains(s, ".")
+	}
+	if isNumber(child) && isNumber(parent) {
+		return child, true
+	}
+	return "", false
+}
+
+// fixtureZero returns the condition if a foreign key column has not been set.
+func fixtureZero(goType, field string) string {
+	switch goType {
+	case "string":
+		return field + ` == ""`
+	case "[]byte":
+		return field + ` == nil`
+	case "time.Time":
+		return field + `.IsZero()`
+	case "null.Decimal":
+		return `!` + field + `.Valid`
+	}
+	return field + ` == 0`
+}
+
+// fixtureValue returns the Go expression which generates a fake value for a
+// column. An empty string leaves the zero value.
+func (t *Table) fixtureValue(g *Generator, c *ddl.Column) string {
+	if c.IsNull() || t.isValidationManaged(c) || c == t.softDeleteColumn {
+		return "" // NULL, database or generated code assigns the value
+	}
+	unique := c.IsPK() || c.IsUnique()
+	maxLen := int64(0)
+	if c.IsChar() {
+		maxLen = c.CharMaxLength.Int64
+	}
+
+	switch goType := g.goTypeNull(c); goType {
+	case "string":
+		if values := c.EnumValues(); len(values) > 0 {
+			quoted := make([]string, len(values))
+			for i, v := range values {
+				quoted[i] = strconv.Quote(v)
+			}
+			return fmt.Sprintf(`[...]string{%s}[f.ps.Intn(%d)]`, strings.Join(quoted, ", "), len(values))
+		}
+		return fmt.Sprintf(`f.fakeString(%q, %d, %t)`, c.Field, maxLen, unique)
+	case "[]byte":
+		return fmt.Sprintf(`[]byte(f.fakeString(%q, %d, %t))`, c.Field, maxLen, unique)
+	case "bool":
+		return `f.ps.Intn(2) == 1`
+	case "float64":
+		return `float64(f.ps.Intn(100000)) / 100`
+	case "time.Time":
+		if c.DataType == "date" {
+			return `f.fakeTime().Truncate(24 * time.Hour)`
+		}
+		return `f.fakeTime()`
+	case "null.Decimal":
+		precision, scale := int64(10), int64(2)
+		if c.Precision.Valid && c.Precision.Int64 > 0 {
+			precision, scale = c.Precision.Int64, c.Scale.Int64
+		}
+		intDigits := precision - scale
+		if intDigits > 6 {
+			intDigits = 6
+		}
+		if scale > 4 {
+			scale = 4
+		}
+		return fmt.Sprintf(`null.MakeDecimalInt64(int64(f.ps.Intn(%d)), %d)`, int64(math.Pow10(int(intDigits+scale))), scale)
+	default:
+		if !strings.Contains(goType, "int") || strings.Contains(goType, ".") {
+			return ""
+		}
+		max := int64(math.MaxInt32)
+		if r, ok := integerRanges[c.DataType]; ok {
+			idx := 0
+			if c.IsUnsigned() {
+				idx = 1
+			}
+			if m, ok := r[idx][1].(int64); ok && m < max {
+				max = m
+			}
+		}
+		if unique {
+			return fmt.Sprintf(`%s(f.unique(%d))`, goType, max)
+		}
+		if max > 1000 {
+			max = 1000
+		}
+		return fmt.Sprintf(`%s(f.ps.Intn(%d))`, goType, max)
+	}
+}
+
+func (g *Generator) fnFixtures(mainGen *codegen.Go) {
+	mainGen.C(`Fixtures creates entities with fake data for integration tests and deletes them via Cleanup. Foreign keys get satisfied by creating the parent entities first. Not thread safe. Auto generated.`)
+	mainGen.Pln(`type Fixtures struct {
+	dbm      *DBM
+	ps       *pseudo.Service
+	seq      uint64
+	cleanups []func(context.Context) error
+}`)
+	mainGen.C(`NewFixtures creates a new fixture factory. If ps is nil, a pseudo service with a random seed gets created.`)
+	mainGen.Pln(`func NewFixtures(dbm *DBM, ps *pseudo.Service) *Fixtures {
+	if ps == nil {
+		ps = pseudo.MustNewService(uint64(time.Now().UnixNano()), &pseudo.Options{Lang: "en"})
+	}
+	return &Fixtures{dbm: dbm, ps: ps, seq: uint64(ps.Intn(1 << 20))}
+}`)
+	mainGen.C(`Cleanup deletes all created entities in the reverse order of their creation.`)
+	mainGen.Pln(`func (f *Fixtures) Cleanup(ctx context.Context) error {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		if err := f.cleanups[i](ctx); err != nil {
+			return errors.WithStack(err)
+		}
+		f.cleanups = f.cleanups[:i]
+	}
+	return nil
+}`)
+	mainGen.C(`unique returns a number between 1 and max which differs from the previous call until max has been reached.`)
+	mainGen.Pln(`func (f *Fixtures) unique(max uint64) uint64 {
+	f.seq++
+	return f.seq%max + 1
+}`)
+	mainGen.C(`fakeString generates a value via the pseudo tag of the column name or falls back to words. Unique values get a prefix.`)
+	mainGen.Pln(`func (f *Fixtures) fakeString(column string, maxLen int, unique bool) string {
+	fakeLen := maxLen
+	if fakeLen == 0 || fakeLen > 255 {
+		fakeLen = 255
+	}
+	var s string
+	if v, ok := f.ps.FakeByTag(column, fakeLen); ok {
+		s = fmt.Sprint(v)
+	}
+	if s == "" {
+		s = f.ps.Words(fakeLen)
+	}
+	if unique {
+		s = strconv.FormatUint(f.unique(math.MaxUint32), 36) + "_" + s
+	}
+	if maxLen > 0 && utf8.RuneCountInString(s) > maxLen {
+		s = string([]rune(s)[:maxLen])
+	}
+	return s
+}`)
+	mainGen.C(`fakeTime returns a time between the years 2000 and 2020 which fits into all date and time columns.`)
+	mainGen.Pln(`func (f *Fixtures) fakeTime() time.Time {
+	return time.Unix(946684800+int64(f.ps.Intn(631152000)), 0).UTC()
+}`)
+}
+
+func (t *Table) fnFixtureFactory(mainGen *codegen.Go, g *Generator) {
+	entity := t.EntityName()
+	factory := entity + "Factory"
+	parents := t.fixtureParents(g)
+	isParentColumn := make(map[*ddl.Column]bool, len(parents))
+	for _, p := range parents {
+		for _, c := range p.columns {
+			isParentColumn[c] = true
+		}
+	}
+
+	mainGen.C(factory, `builds and creates fixtures of`, entity+`. Auto generated.`)
+	mainGen.Pln(`type `, factory, ` struct {
+	f    *Fixtures
+	mods []func(*`, entity, `)
+}`)
+
+	mainGen.C(entity, `returns a new factory for the DB table`, t.Table.Name+`.`)
+	mainGen.Pln(`func (f *Fixtures) `, entity, `() *`, factory, ` {
+	return &`, factory, `{f: f}
+}`)
+
+	mainGen.C(`With adds functions which modify the entity after the fake data has been generated. Setting a foreign key avoids the creation of the parent entity.`)
+	mainGen.Pln(`func (ff *`, factory, `) With(mods ...func(*`, entity, `)) *`, factory, ` {
+	ff.mods = append(ff.mods, mods...)
+	return ff
+}`)
+
+	mainGen.C(`Build creates a new entity with fake data without writing to the database. Nullable columns and foreign keys are not set.`)
+	mainGen.Pln(`func (ff *`, factory, `) Build() *`, entity, ` {`)
+	mainGen.In()
+	var fields []string
+	for _, c := range t.Table.Columns {
+		if v := t.fixtureValue(g, c); v != "" && !isParentColumn[c] {
+			fields = append(fields, t.GoCamelMaybePrivate(c.Field)+`: `+v+`,`)
+		}
+	}
+	mainGen.Pln(len(fields) > 0, `f := ff.f`)
+	mainGen.Pln(`e := &`, entity, `{`)
+	for _, f := range fields {
+		mainGen.Pln(f)
+	}
+	mainGen.Pln(`}`)
+	mainGen.Pln(`for _, fn := range ff.mods {
+		fn(e)
+	}
+	return e`)
+	mainGen.Out()
+	mainGen.Pln(`}`)
+
+	mainGen.C(`Create builds the entity, creates the missing parent entities and inserts it. Fixtures.Cleanup deletes the entity.`)
+	mainGen.Pln(`func (ff *`, factory, `) Create(ctx context.Context) (*`, entity, `, error) {`)
+	mainGen.In()
+	mainGen.Pln(`f := ff.f`)
+	mainGen.Pln(`e := ff.Build()`)
+	for _, p := range parents {
+		// the parent gets only created if none of the foreign key columns
+		// has been set.
+		var unset []string
+		for _, c := range p.columns {
+			unset = append(unset, fixtureZero(g.goTypeNull(c), `e.`+t.GoCamelMaybePrivate(c.Field)))
+		}
+		mainGen.Pln(`if `+strings.Join(unset, ` && `), ` {`)
+		mainGen.Pln(`	p, err := f.`, p.parent.EntityName(), `().Create(ctx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}`)
+		for i, c := range p.columns {
+			pc := p.parentColumns[i]
+			conv, _ := fixtureConvertible(g.goTypeNull(c), g.goTypeNull(pc))
+			parentValue := `p.` + p.parent.GoCamelMaybePrivate(pc.Field)
+			if conv != "" {
+				parentValue = conv + `(` + parentValue + `)`
+			}
+			mainGen.Pln(`	e.`+t.GoCamelMaybePrivate(c.Field), `=`, parentValue)
+		}
+		mainGen.Pln(`}`)
+	}
+	mainGen.Pln(`if _, err := e.Insert(ctx, f.dbm); err != nil {
+		return nil, errors.WithStack(err)
+	}`)
+
+	pkCols := t.Table.Columns.PrimaryKeys()
+	var where, args strings.Builder
+	pkCols.Each(func(c *ddl.Column) {
+		if where.Len() > 0 {
+			where.WriteString(", ")
+			args.WriteString(", ")
+		}
+		where.WriteString("dml.Column(`" + c.Field + "`).PlaceHolder()")
+		args.WriteString(`e.` + t.GoCamelMaybePrivate(c.Field))
+	})
+	mainGen.Pln(`f.cleanups = append(f.cleanups, func(ctx context.Context) error {
+		_, err := f.dbm.ConnPool.WithQueryBuilder(dml.NewDelete(`, constTableName(t.Table.Name), `).Where(`, where.String(), `)).ExecContext(ctx, `, args.String(), `)
+		return err
+	})`)
+	mainGen.Pln(`return e, nil`)
+	mainGen.Out()
+	mainGen.Pln(`}`)
+}
+
+// GenerateFixtures writes test fixture factories for all tables with the
+// features FeatureFixtures, FeatureEntityStruct and FeatureDBInsert into w.
+// The code belongs into the same package as the code of GenerateGo, usually
+// into a file with suffix _test.go. Integration tests can then write:
+//
+//	f := NewFixtures(dbm, nil)
+//	defer f.Cleanup(ctx)
+//	e, err := f.CustomerEntity().With(func(e *CustomerEntity) { e.Email = "a@b.c" }).Create(ctx)
+//
+// The fake data respects the column types, lengths, ENUM values and unique
+// keys. NOT NULL foreign keys get satisfied by creating the parent entity
+// first, which requires the option WithForeignKeyRelationships.
+func (g *Generator) GenerateFixtures(w io.Writer) error {
+	mainGen := codegen.NewGo(g.Package)
+	mainGen.SecondLineComments = []string{"Generated by sql/dmlgen. DO NOT EDIT."}
+	mainGen.BuildTags = g.BuildTags
+
+	g.fnFixtures(mainGen)
+	for _, tblname := range g.sortedTableNames() {
+		if t := g.Tables[tblname]; g.hasFixtures(t) {
+			t.fnFixtureFactory(mainGen, g)
+		}
+	}
+
+	pkgs, err := findUsedPackages(mainGen.Bytes(), []string{
+		"context",
+		"fmt",
+		"math",
+		"strconv",
+		"time",
+		"unicode/utf8",
+		"github.com/corestoreio/errors",
+		"github.com/corestoreio/pkg/sql/dml",
+		"github.com/corestoreio/pkg/storage/null",
+		"github.com/corestoreio/pkg/util/pseudo",
+	})
+	if err != nil {
+		_, _ = w.Write(mainGen.Bytes()) // write for debug reasons
+		return errors.WithStack(err)
+	}
+	mainGen.AddImports(pkgs...)
+	return errors.WithStack(mainGen.GenerateFile(w))
+}
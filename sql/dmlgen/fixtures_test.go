@@ -0,0 +1,122 @@
+package dmlgen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func newFixturesTestGenerator(t *testing.T) *Generator {
+	g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestfixtures",
+		WithTable("store_website", ddl.Columns{
+			&ddl.Column{Field: "website_id", Pos: 1, Null: "NO", DataType: "smallint", ColumnType: "smallint(5) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "code", Pos: 2, Null: "NO", DataType: "varchar", CharMaxLength: null.MakeInt64(32), ColumnType: "varchar(32)", Key: "UNI"},
+		}),
+		WithTable("customer_entity", ddl.Columns{
+			&ddl.Column{Field: "entity_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "website_id", Pos: 2, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "MUL"},
+			&ddl.Column{Field: "email", Pos: 3, Null: "NO", DataType: "varchar", CharMaxLength: null.MakeInt64(255), ColumnType: "varchar(255)"},
+			&ddl.Column{Field: "gender", Pos: 4, Null: "NO", DataType: "enum", ColumnType: "enum('f','m')"},
+			&ddl.Column{Field: "level", Pos: 5, Null: "NO", DataType: "tinyint", ColumnType: "tinyint(3) unsigned"},
+			&ddl.Column{Field: "grand_total", Pos: 6, Null: "NO", DataType: "decimal", Precision: null.MakeInt64(5), Scale: null.MakeInt64(2), ColumnType: "decimal(5,2)"},
+			&ddl.Column{Field: "dob", Pos: 7, Null: "NO", DataType: "date", ColumnType: "date"},
+			&ddl.Column{Field: "nickname", Pos: 8, Null: "YES", DataType: "varchar", CharMaxLength: null.MakeInt64(32), ColumnType: "varchar(32)"},
+			&ddl.Column{Field: "created_at", Pos: 9, Null: "NO", DataType: "timestamp", ColumnType: "timestamp"},
+		}),
+		WithTableConfig("customer_entity", &TableConfig{CreatedAtColumn: "created_at"}),
+	)
+	assert.NoError(t, err)
+	g.kcu = map[string]ddl.KeyColumnUsageCollection{
+		"customer_entity": {Data: []*ddl.KeyColumnUsage{{
+			TableName: "customer_entity", ColumnName: "website_id",
+			ReferencedTableName: null.MakeString("store_website"), ReferencedColumnName: null.MakeString("website_id"),
+		}}},
+	}
+	return g
+}
+
+func TestGenerator_GenerateFixtures(t *testing.T) {
+	g := newFixturesTestGenerator(t)
+	var buf bytes.Buffer
+	assert.NoError(t, g.GenerateFixtures(&buf))
+	code := buf.String()
+
+	assert.Contains(t, code, `"github.com/corestoreio/pkg/util/pseudo"`)
+	assert.Contains(t, code, "func NewFixtures(dbm *DBM, ps *pseudo.Service) *Fixtures {")
+	assert.Contains(t, code, "func (f *Fixtures) Cleanup(ctx context.Context) error {")
+	assert.Contains(t, code, "func (f *Fixtures) CustomerEntity() *CustomerEntityFactory {")
+	assert.Contains(t, code, "func (ff *CustomerEntityFactory) With(mods ...func(*CustomerEntity)) *CustomerEntityFactory {")
+	assert.Contains(t, code, "func (ff *StoreWebsiteFactory) Create(ctx context.Context) (*StoreWebsite, error) {")
+
+	// fake data
+	assert.Contains(t, code, `Code: f.fakeString("code", 32, true),`)
+	assert.Contains(t, code, `Email:      f.fakeString("email", 255, false),`)
+	assert.Contains(t, code, `Gender:     [...]string{"f", "m"}[f.ps.Intn(2)],`)
+	assert.Contains(t, code, `Level:      uint8(f.ps.Intn(255)),`)
+	assert.Contains(t, code, `GrandTotal: null.MakeDecimalInt64(int64(f.ps.Intn(100000)), 2),`)
+	assert.Contains(t, code, `Dob:        f.fakeTime().Truncate(24 * time.Hour),`)
+	assert.NotContains(t, code, `EntityID:`)
+	assert.NotContains(t, code, `Nickname:`)
+	assert.NotContains(t, code, `CreatedAt:`)
+
+	// parents
+	assert.Contains(t, code, "if e.WebsiteID == 0 {\n\t\tp, err := f.StoreWebsite().Create(ctx)\n\t\tif err != nil {\n\t\t\treturn nil, errors.WithStack(err)\n\t\t}\n\t\te.WebsiteID = uint32(p.WebsiteID)\n\t}")
+	assert.Contains(t, code, "dml.NewDelete(TableNameCustomerEntity).Where(dml.Column(`entity_id`).PlaceHolder())).ExecContext(ctx, e.EntityID)")
+}
+
+func TestGenerator_GenerateFixtures_FeatureExclude(t *testing.T) {
+	g := newFixturesTestGenerator(t)
+	assert.NoError(t, WithTableConfig("store_website", &TableConfig{FeaturesExclude: FeatureFixtures}).fn(g))
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.GenerateFixtures(&buf))
+	assert.NotContains(t, buf.String(), "StoreWebsiteFactory")
+	assert.NotContains(t, buf.String(), "if e.WebsiteID == 0 {")
+}
+
+func TestGenerator_GenerateFixtures_CompositeForeignKey(t *testing.T) {
+	g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestfixtures",
+		WithTable("catalog_product_website", ddl.Columns{
+			&ddl.Column{Field: "product_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI"},
+			&ddl.Column{Field: "website_id", Pos: 2, Null: "NO", DataType: "smallint", ColumnType: "smallint(5) unsigned", Key: "PRI"},
+		}),
+		WithTable("catalog_product_website_price", ddl.Columns{
+			&ddl.Column{Field: "price_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "product_id", Pos: 2, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "MUL"},
+			&ddl.Column{Field: "website_id", Pos: 3, Null: "NO", DataType: "smallint", ColumnType: "smallint(5) unsigned"},
+			&ddl.Column{Field: "parent_product_id", Pos: 4, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned"},
+			&ddl.Column{Field: "parent_website_id", Pos: 5, Null: "YES", DataType: "smallint", ColumnType: "smallint(5) unsigned"},
+		}),
+	)
+	assert.NoError(t, err)
+	fk := func(constraint, column, refColumn string) *ddl.KeyColumnUsage {
+		return &ddl.KeyColumnUsage{
+			ConstraintName: constraint, TableName: "catalog_product_website_price", ColumnName: column,
+			ReferencedTableName: null.MakeString("catalog_product_website"), ReferencedColumnName: null.MakeString(refColumn),
+		}
+	}
+	g.kcu = map[string]ddl.KeyColumnUsageCollection{
+		"catalog_product_website_price": {Data: []*ddl.KeyColumnUsage{
+			fk("FK_PRICE_PRODUCT_WEBSITE", "product_id", "product_id"),
+			fk("FK_PRICE_PRODUCT_WEBSITE", "website_id", "website_id"),
+			// nullable column, the whole constraint gets skipped
+			fk("FK_PRICE_PARENT", "parent_product_id", "product_id"),
+			fk("FK_PRICE_PARENT", "parent_website_id", "website_id"),
+			// unknown column
+			fk("FK_PRICE_UNKNOWN", "price_id", "not_a_column"),
+		}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.GenerateFixtures(&buf))
+	code := buf.String()
+
+	assert.Contains(t, code, "if e.ProductID == 0 && e.WebsiteID == 0 {\n\t\tp, err := f.CatalogProductWebsite().Create(ctx)\n\t\tif err != nil {\n\t\t\treturn nil, errors.WithStack(err)\n\t\t}\n\t\te.ProductID = p.ProductID\n\t\te.WebsiteID = p.WebsiteID\n\t}")
+	assert.Exactly(t, 1, strings.Count(code, "f.CatalogProductWebsite().Create(ctx)"))
+	assert.NotContains(t, code, "e.ParentProductID == 0")
+	assert.NotContains(t, code, "e.PriceID == 0")
+}
@@ -0,0 +1,479 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlgen
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/codegen"
+	"github.com/corestoreio/pkg/util/strs"
+)
+
+const importPathCSGRPCAuth = "github.com/corestoreio/pkg/net/csgrpc/auth"
+
+// hasGRPCService reports if a gRPC service gets generated for table t. Views
+// and tables without a primary key are not supported.
+func (g *Generator) hasGRPCService(t *Table) bool {
+	return g.grpcService != nil && !t.Table.IsView() &&
+		t.Table.Columns.PrimaryKeys().Len() > 0 &&
+		t.hasFeature(g, FeatureGRPCService|FeatureEntityStruct|FeatureCollectionStruct)
+}
+
+// grpcPath returns the REST path of the grpc-gateway annotation.
+func (g *Generator) grpcPath(t *Table, withPK bool, customMethod string) string {
+	var buf strings.Builder
+	buf.WriteString(g.grpcService.HTTPPathPrefix)
+	buf.WriteByte('/')
+	buf.WriteString(t.Table.Name)
+	if withPK {
+		t.Table.Columns.PrimaryKeys().Each(func(c *ddl.Column) {
+			buf.WriteString("/{")
+			buf.WriteString(strs.ToGoCamelCase(c.Field))
+			buf.WriteByte('}')
+		})
+	}
+	buf.WriteString(customMethod)
+	return buf.String()
+}
+
+// protoGRPCService writes the request messages and the service definition
+// with the grpc-gateway annotations of a table.
+func (g *Generator) protoGRPCService(proto *codegen.Proto, t *Table) {
+	entity := t.EntityName()
+	coll := t.CollectionName()
+	pkRequest := entity + "PKRequest"
+	listRequest := entity + "ListRequest"
+
+	proto.C(pkRequest, `identifies a single row of the`, t.Table.Name, `DB table by its primary key. Auto generated.`)
+	proto.Pln(`message`, pkRequest, `{`)
+	{
+		proto.In()
+		var pos int
+		t.Table.Columns.PrimaryKeys().Each(func(c *ddl.Column) {
+			pos++
+			proto.Pln(strings.TrimPrefix(g.serializerType(c), "optional "), strs.ToGoCamelCase(c.Field), `=`, pos, `;`)
+		})
+		proto.Out()
+	}
+	proto.Pln(`}`)
+
+	proto.C(listRequest, `defines the pagination and the filter for listing rows of the`, t.Table.Name,
+		`DB table. Filter uses the column name as key and compares for equality. Auto generated.`)
+	proto.Pln(`message`, listRequest, `{`)
+	{
+		proto.In()
+		proto.Pln(`uint64 Limit = 1;`)
+		proto.Pln(`uint64 Offset = 2;`)
+		proto.Pln(`map<string, string> Filter = 3;`)
+		proto.Out()
+	}
+	proto.Pln(`}`)
+
+	type rpc struct {
+		name, request, response, method, path string
+		withBody                              bool
+	}
+	rpcs := [...]rpc{
+		{"Get" + entity, pkRequest, entity, "get", g.grpcPath(t, true, ""), false},
+		{"List" + coll, listRequest, coll, "get", g.grpcPath(t, false, ""), false},
+		{"Create" + entity, entity, entity, "post", g.grpcPath(t, false, ""), true},
+		{"Update" + entity, entity, entity, "put", g.grpcPath(t, true, ""), true},
+		{"Delete" + entity, pkRequest, "google.protobuf.Empty", "delete", g.grpcPath(t, true, ""), false},
+		{"Create" + coll, coll, coll, "post", g.grpcPath(t, false, ":batchCreate"), true},
+		{"Update" + coll, coll, "google.protobuf.Empty", "post", g.grpcPath(t, false, ":batchUpdate"), true},
+		{"Delete" + coll, coll, "google.protobuf.Empty", "post", g.grpcPath(t, false, ":batchDelete"), true},
+	}
+
+	proto.C(entity+`Service`, `provides CRUD operations for the`, t.Table.Name, `DB table. Auto generated.`)
+	proto.Pln(`service`, entity+`Service`, `{`)
+	proto.In()
+	for _, r := range rpcs {
+		proto.Pln(`rpc`, r.name, `(`+r.request+`) returns (`+r.response+`) {`)
+		proto.In()
+		body := ""
+		if r.withBody {
+			body = ` body: "*"`
+		}
+		proto.Pln(`option (google.api.http) = {`, fmt.Sprintf("%s: %q", r.method, r.path)+body, `};`)
+		proto.Out()
+		proto.Pln(`}`)
+	}
+	proto.Out()
+	proto.Pln(`}`)
+}
+
+// GenerateGRPCServer writes the Go implementations of the gRPC services into
+// w. The generated servers embed csgrpc.AbstractServer and the
+// UnimplementedServer of protoc-gen-go-grpc and use the generated DBM type to
+// access the database. A service method whose DB feature has been disabled
+// returns codes.Unimplemented. The function NewGRPCServer registers all
+// services with the authentication interceptors of package csgrpc/auth. The
+// generated protocol buffers Go types must reside in their own package.
+func (g *Generator) GenerateGRPCServer(w io.Writer) error {
+	if g.grpcService == nil {
+		return errors.NotValid.Newf("[dmlgen] GenerateGRPCServer requires the option WithGRPCService.")
+	}
+	if g.PackageSerializerImportPath == g.PackageImportPath {
+		return errors.NotValid.Newf("[dmlgen] GenerateGRPCServer: The protocol buffers package %q must differ from the package %q", g.PackageSerializerImportPath, g.PackageImportPath)
+	}
+
+	mainGen := codegen.NewGo(g.Package)
+	mainGen.SecondLineComments = []string{"Generated by sql/dmlgen. DO NOT EDIT."}
+	mainGen.BuildTags = g.BuildTags
+
+	tables := make([]*Table, 0, len(g.Tables))
+	for _, tblname := range g.sortedTableNames() {
+		if t := g.Tables[tblname]; g.hasGRPCService(t) {
+			tables = append(tables, t)
+		}
+	}
+
+	g.fnGRPCNewServer(mainGen, tables)
+	for _, t := range tables {
+		t.fnEntityProtoConverter(mainGen, g)
+		t.fnCollectionProtoConverter(mainGen, g)
+		t.fnGRPCServer(mainGen, g)
+	}
+
+	pkgs, err := findUsedPackages(mainGen.Bytes(), []string{
+		"context",
+		"time",
+		"github.com/corestoreio/errors",
+		"github.com/corestoreio/log",
+		"github.com/corestoreio/pkg/net/csgrpc",
+		"github.com/corestoreio/pkg/sql/dml",
+		"google.golang.org/grpc",
+		"google.golang.org/grpc/codes",
+		"google.golang.org/grpc/status",
+		"google.golang.org/protobuf/types/known/emptypb",
+		"google.golang.org/protobuf/types/known/timestamppb",
+	})
+	if err != nil {
+		_, _ = w.Write(mainGen.Bytes()) // write for debug reasons
+		return errors.WithStack(err)
+	}
+	mainGen.AddImports(pkgs...)
+	mainGen.AddImport(importPathCSGRPCAuth, "grpc_auth")
+	mainGen.AddImport(g.PackageSerializerImportPath, g.PackageSerializer)
+
+	return errors.WithStack(mainGen.GenerateFile(w))
+}
+
+func (g *Generator) fnGRPCNewServer(mainGen *codegen.Go, tables []*Table) {
+	mainGen.C(`NewGRPCServer creates a new gRPC server with all table services registered. The authentication interceptors of package csgrpc/auth call the AuthFuncOverride function of each service, see csgrpc.WithServerAuthFuncOverrider. Argument serverOpts gets appended to the interceptors and argument opts gets applied to each service.`)
+	mainGen.Pln(`func NewGRPCServer(dbm *DBM, serverOpts []grpc.ServerOption, opts ...csgrpc.Option) (*grpc.Server, error) {`)
+	mainGen.In()
+	mainGen.Pln(`srv := grpc.NewServer(append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(grpc_auth.UnaryServerInterceptor(nil)),
+		grpc.ChainStreamInterceptor(grpc_auth.StreamServerInterceptor(nil)),
+	}, serverOpts...)...)`)
+	for _, t := range tables {
+		mainGen.Pln(`{`)
+		mainGen.Pln(`s, err := `, codegen.SkipWS(`New`, t.EntityName(), `GRPCServer`), `(dbm, opts...)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}`)
+		mainGen.Pln(codegen.SkipWS(g.PackageSerializer, `.Register`, t.EntityName(), `ServiceServer`), `(srv, s)`)
+		mainGen.Pln(`}`)
+	}
+	mainGen.Pln(`return srv, nil`)
+	mainGen.Out()
+	mainGen.Pln(`}`)
+
+	mainGen.C(`grpcError converts err into a gRPC status error and records the error metric. Internal errors get logged and the client receives a generic message because the error might contain SQL or connection details.`)
+	mainGen.Pln(`func grpcError(ctx context.Context, as csgrpc.AbstractServer, err error) error {
+		as.RecordError(ctx)
+		switch {
+		case dml.IsConflictError(err):
+			return status.Error(codes.Aborted, err.Error())
+		case errors.NotFound.Match(err):
+			return status.Error(codes.NotFound, err.Error())
+		case errors.NotValid.Match(err), errors.Empty.Match(err):
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if as.Log != nil && as.Log.IsInfo() {
+			as.Log.Info("grpcError", log.Err(err))
+		}
+		return status.Error(codes.Internal, "internal error")
+	}`)
+}
+
+// protoFieldConverters returns for each column of the entity the Go code to
+// convert the field into the protocol buffers field and back. Columns with a
+// type not supported by the protobuf type map get skipped.
+func (t *Table) protoFieldConverters(g *Generator, fn func(c *ddl.Column, toProto, fromProto string)) {
+	t.Table.Columns.Each(func(c *ddl.Column) {
+		if !t.IsFieldPublic(c.Field) {
+			return
+		}
+		goType := g.goTypeNull(c)
+		if g.serializerType(c) == "bytes" && goType != "[]byte" {
+			return // custom Go type
+		}
+		field := t.GoCamelMaybePrivate(c.Field)
+		pField := strs.ToGoCamelCase(c.Field)
+		switch {
+		case goType == "null.Time":
+			fn(c, `e.`+field+`.Proto()`, `e.`+field+`.SetProto(p.`+pField+`)`)
+		case goType == "time.Time":
+			fn(c, `timestamppb.New(e.`+field+`)`, `e.`+field+` = time.Time{}
+				if p.`+pField+` != nil {
+					e.`+field+` = p.`+pField+`.AsTime()
+				}`)
+		case strings.HasPrefix(goType, "null."):
+			fn(c, `e.`+field+`.Ptr()`, `e.`+field+`.SetPtr(p.`+pField+`)`)
+		default:
+			fn(c, `e.`+field, `e.`+field+` = p.`+pField)
+		}
+	})
+}
+
+func (t *Table) fnEntityProtoConverter(mainGen *codegen.Go, g *Generator) {
+	entity := t.EntityName()
+	pbEntity := codegen.SkipWS(g.PackageSerializer, `.`, entity)
+
+	mainGen.C(`ToProto converts the entity into its protocol buffers message. Relations are not converted.`)
+	mainGen.Pln(`func (e *`, entity, `) ToProto() *`, pbEntity, `{`)
+	mainGen.In()
+	mainGen.Pln(`if e == nil {
+		return nil
+	}`)
+	mainGen.Pln(`return &`, pbEntity, `{`)
+	t.protoFieldConverters(g, func(c *ddl.Column, toProto, _ string) {
+		mainGen.Pln(strs.ToGoCamelCase(c.Field)+`:`, toProto+`,`)
+	})
+	mainGen.Pln(`}`)
+	mainGen.Out()
+	mainGen.Pln(`}`)
+
+	mainGen.C(`FromProto sets the fields of the protocol buffers message to the entity. Relations are not converted.`)
+	mainGen.Pln(`func (e *`, entity, `) FromProto(p *`, pbEntity, `) *`, entity, `{`)
+	mainGen.In()
+	mainGen.Pln(`if p == nil {
+		return e
+	}`)
+	t.protoFieldConverters(g, func(_ *ddl.Column, _, fromProto string) {
+		mainGen.Pln(fromProto)
+	})
+	mainGen.Pln(`return e`)
+	mainGen.Out()
+	mainGen.Pln(`}`)
+}
+
+func (t *Table) fnCollectionProtoConverter(mainGen *codegen.Go, g *Generator) {
+	entity := t.EntityName()
+	coll := t.CollectionName()
+	pbEntity := codegen.SkipWS(g.PackageSerializer, `.`, entity)
+	pbColl := codegen.SkipWS(g.PackageSerializer, `.`, coll)
+
+	mainGen.C(`ToProto converts the collection into its protocol buffers message.`)
+	mainGen.Pln(`func (cc *`, coll, `) ToProto() *`, pbColl, `{
+		if cc == nil {
+			return nil
+		}
+		p := &`, pbColl, `{Data: make([]*`, pbEntity, `, 0, len(cc.Data))}
+		for _, e := range cc.Data {
+			p.Data = append(p.Data, e.ToProto())
+		}
+		return p
+	}`)
+
+	mainGen.C(`FromProto replaces the entities of the collection with the protocol buffers message.`)
+	mainGen.Pln(`func (cc *`, coll, `) FromProto(p *`, pbColl, `) *`, coll, `{
+		if p == nil {
+			return cc
+		}
+		cc.Data = make([]*`, entity, `, 0, len(p.Data))
+		for _, d := range p.Data {
+			cc.Data = append(cc.Data, new(`, entity, `).FromProto(d))
+		}
+		return cc
+	}`)
+}
+
+func (t *Table) fnGRPCServer(mainGen *codegen.Go, g *Generator) {
+	entity := t.EntityName()
+	coll := t.CollectionName()
+	pb := g.PackageSerializer
+	pbEntity := codegen.SkipWS(pb, `.`, entity)
+	pbColl := codegen.SkipWS(pb, `.`, coll)
+	pbPKRequest := codegen.SkipWS(pb, `.`, entity, `PKRequest`)
+	serverName := codegen.SkipWS(entity, `GRPCServer`)
+
+	tblPkCols := t.Table.Columns.PrimaryKeys()
+	var pkArgs strings.Builder
+	if tblPkCols.Len() > 1 {
+		pkArgs.WriteString(entity + "LoadArgs{\n")
+	}
+	var pkFields strings.Builder
+	tblPkCols.Each(func(c *ddl.Column) {
+		field := strs.ToGoCamelCase(c.Field)
+		if tblPkCols.Len() > 1 {
+			pkArgs.WriteString(field + ": r." + field + ",\n")
+		} else {
+			pkArgs.WriteString("r." + field)
+		}
+		pkFields.WriteString(t.GoCamelMaybePrivate(c.Field) + ": r." + field + ",\n")
+	})
+	if tblPkCols.Len() > 1 {
+		pkArgs.WriteString("}")
+	}
+
+	mainGen.C(serverName, `implements the gRPC service`, entity+`Service`, `for the`, t.Table.Name, `DB table.`)
+	mainGen.Pln(`type`, serverName, `struct {`)
+	mainGen.In()
+	mainGen.Pln(codegen.SkipWS(pb, `.Unimplemented`, entity, `ServiceServer`))
+	mainGen.Pln(`csgrpc.AbstractServer`)
+	mainGen.Pln(`dbm *DBM`)
+	mainGen.Out()
+	mainGen.Pln(`}`)
+
+	mainGen.C(`New`+string(serverName), `creates a new gRPC server for the`, t.Table.Name, `DB table.`)
+	mainGen.Pln(`func New`+string(serverName), `(dbm *DBM, opts ...csgrpc.Option) (*`, serverName, `, error) {
+		as, err := csgrpc.NewAbstractServer(append([]csgrpc.Option{csgrpc.WithErrorMetrics(`,
+		codegen.SkipWS(`"`, g.Package, `/`, serverName, `/errors"`), `)}, opts...)...)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return &`, serverName, `{AbstractServer: as, dbm: dbm}, nil
+	}`)
+
+	selectEnabled := t.hasFeature(g, FeatureDBSelect)
+	mainGen.C(selectEnabled, `Get`+entity, `loads a single row by its primary key.`)
+	mainGen.Pln(selectEnabled, `func (s *`, serverName, `) Get`+entity, `(ctx context.Context, r *`, pbPKRequest, `) (*`, pbEntity, `, error) {
+		e := new(`, entity, `)
+		if err := e.Load(ctx, s.dbm, `, pkArgs.String(), `); err != nil {
+			return nil, grpcError(ctx, s.AbstractServer, err)
+		}
+		if !e.IsSet() {
+			return nil, grpcError(ctx, s.AbstractServer, errors.NotFound.Newf(`, codegen.SkipWS(`"`, entity), `not found"))
+		}
+		return e.ToProto(), nil
+	}`)
+
+	mainGen.C(selectEnabled, `List`+coll, `loads the rows with pagination. The filter only allows column names as keys.`)
+	mainGen.Pln(selectEnabled, `func (s *`, serverName, `) List`+coll, `(ctx context.Context, r *`, codegen.SkipWS(pb, `.`, entity, `ListRequest`), `) (*`, pbColl, `, error) {`)
+	mainGen.In()
+	mainGen.Pln(selectEnabled, `limit := r.Limit
+		switch {
+		case limit == 0:
+			limit =`, g.grpcService.DefaultListLimit, `
+		case limit >`, g.grpcService.MaxListLimit, `:
+			limit =`, g.grpcService.MaxListLimit, `
+		}`)
+	// same soft delete conditions as the SELECT queries of the DBM
+	selectCode, whereCode := t.softDeleteSelect("")
+	mainGen.Pln(selectEnabled, `tbls := s.dbm.Tables`)
+	mainGen.Pln(selectEnabled, `sel := `, selectCode, softDeleteWhere(whereCode))
+	if t.hasSoftDelete() {
+		mainGen.Pln(selectEnabled, `switch qo := dml.FromContextQueryOptions(ctx); {`)
+		for _, suffix := range [...]string{"OnlyDeleted", "WithDeleted"} {
+			selectCode, whereCode = t.softDeleteSelect(suffix)
+			mainGen.Pln(selectEnabled, `case qo.`+suffix+`:`)
+			mainGen.Pln(selectEnabled, `sel = `, selectCode, softDeleteWhere(whereCode))
+		}
+		mainGen.Pln(selectEnabled, `}`)
+	}
+	// a stable order is required for the pagination
+	pkNames := make([]string, 0, tblPkCols.Len())
+	tblPkCols.Each(func(c *ddl.Column) {
+		pkNames = append(pkNames, "`"+c.Field+"`")
+	})
+	mainGen.Pln(selectEnabled, `sel.OrderBy(`, strings.Join(pkNames, ", "), `).Limit(r.Offset, limit)`)
+	var columnNames []string
+	t.Table.Columns.Each(func(c *ddl.Column) {
+		if t.IsFieldPublic(c.Field) {
+			columnNames = append(columnNames, "`"+c.Field+"`")
+		}
+	})
+	mainGen.Pln(selectEnabled, `var filterCount int
+		for _, col := range [...]string{`, strings.Join(columnNames, ", "), `} {
+			if v, ok := r.Filter[col]; ok {
+				sel.Where(dml.Column(col).Equal().Str(v))
+				filterCount++
+			}
+		}
+		if filterCount != len(r.Filter) {
+			return nil, grpcError(ctx, s.AbstractServer, errors.NotValid.Newf(`, codegen.SkipWS(`"List`, coll, `: filter contains unknown columns: %v"`), `, r.Filter))
+		}`)
+	mainGen.Pln(selectEnabled, `cc := new(`, coll, `)
+		if _, err := s.dbm.ConnPool.WithQueryBuilder(sel).Load(ctx, cc); err != nil {
+			return nil, grpcError(ctx, s.AbstractServer, err)
+		}
+		return cc.ToProto(), nil`)
+	mainGen.Out()
+	mainGen.Pln(selectEnabled, `}`)
+
+	insertEnabled := t.hasFeature(g, FeatureDBInsert)
+	mainGen.C(insertEnabled, `Create`+entity, `inserts a new row and returns it including the auto increment ID.`)
+	mainGen.Pln(insertEnabled, `func (s *`, serverName, `) Create`+entity, `(ctx context.Context, r *`, pbEntity, `) (*`, pbEntity, `, error) {
+		e := new(`, entity, `).FromProto(r)
+		if _, err := e.Insert(ctx, s.dbm); err != nil {
+			return nil, grpcError(ctx, s.AbstractServer, err)
+		}
+		return e.ToProto(), nil
+	}`)
+	mainGen.C(insertEnabled, `Create`+coll, `inserts multiple rows.`)
+	mainGen.Pln(insertEnabled, `func (s *`, serverName, `) Create`+coll, `(ctx context.Context, r *`, pbColl, `) (*`, pbColl, `, error) {
+		cc := new(`, coll, `).FromProto(r)
+		if err := cc.DBInsert(ctx, s.dbm); err != nil {
+			return nil, grpcError(ctx, s.AbstractServer, err)
+		}
+		return cc.ToProto(), nil
+	}`)
+
+	updateEnabled := t.hasFeature(g, FeatureDBUpdate)
+	mainGen.C(updateEnabled, `Update`+entity, `updates a row identified by its primary key.`)
+	mainGen.Pln(updateEnabled, `func (s *`, serverName, `) Update`+entity, `(ctx context.Context, r *`, pbEntity, `) (*`, pbEntity, `, error) {
+		e := new(`, entity, `).FromProto(r)
+		if _, err := e.Update(ctx, s.dbm); err != nil {
+			return nil, grpcError(ctx, s.AbstractServer, err)
+		}
+		return e.ToProto(), nil
+	}`)
+	mainGen.C(updateEnabled, `Update`+coll, `updates multiple rows.`)
+	mainGen.Pln(updateEnabled, `func (s *`, serverName, `) Update`+coll, `(ctx context.Context, r *`, pbColl, `) (*emptypb.Empty, error) {
+		if err := new(`, coll, `).FromProto(r).DBUpdate(ctx, s.dbm); err != nil {
+			return nil, grpcError(ctx, s.AbstractServer, err)
+		}
+		return &emptypb.Empty{}, nil
+	}`)
+
+	deleteEnabled := t.hasFeature(g, FeatureDBDelete)
+	mainGen.C(deleteEnabled, `Delete`+entity, `deletes a row identified by its primary key.`)
+	mainGen.Pln(deleteEnabled, `func (s *`, serverName, `) Delete`+entity, `(ctx context.Context, r *`, pbPKRequest, `) (*emptypb.Empty, error) {
+		e := &`, entity, `{
+		`, pkFields.String(), `}
+		res, err := e.Delete(ctx, s.dbm)
+		if err != nil {
+			return nil, grpcError(ctx, s.AbstractServer, err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return nil, grpcError(ctx, s.AbstractServer, errors.NotFound.Newf(`, codegen.SkipWS(`"`, entity), `not found"))
+		}
+		return &emptypb.Empty{}, nil
+	}`)
+	mainGen.C(deleteEnabled, `Delete`+coll, `deletes multiple rows identified by their primary keys.`)
+	mainGen.Pln(deleteEnabled, `func (s *`, serverName, `) Delete`+coll, `(ctx context.Context, r *`, pbColl, `) (*emptypb.Empty, error) {
+		if _, err := new(`, coll, `).FromProto(r).DBDelete(ctx, s.dbm); err != nil {
+			return nil, grpcError(ctx, s.AbstractServer, err)
+		}
+		return &emptypb.Empty{}, nil
+	}`)
+}
@@ -0,0 +1,119 @@
+package dmlgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func newGRPCTestGenerator(t *testing.T, opts ...Option) *Generator {
+	g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestgrpc",
+		append([]Option{
+			WithProtobuf(&SerializerConfig{PackageImportPath: "github.com/corestoreio/pkg/sql/dmlgen/dmltestgrpc/dmltestgrpcpb"}),
+			WithTable("soft_entity", newSoftDeleteTestColumns()),
+			WithTableConfig("soft_entity", &TableConfig{SoftDeleteColumn: "deleted_at"}),
+			WithTable("link_entity", ddl.Columns{
+				&ddl.Column{Field: "left_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI"},
+				&ddl.Column{Field: "right_id", Pos: 2, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI"},
+				&ddl.Column{Field: "price", Pos: 3, Null: "YES", DataType: "decimal", ColumnType: "decimal(12,4)"},
+				&ddl.Column{Field: "comment", Pos: 4, Null: "YES", DataType: "varchar", ColumnType: "varchar(255)"},
+			}),
+		}, opts...)...,
+	)
+	assert.NoError(t, err)
+	return g
+}
+
+func TestWithGRPCService(t *testing.T) {
+	t.Run("requires protobuf", func(t *testing.T) {
+		_, err := NewGenerator("dmltestgrpc", WithGRPCService(nil))
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+	t.Run("invalid list limits", func(t *testing.T) {
+		_, err := NewGenerator("dmltestgrpc",
+			WithProtobuf(&SerializerConfig{PackageImportPath: "dmltestgrpcpb"}),
+			WithGRPCService(&GRPCServiceConfig{DefaultListLimit: 10, MaxListLimit: 5}),
+		)
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+	t.Run("server requires option", func(t *testing.T) {
+		g := newGRPCTestGenerator(t)
+		assert.ErrorIsKind(t, errors.NotValid, g.GenerateGRPCServer(new(bytes.Buffer)))
+	})
+	t.Run("server requires own pb package", func(t *testing.T) {
+		g, err := NewGenerator("dmltestgrpc",
+			WithProtobuf(&SerializerConfig{PackageImportPath: "dmltestgrpc"}),
+			WithGRPCService(nil),
+		)
+		assert.NoError(t, err)
+		assert.ErrorIsKind(t, errors.NotValid, g.GenerateGRPCServer(new(bytes.Buffer)))
+	})
+}
+
+func TestGenerator_GRPCService_Proto(t *testing.T) {
+	g := newGRPCTestGenerator(t, WithGRPCService(&GRPCServiceConfig{HTTPPathPrefix: "/api/v2/"}))
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.GenerateSerializer(&buf, nil))
+	proto := buf.String()
+
+	assert.Contains(t, proto, `import "google/api/annotations.proto";`)
+	assert.Contains(t, proto, `import "google/protobuf/empty.proto";`)
+	assert.Contains(t, proto, "message LinkEntityPKRequest { \n\tuint32 LeftID = 1 ; \n\tuint32 RightID = 2 ; \n}")
+	assert.Contains(t, proto, "map<string, string> Filter = 3;")
+	assert.Contains(t, proto, "service SoftEntityService {")
+	assert.Contains(t, proto, `rpc GetSoftEntity (SoftEntityPKRequest) returns (SoftEntity) {`)
+	assert.Contains(t, proto, `option (google.api.http) = { get: "/api/v2/soft_entity/{EntityID}" };`)
+	assert.Contains(t, proto, `option (google.api.http) = { delete: "/api/v2/link_entity/{LeftID}/{RightID}" };`)
+	assert.Contains(t, proto, `rpc UpdateLinkEntities (LinkEntities) returns (google.protobuf.Empty) {`)
+	assert.Contains(t, proto, `option (google.api.http) = { post: "/api/v2/soft_entity:batchCreate" body: "*" };`)
+}
+
+func TestGenerator_GRPCService_FeatureExclude(t *testing.T) {
+	g := newGRPCTestGenerator(t, WithGRPCService(nil),
+		WithTableConfig("link_entity", &TableConfig{FeaturesExclude: FeatureGRPCService}),
+	)
+	var buf bytes.Buffer
+	assert.NoError(t, g.GenerateSerializer(&buf, nil))
+	assert.Contains(t, buf.String(), "service SoftEntityService {")
+	assert.NotContains(t, buf.String(), "service LinkEntityService {")
+}
+
+func TestGenerator_GenerateGRPCServer(t *testing.T) {
+	g := newGRPCTestGenerator(t, WithGRPCService(nil))
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.GenerateGRPCServer(&buf))
+	code := buf.String()
+
+	assert.Contains(t, code, `dmltestgrpcpb "github.com/corestoreio/pkg/sql/dmlgen/dmltestgrpc/dmltestgrpcpb"`)
+	assert.Contains(t, code, `grpc_auth "github.com/corestoreio/pkg/net/csgrpc/auth"`)
+	assert.Contains(t, code, `grpc.ChainUnaryInterceptor(grpc_auth.UnaryServerInterceptor(nil)),`)
+	assert.Contains(t, code, `dmltestgrpcpb.RegisterSoftEntityServiceServer(srv, s)`)
+
+	assert.Contains(t, code, "type SoftEntityGRPCServer struct {\n\tdmltestgrpcpb.UnimplementedSoftEntityServiceServer\n\tcsgrpc.AbstractServer\n\tdbm *DBM\n}")
+	assert.Contains(t, code, `csgrpc.WithErrorMetrics("dmltestgrpc/SoftEntityGRPCServer/errors")`)
+	assert.Contains(t, code, `if err := e.Load(ctx, s.dbm, r.EntityID); err != nil {`)
+	assert.Contains(t, code, "if err := e.Load(ctx, s.dbm, LinkEntityLoadArgs{\n\t\tLeftID:  r.LeftID,\n\t\tRightID: r.RightID,\n\t}); err != nil {")
+	assert.Contains(t, code, "case limit == 0:\n\t\tlimit = 100\n\tcase limit > 1000:")
+	assert.Contains(t, code, "for _, col := range [...]string{`entity_id`, `name`, `deleted_at`, `created_at`} {")
+	assert.Contains(t, code, "sel := tbls.MustTable(TableNameSoftEntity).Select(\"*\").Where(\n\t\tdml.Column(`deleted_at`).Null(),\n\t)")
+	assert.Contains(t, code, "case qo.OnlyDeleted:\n\t\tsel = tbls.MustTable(TableNameSoftEntity).Select(\"*\").Where(\n\t\t\tdml.Column(`deleted_at`).NotNull(),\n\t\t)")
+	assert.Contains(t, code, "case qo.WithDeleted:\n\t\tsel = tbls.MustTable(TableNameSoftEntity).Select(\"*\")\n\t}")
+	assert.Contains(t, code, "sel.OrderBy(`entity_id`).Limit(r.Offset, limit)")
+	assert.Contains(t, code, "sel := tbls.MustTable(TableNameLinkEntity).Select(\"*\")\n\tsel.OrderBy(`left_id`, `right_id`).Limit(r.Offset, limit)")
+	assert.Contains(t, code, `if n, _ := res.RowsAffected(); n == 0 {`)
+	assert.Contains(t, code, "case dml.IsConflictError(err):\n\t\treturn status.Error(codes.Aborted, err.Error())")
+	assert.Contains(t, code, "as.Log.Info(\"grpcError\", log.Err(err))\n\t}\n\treturn status.Error(codes.Internal, \"internal error\")")
+	assert.Contains(t, code, `errors.NotValid.Newf("ListSoftEntities: filter contains unknown columns: %v", r.Filter)`)
+
+	// converters
+	assert.Contains(t, code, `DeletedAt: e.DeletedAt.Proto(),`)
+	assert.Contains(t, code, `CreatedAt: timestamppb.New(e.CreatedAt),`)
+	assert.Contains(t, code, `Price:   e.Price.Ptr(),`)
+	assert.Contains(t, code, `e.Comment.SetPtr(p.Comment)`)
+	assert.Contains(t, code, "e.CreatedAt = time.Time{}\n\tif p.CreatedAt != nil {\n\t\te.CreatedAt = p.CreatedAt.AsTime()\n\t}")
+}
@@ -0,0 +1,314 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlgen
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/mailru/easyjson/gen"
+)
+
+const (
+	jsonSchemaDraft      = "https://json-schema.org/draft/2020-12/schema"
+	jsonSchemaRefDefs    = "#/$defs/"
+	jsonSchemaRefOpenAPI = "#/components/schemas/"
+)
+
+// jsonSchema represents the subset of JSON Schema draft 2020-12 used to
+// describe the generated entities. OpenAPI 3.1 uses the same vocabulary.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 interface{}            `json:"type,omitempty"` // string or []string
+	Format               string                 `json:"format,omitempty"`
+	ContentEncoding      string                 `json:"contentEncoding,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	MaxLength            int64                  `json:"maxLength,omitempty"`
+	Minimum              interface{}            `json:"minimum,omitempty"`
+	Maximum              interface{}            `json:"maximum,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+	ReadOnly             bool                   `json:"readOnly,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+	// XColumn and XAliases are extensions which refer to the DB column.
+	XColumn  string   `json:"x-db-column,omitempty"`
+	XAliases []string `json:"x-db-aliases,omitempty"`
+}
+
+// integerRanges contains the minimum and maximum values of the MySQL integer
+// types. Index 0 contains the signed and index 1 the unsigned range.
+var integerRanges = map[string][2][2]interface{}{
+	"tinyint":   {{int64(math.MinInt8), int64(math.MaxInt8)}, {int64(0), int64(math.MaxUint8)}},
+	"smallint":  {{int64(math.MinInt16), int64(math.MaxInt16)}, {int64(0), int64(math.MaxUint16)}},
+	"mediumint": {{int64(-8388608), int64(8388607)}, {int64(0), int64(16777215)}},
+	"int":       {{int64(math.MinInt32), int64(math.MaxInt32)}, {int64(0), int64(math.MaxUint32)}},
+	"bigint":    {{int64(math.MinInt64), int64(math.MaxInt64)}, {int64(0), uint64(math.MaxUint64)}},
+}
+
+// jsonFieldName returns the JSON name of a struct field and if empty values
+// get omitted. The rules of easyjson apply if the table has the easyjson
+// encoder, see GenerateJSON, otherwise the rules of encoding/json. An empty
+// name means the field won't be encoded.
+func (t *Table) jsonFieldName(c *ddl.Column) (name string, omitEmpty bool) {
+	sf := reflect.StructField{
+		Name: t.GoCamelMaybePrivate(c.Field),
+		Tag:  reflect.StructTag(strings.Trim(c.StructTag, "`")),
+	}
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	tagName, tagOpts := tag, ""
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		tagName, tagOpts = tag[:i], tag[i:]
+	}
+
+	if t.HasEasyJSONMarshaler {
+		name = gen.LowerCamelCaseFieldNamer{}.GetJSONFieldName(nil, sf)
+		omitEmpty = !strings.Contains(tagOpts, ",!omitempty") // GenerateJSON enables OmitEmpty
+		return name, omitEmpty
+	}
+	name = tagName
+	if name == "" {
+		name = sf.Name
+	}
+	return name, strings.Contains(tagOpts, ",omitempty")
+}
+
+// jsonSchemaColumn converts a column into the schema of its JSON value. The
+// Go type of the column determines the JSON type.
+func (g *Generator) jsonSchemaColumn(c *ddl.Column) *jsonSchema {
+	s := &jsonSchema{
+		Description: c.Comment,
+		XColumn:     c.Field,
+		XAliases:    c.Aliases,
+		ReadOnly:    c.IsAutoIncrement() || c.IsGenerated(),
+	}
+
+	var jsonType []string
+	switch goType := g.goType(c); goType {
+	case "bool":
+		jsonType = []string{"boolean"}
+	case "float64":
+		jsonType = []string{"number"}
+	case "null.Decimal":
+		jsonType = []string{"number", "string"} // Decimal.Quote encodes as string
+	case "time.Time":
+		jsonType = []string{"string"}
+		s.Format = "date-time"
+	case "[]byte":
+		jsonType = []string{"string"}
+		s.ContentEncoding = "base64"
+	case "string":
+		jsonType = []string{"string"}
+		if c.CharMaxLength.Valid && c.CharMaxLength.Int64 > 0 {
+			s.MaxLength = c.CharMaxLength.Int64
+		}
+	default:
+		if strings.Contains(goType, "int") {
+			jsonType = []string{"integer"}
+			if r, ok := integerRanges[c.DataType]; ok {
+				idx := 0
+				if c.IsUnsigned() {
+					idx = 1
+				}
+				s.Minimum, s.Maximum = r[idx][0], r[idx][1]
+			}
+		} else {
+			jsonType = []string{"string"}
+		}
+	}
+
+	if values := c.EnumValues(); values != nil && jsonType[0] == "string" {
+		if c.DataType == "set" {
+			// a set contains a comma separated list of the values.
+			quoted := make([]string, len(values))
+			for i, v := range values {
+				quoted[i] = regexp.QuoteMeta(v)
+			}
+			alt := "(" + strings.Join(quoted, "|") + ")"
+			s.Pattern = "^(" + alt + "(," + alt + ")*)?$"
+		} else {
+			for _, v := range values {
+				s.Enum = append(s.Enum, v)
+			}
+			if c.IsNull() {
+				s.Enum = append(s.Enum, nil)
+			}
+		}
+	}
+
+	s.Default = jsonSchemaDefault(c, jsonType[0], s.Format)
+	if c.IsNull() {
+		jsonType = append(jsonType, "null")
+	}
+	if len(jsonType) == 1 {
+		s.Type = jsonType[0]
+	} else {
+		s.Type = jsonType
+	}
+	return s
+}
+
+// jsonSchemaDefault converts the DEFAULT of a column into a JSON value.
+// Functions like CURRENT_TIMESTAMP and defaults of time columns get skipped.
+func jsonSchemaDefault(c *ddl.Column, jsonType, format string) interface{} {
+	if !c.Default.Valid || strings.EqualFold(c.Default.Data, "NULL") || c.IsCurrentTimestamp() || format != "" {
+		return nil
+	}
+	d := c.Default.Data
+	quoted := len(d) >= 2 && d[0] == '\'' && d[len(d)-1] == '\''
+	if quoted { // MariaDB quotes string literals
+		d = strings.ReplaceAll(d[1:len(d)-1], "''", "'")
+	}
+	switch jsonType {
+	case "integer":
+		if c.IsUnsigned() {
+			if v, err := strconv.ParseUint(d, 10, 64); err == nil {
+				return v
+			}
+			return nil
+		}
+		if v, err := strconv.ParseInt(d, 10, 64); err == nil {
+			return v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(d, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(d); err == nil {
+			return v
+		}
+	case "string":
+		if !quoted && strings.IndexByte(d, '(') > 0 {
+			return nil // function call
+		}
+		return d
+	}
+	return nil
+}
+
+// jsonSchemaTable creates the object schema of the entity of a table.
+func (g *Generator) jsonSchemaTable(t *Table) *jsonSchema {
+	noAdditional := false
+	s := &jsonSchema{
+		Title:                t.EntityName(),
+		Description:          t.Table.TableComment,
+		Type:                 "object",
+		Properties:           map[string]*jsonSchema{},
+		AdditionalProperties: &noAdditional,
+	}
+	t.Table.Columns.Each(func(c *ddl.Column) {
+		if !t.IsFieldPublic(c.Field) {
+			return // unexported fields do not get encoded
+		}
+		name, omitEmpty := t.jsonFieldName(c)
+		if name == "" {
+			return
+		}
+		s.Properties[name] = g.jsonSchemaColumn(c)
+		if !omitEmpty && !c.IsNull() {
+			s.Required = append(s.Required, name)
+		}
+	})
+	return s
+}
+
+// jsonSchemaDefinitions returns the schemas of all entities and collections.
+// refPrefix points to the location of the definitions.
+func (g *Generator) jsonSchemaDefinitions(refPrefix string) map[string]*jsonSchema {
+	defs := make(map[string]*jsonSchema, len(g.Tables)*2)
+	for _, tblname := range g.sortedTableNames() {
+		t := g.Tables[tblname]
+		if !t.hasFeature(g, FeatureEntityStruct) {
+			continue
+		}
+		defs[t.EntityName()] = g.jsonSchemaTable(t)
+		if t.hasFeature(g, FeatureCollectionStruct) {
+			// see fnCollectionStruct for the struct tag
+			defs[t.CollectionName()] = &jsonSchema{
+				Title:       t.CollectionName(),
+				Description: "Collection of " + t.EntityName(),
+				Type:        "object",
+				Properties: map[string]*jsonSchema{
+					"data": {
+						Type:  "array",
+						Items: &jsonSchema{Ref: refPrefix + t.EntityName()},
+					},
+				},
+			}
+		}
+	}
+	return defs
+}
+
+// GenerateJSONSchema writes a JSON Schema (draft 2020-12) document into w
+// which contains the schemas of all entities and collections in its $defs.
+// The schema takes the column types, nullability, lengths, enum and set
+// values, unsigned ranges, defaults and comments into account. Field names
+// follow the struct tags and the naming rules of the JSON encoder of a table.
+// Private fields and relations are not part of the schema.
+func (g *Generator) GenerateJSONSchema(w io.Writer) error {
+	doc := &jsonSchema{
+		Schema: jsonSchemaDraft,
+		ID:     g.PackageImportPath,
+		Title:  g.Package,
+		Defs:   g.jsonSchemaDefinitions(jsonSchemaRefDefs),
+	}
+	return errors.WithStack(writeJSONIndent(w, doc))
+}
+
+// GenerateOpenAPI writes an OpenAPI 3.1 document into w which contains the
+// schemas of GenerateJSONSchema as components. The document does not contain
+// any paths and can be merged into an existing API description.
+func (g *Generator) GenerateOpenAPI(w io.Writer) error {
+	doc := struct {
+		OpenAPI    string                 `json:"openapi"`
+		Info       map[string]string      `json:"info"`
+		Paths      map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]*jsonSchema `json:"schemas"`
+		} `json:"components"`
+	}{
+		OpenAPI: "3.1.0",
+		Info:    map[string]string{"title": g.Package, "version": "1.0.0"},
+		Paths:   map[string]interface{}{},
+	}
+	doc.Components.Schemas = g.jsonSchemaDefinitions(jsonSchemaRefOpenAPI)
+	return errors.WithStack(writeJSONIndent(w, doc))
+}
+
+func writeJSONIndent(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
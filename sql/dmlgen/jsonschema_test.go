@@ -0,0 +1,107 @@
+package dmlgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func newJSONSchemaTestGenerator(t *testing.T, encoders ...string) *Generator {
+	g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestschema",
+		WithTable("customer", ddl.Columns{
+			&ddl.Column{Field: "entity_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment", Comment: "Entity ID"},
+			&ddl.Column{Field: "email", Pos: 2, Null: "YES", DataType: "varchar", CharMaxLength: null.MakeInt64(255), ColumnType: "varchar(255)"},
+			&ddl.Column{Field: "gender", Pos: 3, Null: "NO", Default: null.MakeString(`'f'`), DataType: "enum", ColumnType: "enum('f','m','d')"},
+			&ddl.Column{Field: "tags", Pos: 4, Null: "YES", DataType: "set", ColumnType: "set('a','b')"},
+			&ddl.Column{Field: "level", Pos: 5, Null: "NO", Default: null.MakeString(`3`), DataType: "tinyint", ColumnType: "tinyint(3) unsigned"},
+			&ddl.Column{Field: "is_active", Pos: 6, Null: "NO", Default: null.MakeString(`1`), DataType: "smallint", ColumnType: "smallint(5)"},
+			&ddl.Column{Field: "grand_total", Pos: 7, Null: "YES", DataType: "decimal", ColumnType: "decimal(12,4)"},
+			&ddl.Column{Field: "created_at", Pos: 8, Null: "NO", Default: null.MakeString(`current_timestamp()`), DataType: "timestamp", ColumnType: "timestamp"},
+			&ddl.Column{Field: "password_hash", Pos: 9, Null: "NO", DataType: "varchar", CharMaxLength: null.MakeInt64(64), ColumnType: "varchar(64)"},
+			&ddl.Column{Field: "notes", Pos: 10, Null: "NO", DataType: "varchar", CharMaxLength: null.MakeInt64(64), ColumnType: "varchar(64)"},
+		}),
+		WithTableConfig("customer", &TableConfig{
+			Encoders:         encoders,
+			PrivateFields:    []string{"password_hash"},
+			ColumnAliases:    map[string][]string{"entity_id": {"customer_id"}},
+			CustomStructTags: []string{"email", `json:"mail,omitempty"`, "notes", `json:"-"`},
+		}),
+	)
+	assert.NoError(t, err)
+	return g
+}
+
+func TestGenerator_GenerateJSONSchema(t *testing.T) {
+	t.Run("easyjson", func(t *testing.T) {
+		g := newJSONSchemaTestGenerator(t, "easyjson")
+		var buf bytes.Buffer
+		assert.NoError(t, g.GenerateJSONSchema(&buf))
+
+		var doc struct {
+			Schema string                 `json:"$schema"`
+			Defs   map[string]*jsonSchema `json:"$defs"`
+		}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		assert.Exactly(t, jsonSchemaDraft, doc.Schema)
+
+		cust := doc.Defs["Customer"]
+		assert.Exactly(t, "object", cust.Type)
+		assert.Exactly(t, []string(nil), cust.Required) // GenerateJSON enables omitempty
+
+		ps := cust.Properties
+		assert.Len(t, ps, 8)
+		assert.NotNil(t, ps["mail"], "custom struct tag")
+		assert.Nil(t, ps["passwordHash"], "private field")
+		assert.Nil(t, ps["notes"], "excluded by struct tag")
+
+		assert.Exactly(t, "integer", ps["entityID"].Type)
+		assert.Exactly(t, true, ps["entityID"].ReadOnly)
+		assert.Exactly(t, []string{"customer_id"}, ps["entityID"].XAliases)
+		assert.Exactly(t, "Entity ID", ps["entityID"].Description)
+		assert.Exactly(t, []interface{}{"string", "null"}, ps["mail"].Type)
+		assert.Exactly(t, int64(255), ps["mail"].MaxLength)
+		assert.Exactly(t, []interface{}{"f", "m", "d"}, ps["gender"].Enum)
+		assert.Exactly(t, "f", ps["gender"].Default)
+		assert.Exactly(t, "^((a|b)(,(a|b))*)?$", ps["tags"].Pattern)
+		assert.Exactly(t, float64(0), ps["level"].Minimum)
+		assert.Exactly(t, float64(255), ps["level"].Maximum)
+		assert.Exactly(t, float64(3), ps["level"].Default)
+		assert.Exactly(t, "boolean", ps["isActive"].Type)
+		assert.Exactly(t, true, ps["isActive"].Default)
+		assert.Exactly(t, []interface{}{"number", "string", "null"}, ps["grandTotal"].Type)
+		assert.Exactly(t, "date-time", ps["createdAt"].Format)
+		assert.Nil(t, ps["createdAt"].Default)
+
+		coll := doc.Defs["Customers"]
+		assert.Exactly(t, "#/$defs/Customer", coll.Properties["data"].Items.Ref)
+	})
+
+	t.Run("encoding/json", func(t *testing.T) {
+		g := newJSONSchemaTestGenerator(t)
+		var buf bytes.Buffer
+		assert.NoError(t, g.GenerateJSONSchema(&buf))
+
+		var doc struct {
+			Defs map[string]*jsonSchema `json:"$defs"`
+		}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		cust := doc.Defs["Customer"]
+		assert.NotNil(t, cust.Properties["EntityID"])
+		assert.NotNil(t, cust.Properties["mail"])
+		assert.Exactly(t, []string{"EntityID", "Gender", "Level", "IsActive", "CreatedAt"}, cust.Required)
+	})
+}
+
+func TestGenerator_GenerateOpenAPI(t *testing.T) {
+	g := newJSONSchemaTestGenerator(t, "easyjson")
+	var buf bytes.Buffer
+	assert.NoError(t, g.GenerateOpenAPI(&buf))
+
+	assert.Contains(t, buf.String(), `"openapi": "3.1.0",`)
+	assert.Contains(t, buf.String(), `"$ref": "#/components/schemas/Customer"`)
+	assert.Contains(t, buf.String(), `"x-db-column": "gender"`)
+}
@@ -0,0 +1,129 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlgen
+
+import (
+	"strings"
+
+	"github.com/corestoreio/pkg/util/codegen"
+)
+
+// fnTimestamps writes the code which sets the created and updated timestamp
+// columns. withCreatedAt sets the created column only if it is empty. If
+// isCollection is true, all entities of the collection `cc` get the same
+// timestamp, otherwise the entity `e`.
+func (t *Table) fnTimestamps(mainGen *codegen.Go, g *Generator, dmlEnabled, withCreatedAt, isCollection bool) {
+	if !dmlEnabled || (t.updatedAtColumn == nil && (!withCreatedAt || t.createdAtColumn == nil)) {
+		return
+	}
+	mainGen.Pln(`if !qo.SkipTimestamps {`)
+	mainGen.In()
+	mainGen.Pln(`now := time.Now()`)
+	if isCollection {
+		mainGen.Pln(`for _, e := range cc.Data {`)
+		mainGen.In()
+	}
+	if c := t.createdAtColumn; withCreatedAt && c != nil {
+		field := `e.` + t.GoCamelMaybePrivate(c.Field)
+		if strings.HasPrefix(g.goTypeNull(c), "null.") {
+			mainGen.Pln(`if !`+field+`.Valid {`, field, `= null.MakeTime(now) }`)
+		} else {
+			mainGen.Pln(`if `+field+`.IsZero() {`, field, `= now }`)
+		}
+	}
+	if c := t.updatedAtColumn; c != nil {
+		field := `e.` + t.GoCamelMaybePrivate(c.Field)
+		if strings.HasPrefix(g.goTypeNull(c), "null.") {
+			mainGen.Pln(field, `= null.MakeTime(now)`)
+		} else {
+			mainGen.Pln(field, `= now`)
+		}
+	}
+	if isCollection {
+		mainGen.Out()
+		mainGen.Pln(`}`)
+	}
+	mainGen.Out()
+	mainGen.Pln(`}`)
+}
+
+// updateCacheKey writes, for tables with a version column, the variable
+// cacheKey which switches to the query without the version check if
+// QueryOptions.SkipVersion has been set. It returns the Go expression of the
+// cache key.
+func (t *Table) updateCacheKey(mainGen *codegen.Go, dmlEnabled bool, entityFuncName []byte) []byte {
+	if t.versionColumn == nil {
+		return codegen.SkipWS(`"`, entityFuncName, `"`)
+	}
+	mainGen.Pln(dmlEnabled, `cacheKey := `, codegen.SkipWS(`"`, entityFuncName, `"`), `
+	if qo.SkipVersion {
+		cacheKey = `, codegen.SkipWS(`"`, entityFuncName, `SkipVersion"`), `
+	}`)
+	return []byte(`cacheKey`)
+}
+
+// fnVersionCheck writes the optimistic locking check for the result `res` of
+// the entity in variable varName. returnPrefix gets prepended to the returned
+// error, e.g. for multiple return values.
+func (t *Table) fnVersionCheck(mainGen *codegen.Go, dmlEnabled bool, varName, returnPrefix string) {
+	if t.versionColumn == nil {
+		return
+	}
+	mainGen.Pln(dmlEnabled, `if !qo.SkipVersion {
+		if rowCount, err := res.RowsAffected(); err != nil {
+			return `, returnPrefix, `errors.WithStack(err)
+		} else if rowCount == 0 {
+			return `, returnPrefix, `&dml.ConflictError{Table: `, constTableName(t.Table.Name), `, Version: `, varName+`.`+t.GoCamelMaybePrivate(t.versionColumn.Field), `}
+		}
+	}`)
+}
+
+// fnVersionIncrement writes the increment of the version field after a
+// successful UPDATE because the database increments the version itself.
+func (t *Table) fnVersionIncrement(mainGen *codegen.Go, dmlEnabled bool, varName string) {
+	if t.versionColumn == nil {
+		return
+	}
+	mainGen.Pln(dmlEnabled, `if !qo.SkipVersion {`, varName+`.`+t.GoCamelMaybePrivate(t.versionColumn.Field)+`++ }`)
+}
+
+// hasUpsert reports whether the Upsert functions and the UpsertByPK query get
+// generated. Tables with a version column have none, because INSERT ... ON
+// DUPLICATE KEY UPDATE writes the version of the client without checking it
+// and would bypass the optimistic locking.
+func (t *Table) hasUpsert(g *Generator, f FeatureToggle) bool {
+	return t.versionColumn == nil && t.hasFeature(g, f)
+}
+
+// fnDBMOptionsUpdateQueries writes the UPDATE queries for tables with a
+// version column. The default query checks and increments the version. The
+// SkipVersion query writes the version of the entity unchanged.
+func (t *Table) fnDBMOptionsUpdateQueries(mainGen *codegen.Go, g *Generator, pkWhereEQ string) {
+	enabled := t.hasFeature(g, FeatureDBUpdate|FeatureEntityStruct|FeatureCollectionStruct)
+	if t.versionColumn == nil {
+		mainGen.Pln(enabled,
+			codegen.SkipWS(`"`, t.EntityName(), `UpdateByPK"`),
+			`: dbmo.InitUpdateFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Update().Where(`, pkWhereEQ, `)),`)
+		return
+	}
+	updateVersioned := `).UpdateVersioned(` + "`" + t.versionColumn.Field + "`" + `).Where(`
+	mainGen.Pln(enabled,
+		codegen.SkipWS(`"`, t.EntityName(), `UpdateByPK"`),
+		`: dbmo.InitUpdateFn(tbls.MustTable(`, constTableName(t.Table.Name), updateVersioned, pkWhereEQ,
+		"dml.Column(`"+t.versionColumn.Field+"`).Equal().PlaceHolder(),\n", `)),`)
+	mainGen.Pln(enabled,
+		codegen.SkipWS(`"`, t.EntityName(), `UpdateByPKSkipVersion"`),
+		`: dbmo.InitUpdateFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Update().Where(`, pkWhereEQ, `)),`)
+}
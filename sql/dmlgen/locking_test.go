@@ -0,0 +1,103 @@
+package dmlgen
+
+import (
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/codegen"
+)
+
+func newLockingTestColumns() ddl.Columns {
+	return ddl.Columns{
+		&ddl.Column{Field: "entity_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+		&ddl.Column{Field: "name", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(255)"},
+		&ddl.Column{Field: "version", Pos: 3, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned"},
+		&ddl.Column{Field: "created_at", Pos: 4, Null: "NO", DataType: "timestamp", ColumnType: "timestamp"},
+		&ddl.Column{Field: "updated_at", Pos: 5, Null: "YES", DataType: "datetime", ColumnType: "datetime"},
+		&ddl.Column{Field: "price", Pos: 6, Null: "NO", DataType: "decimal", ColumnType: "decimal(12,4)"},
+	}
+}
+
+func newLockingTestGenerator(t *testing.T) *Generator {
+	g, err := NewGenerator("dmltestlocking",
+		WithTable("locked_entity", newLockingTestColumns()),
+		WithTableConfig("locked_entity", &TableConfig{
+			VersionColumn:   "version",
+			CreatedAtColumn: "created_at",
+			UpdatedAtColumn: "updated_at",
+		}),
+	)
+	assert.NoError(t, err)
+	return g
+}
+
+func TestTableConfig_VersionAndTimestamps(t *testing.T) {
+	t.Run("invalid version column", func(t *testing.T) {
+		for _, col := range []string{"name", "updated_at", "price", "not_found"} {
+			_, err := NewGenerator("dmltestlocking",
+				WithTable("locked_entity", newLockingTestColumns()),
+				WithTableConfig("locked_entity", &TableConfig{VersionColumn: col}),
+			)
+			assert.ErrorIsKind(t, errors.NotValid, err)
+		}
+	})
+	t.Run("invalid timestamp column", func(t *testing.T) {
+		_, err := NewGenerator("dmltestlocking",
+			WithTable("locked_entity", newLockingTestColumns()),
+			WithTableConfig("locked_entity", &TableConfig{UpdatedAtColumn: "version"}),
+		)
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	})
+	t.Run("valid", func(t *testing.T) {
+		g := newLockingTestGenerator(t)
+		tbl := g.Tables["locked_entity"]
+		assert.Exactly(t, "version", tbl.versionColumn.Field)
+		assert.Exactly(t, "created_at", tbl.createdAtColumn.Field)
+		assert.Exactly(t, "updated_at", tbl.updatedAtColumn.Field)
+	})
+}
+
+func TestTable_fnEntityDBMHandler_Locking(t *testing.T) {
+	g := newLockingTestGenerator(t)
+
+	mainGen := codegen.NewGo("dmltestlocking")
+	g.Tables["locked_entity"].fnEntityDBMHandler(mainGen, g)
+	code := formatPreloadTestCode(t, mainGen)
+
+	assert.Contains(t, code, "if !qo.SkipTimestamps {\n\t\tnow := time.Now()\n\t\tif e.CreatedAt.IsZero() {\n\t\t\te.CreatedAt = now\n\t\t}\n\t\te.UpdatedAt = null.MakeTime(now)\n\t}")
+	assert.Contains(t, code, `cacheKey := "LockedEntityUpdateByPK"`)
+	assert.Contains(t, code, `cacheKey = "LockedEntityUpdateByPKSkipVersion"`)
+	assert.Contains(t, code, `return nil, &dml.ConflictError{Table: TableNameLockedEntity, Version: e.Version}`)
+	assert.Contains(t, code, "if !qo.SkipVersion {\n\t\te.Version++\n\t}")
+	assert.NotContains(t, code, "Upsert(")
+}
+
+func TestTable_fnCollectionDBMHandler_Locking(t *testing.T) {
+	g := newLockingTestGenerator(t)
+
+	mainGen := codegen.NewGo("dmltestlocking")
+	g.Tables["locked_entity"].fnCollectionDBMHandler(mainGen, g)
+	code := formatPreloadTestCode(t, mainGen)
+
+	assert.Contains(t, code, "for _, e := range cc.Data {\n\t\t\tif e.CreatedAt.IsZero() {")
+	assert.Contains(t, code, `dbr := dbm.ConnPool.WithCacheKey(cacheKey, opts...)`)
+	assert.Contains(t, code, `return &dml.ConflictError{Table: TableNameLockedEntity, Version: c.Version}`)
+	assert.Contains(t, code, "if !qo.SkipVersion {\n\t\t\tc.Version++\n\t\t}")
+	assert.NotContains(t, code, "DBUpsert(")
+}
+
+func TestTable_fnDBMOptionsUpdateQueries(t *testing.T) {
+	g := newLockingTestGenerator(t)
+
+	mainGen := codegen.NewGo("dmltestlocking")
+	mainGen.Pln(`var _ = map[string]dml.QueryBuilder{`)
+	g.Tables["locked_entity"].fnDBMOptionsSQLBuildQueries(mainGen, g)
+	mainGen.Pln(`}`)
+	code := formatPreloadTestCode(t, mainGen)
+
+	assert.Contains(t, code, "\"LockedEntityUpdateByPK\": dbmo.InitUpdateFn(tbls.MustTable(TableNameLockedEntity).UpdateVersioned(`version`).Where(\n\t\tdml.Column(`entity_id`).Equal().PlaceHolder(),\n\t\tdml.Column(`version`).Equal().PlaceHolder(),")
+	assert.Contains(t, code, "\"LockedEntityUpdateByPKSkipVersion\": dbmo.InitUpdateFn(tbls.MustTable(TableNameLockedEntity).Update().Where(\n\t\tdml.Column(`entity_id`).Equal().PlaceHolder(),\n\t)),")
+	assert.NotContains(t, code, "LockedEntityUpsertByPK")
+}
@@ -59,6 +59,8 @@ func WithTableConfig(tableName string, opt *TableConfig) (o Option) {
 		opt.applyComments(t)
 		opt.applyColumnAliases(t)
 		opt.applyUniquifiedColumns(t)
+		opt.applyVersionAndTimestamps(t, g)
+		opt.applySoftDelete(t)
 		t.featuresInclude = opt.FeaturesInclude | g.defaultTableConfig.FeaturesInclude
 		t.featuresExclude = opt.FeaturesExclude | g.defaultTableConfig.FeaturesExclude
 		t.fieldMapFn = opt.FieldMapFn
@@ -309,6 +311,73 @@ func WithProtobuf(sc *SerializerConfig) (opt Option) {
 	return opt
 }
 
+// WithFlatbuffers enables FlatBuffers as a serialization method. In contrast
+// to protocol buffers, the Go types keep their width, e.g. a smallint column
+// stays an int16. GenerateSerializer writes the .fbs schema and GenerateGo
+// adds the FlatBuffers encoding methods to the entities and collections of
+// the tables with the encoder "fbs". Those methods do not depend on code
+// generated by flatc, only on the FlatBuffers Go runtime.
+func WithFlatbuffers(sc *SerializerConfig) (opt Option) {
+	_, pkg := filepath.Split(sc.PackageImportPath)
+
+	opt.sortOrder = 110
+	opt.fn = func(g *Generator) error {
+		g.Serializer = "fbs"
+		g.PackageSerializer = pkg
+		g.PackageSerializerImportPath = sc.PackageImportPath
+		g.SerializerHeaderOptions = append(g.SerializerHeaderOptions, sc.AdditionalHeaders...)
+		return nil
+	}
+	return opt
+}
+
+// GRPCServiceConfig applies optional settings to WithGRPCService.
+type GRPCServiceConfig struct {
+	// HTTPPathPrefix gets prepended to the REST paths of the grpc-gateway
+	// annotations. Default: /api/v1
+	HTTPPathPrefix string
+	// DefaultListLimit defines the amount of rows returned by a List call if
+	// the request does not specify a limit. Default: 100
+	DefaultListLimit uint64
+	// MaxListLimit caps the limit of a List request. Default: 1000
+	MaxListLimit uint64
+}
+
+// WithGRPCService generates for each table with a primary key a gRPC service
+// with Get, List, Create, Update and Delete and the batch variants. The service
+// definitions including the grpc-gateway annotations get written into the
+// .proto file and the server implementations with GenerateGRPCServer.
+// WithProtobuf must be set too. The feature FeatureGRPCService can exclude
+// tables. Argument sc can be nil.
+func WithGRPCService(sc *GRPCServiceConfig) (opt Option) {
+	opt.sortOrder = 111 // after WithProtobuf
+	opt.fn = func(g *Generator) error {
+		if g.Serializer != "protobuf" {
+			return errors.NotValid.Newf("[dmlgen] WithGRPCService requires the option WithProtobuf.")
+		}
+		cfg := GRPCServiceConfig{}
+		if sc != nil {
+			cfg = *sc
+		}
+		if cfg.HTTPPathPrefix == "" {
+			cfg.HTTPPathPrefix = "/api/v1"
+		}
+		cfg.HTTPPathPrefix = strings.TrimRight(cfg.HTTPPathPrefix, "/")
+		if cfg.DefaultListLimit == 0 {
+			cfg.DefaultListLimit = 100
+		}
+		if cfg.MaxListLimit == 0 {
+			cfg.MaxListLimit = 1000
+		}
+		if cfg.DefaultListLimit > cfg.MaxListLimit {
+			return errors.NotValid.Newf("[dmlgen] WithGRPCService: DefaultListLimit %d must be lower than MaxListLimit %d", cfg.DefaultListLimit, cfg.MaxListLimit)
+		}
+		g.grpcService = &cfg
+		return nil
+	}
+	return opt
+}
+
 // WithBuildTags adds your build tags to the file header. Each argument
 // represents a build tag line.
 func WithBuildTags(lines ...string) (opt Option) {
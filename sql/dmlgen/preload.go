@@ -0,0 +1,325 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlgen
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/codegen"
+	"github.com/corestoreio/pkg/util/strs"
+)
+
+// linkColumnName returns the column of the M:N link table which references
+// targetTable.targetColumn or an empty string if it can't be found.
+func (g *Generator) linkColumnName(linkTable, targetTable, targetColumn string) string {
+	kcuc, ok := g.kcu[linkTable]
+	if !ok {
+		return ""
+	}
+	for _, kcuce := range kcuc.Data {
+		if kcuce.ReferencedTableName.Data == targetTable && kcuce.ReferencedColumnName.Data == targetColumn {
+			return kcuce.ColumnName
+		}
+	}
+	return ""
+}
+
+// isManyToMany reports whether the relation uses a link table.
+func (rs relationShipInfo) isManyToMany() bool {
+	return rs.targetTableName != ""
+}
+
+// canPreload reports whether a batched loader can be generated for the
+// relation. All involved tables must be known to the generator and the key
+// columns must be usable as map keys.
+func (rs relationShipInfo) canPreload(g *Generator, parentTable string) bool {
+	if rs.parentColumnName == "" || g.Tables[parentTable] == nil || g.Tables[rs.tableName] == nil {
+		return false
+	}
+	if rs.isManyToMany() && (rs.linkTargetColumnName == "" || g.Tables[rs.targetTableName] == nil) {
+		return false
+	}
+	cols := []*ddl.Column{
+		g.findColumn(parentTable, rs.parentColumnName),
+		g.findColumn(rs.tableName, rs.columnName),
+	}
+	if rs.isManyToMany() {
+		cols = append(cols,
+			g.findColumn(rs.tableName, rs.linkTargetColumnName),
+			g.findColumn(rs.targetTableName, rs.targetColumnName),
+		)
+	}
+	for _, c := range cols {
+		if c == nil || strings.HasPrefix(g.mySQLToGoType(c, false), "[]") {
+			return false
+		}
+	}
+	return true
+}
+
+// preloadableRelationships returns all relations of the table for which a
+// preload function gets generated.
+func (t *Table) preloadableRelationships(g *Generator) []relationShipInfo {
+	// the preload functions query via the DBM type
+	if !g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureEntityRelationships) ||
+		!g.hasFeature(t.featuresInclude, t.featuresExclude, FeatureDB) {
+		return nil
+	}
+	var ret []relationShipInfo
+	for _, rs := range t.availableRelationships {
+		if rs.canPreload(g, t.Table.Name) {
+			ret = append(ret, rs)
+		}
+	}
+	return ret
+}
+
+// relationKeyExpr returns the Go expression which reads the column `from` of
+// the variable `varName` and converts it to the not null Go type of the column
+// `to`.
+func (g *Generator) relationKeyExpr(from, to *ddl.Column, varName string) string {
+	expr := varName + "." + strs.ToGoCamelCase(from.Field)
+	if from.IsNull() {
+		expr = varName + "." + g.toGoPrimitiveFromNull(from)
+	}
+	if toType := g.mySQLToGoType(to, false); g.mySQLToGoType(from, false) != toType {
+		expr = toType + "(" + expr + ")"
+	}
+	return expr
+}
+
+// relationKeyInvalid returns a condition which is true when a nullable key
+// column of the variable `varName` contains NULL. Returns an empty string for
+// not null columns.
+func relationKeyInvalid(c *ddl.Column, varName string) string {
+	if !c.IsNull() {
+		return ""
+	}
+	return "!" + varName + "." + strs.ToGoCamelCase(c.Field) + ".Valid"
+}
+
+// fnDBMOptionsPreloadQueries writes the queries used by the generated preload
+// functions. One query for 1:M and 1:1 relations and two queries for M:N
+// relations, one for the link table and one for the target table.
+func (t *Table) fnDBMOptionsPreloadQueries(mainGen *codegen.Go, g *Generator) {
+	for _, rs := range t.availableRelationships {
+		if !rs.canPreload(g, t.Table.Name) {
+			continue
+		}
+		if rs.isManyToMany() {
+			mainGen.Pln(codegen.SkipWS(`"`, rs.mappedStructFieldName, `SelectLinkByFKs"`),
+				`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(rs.tableName), `).Select("*").Where(`,
+				"\ndml.Column(`"+rs.columnName+"`).In().PlaceHolder(),\n", `)),`)
+			mainGen.Pln(codegen.SkipWS(`"`, rs.mappedStructFieldName, `SelectByFKs"`),
+				`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(rs.targetTableName), `).Select("*").Where(`,
+				"\ndml.Column(`"+rs.targetColumnName+"`).In().PlaceHolder(),\n", `)),`)
+			continue
+		}
+		mainGen.Pln(codegen.SkipWS(`"`, rs.mappedStructFieldName, `SelectByFKs"`),
+			`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(rs.tableName), `).Select("*").Where(`,
+			"\ndml.Column(`"+rs.columnName+"`).In().PlaceHolder(),\n", `)),`)
+	}
+}
+
+// fnCollectionPreload generates for each relation a Preload<Relation>
+// function which loads the relation for all entities of a collection with a
+// single IN query and distributes the rows to the Relations struct of each
+// entity. The Preload function dispatches dotted paths to the relation
+// functions and into the nested collections.
+func (t *Table) fnCollectionPreload(mainGen *codegen.Go, g *Generator) {
+	preloadable := t.preloadableRelationships(g)
+	if len(preloadable) == 0 {
+		return
+	}
+
+	collectionPTRName := codegen.SkipWS("*", t.CollectionName())
+
+	for _, rs := range preloadable {
+		parentCol := g.findColumn(t.Table.Name, rs.parentColumnName)
+		keyType := g.mySQLToGoType(parentCol, false)
+		childCollection := pluralize(rs.tableName)
+		if rs.isManyToMany() {
+			childCollection = pluralize(rs.targetTableName)
+		}
+
+		mainGen.C(`Preload`+rs.mappedStructFieldName, `loads the relation`, rs.mappedStructFieldName, `for all entities of the collection with one query and assigns the rows to the Relations field of each entity. It returns all loaded rows to allow further preloading. Auto generated.`)
+		mainGen.Pln(`func (cc `, collectionPTRName, `) `, codegen.SkipWS("Preload", rs.mappedStructFieldName), `(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (_ *`, childCollection, `, err error) {`)
+		mainGen.In()
+		mainGen.Pln(`if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+			return nil, nil
+		}`)
+		mainGen.Pln(`parents := make(map[`, keyType, `][]*`, t.EntityName(), `, len(cc.Data))`)
+		mainGen.Pln(`keys := make([]`, keyType, `, 0, len(cc.Data))`)
+		mainGen.Pln(`for _, e := range cc.Data {`)
+		{
+			mainGen.In()
+			mainGen.Pln(`if e.Relations == nil {
+				e.NewRelations()
+			}`)
+			mainGen.Pln(`e.setRelationParent()`)
+			if rs.isOneToOne {
+				mainGen.Pln(`e.Relations.`+rs.mappedStructFieldName, ` = nil`)
+			} else {
+				mainGen.Pln(`e.Relations.`+rs.mappedStructFieldName, ` = &`, childCollection, `{}`)
+			}
+			if cond := relationKeyInvalid(parentCol, "e"); cond != "" {
+				mainGen.Pln(`if `, cond, ` {
+					continue
+				}`)
+			}
+			mainGen.Pln(`k := `, g.relationKeyExpr(parentCol, parentCol, "e"))
+			mainGen.Pln(`if _, ok := parents[k]; !ok {
+				keys = append(keys, k)
+			}`)
+			mainGen.Pln(`parents[k] = append(parents[k], e)`)
+			mainGen.Out()
+		}
+		mainGen.Pln(`}`)
+		mainGen.Pln(`children := &`, childCollection, `{}`)
+		mainGen.Pln(`if len(keys) == 0 {
+			return children, nil
+		}`)
+
+		// the map which gets used to find the parents of a loaded row.
+		childParents := "parents"
+		childKeyCol := g.findColumn(rs.tableName, rs.columnName)
+		childKeyTo := parentCol
+		if rs.isManyToMany() {
+			linkCol := g.findColumn(rs.tableName, rs.columnName)
+			linkTargetCol := g.findColumn(rs.tableName, rs.linkTargetColumnName)
+			targetCol := g.findColumn(rs.targetTableName, rs.targetColumnName)
+			targetKeyType := g.mySQLToGoType(targetCol, false)
+
+			mainGen.Pln(`links := &`, pluralize(rs.tableName), `{}`)
+			mainGen.Pln(`if _, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, rs.mappedStructFieldName, `SelectLinkByFKs"`), `, opts...).Load(ctx, links, keys); err != nil {
+				return nil, errors.WithStack(err)
+			}`)
+			mainGen.Pln(`targetParents := make(map[`, targetKeyType, `][]*`, t.EntityName(), `, len(links.Data))`)
+			mainGen.Pln(`targetKeys := make([]`, targetKeyType, `, 0, len(links.Data))`)
+			mainGen.Pln(`for _, l := range links.Data {`)
+			{
+				mainGen.In()
+				for _, c := range []*ddl.Column{linkCol, linkTargetCol} {
+					if cond := relationKeyInvalid(c, "l"); cond != "" {
+						mainGen.Pln(`if `, cond, ` {
+							continue
+						}`)
+					}
+				}
+				mainGen.Pln(`k := `, g.relationKeyExpr(linkTargetCol, targetCol, "l"))
+				mainGen.Pln(`if _, ok := targetParents[k]; !ok {
+					targetKeys = append(targetKeys, k)
+				}`)
+				mainGen.Pln(`targetParents[k] = append(targetParents[k], parents[`, g.relationKeyExpr(linkCol, parentCol, "l"), `]...)`)
+				mainGen.Out()
+			}
+			mainGen.Pln(`}`)
+			mainGen.Pln(`if len(targetKeys) == 0 {
+				return children, nil
+			}`)
+			mainGen.Pln(`if _, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, rs.mappedStructFieldName, `SelectByFKs"`), `, opts...).Load(ctx, children, targetKeys); err != nil {
+				return nil, errors.WithStack(err)
+			}`)
+			childParents = "targetParents"
+			childKeyCol = targetCol
+			childKeyTo = targetCol
+		} else {
+			mainGen.Pln(`if _, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, rs.mappedStructFieldName, `SelectByFKs"`), `, opts...).Load(ctx, children, keys); err != nil {
+				return nil, errors.WithStack(err)
+			}`)
+		}
+
+		mainGen.Pln(`for _, c := range children.Data {`)
+		{
+			mainGen.In()
+			if cond := relationKeyInvalid(childKeyCol, "c"); cond != "" {
+				mainGen.Pln(`if `, cond, ` {
+					continue
+				}`)
+			}
+			mainGen.Pln(`for _, e := range `, childParents, `[`, g.relationKeyExpr(childKeyCol, childKeyTo, "c"), `] {`)
+			if rs.isOneToOne {
+				mainGen.Pln(`e.Relations.`+rs.mappedStructFieldName, ` = c`)
+			} else {
+				mainGen.Pln(`e.Relations.` + rs.mappedStructFieldName + `.Data = append(e.Relations.` + rs.mappedStructFieldName + `.Data, c)`)
+			}
+			mainGen.Pln(`}`)
+			mainGen.Out()
+		}
+		mainGen.Pln(`}`)
+		mainGen.Pln(`return children, nil`)
+		mainGen.Out()
+		mainGen.Pln(`}`)
+	}
+
+	mainGen.C(`Preload loads the relations named in paths for all entities of the collection with one query per relation. A path can address nested relations separated by a dot, e.g. "` + preloadable[0].mappedStructFieldName + `.Name". Preloading gets skipped if dml.QueryOptions.SkipRelations has been set. Auto generated.`)
+	mainGen.Pln(`func (cc `, collectionPTRName, `) Preload(ctx context.Context, dbm *DBM, paths []string, opts ...dml.DBRFunc) error {`)
+	mainGen.In()
+	mainGen.Pln(`if cc == nil || len(cc.Data) == 0 || dml.FromContextQueryOptions(ctx).SkipRelations {
+		return nil
+	}`)
+	mainGen.Pln(`var names []string
+	nested := make(map[string][]string, len(paths))
+	for _, p := range paths {
+		name, rest, _ := strings.Cut(p, ".")
+		if _, ok := nested[name]; !ok {
+			names = append(names, name)
+			nested[name] = nil
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}`)
+	mainGen.Pln(`for _, name := range names {`)
+	mainGen.In()
+	mainGen.Pln(`switch name {`)
+	for _, rs := range preloadable {
+		childTable := rs.tableName
+		if rs.isManyToMany() {
+			childTable = rs.targetTableName
+		}
+		mainGen.Pln(`case `, strconv.Quote(rs.mappedStructFieldName), `:`)
+		mainGen.In()
+		if ct := g.Tables[childTable]; ct != nil && len(ct.preloadableRelationships(g)) > 0 {
+			mainGen.Pln(`children, err := cc.`, codegen.SkipWS("Preload", rs.mappedStructFieldName), `(ctx, dbm, opts...)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if len(nested[name]) > 0 {
+				if err := children.Preload(ctx, dbm, nested[name], opts...); err != nil {
+					return errors.WithStack(err)
+				}
+			}`)
+		} else {
+			mainGen.Pln(`if len(nested[name]) > 0 {
+				return errors.NotSupported.Newf("[`+t.Package+`] `+t.CollectionName()+`.Preload: relation %q has no nested relations", name)
+			}
+			if _, err := cc.`, codegen.SkipWS("Preload", rs.mappedStructFieldName), `(ctx, dbm, opts...); err != nil {
+				return errors.WithStack(err)
+			}`)
+		}
+		mainGen.Out()
+	}
+	mainGen.Pln(`default:
+		return errors.NotFound.Newf("[` + t.Package + `] ` + t.CollectionName() + `.Preload: relation %q not found", name)
+	}`)
+	mainGen.Out()
+	mainGen.Pln(`}`)
+	mainGen.Pln(`return nil`)
+	mainGen.Out()
+	mainGen.Pln(`}`)
+}
@@ -0,0 +1,115 @@
+package dmlgen
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/codegen"
+)
+
+func newPreloadTestGenerator(t *testing.T) *Generator {
+	g, err := NewGenerator("dmltestpreload",
+		WithTable("athlete", ddl.Columns{
+			&ddl.Column{Field: "athlete_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "lastname", Pos: 2, Null: "YES", DataType: "varchar", ColumnType: "varchar(340)"},
+		}),
+		WithTable("athlete_team", ddl.Columns{
+			&ddl.Column{Field: "team_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "name", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(340)"},
+		}),
+		WithTable("athlete_team_member", ddl.Columns{
+			&ddl.Column{Field: "id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "team_id", Pos: 2, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned"},
+			&ddl.Column{Field: "athlete_id", Pos: 3, Null: "YES", DataType: "int", ColumnType: "int(10) unsigned"},
+		}),
+	)
+	assert.NoError(t, err)
+
+	g.Tables["athlete"].availableRelationships = []relationShipInfo{
+		{
+			isCollection:          true,
+			tableName:             "athlete_team_member",
+			structName:            "AthleteTeamMembers",
+			mappedStructFieldName: "AthleteTeamMembers",
+			columnName:            "athlete_id",
+			parentColumnName:      "athlete_id",
+		},
+		{
+			isCollection:          true,
+			tableName:             "athlete_team_member",
+			structName:            "AthleteTeams",
+			mappedStructFieldName: "AthleteTeams",
+			columnName:            "athlete_id",
+			parentColumnName:      "athlete_id",
+			targetTableName:       "athlete_team",
+			targetColumnName:      "team_id",
+			linkTargetColumnName:  "team_id",
+		},
+	}
+	g.Tables["athlete_team"].availableRelationships = []relationShipInfo{
+		{
+			isCollection:          true,
+			tableName:             "athlete_team_member",
+			structName:            "Athletes",
+			mappedStructFieldName: "Athletes",
+			columnName:            "team_id",
+			parentColumnName:      "team_id",
+			targetTableName:       "athlete",
+			targetColumnName:      "athlete_id",
+			// empty linkTargetColumnName disables preloading
+		},
+	}
+	return g
+}
+
+func formatPreloadTestCode(t *testing.T, mainGen *codegen.Go) string {
+	code, err := format.Source(append([]byte("package dmltestpreload\n"), mainGen.Bytes()...))
+	assert.NoError(t, err, "%s", mainGen.String())
+	return string(code)
+}
+
+func TestTable_fnCollectionPreload(t *testing.T) {
+	g := newPreloadTestGenerator(t)
+
+	mainGen := codegen.NewGo("dmltestpreload")
+	g.Tables["athlete"].fnCollectionPreload(mainGen, g)
+	code := formatPreloadTestCode(t, mainGen)
+
+	assert.Contains(t, code, `func (cc *Athletes) PreloadAthleteTeamMembers(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (_ *AthleteTeamMembers, err error) {`)
+	assert.Contains(t, code, `dml.FromContextQueryOptions(ctx).SkipRelations`)
+	assert.Contains(t, code, `parents := make(map[uint32][]*Athlete, len(cc.Data))`)
+	// nullable FK column in the child table
+	assert.Contains(t, code, `if !c.AthleteID.Valid {`)
+	assert.Contains(t, code, `for _, e := range parents[c.AthleteID.Uint32] {`)
+
+	// M:N via the link table
+	assert.Contains(t, code, `func (cc *Athletes) PreloadAthleteTeams(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (_ *AthleteTeams, err error) {`)
+	assert.Contains(t, code, `WithCacheKey("AthleteTeamsSelectLinkByFKs", opts...).Load(ctx, links, keys)`)
+	assert.Contains(t, code, `targetParents[k] = append(targetParents[k], parents[l.AthleteID.Uint32]...)`)
+	assert.Contains(t, code, `WithCacheKey("AthleteTeamsSelectByFKs", opts...).Load(ctx, children, targetKeys)`)
+
+	// athlete_team can't preload, hence no nested paths for AthleteTeams.
+	assert.Contains(t, code, `func (cc *Athletes) Preload(ctx context.Context, dbm *DBM, paths []string, opts ...dml.DBRFunc) error {`)
+	assert.Contains(t, code, `relation %q has no nested relations`)
+
+	mainGen = codegen.NewGo("dmltestpreload")
+	g.Tables["athlete_team"].fnCollectionPreload(mainGen, g)
+	assert.Exactly(t, "", mainGen.String())
+}
+
+func TestTable_fnDBMOptionsPreloadQueries(t *testing.T) {
+	g := newPreloadTestGenerator(t)
+
+	mainGen := codegen.NewGo("dmltestpreload")
+	mainGen.Pln(`var _ = map[string]dml.QueryBuilder{`)
+	g.Tables["athlete"].fnDBMOptionsPreloadQueries(mainGen, g)
+	mainGen.Pln(`}`)
+	code := formatPreloadTestCode(t, mainGen)
+
+	assert.Contains(t, code, `"AthleteTeamMembersSelectByFKs": dbmo.InitSelectFn(tbls.MustTable(TableNameAthleteTeamMember)`)
+	assert.Contains(t, code, `"AthleteTeamsSelectLinkByFKs": dbmo.InitSelectFn(tbls.MustTable(TableNameAthleteTeamMember)`)
+	assert.Contains(t, code, `"AthleteTeamsSelectByFKs": dbmo.InitSelectFn(tbls.MustTable(TableNameAthleteTeam)`)
+	assert.Contains(t, code, "dml.Column(`team_id`).In().PlaceHolder(),")
+}
@@ -208,8 +208,8 @@ func RunProtoc(protoFilesPath string, po *ProtocOptions) error {
 	return nil
 }
 
-// GenerateSerializer writes the protocol buffer specifications into `w` and its test
-// sources into wTest, if there are any tests.
+// GenerateSerializer writes the protocol buffer or FlatBuffers specifications
+// into `w` and its test sources into wTest, if there are any tests.
 func (g *Generator) GenerateSerializer(wMain, wTest io.Writer) error {
 	switch g.Serializer {
 	case "protobuf":
@@ -217,7 +217,9 @@ func (g *Generator) GenerateSerializer(wMain, wTest io.Writer) error {
 			return errors.WithStack(err)
 		}
 	case "fbs":
-		panic("not yet supported")
+		if err := g.generateFlatbuffers(wMain); err != nil {
+			return errors.WithStack(err)
+		}
 	case "", "default", "none":
 		return nil // do nothing
 	default:
@@ -233,6 +235,10 @@ func (g *Generator) generateProto(w io.Writer) error {
 	const importTimeStamp = `import "google/protobuf/timestamp.proto";`
 	proto.Pln(importTimeStamp)
 	proto.Pln(`import "github.com/corestoreio/pkg/storage/null/null.proto";`)
+	if g.grpcService != nil {
+		proto.Pln(`import "google/api/annotations.proto";`)
+		proto.Pln(`import "google/protobuf/empty.proto";`)
+	}
 	var hasGoPackageOption bool
 	for _, o := range g.SerializerHeaderOptions {
 		proto.Pln(`option ` + o + `;`)
@@ -387,6 +393,10 @@ func (g *Generator) generateProto(w io.Writer) error {
 			proto.Out()
 		}
 		proto.Pln(`}`)
+
+		if g.hasGRPCService(t) {
+			g.protoGRPCService(proto, t)
+		}
 	}
 
 	if !hasTimestampField {
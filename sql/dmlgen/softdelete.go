@@ -0,0 +1,241 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlgen
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/codegen"
+	"github.com/corestoreio/pkg/util/strs"
+)
+
+// softDeleteQuerySuffixes must match the suffixes of
+// dml.QueryOptions.SoftDeleteCacheKey.
+var softDeleteQuerySuffixes = [...]string{"", "WithDeleted", "OnlyDeleted"}
+
+func (t *Table) hasSoftDelete() bool {
+	return t.softDeleteColumn != nil || t.systemVersioned
+}
+
+// softDeleteCacheKey wraps the Go expression of a SELECT cache key into
+// qo.SoftDeleteCacheKey to switch to the queries which include soft deleted
+// rows.
+func (t *Table) softDeleteCacheKey(cacheKey []byte) []byte {
+	if !t.hasSoftDelete() {
+		return cacheKey
+	}
+	return codegen.SkipWS(`qo.SoftDeleteCacheKey(`, cacheKey, `)`)
+}
+
+// softDeleteSelect returns the Go code of the SELECT statement and its
+// additional WHERE conditions for the soft delete query suffix.
+func (t *Table) softDeleteSelect(suffix string) (selectCode, whereCode string) {
+	selectCode = `tbls.MustTable(` + constTableName(t.Table.Name) + `).Select("*")`
+	switch {
+	case t.softDeleteColumn != nil && suffix == "":
+		whereCode = "dml.Column(`" + t.softDeleteColumn.Field + "`).Null(),\n"
+	case t.softDeleteColumn != nil && suffix == "OnlyDeleted":
+		whereCode = "dml.Column(`" + t.softDeleteColumn.Field + "`).NotNull(),\n"
+	case t.systemVersioned && suffix != "":
+		selectCode += `.ForSystemTimeAll()`
+		pkFields := t.Table.Columns.PrimaryKeys().FieldNames()
+		whereCode = "dml.Expr(" + strconv.Quote(t.systemVersionedLatest(pkFields)) + "),\n"
+		if suffix == "OnlyDeleted" {
+			// rows which do not exist anymore in the current table
+			if len(pkFields) == 1 {
+				pkName := "`" + pkFields[0] + "`"
+				whereCode += "dml.Column(" + pkName + ").NotIn().Sub(dml.NewSelect(" + pkName + ").From(" +
+					constTableName(t.Table.Name) + ")),\n"
+			} else {
+				// dml.Columns can't be used with a sub select.
+				pkNames := "`" + strings.Join(pkFields, "`, `") + "`"
+				whereCode += "dml.Expr(\"(" + pkNames + ") NOT IN (SELECT " + pkNames + " FROM `" + t.Table.Name + "`)\"),\n"
+			}
+		}
+	}
+	return selectCode, whereCode
+}
+
+// softDeleteWhere wraps the WHERE conditions of softDeleteSelect into a call
+// of the Where function.
+func softDeleteWhere(whereCode string) string {
+	if whereCode == "" {
+		return ""
+	}
+	return ".Where(\n" + whereCode + ")"
+}
+
+// systemVersionedLatest returns the WHERE condition which restricts FOR
+// SYSTEM_TIME ALL to the latest version of each primary key. Without it the
+// query returns every historical version of a row.
+func (t *Table) systemVersionedLatest(pkFields []string) string {
+	rowEnd := "ROW_END"
+	for _, c := range t.Table.Columns {
+		if c.GenerationExpression.Data == "ROW END" {
+			rowEnd = c.Field
+		}
+	}
+	var buf strings.Builder
+	buf.WriteString("`" + ddl.MainTable + "`.`" + rowEnd + "` = (SELECT MAX(`h`.`" + rowEnd + "`) FROM `" +
+		t.Table.Name + "` FOR SYSTEM_TIME ALL AS `h` WHERE ")
+	for i, pk := range pkFields {
+		if i > 0 {
+			buf.WriteString(" AND ")
+		}
+		buf.WriteString("`h`.`" + pk + "` = `" + ddl.MainTable + "`.`" + pk + "`")
+	}
+	buf.WriteString(")")
+	return buf.String()
+}
+
+// fnDBMOptionsSelectQueries writes the SELECT queries. For tables with soft
+// deletion the default queries exclude the deleted rows and the WithDeleted
+// and OnlyDeleted variants get written.
+func (t *Table) fnDBMOptionsSelectQueries(mainGen *codegen.Go, g *Generator, tblPKLen int, pkWhereIN, pkWhereEQ string) {
+	suffixes := softDeleteQuerySuffixes[:1]
+	if t.hasSoftDelete() && !t.Table.IsView() {
+		suffixes = softDeleteQuerySuffixes[:]
+	}
+	for _, suffix := range suffixes {
+		selectCode, whereCode := t.softDeleteSelect(suffix)
+
+		mainGen.Pln(tblPKLen > 0 && t.hasFeature(g, FeatureDBSelect|FeatureCollectionStruct),
+			codegen.SkipWS(`"`, t.CollectionName(), `SelectAll`, suffix, `"`),
+			`: dbmo.InitSelectFn(`, selectCode, `)`, softDeleteWhere(whereCode), `,`)
+
+		mainGen.Pln(tblPKLen > 0 && t.hasFeature(g, FeatureDBSelect|FeatureEntityStruct|FeatureCollectionStruct),
+			codegen.SkipWS(`"`, t.CollectionName(), `SelectByPK`, suffix, `"`),
+			`: dbmo.InitSelectFn(`, selectCode, `).Where(`, pkWhereIN, whereCode, `),`)
+
+		mainGen.Pln(tblPKLen > 0 && t.hasFeature(g, FeatureDBSelect|FeatureEntityStruct|FeatureCollectionStruct),
+			codegen.SkipWS(`"`, t.EntityName(), `SelectByPK`, suffix, `"`),
+			`: dbmo.InitSelectFn(`, selectCode, `).Where(`, pkWhereEQ, whereCode, `),`)
+	}
+}
+
+// fnDBMOptionsDeleteQueries writes the DELETE query or for a soft delete
+// column the UPDATE queries for deletion and restoring.
+func (t *Table) fnDBMOptionsDeleteQueries(mainGen *codegen.Go, g *Generator, pkWhereIN string) {
+	enabled := t.hasFeature(g, FeatureDBDelete|FeatureEntityStruct|FeatureCollectionStruct)
+	if t.softDeleteColumn == nil {
+		mainGen.Pln(enabled,
+			codegen.SkipWS(`"`, t.EntityName(), `DeleteByPK"`),
+			`: dbmo.InitDeleteFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Delete().Where(`, pkWhereIN, `)),`)
+		return
+	}
+	col := "`" + t.softDeleteColumn.Field + "`"
+	mainGen.Pln(enabled,
+		codegen.SkipWS(`"`, t.EntityName(), `DeleteByPK"`),
+		`: dbmo.InitUpdateFn(tbls.MustTable(`, constTableName(t.Table.Name), `).SoftDelete(`, col, `).Where(`, pkWhereIN,
+		"dml.Column(", col, ").Null(),\n", `)),`)
+	mainGen.Pln(enabled,
+		codegen.SkipWS(`"`, t.EntityName(), `RestoreByPK"`),
+		`: dbmo.InitUpdateFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Restore(`, col, `).Where(`, pkWhereIN, `)),`)
+}
+
+// restoreCacheKey returns the cache key of the query used to restore soft
+// deleted rows. System-versioned tables restore the row by inserting it again.
+func (t *Table) restoreCacheKey() []byte {
+	if t.systemVersioned {
+		return codegen.SkipWS(`"`, t.EntityName(), `Insert"`)
+	}
+	return codegen.SkipWS(`"`, t.EntityName(), `RestoreByPK"`)
+}
+
+// fnEntityRestore writes the Restore function which undeletes a soft deleted
+// entity.
+func (t *Table) fnEntityRestore(mainGen *codegen.Go, g *Generator) {
+	dmlEnabled := t.hasFeature(g, FeatureDBDelete)
+	if !dmlEnabled || !t.hasSoftDelete() || t.Table.IsView() {
+		return
+	}
+	entityEventName := codegen.SkipWS(`event`, t.EntityName(), `Func`)
+	funcName := codegen.SkipWS(t.EntityName(), "RestoreByPK")
+	// same arguments as the Delete function or the whole entity for an INSERT
+	args := "e"
+	if !t.systemVersioned {
+		pkArgs := make([]string, 0, 2)
+		t.Table.Columns.PrimaryKeys().Each(func(c *ddl.Column) {
+			pkArgs = append(pkArgs, "e."+strs.ToGoCamelCase(c.Field))
+		})
+		args = strings.Join(pkArgs, ",")
+	}
+
+	mainGen.C(`Restore undeletes a soft deleted entity. It triggers the update events.`)
+	mainGen.Pln(dmlEnabled, `func (e `, codegen.SkipWS("*", t.EntityName()), `) Restore(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result, err error) {`)
+	mainGen.Pln(dmlEnabled && t.hasFeature(g, FeatureDBTracing), `	ctx, span := dbm.option.Trace.Start(ctx, `, codegen.SkipWS(`"`, funcName, `"`), `)
+			defer func(){ cstrace.Status(span, err, ""); span.End(); }()`)
+	mainGen.Pln(dmlEnabled, `if e == nil {
+		return nil, errors.NotValid.Newf(`, codegen.SkipWS(`"`, t.EntityName()), `can't be nil")
+	}`)
+	mainGen.Pln(dmlEnabled, `qo := dml.FromContextQueryOptions(ctx)`)
+	mainGen.Pln(dmlEnabled, `if err = dbm.`, entityEventName, `(ctx, dml.EventFlagBeforeUpdate, qo.SkipEvents, nil, e); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if res, err = dbm.ConnPool.WithCacheKey(`, t.restoreCacheKey(), `, opts...).ExecContext(ctx, `, args, `); err != nil {
+			return nil, errors.WithStack(err)
+		}`)
+	if c := t.softDeleteColumn; c != nil {
+		mainGen.Pln(dmlEnabled, `e.`+t.GoCamelMaybePrivate(c.Field), `=`, g.goTypeNull(c)+`{}`)
+	}
+	mainGen.Pln(dmlEnabled, `if err = dbm.`, entityEventName, `(ctx, dml.EventFlagAfterUpdate, qo.SkipEvents, nil, e); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return res, nil
+	}`)
+}
+
+// fnCollectionDBRestore writes the DBRestore function which undeletes all soft
+// deleted entities of a collection.
+func (t *Table) fnCollectionDBRestore(mainGen *codegen.Go, g *Generator) {
+	dmlEnabled := t.hasFeature(g, FeatureDBDelete)
+	if !dmlEnabled || !t.hasSoftDelete() || t.Table.IsView() {
+		return
+	}
+	entityEventName := codegen.SkipWS(`event`, t.EntityName(), `Func`)
+
+	mainGen.C(`DBRestore undeletes all soft deleted entities. It triggers the update events.`)
+	mainGen.Pln(dmlEnabled, `func (cc `, codegen.SkipWS("*", t.CollectionName()), `) DBRestore(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result, err error) {`)
+	mainGen.Pln(dmlEnabled && t.hasFeature(g, FeatureDBTracing), `	ctx, span := dbm.option.Trace.Start(ctx, `, codegen.SkipWS(`"`, t.CollectionName(), "RestoreByPK", `"`), `)
+			defer func(){ cstrace.Status(span, err, ""); span.End(); }()`)
+	mainGen.Pln(dmlEnabled, `if cc == nil {
+		return nil, errors.NotValid.Newf(`, codegen.SkipWS(`"`, t.CollectionName()), `can't be nil")
+	}`)
+	mainGen.Pln(dmlEnabled, `qo := dml.FromContextQueryOptions(ctx)`)
+	var args []byte
+	if t.systemVersioned {
+		args = []byte(`cc`)
+	} else {
+		args = []byte(`dml.Qualify("", cc)`)
+	}
+	mainGen.Pln(dmlEnabled, `if err = dbm.`, entityEventName, `(ctx, dml.EventFlagBeforeUpdate, qo.SkipEvents, cc, nil); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if res, err = dbm.ConnPool.WithCacheKey(`, t.restoreCacheKey(), `, opts...).ExecContext(ctx, `, args, `); err != nil {
+			return nil, errors.WithStack(err)
+		}`)
+	if c := t.softDeleteColumn; c != nil {
+		mainGen.Pln(dmlEnabled, `for _, e := range cc.Data {
+			e.`+t.GoCamelMaybePrivate(c.Field), `=`, g.goTypeNull(c)+`{}
+		}`)
+	}
+	mainGen.Pln(dmlEnabled, `if err = dbm.`, entityEventName, `(ctx, dml.EventFlagAfterUpdate, qo.SkipEvents, cc, nil); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return res, nil
+	}`)
+}
@@ -0,0 +1,197 @@
+package dmlgen
+
+import (
+	"context"
+	"testing"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/codegen"
+)
+
+func newSoftDeleteTestColumns() ddl.Columns {
+	return ddl.Columns{
+		&ddl.Column{Field: "entity_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+		&ddl.Column{Field: "name", Pos: 2, Null: "NO", DataType: "varchar", ColumnType: "varchar(255)"},
+		&ddl.Column{Field: "deleted_at", Pos: 3, Null: "YES", DataType: "datetime", ColumnType: "datetime"},
+		&ddl.Column{Field: "created_at", Pos: 4, Null: "NO", DataType: "timestamp", ColumnType: "timestamp"},
+	}
+}
+
+func newSoftDeleteTestGenerator(t *testing.T, tc *TableConfig) *Generator {
+	g, err := NewGenerator("dmltestsoftdelete",
+		WithTable("soft_entity", newSoftDeleteTestColumns()),
+		WithTableConfig("soft_entity", tc),
+	)
+	assert.NoError(t, err)
+	return g
+}
+
+func TestTableConfig_SoftDelete(t *testing.T) {
+	for _, tc := range []*TableConfig{
+		{SoftDeleteColumn: "name"},
+		{SoftDeleteColumn: "created_at"}, // NOT NULL
+		{SoftDeleteColumn: "not_found"},
+		{SoftDeleteColumn: "deleted_at", SystemVersioned: true},
+	} {
+		_, err := NewGenerator("dmltestsoftdelete",
+			WithTable("soft_entity", newSoftDeleteTestColumns()),
+			WithTableConfig("soft_entity", tc),
+		)
+		assert.ErrorIsKind(t, errors.NotValid, err)
+	}
+}
+
+func TestTable_SoftDeleteColumn(t *testing.T) {
+	g := newSoftDeleteTestGenerator(t, &TableConfig{SoftDeleteColumn: "deleted_at"})
+	tbl := g.Tables["soft_entity"]
+
+	t.Run("DBMOptions", func(t *testing.T) {
+		mainGen := codegen.NewGo("dmltestsoftdelete")
+		mainGen.Pln(`var _ = map[string]dml.QueryBuilder{`)
+		tbl.fnDBMOptionsSQLBuildQueries(mainGen, g)
+		mainGen.Pln(`}`)
+		code := formatPreloadTestCode(t, mainGen)
+
+		assert.Contains(t, code, "\"SoftEntitiesSelectAll\": dbmo.InitSelectFn(tbls.MustTable(TableNameSoftEntity).Select(\"*\")).Where(\n\t\tdml.Column(`deleted_at`).Null(),\n\t),")
+		assert.Contains(t, code, "\"SoftEntitiesSelectAllWithDeleted\": dbmo.InitSelectFn(tbls.MustTable(TableNameSoftEntity).Select(\"*\")),")
+		assert.Contains(t, code, "\"SoftEntitySelectByPKOnlyDeleted\": dbmo.InitSelectFn(tbls.MustTable(TableNameSoftEntity).Select(\"*\")).Where(\n\t\tdml.Column(`entity_id`).Equal().PlaceHolder(),\n\t\tdml.Column(`deleted_at`).NotNull(),\n\t),")
+		assert.Contains(t, code, "\"SoftEntityDeleteByPK\": dbmo.InitUpdateFn(tbls.MustTable(TableNameSoftEntity).SoftDelete(`deleted_at`).Where(\n\t\tdml.Column(`entity_id`).In().PlaceHolder(),\n\t\tdml.Column(`deleted_at`).Null(),\n\t)),")
+		assert.Contains(t, code, "\"SoftEntityRestoreByPK\": dbmo.InitUpdateFn(tbls.MustTable(TableNameSoftEntity).Restore(`deleted_at`).Where(\n\t\tdml.Column(`entity_id`).In().PlaceHolder(),\n\t)),")
+	})
+
+	t.Run("Entity", func(t *testing.T) {
+		mainGen := codegen.NewGo("dmltestsoftdelete")
+		tbl.fnEntityDBMHandler(mainGen, g)
+		code := formatPreloadTestCode(t, mainGen)
+
+		assert.Contains(t, code, `WithCacheKey(qo.SoftDeleteCacheKey("SoftEntitySelectByPK"), opts...).Load(ctx, e, primaryKey)`)
+		assert.Contains(t, code, `func (e *SoftEntity) Restore(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result, err error) {`)
+		assert.Contains(t, code, `WithCacheKey("SoftEntityRestoreByPK", opts...).ExecContext(ctx, e.EntityID)`)
+		assert.Contains(t, code, `e.DeletedAt = null.Time{}`)
+	})
+
+	t.Run("Collection", func(t *testing.T) {
+		mainGen := codegen.NewGo("dmltestsoftdelete")
+		tbl.fnCollectionDBMHandler(mainGen, g)
+		code := formatPreloadTestCode(t, mainGen)
+
+		assert.Contains(t, code, `WithCacheKey(qo.SoftDeleteCacheKey("SoftEntitiesSelectByPK"), opts...).Load(ctx, cc, pkIDs)`)
+		assert.Contains(t, code, `WithCacheKey(qo.SoftDeleteCacheKey("SoftEntitiesSelectAll"), opts...).Load(ctx, cc)`)
+		assert.Contains(t, code, `func (cc *SoftEntities) DBRestore(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result, err error) {`)
+		assert.Contains(t, code, `WithCacheKey("SoftEntityRestoreByPK", opts...).ExecContext(ctx, dml.Qualify("", cc))`)
+	})
+}
+
+func TestTable_SystemVersioned(t *testing.T) {
+	g := newSoftDeleteTestGenerator(t, &TableConfig{SystemVersioned: true})
+	tbl := g.Tables["soft_entity"]
+
+	t.Run("DBMOptions", func(t *testing.T) {
+		mainGen := codegen.NewGo("dmltestsoftdelete")
+		mainGen.Pln(`var _ = map[string]dml.QueryBuilder{`)
+		tbl.fnDBMOptionsSQLBuildQueries(mainGen, g)
+		mainGen.Pln(`}`)
+		code := formatPreloadTestCode(t, mainGen)
+
+		assert.Contains(t, code, "\"SoftEntitiesSelectAll\": dbmo.InitSelectFn(tbls.MustTable(TableNameSoftEntity).Select(\"*\")),")
+		latest := "dml.Expr(\"`main_table`.`ROW_END` = (SELECT MAX(`h`.`ROW_END`) FROM `soft_entity` FOR SYSTEM_TIME ALL AS `h` WHERE `h`.`entity_id` = `main_table`.`entity_id`)\"),"
+		assert.Contains(t, code, "\"SoftEntitiesSelectAllWithDeleted\": dbmo.InitSelectFn(tbls.MustTable(TableNameSoftEntity).Select(\"*\").ForSystemTimeAll()).Where(\n\t\t"+latest+"\n\t),")
+		assert.Contains(t, code, "\"SoftEntitySelectByPKWithDeleted\": dbmo.InitSelectFn(tbls.MustTable(TableNameSoftEntity).Select(\"*\").ForSystemTimeAll()).Where(\n\t\tdml.Column(`entity_id`).Equal().PlaceHolder(),\n\t\t"+latest+"\n\t),")
+		assert.Contains(t, code, "\"SoftEntitiesSelectAllOnlyDeleted\": dbmo.InitSelectFn(tbls.MustTable(TableNameSoftEntity).Select(\"*\").ForSystemTimeAll()).Where(\n\t\t"+latest+"\n\t\tdml.Column(`entity_id`).NotIn().Sub(dml.NewSelect(`entity_id`).From(TableNameSoftEntity)),\n\t),")
+		assert.Contains(t, code, "\"SoftEntityDeleteByPK\": dbmo.InitDeleteFn(")
+		assert.NotContains(t, code, "RestoreByPK")
+	})
+
+	t.Run("Entity", func(t *testing.T) {
+		mainGen := codegen.NewGo("dmltestsoftdelete")
+		tbl.fnEntityDBMHandler(mainGen, g)
+		code := formatPreloadTestCode(t, mainGen)
+
+		assert.Contains(t, code, `WithCacheKey("SoftEntityInsert", opts...).ExecContext(ctx, e)`)
+		assert.NotContains(t, code, `null.Time{}`)
+	})
+}
+
+func TestTable_SystemVersioned_RowEndColumn(t *testing.T) {
+	cols := append(newSoftDeleteTestColumns(),
+		&ddl.Column{Field: "version_ts", Pos: 5, Null: "NO", DataType: "timestamp", ColumnType: "timestamp(6)", Generated: "ALWAYS", GenerationExpression: null.MakeString("ROW START")},
+		&ddl.Column{Field: "version_te", Pos: 6, Null: "NO", DataType: "timestamp", ColumnType: "timestamp(6)", Generated: "ALWAYS", GenerationExpression: null.MakeString("ROW END")},
+	)
+	g, err := NewGenerator("dmltestsoftdelete",
+		WithTable("soft_entity", cols),
+		WithTableConfig("soft_entity", &TableConfig{SystemVersioned: true}),
+	)
+	assert.NoError(t, err)
+	assert.Exactly(t,
+		"`main_table`.`version_te` = (SELECT MAX(`h`.`version_te`) FROM `soft_entity` FOR SYSTEM_TIME ALL AS `h` WHERE `h`.`entity_id` = `main_table`.`entity_id` AND `h`.`name` = `main_table`.`name`)",
+		g.Tables["soft_entity"].systemVersionedLatest([]string{"entity_id", "name"}))
+}
+
+// TestTable_SystemVersioned_LatestVersion runs the WithDeleted and
+// OnlyDeleted queries against a table with several versions per primary key.
+func TestTable_SystemVersioned_LatestVersion(t *testing.T) {
+	db := dmltest.MustConnectDB(t)
+	defer dmltest.Close(t, db)
+	ctx := context.Background()
+
+	const tableName = "dmlgen_system_versioned"
+	for _, stmt := range []string{
+		"DROP TABLE IF EXISTS `" + tableName + "`",
+		"CREATE TABLE `" + tableName + "` (`entity_id` int(10) unsigned NOT NULL, `name` varchar(255) NOT NULL, PRIMARY KEY (`entity_id`)) WITH SYSTEM VERSIONING",
+		"INSERT INTO `" + tableName + "` VALUES (1, 'a1'), (2, 'b1'), (3, 'c1')",
+		"UPDATE `" + tableName + "` SET `name` = 'a2' WHERE `entity_id` = 1",
+		"UPDATE `" + tableName + "` SET `name` = 'a3' WHERE `entity_id` = 1",
+		"UPDATE `" + tableName + "` SET `name` = 'b2' WHERE `entity_id` = 2",
+		"DELETE FROM `" + tableName + "` WHERE `entity_id` = 2",
+	} {
+		_, err := db.DB.ExecContext(ctx, stmt)
+		assert.NoError(t, err, "%s", stmt)
+	}
+	defer func() {
+		_, err := db.DB.ExecContext(ctx, "DROP TABLE IF EXISTS `"+tableName+"`")
+		assert.NoError(t, err)
+	}()
+
+	cols := newSoftDeleteTestColumns()[:2]
+	g, err := NewGenerator("dmltestsoftdelete",
+		WithTable(tableName, cols),
+		WithTableConfig(tableName, &TableConfig{SystemVersioned: true}),
+	)
+	assert.NoError(t, err)
+	tbls, err := ddl.NewTables(ddl.WithConnPool(db), ddl.WithTable(tableName, cols...))
+	assert.NoError(t, err)
+	latest := dml.Expr(g.Tables[tableName].systemVersionedLatest([]string{"entity_id"}))
+
+	load := func(sel *dml.Select) map[int]string {
+		query, args, err := sel.ToSQL()
+		assert.NoError(t, err)
+		rows, err := db.DB.QueryContext(ctx, query, args...)
+		assert.NoError(t, err)
+		defer rows.Close()
+		got := map[int]string{}
+		for rows.Next() {
+			var id int
+			var name string
+			assert.NoError(t, rows.Scan(&id, &name))
+			_, ok := got[id]
+			assert.False(t, ok, "entity_id %d loaded twice", id)
+			got[id] = name
+		}
+		assert.NoError(t, rows.Err())
+		return got
+	}
+
+	withDeleted := tbls.MustTable(tableName).Select("*").ForSystemTimeAll().Where(latest)
+	assert.Exactly(t, map[int]string{1: "a3", 2: "b2", 3: "c1"}, load(withDeleted))
+
+	onlyDeleted := tbls.MustTable(tableName).Select("*").ForSystemTimeAll().Where(
+		latest,
+		dml.Column("entity_id").NotIn().Sub(dml.NewSelect("entity_id").From(tableName)),
+	)
+	assert.Exactly(t, map[int]string{2: "b2"}, load(onlyDeleted))
+}
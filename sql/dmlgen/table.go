@@ -45,6 +45,15 @@ type Table struct {
 	customStructTagFields  map[string]string
 	relationshipSeen       map[string]bool // to not print twice a relationship
 	availableRelationships []relationShipInfo
+	// versionColumn, createdAtColumn and updatedAtColumn are set via
+	// TableConfig and can be nil.
+	versionColumn   *ddl.Column
+	createdAtColumn *ddl.Column
+	updatedAtColumn *ddl.Column
+	// softDeleteColumn and systemVersioned are set via TableConfig and enable
+	// the soft deletion.
+	softDeleteColumn *ddl.Column
+	systemVersioned  bool
 }
 
 type relationShipInfo struct {
@@ -53,6 +62,17 @@ type relationShipInfo struct {
 	structName            string
 	mappedStructFieldName string // name of the struct in the relation struct
 	columnName            string
+	// parentColumnName defines the column of the parent table which holds the
+	// value to match with columnName. Empty value disables preloading.
+	parentColumnName string
+	isOneToOne       bool // struct field is an entity and not a collection
+	// targetTableName and targetColumnName are only set for M:N relations.
+	// tableName and columnName then point to the link table and
+	// linkTargetColumnName defines the column in the link table which
+	// references targetTableName.targetColumnName.
+	targetTableName      string
+	targetColumnName     string
+	linkTargetColumnName string
 }
 
 func (t *Table) getFieldMapFn(g *Generator) func(dbIdentifier string) (newName string) {
@@ -244,6 +264,7 @@ func (t *Table) fnEntityRelationStruct(mainGen *codegen.Go, g *Generator) {
 					structName:            name,
 					mappedStructFieldName: fieldName,
 					columnName:            kcuce.ReferencedColumnName.Data,
+					parentColumnName:      kcuce.ColumnName,
 				})
 
 				mainGen.Pln(fieldName, " *", name,
@@ -265,6 +286,8 @@ func (t *Table) fnEntityRelationStruct(mainGen *codegen.Go, g *Generator) {
 					structName:            name,
 					mappedStructFieldName: fieldName,
 					columnName:            kcuce.ReferencedColumnName.Data,
+					parentColumnName:      kcuce.ColumnName,
+					isOneToOne:            true,
 				})
 
 				mainGen.Pln(fieldName, " *", name, t.customStructTagFields[kcuce.ReferencedTableName.Data],
@@ -322,6 +345,7 @@ func (t *Table) fnEntityRelationStruct(mainGen *codegen.Go, g *Generator) {
 					structName:            name,
 					mappedStructFieldName: fieldName,
 					columnName:            kcuce.ReferencedColumnName.Data,
+					parentColumnName:      kcuce.ColumnName,
 				})
 
 				mainGen.Pln(fieldName, " *", name, t.customStructTagFields[kcuce.ReferencedTableName.Data],
@@ -342,6 +366,8 @@ func (t *Table) fnEntityRelationStruct(mainGen *codegen.Go, g *Generator) {
 					structName:            name,
 					mappedStructFieldName: fieldName,
 					columnName:            kcuce.ReferencedColumnName.Data,
+					parentColumnName:      kcuce.ColumnName,
+					isOneToOne:            true,
 				})
 
 				mainGen.Pln(fieldName, " *", name, t.customStructTagFields[kcuce.ReferencedTableName.Data],
@@ -371,6 +397,10 @@ func (t *Table) fnEntityRelationStruct(mainGen *codegen.Go, g *Generator) {
 					structName:            name,
 					mappedStructFieldName: fieldName,
 					columnName:            kcuce.ReferencedColumnName.Data,
+					parentColumnName:      kcuce.ColumnName,
+					targetTableName:       targetTbl,
+					targetColumnName:      targetColumn,
+					linkTargetColumnName:  g.linkColumnName(kcuce.ReferencedTableName.Data, targetTbl, targetColumn),
 				})
 
 				mainGen.Pln(fieldName, " *", name, t.customStructTagFields[targetTbl],
@@ -409,6 +439,9 @@ func (t *Table) fnEntityRelationMethods(mainGen *codegen.Go, g *Generator) {
 	parentPKFieldName := strs.ToGoCamelCase(parentPK.Field)
 
 	for _, rs := range t.availableRelationships {
+		if rs.isManyToMany() {
+			continue // loaded with the Preload functions, writing requires the link table
+		}
 		// <DELETE>
 		mainGen.Pln(`func (r *`, t.relationStructName(), `) `, "Delete"+rs.mappedStructFieldName, `(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) error {`)
 		mainGen.Pln(`dbr := dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, rs.mappedStructFieldName, `DeleteByFK`, `"`), `, opts...)`)
@@ -433,7 +466,7 @@ func (t *Table) fnEntityRelationMethods(mainGen *codegen.Go, g *Generator) {
 		// </INSERT>
 
 		// <UPDATE>
-		mainGen.Pln(`func (r *customerEntityRelations) `, codegen.SkipWS(`Update`, rs.mappedStructFieldName), `(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {`)
+		mainGen.Pln(`func (r *`, t.relationStructName(), `) `, codegen.SkipWS(`Update`, rs.mappedStructFieldName), `(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {`)
 		mainGen.Pln(`if r.`, rs.mappedStructFieldName, ` == nil || len(r.`, rs.mappedStructFieldName, `.Data) == 0 {
 			dbr := dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, rs.mappedStructFieldName, `DeleteByFK`, `"`), `, opts...)
 			res, err := dbr.ExecContext(ctx, r.parent.`, parentPKFieldName, `)
@@ -455,7 +488,7 @@ func (t *Table) fnEntityRelationMethods(mainGen *codegen.Go, g *Generator) {
 		mainGen.Pln(`func (r *`, t.relationStructName(), `) `, "Load"+rs.mappedStructFieldName, `(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (rowCount uint64, err error) {`)
 		mainGen.Pln(`if r.`, rs.mappedStructFieldName, ` == nil { r.`, rs.mappedStructFieldName, ` = &`, rs.mappedStructFieldName, `{} }`)
 		mainGen.Pln(`r.`, rs.mappedStructFieldName, `.Clear()
-			  rowCount, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, rs.mappedStructFieldName, `SelectByFK"`), `, opts...).Load(ctx, r.`, rs.mappedStructFieldName, `, r.parent.EntityID)
+			  rowCount, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, rs.mappedStructFieldName, `SelectByFK"`), `, opts...).Load(ctx, r.`, rs.mappedStructFieldName, `, r.parent.`, parentPKFieldName, `)
 				return rowCount, errors.WithStack(err) }`)
 		// </SELECT>
 
@@ -472,6 +505,9 @@ func (t *Table) fnEntityRelationMethods(mainGen *codegen.Go, g *Generator) {
 	// <INSERT_ALL>
 	mainGen.Pln(`func (r *`, t.relationStructName(), `) InsertAll(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) error {`)
 	for _, rs := range t.availableRelationships {
+		if rs.isManyToMany() {
+			continue
+		}
 		mainGen.Pln(`if err := r.`, codegen.SkipWS("Insert", rs.mappedStructFieldName), `(ctx, dbm, opts...); err != nil { return errors.WithStack(err) }`)
 	}
 	mainGen.Pln(`return nil }`)
@@ -480,6 +516,9 @@ func (t *Table) fnEntityRelationMethods(mainGen *codegen.Go, g *Generator) {
 	// <SELECT_ALL>
 	mainGen.Pln(`func (r *`, t.relationStructName(), `) LoadAll(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (err error) {`)
 	for _, rs := range t.availableRelationships {
+		if rs.isManyToMany() {
+			continue
+		}
 		mainGen.Pln(`if _, err = r.`, codegen.SkipWS("Load", rs.mappedStructFieldName), `(ctx, dbm, opts...); err != nil { return errors.WithStack(err) }`)
 	}
 	mainGen.Pln(`return nil }`)
@@ -488,6 +527,9 @@ func (t *Table) fnEntityRelationMethods(mainGen *codegen.Go, g *Generator) {
 	// <UPDATE_ALL>
 	mainGen.Pln(`func (r *`, t.relationStructName(), `) UpdateAll(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) error {`)
 	for _, rs := range t.availableRelationships {
+		if rs.isManyToMany() {
+			continue
+		}
 		mainGen.Pln(`if err := r.`, codegen.SkipWS("Update", rs.mappedStructFieldName), `(ctx, dbm, opts...); err != nil { return errors.WithStack(err) }`)
 	}
 	mainGen.Pln(`return nil }`)
@@ -496,6 +538,9 @@ func (t *Table) fnEntityRelationMethods(mainGen *codegen.Go, g *Generator) {
 	// <DELETE_ALL>
 	mainGen.Pln(`func (r *`, t.relationStructName(), `) DeleteAll(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) error {`)
 	for _, rs := range t.availableRelationships {
+		if rs.isManyToMany() {
+			continue
+		}
 		mainGen.Pln(`if err := r.`, codegen.SkipWS("Delete", rs.mappedStructFieldName), `(ctx, dbm, opts...); err != nil { return errors.WithStack(err) }`)
 	}
 	mainGen.Pln(`return nil }`)
@@ -985,22 +1030,33 @@ func (t *Table) fnEntityValidate(mainGen *codegen.Go, g *Generator) {
 
 	if !ok {
 		mainGen.C(`This variable can be set in another file to provide a custom validator.`)
+		mainGen.C(`A returned dml.FieldErrors gets merged with the errors of the column constraints.`)
 		mainGen.Pln(`var validate`+t.EntityName(), ` func(*`, t.EntityName(), `) error `)
 	}
-	mainGen.C(`Validate runs internal consistency tests.`)
+	mainGen.C(`Validate runs internal consistency tests and checks the values against the constraints of the table columns. It returns dml.FieldErrors if a constraint has been violated.`)
 	mainGen.Pln(`func (e *`, t.EntityName(), `) Validate() error {`)
+	var hasRules bool
 	{
 		mainGen.In()
 		mainGen.Pln(`if e == nil { return errors.NotValid.Newf("Type %T cannot be nil", e) }`)
-		if ok {
+		hasRules = t.fnEntityValidateRules(mainGen, g)
+		switch {
+		case ok:
+			// custom code can append to variable fe
 			fn(g, t, mainGen)
-		} else {
+		case hasRules:
+			mainGen.Pln(`if validate`+t.EntityName(), ` != nil { return fe.Join(validate`+t.EntityName(), `(e)) }`)
+		default:
 			mainGen.Pln(`if validate`+t.EntityName(), ` != nil { return validate`+t.EntityName(), `(e) }`)
 		}
 
 		mainGen.Out()
 	}
-	mainGen.Pln(`return nil }`)
+	if hasRules {
+		mainGen.Pln(`return fe.ErrorOrNil() }`)
+	} else {
+		mainGen.Pln(`return nil }`)
+	}
 }
 
 func (t *Table) fnCollectionValidate(mainGen *codegen.Go, g *Generator) {
@@ -1156,21 +1212,21 @@ func (t *Table) fnCollectionDBMHandler(mainGen *codegen.Go, g *Generator) {
 		mainGen.Pln(dmlEnabled, `}
 		cacheKey = `, codegen.SkipWS(`"`, t.CollectionName(), "SelectByPK", `"`), `
 	}
-	if _, err = dbm.ConnPool.WithCacheKey(cacheKey, opts...).Load(ctx, cc, args...); err != nil {
+	if _, err = dbm.ConnPool.WithCacheKey(`, t.softDeleteCacheKey([]byte(`cacheKey`)), `, opts...).Load(ctx, cc, args...); err != nil {
 		return errors.WithStack(err)
 	}`)
 	} else {
 		mainGen.Pln(dmlEnabled, `if len(pkIDs) > 0 {`)
 		mainGen.In()
 		{
-			mainGen.Pln(dmlEnabled, `if _, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, t.CollectionName(), "SelectByPK", `"`), `, opts...).Load(ctx, cc, pkIDs); err != nil {
+			mainGen.Pln(dmlEnabled, `if _, err = dbm.ConnPool.WithCacheKey(`, t.softDeleteCacheKey(codegen.SkipWS(`"`, t.CollectionName(), "SelectByPK", `"`)), `, opts...).Load(ctx, cc, pkIDs); err != nil {
 		return errors.WithStack(err); }`)
 		}
 		mainGen.Out()
 		mainGen.Pln(dmlEnabled, `} else {`)
 		mainGen.In()
 		{
-			mainGen.Pln(dmlEnabled, `if _, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, collectionFuncName, "", `"`), `, opts...).Load(ctx, cc); err != nil {
+			mainGen.Pln(dmlEnabled, `if _, err = dbm.ConnPool.WithCacheKey(`, t.softDeleteCacheKey(codegen.SkipWS(`"`, collectionFuncName, "", `"`)), `, opts...).Load(ctx, cc); err != nil {
 		return errors.WithStack(err); }`)
 		}
 		mainGen.Out()
@@ -1206,6 +1262,7 @@ func (t *Table) fnCollectionDBMHandler(mainGen *codegen.Go, g *Generator) {
 		}
 		return res, nil
 	}`)
+	t.fnCollectionDBRestore(mainGen, g)
 
 	dmlEnabled = t.hasFeature(g, FeatureDBUpdate)
 	collectionFuncName = codegen.SkipWS(t.EntityName(), "UpdateByPK")
@@ -1216,21 +1273,29 @@ func (t *Table) fnCollectionDBMHandler(mainGen *codegen.Go, g *Generator) {
 		return errors.NotValid.Newf(`, codegen.SkipWS(`"`, t.CollectionName()), `can't be nil")
 	}`)
 	mainGen.Pln(dmlEnabled, `qo := dml.FromContextQueryOptions(ctx)`)
+	t.fnTimestamps(mainGen, g, dmlEnabled, false, true)
 
 	mainGen.Pln(dmlEnabled, `if err = dbm.`, entityEventName, `(ctx, dml.EventFlagBeforeUpdate, qo.SkipEvents, cc, nil); err != nil {
 			return errors.WithStack(err)
 		}`)
 
-	mainGen.Pln(dmlEnabled, `dbr := dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, collectionFuncName, `"`), `, opts...)`)
+	cacheKey := t.updateCacheKey(mainGen, dmlEnabled, collectionFuncName)
+	mainGen.Pln(dmlEnabled, `dbr := dbm.ConnPool.WithCacheKey(`, cacheKey, `, opts...)`)
 	mainGen.Pln(dmlEnabled, `dbrStmt, err := dbr.Prepare(ctx)
 		if err != nil {	return errors.WithStack(err) }`)
 
 	mainGen.Pln(dmlEnabled, `for _, c := range cc.Data {
-		res, err := dbrStmt.ExecContext(ctx, c)
-		if err := dbr.ResultCheckFn(`, constTableName(t.Table.Name), `, 1, res, err); err != nil {
+		res, err := dbrStmt.ExecContext(ctx, c)`)
+	if t.versionColumn != nil {
+		mainGen.Pln(dmlEnabled, `if err == nil {`)
+		t.fnVersionCheck(mainGen, dmlEnabled, "c", "")
+		mainGen.Pln(dmlEnabled, `}`)
+	}
+	mainGen.Pln(dmlEnabled, `if err := dbr.ResultCheckFn(`, constTableName(t.Table.Name), `, 1, res, err); err != nil {
 			return errors.WithStack(err)
-		}
-	}`)
+		}`)
+	t.fnVersionIncrement(mainGen, dmlEnabled, "c")
+	mainGen.Pln(dmlEnabled, `}`)
 
 	mainGen.Pln(dmlEnabled, `return errors.WithStack(dbm.`, entityEventName, `(ctx, dml.EventFlagAfterUpdate, qo.SkipEvents,cc, nil))
 	}`)
@@ -1244,6 +1309,7 @@ func (t *Table) fnCollectionDBMHandler(mainGen *codegen.Go, g *Generator) {
 		return errors.NotValid.Newf(`, codegen.SkipWS(`"`, t.CollectionName()), `can't be nil")
 	}`)
 	mainGen.Pln(dmlEnabled, `qo := dml.FromContextQueryOptions(ctx)`)
+	t.fnTimestamps(mainGen, g, dmlEnabled, true, true)
 
 	mainGen.Pln(dmlEnabled, `if err := dbm.`, entityEventName, `(ctx, dml.EventFlagBeforeInsert, qo.SkipEvents, cc, nil); err != nil {
 			return errors.WithStack(err)
@@ -1256,7 +1322,7 @@ func (t *Table) fnCollectionDBMHandler(mainGen *codegen.Go, g *Generator) {
 		return errors.WithStack(dbm.`, entityEventName, `(ctx, dml.EventFlagAfterInsert, qo.SkipEvents,cc, nil))
 	}`)
 
-	dmlEnabled = t.hasFeature(g, FeatureDBUpsert)
+	dmlEnabled = t.hasUpsert(g, FeatureDBUpsert)
 	collectionFuncName = codegen.SkipWS(t.EntityName(), "UpsertByPK")
 	mainGen.Pln(dmlEnabled, `func (cc `, collectionPTRName, `) DBUpsert(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc)  (err error) {`)
 	mainGen.Pln(dmlEnabled && tracingEnabled, `	ctx, span := dbm.option.Trace.Start(ctx, `, codegen.SkipWS(`"`, t.CollectionName(), "UpsertByPK", `"`), `);
@@ -1265,6 +1331,7 @@ func (t *Table) fnCollectionDBMHandler(mainGen *codegen.Go, g *Generator) {
 		return errors.NotValid.Newf(`, codegen.SkipWS(`"`, t.CollectionName()), `can't be nil")
 	}`)
 	mainGen.Pln(dmlEnabled, `qo := dml.FromContextQueryOptions(ctx)`)
+	t.fnTimestamps(mainGen, g, dmlEnabled, true, true)
 
 	mainGen.Pln(dmlEnabled, `if err := dbm.`, entityEventName, `(ctx, dml.EventFlagBeforeUpsert, qo.SkipEvents, cc, nil); err != nil {
 			return errors.WithStack(err)
@@ -1350,7 +1417,7 @@ func (t *Table) fnEntityDBMHandler(mainGen *codegen.Go, g *Generator) {
 	if e.IsSet() {
 		return nil // might return data from cache
 	}
-	if _, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, entityFuncName, `"`), `, opts...).Load(ctx, e, `, &bufPKNames, `); err != nil {
+	if _, err = dbm.ConnPool.WithCacheKey(`, t.softDeleteCacheKey(codegen.SkipWS(`"`, entityFuncName, `"`)), `, opts...).Load(ctx, e, `, &bufPKNames, `); err != nil {
 		return errors.WithStack(err)
 	}
 	return errors.WithStack(dbm.`, entityEventName, `(ctx, dml.EventFlagAfterSelect, qo.SkipEvents,nil, e))
@@ -1383,6 +1450,7 @@ func (t *Table) fnEntityDBMHandler(mainGen *codegen.Go, g *Generator) {
 		}
 		return res, nil
 	}`)
+	t.fnEntityRestore(mainGen, g)
 
 	dmlEnabled = t.hasFeature(g, FeatureDBUpdate)
 	entityFuncName = codegen.SkipWS(t.EntityName(), "UpdateByPK")
@@ -1394,14 +1462,18 @@ func (t *Table) fnEntityDBMHandler(mainGen *codegen.Go, g *Generator) {
 	}`)
 	mainGen.Pln(dmlEnabled && len(t.availableRelationships) > 0, `e.setRelationParent()`)
 	mainGen.Pln(dmlEnabled, `qo := dml.FromContextQueryOptions(ctx)`)
+	t.fnTimestamps(mainGen, g, dmlEnabled, false, false)
 
 	mainGen.Pln(dmlEnabled, `if err = dbm.`, entityEventName, `(ctx, dml.EventFlagBeforeUpdate, qo.SkipEvents, nil, e); err != nil {
 			return nil, errors.WithStack(err)
-		}
-		if res, err = dbm.ConnPool.WithCacheKey(`, codegen.SkipWS(`"`, entityFuncName, `"`), `, opts...).ExecContext(ctx, e); err != nil {
+		}`)
+	cacheKey := t.updateCacheKey(mainGen, dmlEnabled, entityFuncName)
+	mainGen.Pln(dmlEnabled, `if res, err = dbm.ConnPool.WithCacheKey(`, cacheKey, `, opts...).ExecContext(ctx, e); err != nil {
 			return nil, errors.WithStack(err)
-		}
-		if err = dbm.`, entityEventName, `(ctx, dml.EventFlagAfterUpdate, qo.SkipEvents,nil, e); err != nil {
+		}`)
+	t.fnVersionCheck(mainGen, dmlEnabled, "e", "nil, ")
+	t.fnVersionIncrement(mainGen, dmlEnabled, "e")
+	mainGen.Pln(dmlEnabled, `if err = dbm.`, entityEventName, `(ctx, dml.EventFlagAfterUpdate, qo.SkipEvents,nil, e); err != nil {
 			return nil, errors.WithStack(err)
 		}
 		return res, nil
@@ -1417,6 +1489,7 @@ func (t *Table) fnEntityDBMHandler(mainGen *codegen.Go, g *Generator) {
 	}`)
 	mainGen.Pln(dmlEnabled && len(t.availableRelationships) > 0, `e.setRelationParent()`)
 	mainGen.Pln(dmlEnabled, `qo := dml.FromContextQueryOptions(ctx)`)
+	t.fnTimestamps(mainGen, g, dmlEnabled, true, false)
 
 	mainGen.Pln(dmlEnabled, `if err = dbm.`, entityEventName, `(ctx, dml.EventFlagBeforeInsert, qo.SkipEvents, nil, e); err != nil {
 			return nil, errors.WithStack(err)
@@ -1430,7 +1503,7 @@ func (t *Table) fnEntityDBMHandler(mainGen *codegen.Go, g *Generator) {
 		return res, nil
 	}`)
 
-	dmlEnabled = t.hasFeature(g, FeatureDBUpsert)
+	dmlEnabled = t.hasUpsert(g, FeatureDBUpsert)
 	entityFuncName = codegen.SkipWS(t.EntityName(), "UpsertByPK")
 	mainGen.Pln(dmlEnabled, `func (e `, entityPTRName, `) Upsert(ctx context.Context, dbm *DBM, opts ...dml.DBRFunc) (res sql.Result, err error) {`)
 	mainGen.Pln(dmlEnabled && tracingEnabled, `	ctx, span := dbm.option.Trace.Start(ctx, `, codegen.SkipWS(`"`, entityFuncName, `"`), `);
@@ -1440,6 +1513,7 @@ func (t *Table) fnEntityDBMHandler(mainGen *codegen.Go, g *Generator) {
 	}`)
 	mainGen.Pln(dmlEnabled && len(t.availableRelationships) > 0, `e.setRelationParent()`)
 	mainGen.Pln(dmlEnabled, `qo := dml.FromContextQueryOptions(ctx)`)
+	t.fnTimestamps(mainGen, g, dmlEnabled, true, false)
 
 	mainGen.Pln(dmlEnabled, `if err = dbm.`, entityEventName, `(ctx, dml.EventFlagBeforeUpsert, qo.SkipEvents, nil, e); err != nil {
 			return nil, errors.WithStack(err)
@@ -1476,32 +1550,18 @@ func (t *Table) fnDBMOptionsSQLBuildQueries(mainGen *codegen.Go, g *Generator) {
 		pkWhereEQ.WriteString("Tuples(),\n")
 	}
 
-	mainGen.Pln(tblPKLen > 0 && t.hasFeature(g, FeatureDBSelect|FeatureCollectionStruct),
-		codegen.SkipWS(`"`, t.CollectionName(), `SelectAll"`),
-		`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Select("*")),`)
-
-	mainGen.Pln(tblPKLen > 0 && t.hasFeature(g, FeatureDBSelect|FeatureEntityStruct|FeatureCollectionStruct),
-		codegen.SkipWS(`"`, t.CollectionName(), `SelectByPK"`),
-		`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Select("*")).Where(`, pkWhereIN.String(), `),`)
-
-	mainGen.Pln(tblPKLen > 0 && t.hasFeature(g, FeatureDBSelect|FeatureEntityStruct|FeatureCollectionStruct),
-		codegen.SkipWS(`"`, t.EntityName(), `SelectByPK"`),
-		`: dbmo.InitSelectFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Select("*")).Where(`, pkWhereEQ.String(), `),`)
+	t.fnDBMOptionsSelectQueries(mainGen, g, tblPKLen, pkWhereIN.String(), pkWhereEQ.String())
 
 	if t.Table.IsView() {
 		return
 	}
 
-	mainGen.Pln(t.hasFeature(g, FeatureDBUpdate|FeatureEntityStruct|FeatureCollectionStruct),
-		codegen.SkipWS(`"`, t.EntityName(), `UpdateByPK"`),
-		`: dbmo.InitUpdateFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Update().Where(`, pkWhereEQ.String(), `)),`)
-	mainGen.Pln(t.hasFeature(g, FeatureDBDelete|FeatureEntityStruct|FeatureCollectionStruct),
-		codegen.SkipWS(`"`, t.EntityName(), `DeleteByPK"`),
-		`: dbmo.InitDeleteFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Delete().Where(`, pkWhereIN.String(), `)),`)
+	t.fnDBMOptionsUpdateQueries(mainGen, g, pkWhereEQ.String())
+	t.fnDBMOptionsDeleteQueries(mainGen, g, pkWhereIN.String())
 	mainGen.Pln(t.hasFeature(g, FeatureDBInsert|FeatureEntityStruct|FeatureCollectionStruct),
 		codegen.SkipWS(`"`, t.EntityName(), `Insert"`),
 		`: dbmo.InitInsertFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Insert()),`)
-	mainGen.Pln(t.hasFeature(g, FeatureDBUpsert|FeatureEntityStruct|FeatureCollectionStruct),
+	mainGen.Pln(t.hasUpsert(g, FeatureDBUpsert|FeatureEntityStruct|FeatureCollectionStruct),
 		codegen.SkipWS(`"`, t.EntityName(), `UpsertByPK"`),
 		`: dbmo.InitInsertFn(tbls.MustTable(`, constTableName(t.Table.Name), `).Insert()).OnDuplicateKey(),`)
 
@@ -1524,6 +1584,7 @@ func (t *Table) fnDBMOptionsSQLBuildQueries(mainGen *codegen.Go, g *Generator) {
 
 			fkWhereEQ.Reset()
 		}
+		t.fnDBMOptionsPreloadQueries(mainGen, g)
 		mainGen.C(`</FOREIGN_KEY_QUERIES`, t.Table.Name, `>`)
 	}
 }
@@ -1562,6 +1623,7 @@ func (t *Table) generateTestOther(testGen *codegen.Go, g *Generator) (codeWritte
 		testGen.Pln(`})`) // end t.Run
 		codeWritten++
 	}
+	codeWritten += t.generateTestFlatbuffers(testGen, g)
 	// more feature tests to follow
 	return
 }
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
 )
 
 // TableConfig used in conjunction with WithTableConfig and
@@ -60,7 +61,37 @@ type TableConfig struct {
 	// table to a new name. dbIdentifier is in most cases the column name and in
 	// cases of foreign keys, it is the table name.
 	FieldMapFn func(dbIdentifier string) (newName string)
-	lastErr    error
+	// VersionColumn defines a NOT NULL integer column used for optimistic
+	// locking. The generated Update functions increment the version and add
+	// the current version to the WHERE clause. If no row has been affected,
+	// a *dml.ConflictError gets returned. Can be disabled per query with
+	// dml.QueryOptions.SkipVersion, which neither checks nor increments the
+	// version. Tables with a VersionColumn get no Upsert functions because
+	// INSERT ... ON DUPLICATE KEY UPDATE cannot check the version.
+	VersionColumn string
+	// CreatedAtColumn defines a date/time column which gets set to the
+	// current time on insert and upsert if it is empty. The column must not
+	// have a DEFAULT CURRENT_TIMESTAMP because then the column won't be part
+	// of the INSERT statement. Can be disabled per query with
+	// dml.QueryOptions.SkipTimestamps.
+	CreatedAtColumn string
+	// UpdatedAtColumn defines a date/time column which gets set to the
+	// current time on insert, update and upsert. The same restrictions as for
+	// CreatedAtColumn apply.
+	UpdatedAtColumn string
+	// SoftDeleteColumn defines a nullable date/time column, like deleted_at,
+	// for soft deletion. The generated Delete functions set the column to the
+	// current time instead of deleting the row and the generated Load
+	// functions skip soft deleted rows. Use dml.QueryOptions.WithDeleted or
+	// OnlyDeleted to load soft deleted rows and Restore to undelete them.
+	SoftDeleteColumn string
+	// SystemVersioned declares the table as a MariaDB system-versioned table.
+	// Deleted rows remain in the history and can be loaded with
+	// dml.QueryOptions.WithDeleted or OnlyDeleted, which return all historical
+	// row versions. Restore inserts the row again. Cannot be combined with
+	// SoftDeleteColumn.
+	SystemVersioned bool
+	lastErr         error
 }
 
 func (to *TableConfig) applyEncoders(t *Table, g *Generator) {
@@ -211,6 +242,60 @@ func (to *TableConfig) applyColumnAliases(t *Table) {
 	}
 }
 
+func (to *TableConfig) applyVersionAndTimestamps(t *Table, g *Generator) {
+	if to.lastErr != nil {
+		return
+	}
+	if to.VersionColumn != "" {
+		c := t.Table.Columns.ByField(to.VersionColumn)
+		if c.Field == "" || c.IsNull() || c.IsFloat() || c.IsMoney() || !strings.Contains(g.mySQLToGoType(c, false), "int") {
+			to.lastErr = errors.NotValid.Newf("[dmlgen] WithTableConfig:VersionColumn: For table %q the Column %q cannot be found or is not a NOT NULL integer column.",
+				t.Table.Name, to.VersionColumn)
+			return
+		}
+		t.versionColumn = c
+	}
+	for _, tc := range []struct {
+		name string
+		dst  **ddl.Column
+	}{
+		{to.CreatedAtColumn, &t.createdAtColumn},
+		{to.UpdatedAtColumn, &t.updatedAtColumn},
+	} {
+		if tc.name == "" {
+			continue
+		}
+		c := t.Table.Columns.ByField(tc.name)
+		if c.Field == "" || !c.IsTime() {
+			to.lastErr = errors.NotValid.Newf("[dmlgen] WithTableConfig: For table %q the timestamp Column %q cannot be found or is not a date/time column.",
+				t.Table.Name, tc.name)
+			return
+		}
+		*tc.dst = c
+	}
+}
+
+func (to *TableConfig) applySoftDelete(t *Table) {
+	if to.lastErr != nil {
+		return
+	}
+	if to.SoftDeleteColumn != "" && to.SystemVersioned {
+		to.lastErr = errors.NotValid.Newf("[dmlgen] WithTableConfig: For table %q SoftDeleteColumn and SystemVersioned cannot be combined.", t.Table.Name)
+		return
+	}
+	t.systemVersioned = to.SystemVersioned
+	if to.SoftDeleteColumn == "" {
+		return
+	}
+	c := t.Table.Columns.ByField(to.SoftDeleteColumn)
+	if c.Field == "" || !c.IsNull() || !c.IsTime() {
+		to.lastErr = errors.NotValid.Newf("[dmlgen] WithTableConfig:SoftDeleteColumn: For table %q the Column %q cannot be found or is not a nullable date/time column.",
+			t.Table.Name, to.SoftDeleteColumn)
+		return
+	}
+	t.softDeleteColumn = c
+}
+
 // skips text and blob and varbinary and json and geo
 func (to *TableConfig) applyUniquifiedColumns(t *Table) {
 	for i := 0; i < len(to.UniquifiedColumns) && to.lastErr == nil; i++ {
@@ -77,10 +77,10 @@ var typeMap = map[string]map[string]*TypeDef{ // immutable
 
 			// native types of the flatbuffers implementation. pkg null refers
 			// to storage/null/null.fbs file
-			SerializerUNull:    "optional uint64", // fbs unsigned null
-			SerializerUNotNull: "ulong",           // fbs unsigned not null
-			SerializerNull:     "optional int64",  // fbs signed null
-			SerializerNotNull:  "long",            // fbs signed not null
+			SerializerUNull:    "ulong = null", // fbs unsigned null
+			SerializerUNotNull: "ulong",        // fbs unsigned not null
+			SerializerNull:     "long = null",  // fbs signed null
+			SerializerNotNull:  "long",         // fbs signed not null
 		},
 	},
 	"int32": {
@@ -105,9 +105,9 @@ var typeMap = map[string]map[string]*TypeDef{ // immutable
 			GoUNotNull:         "uint32",
 			GoNull:             "null.Int32",
 			GoNotNull:          "int32",
-			SerializerUNull:    "optional uint32",
+			SerializerUNull:    "uint = null",
 			SerializerUNotNull: "uint",
-			SerializerNull:     "optional int32",
+			SerializerNull:     "int = null",
 			SerializerNotNull:  "int",
 		},
 	},
@@ -133,9 +133,9 @@ var typeMap = map[string]map[string]*TypeDef{ // immutable
 			GoUNotNull:         "uint16",
 			GoNull:             "null.Int16",
 			GoNotNull:          "int16",
-			SerializerUNull:    "optional uint16",
+			SerializerUNull:    "ushort = null",
 			SerializerUNotNull: "ushort",
-			SerializerNull:     "null.Int16",
+			SerializerNull:     "short = null",
 			SerializerNotNull:  "short",
 		},
 	},
@@ -161,9 +161,9 @@ var typeMap = map[string]map[string]*TypeDef{ // immutable
 			GoUNotNull:         "uint8",
 			GoNull:             "null.Int8",
 			GoNotNull:          "int8",
-			SerializerUNull:    "optional uint8",
+			SerializerUNull:    "ubyte = null",
 			SerializerUNotNull: "ubyte",
-			SerializerNull:     "optional int8",
+			SerializerNull:     "byte = null",
 			SerializerNotNull:  "byte",
 		},
 	},
@@ -189,9 +189,9 @@ var typeMap = map[string]map[string]*TypeDef{ // immutable
 			GoUNotNull:         "float64",
 			GoNull:             "null.Float64",
 			GoNotNull:          "float64",
-			SerializerUNull:    "null.Double",
+			SerializerUNull:    "double = null",
 			SerializerUNotNull: "double",
-			SerializerNull:     "null.Double",
+			SerializerNull:     "double = null",
 			SerializerNotNull:  "double",
 		},
 	},
@@ -245,9 +245,9 @@ var typeMap = map[string]map[string]*TypeDef{ // immutable
 			GoUNotNull:         "string",
 			GoNull:             "null.String",
 			GoNotNull:          "string",
-			SerializerUNull:    "null.String",
+			SerializerUNull:    "string",
 			SerializerUNotNull: "string",
-			SerializerNull:     "null.String",
+			SerializerNull:     "string",
 			SerializerNotNull:  "string",
 		},
 	},
@@ -273,9 +273,9 @@ var typeMap = map[string]map[string]*TypeDef{ // immutable
 			GoUNotNull:         "bool",
 			GoNull:             "null.Bool",
 			GoNotNull:          "bool",
-			SerializerUNull:    "null.Bool",
+			SerializerUNull:    "bool = null",
 			SerializerUNotNull: "bool",
-			SerializerNull:     "null.Bool",
+			SerializerNull:     "bool = null",
 			SerializerNotNull:  "bool",
 		},
 	},
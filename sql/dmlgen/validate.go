@@ -0,0 +1,186 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dmlgen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/codegen"
+)
+
+// integerBits contains the storage size of the MySQL integer types.
+var integerBits = map[string]int{
+	"tinyint":   8,
+	"smallint":  16,
+	"mediumint": 24,
+	"int":       32,
+	"bigint":    64,
+}
+
+// validateRule represents a generated condition which reports a violated
+// constraint of a column.
+type validateRule struct {
+	cond string // Go expression, true if the value is invalid
+	rule string // name of a dml.Rule* constant
+	msg  string // format and arguments of dml.FieldErrors.Add
+}
+
+// isValidationManaged reports whether the value of the column gets set by the
+// database or by the generated code and therefore can't be required.
+func (t *Table) isValidationManaged(c *ddl.Column) bool {
+	return c.IsAutoIncrement() || c.IsGenerated() || c.IsSystemVersioned() ||
+		c == t.versionColumn || c == t.createdAtColumn || c == t.updatedAtColumn
+}
+
+// validateRules derives the validation rules of a column from its
+// definition: NOT NULL without default, the maximum length, the range of
+// numbers, the digits of decimals, ENUM and SET values and the supported
+// range of dates. The conditions get chained via else-if, so only the first
+// violation of a column gets reported.
+func (t *Table) validateRules(g *Generator, c *ddl.Column) []validateRule {
+	if c.IsGenerated() || c.IsSystemVersioned() {
+		return nil
+	}
+	field := `e.` + t.GoCamelMaybePrivate(c.Field)
+	goType := g.goTypeNull(c)
+
+	// value contains the expression of the primitive value and valid the
+	// condition when the value is not NULL.
+	value, valid := field, ""
+	if strings.HasPrefix(goType, "null.") {
+		valid = field + `.Valid && `
+		if goType != "null.Decimal" {
+			f := goType[5:] // 5 == len("null.")
+			if goType == "null.String" {
+				f = "Data"
+			}
+			value = field + `.` + f
+		}
+	}
+	primitive := strings.TrimPrefix(goType, "null.")
+
+	var rules []validateRule
+	add := func(cond, rule, msg string) {
+		rules = append(rules, validateRule{cond: valid + cond, rule: rule, msg: msg})
+	}
+
+	if !c.IsNull() && !c.Default.Valid && !t.isValidationManaged(c) {
+		switch goType {
+		case "string":
+			rules = append(rules, validateRule{cond: value + ` == ""`, rule: "RuleRequired", msg: `"must not be empty"`})
+		case "[]byte":
+			rules = append(rules, validateRule{cond: value + ` == nil`, rule: "RuleRequired", msg: `"must not be nil"`})
+		case "time.Time":
+			rules = append(rules, validateRule{cond: value + `.IsZero()`, rule: "RuleRequired", msg: `"must not be zero"`})
+		case "null.Decimal":
+			rules = append(rules, validateRule{cond: `!` + value + `.Valid`, rule: "RuleRequired", msg: `"must not be NULL"`})
+		}
+	}
+
+	switch dt := c.DataType; {
+	case (dt == "char" || dt == "varchar") && c.IsChar() && (primitive == "string" || primitive == "String"):
+		add(fmt.Sprintf(`utf8.RuneCountInString(%s) > %d`, value, c.CharMaxLength.Int64),
+			"RuleMaxLength", fmt.Sprintf(`"must not exceed %d characters"`, c.CharMaxLength.Int64))
+
+	case (dt == "binary" || dt == "varbinary") && c.IsChar() && goType == "[]byte":
+		add(fmt.Sprintf(`len(%s) > %d`, value, c.CharMaxLength.Int64),
+			"RuleMaxLength", fmt.Sprintf(`"must not exceed %d bytes"`, c.CharMaxLength.Int64))
+
+	case (dt == "enum" || dt == "set") && (primitive == "string" || primitive == "String"):
+		values := c.EnumValues()
+		if len(values) == 0 {
+			break
+		}
+		quoted := make([]string, len(values))
+		for i, v := range values {
+			quoted[i] = strconv.Quote(v)
+		}
+		fn, rule := "ValidEnum", "RuleEnum"
+		if dt == "set" {
+			fn, rule = "ValidSet", "RuleSet"
+		}
+		add(fmt.Sprintf(`!dml.%s(%s, %s)`, fn, value, strings.Join(quoted, ", ")),
+			rule, fmt.Sprintf(`"invalid value %%q", %s`, value))
+
+	case goType == "null.Decimal":
+		if c.IsUnsigned() {
+			add(value+`.Negative`, "RuleRange", fmt.Sprintf(`"value %%s must not be negative", %s`, value))
+		}
+		if c.Precision.Valid && c.Precision.Int64 > 0 {
+			add(fmt.Sprintf(`!dml.ValidDecimal(%s, %d, %d)`, value, c.Precision.Int64, c.Scale.Int64),
+				"RulePrecision", fmt.Sprintf(`"value %%s exceeds DECIMAL(%d,%d)", %s`, c.Precision.Int64, c.Scale.Int64, value))
+		}
+
+	case dt == "date" || dt == "datetime" || dt == "timestamp":
+		cond := fmt.Sprintf(`!dml.ValidDateRange(%q, %s)`, dt, value)
+		if goType == "time.Time" {
+			cond = `!` + value + `.IsZero() && ` + cond // zero gets checked by the required rule
+		}
+		add(cond, "RuleDateRange", fmt.Sprintf(`"%%s is out of the %s range", %s`, dt, value))
+
+	case primitive == "Float64" || primitive == "float64":
+		if c.IsUnsigned() {
+			add(value+` < 0`, "RuleRange", fmt.Sprintf(`"value %%v must not be negative", %s`, value))
+		}
+
+	case strings.Contains(strings.ToLower(primitive), "int"):
+		colBits, ok := integerBits[dt]
+		goBits, _ := strconv.Atoi(strings.TrimLeft(strings.ToLower(primitive), "uint"))
+		if !ok || goBits <= colBits {
+			break // the Go type can't hold invalid values
+		}
+		r := integerRanges[dt]
+		if c.IsUnsigned() {
+			add(fmt.Sprintf(`%s > %v`, value, r[1][1]),
+				"RuleRange", fmt.Sprintf(`"value %%d exceeds the maximum of %v", %s`, r[1][1], value))
+			break
+		}
+		add(fmt.Sprintf(`(%s < %v || %s > %v)`, value, r[0][0], value, r[0][1]),
+			"RuleRange", fmt.Sprintf(`"value %%d out of range [%v, %v]", %s`, r[0][0], r[0][1], value))
+	}
+	return rules
+}
+
+// fnEntityValidateRules writes the derived validation rules of all columns.
+// Returns false if no rules exist.
+func (t *Table) fnEntityValidateRules(mainGen *codegen.Go, g *Generator) bool {
+	if _, ok := g.customCode["type_"+t.EntityName()]; ok {
+		return false // the struct fields are unknown
+	}
+	var written bool
+	for _, c := range t.Table.Columns {
+		rules := t.validateRules(g, c)
+		if len(rules) == 0 {
+			continue
+		}
+		if !written {
+			mainGen.Pln(`var fe dml.FieldErrors`)
+			written = true
+		}
+		for i, r := range rules {
+			prefix := `if `
+			if i > 0 {
+				prefix = `} else if `
+			}
+			mainGen.Pln(prefix + r.cond + ` {`)
+			mainGen.Pln(fmt.Sprintf(`fe = fe.Add(%q, %q, dml.%s, %s)`, c.Field, t.GoCamelMaybePrivate(c.Field), r.rule, r.msg))
+		}
+		mainGen.Pln(`}`)
+	}
+	return written
+}
@@ -0,0 +1,58 @@
+package dmlgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+func TestGenerator_EntityValidate_Rules(t *testing.T) {
+	g, err := NewGenerator("github.com/corestoreio/pkg/sql/dmlgen/dmltestvalidate",
+		WithTable("customer", ddl.Columns{
+			&ddl.Column{Field: "entity_id", Pos: 1, Null: "NO", DataType: "int", ColumnType: "int(10) unsigned", Key: "PRI", Extra: "auto_increment"},
+			&ddl.Column{Field: "email", Pos: 2, Null: "NO", DataType: "varchar", CharMaxLength: null.MakeInt64(255), ColumnType: "varchar(255)"},
+			&ddl.Column{Field: "nickname", Pos: 3, Null: "YES", DataType: "varchar", CharMaxLength: null.MakeInt64(32), ColumnType: "varchar(32)"},
+			&ddl.Column{Field: "gender", Pos: 4, Null: "NO", Default: null.MakeString(`'f'`), DataType: "enum", ColumnType: "enum('f','m','d')"},
+			&ddl.Column{Field: "tags", Pos: 5, Null: "YES", DataType: "set", ColumnType: "set('a','b')"},
+			&ddl.Column{Field: "level", Pos: 6, Null: "NO", Default: null.MakeString(`3`), DataType: "mediumint", ColumnType: "mediumint(8) unsigned"},
+			&ddl.Column{Field: "score", Pos: 7, Null: "YES", DataType: "mediumint", ColumnType: "mediumint(8)"},
+			&ddl.Column{Field: "age", Pos: 8, Null: "NO", Default: null.MakeString(`0`), DataType: "tinyint", ColumnType: "tinyint(3) unsigned"},
+			&ddl.Column{Field: "grand_total", Pos: 9, Null: "NO", DataType: "decimal", Precision: null.MakeInt64(12), Scale: null.MakeInt64(4), ColumnType: "decimal(12,4) unsigned"},
+			&ddl.Column{Field: "dob", Pos: 10, Null: "YES", DataType: "date", ColumnType: "date"},
+			&ddl.Column{Field: "last_login", Pos: 11, Null: "NO", DataType: "timestamp", ColumnType: "timestamp"},
+			&ddl.Column{Field: "created_at", Pos: 12, Null: "NO", DataType: "datetime", ColumnType: "datetime"},
+		}),
+		WithTableConfig("customer", &TableConfig{
+			FeaturesInclude: FeatureEntityStruct | FeatureEntityValidate,
+			CreatedAtColumn: "created_at",
+		}),
+	)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, g.GenerateGo(&buf, new(bytes.Buffer)))
+	code := buf.String()
+
+	assert.Contains(t, code, `"unicode/utf8"`)
+	assert.Contains(t, code, "var fe dml.FieldErrors\n")
+	assert.NotContains(t, code, `fe.Add("entity_id"`)
+	assert.Contains(t, code, "if e.Email == \"\" {\n\t\tfe = fe.Add(\"email\", \"Email\", dml.RuleRequired, \"must not be empty\")\n\t} else if utf8.RuneCountInString(e.Email) > 255 {\n\t\tfe = fe.Add(\"email\", \"Email\", dml.RuleMaxLength, \"must not exceed 255 characters\")\n\t}")
+	assert.Contains(t, code, `if e.Nickname.Valid && utf8.RuneCountInString(e.Nickname.Data) > 32 {`)
+	assert.Contains(t, code, `if !dml.ValidEnum(e.Gender, "f", "m", "d") {`)
+	assert.Contains(t, code, `fe = fe.Add("gender", "Gender", dml.RuleEnum, "invalid value %q", e.Gender)`)
+	assert.Contains(t, code, `if e.Tags.Valid && !dml.ValidSet(e.Tags.Data, "a", "b") {`)
+	assert.Contains(t, code, `if e.Level > 16777215 {`)
+	assert.Contains(t, code, `if e.Score.Valid && (e.Score.Int32 < -8388608 || e.Score.Int32 > 8388607) {`)
+	assert.NotContains(t, code, `fe.Add("age"`)
+	assert.Contains(t, code, "if !e.GrandTotal.Valid {\n\t\tfe = fe.Add(\"grand_total\", \"GrandTotal\", dml.RuleRequired, \"must not be NULL\")\n\t} else if e.GrandTotal.Valid && e.GrandTotal.Negative {")
+	assert.Contains(t, code, `} else if e.GrandTotal.Valid && !dml.ValidDecimal(e.GrandTotal, 12, 4) {`)
+	assert.Contains(t, code, `if e.Dob.Valid && !dml.ValidDateRange("date", e.Dob.Time) {`)
+	assert.Contains(t, code, `} else if !e.LastLogin.IsZero() && !dml.ValidDateRange("timestamp", e.LastLogin) {`)
+	assert.Contains(t, code, `if !e.CreatedAt.IsZero() && !dml.ValidDateRange("datetime", e.CreatedAt) {`) // not required
+	assert.Contains(t, code, `if validateCustomer != nil {`)
+	assert.Contains(t, code, `return fe.Join(validateCustomer(e))`)
+	assert.Contains(t, code, "return fe.ErrorOrNil()\n}")
+}
@@ -0,0 +1,57 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mask copies or dumps table data while anonymizing personal data.
+//
+// Reproducing bugs often requires production data in a development
+// environment. Package mask streams the rows of the tables loaded in a
+// ddl.Tables object into another database connection or into a SQL file and
+// applies per column masking rules on the fly. Rules get declared in YAML and
+// get validated against the loaded ddl.Columns, so a typo or a schema change
+// does not silently leak data.
+//
+// Rule types:
+//   - fake: replaces the value with fake data from package util/pseudo. The
+//     field `fake` contains the name of the generator, e.g. email,
+//     first_name, last_name, street, city, postcode or telephone.
+//   - hash: replaces the value with the hex encoded SHA-256 hash of the salt
+//     and the value.
+//   - null: sets the value to NULL, the column must be nullable.
+//   - value: sets the value to a constant.
+//   - date_shift: shifts a date by `shift_days` plus a random amount of days
+//     between -`jitter_days` and +`jitter_days`.
+//
+// Consistent pseudonymization: A rule with a `domain` generates the same fake
+// value for the same input value. All columns with the same domain and the
+// same salt, even in different tables, map equal values to equal fake
+// values. Use this for columns which are used to join tables, for example
+// customer_entity.email and sales_order.customer_email.
+//
+// Example YAML file:
+//
+//	salt: "s3cr3t"
+//	tables:
+//	  customer_entity:
+//	    columns:
+//	      - {column: email, type: fake, fake: email, domain: customer_email}
+//	      - {column: firstname, type: fake, fake: first_name}
+//	      - {column: password_hash, type: hash}
+//	      - {column: dob, type: date_shift, shift_days: -30, jitter_days: 15}
+//	  sales_order:
+//	    columns:
+//	      - {column: customer_email, type: fake, fake: email, domain: customer_email}
+//	      - {column: remote_ip, type: "null"}
+//	  customer_log:
+//	    skip: true
+package mask
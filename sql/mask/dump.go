@@ -0,0 +1,188 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mask
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/sql/dml"
+)
+
+// batchFn receives a batch of masked rows. The slices are only valid during
+// the call.
+type batchFn func(table string, columns []string, rows [][][]byte) error
+
+// Copy streams the masked rows of the tables into the connection pool dst.
+// The tables must already exist in dst. Foreign key checks are disabled
+// during the copy. An empty tables argument copies all tables which are not
+// skipped.
+func (m *Masker) Copy(ctx context.Context, dst *dml.ConnPool, tables ...string) (err error) {
+	con, err := dst.Conn(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		if errC := con.Close(); err == nil && errC != nil {
+			err = errors.WithStack(errC)
+		}
+	}()
+	if _, err := con.DB.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS=0"); err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = m.stream(ctx, tables, func(table string, columns []string, rows [][][]byte) error {
+		_, err := con.WithQueryBuilder(insertStmt(table, columns, len(rows))).ExecContext(ctx, flattenRows(rows)...)
+		return errors.WithStack(err)
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = con.DB.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS=1")
+	return errors.WithStack(err)
+}
+
+// Dump writes the masked rows of the tables as INSERT statements to w. An
+// empty tables argument dumps all tables which are not skipped.
+func (m *Masker) Dump(ctx context.Context, w io.Writer, tables ...string) error {
+	if _, err := io.WriteString(w, "SET FOREIGN_KEY_CHECKS=0;\n"); err != nil {
+		return errors.WithStack(err)
+	}
+	lastTable := ""
+	err := m.stream(ctx, tables, func(table string, columns []string, rows [][][]byte) error {
+		if table != lastTable {
+			if _, err := fmt.Fprintf(w, "\n-- Data for table %s\n", dml.Quoter.QualifierName("", table)); err != nil {
+				return errors.WithStack(err)
+			}
+			lastTable = table
+		}
+		rawSQL, _, err := insertStmt(table, columns, len(rows)).ToSQL()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		qry, _, err := dml.Interpolate(rawSQL).Unsafe(flattenRows(rows)...).ToSQL()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		_, err = fmt.Fprintf(w, "%s;\n", qry)
+		return errors.WithStack(err)
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = io.WriteString(w, "\nSET FOREIGN_KEY_CHECKS=1;\n")
+	return errors.WithStack(err)
+}
+
+func insertStmt(table string, columns []string, rowCount int) *dml.Insert {
+	return dml.NewInsert(table).AddColumns(columns...).SetRowCount(rowCount).BuildValues()
+}
+
+// flattenRows converts the rows into arguments. A nil byte slice becomes a
+// NULL value.
+func flattenRows(rows [][][]byte) []any {
+	if len(rows) == 0 {
+		return nil
+	}
+	args := make([]any, 0, len(rows)*len(rows[0]))
+	for _, row := range rows {
+		for _, v := range row {
+			if v == nil {
+				args = append(args, nil)
+				continue
+			}
+			args = append(args, v)
+		}
+	}
+	return args
+}
+
+// stream reads all rows of the tables, masks them and calls fn for each batch.
+func (m *Masker) stream(ctx context.Context, tables []string, fn batchFn) error {
+	if len(tables) == 0 {
+		tables = m.TableNames()
+	}
+	for _, tn := range tables {
+		if m.rules.Tables[tn].Skip {
+			continue
+		}
+		if err := m.streamTable(ctx, tn, fn); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (m *Masker) streamTable(ctx context.Context, table string, fn batchFn) error {
+	columns, err := m.Columns(table)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	rawSQL, _, err := dml.NewSelect(columns...).From(table).ToSQL()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	rows, err := m.src.ConnPool.DB.QueryContext(ctx, rawSQL)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	raw := make([]sql.RawBytes, len(columns))
+	dest := make([]any, len(columns))
+	for i := range raw {
+		dest[i] = &raw[i]
+	}
+	batch := make([][][]byte, 0, m.opt.BatchSize)
+	var rowCount int
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return errors.WithStack(err)
+		}
+		row := make([][]byte, len(raw))
+		for i, r := range raw {
+			if r != nil {
+				row[i] = append([]byte{}, r...) // RawBytes gets reused
+			}
+		}
+		if err := m.MaskRow(table, row); err != nil {
+			return errors.WithStack(err)
+		}
+		batch = append(batch, row)
+		rowCount++
+		if len(batch) == m.opt.BatchSize {
+			if err := fn(table, columns, batch); err != nil {
+				return errors.WithStack(err)
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.WithStack(err)
+	}
+	if len(batch) > 0 {
+		if err := fn(table, columns, batch); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if m.opt.Log.IsInfo() {
+		m.opt.Log.Info("mask.Masker.streamTable", log.String("table", table), log.Int("rows", rowCount))
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mask_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/sql/mask"
+	"github.com/corestoreio/pkg/storage/null"
+	"github.com/corestoreio/pkg/util/assert"
+	"github.com/corestoreio/pkg/util/pseudo"
+)
+
+func newTables(t *testing.T, dbc *dml.ConnPool) *ddl.Tables {
+	varchar := func(field string, pos uint64, nullable string) *ddl.Column {
+		return &ddl.Column{Field: field, Pos: pos, DataType: "varchar", Null: nullable, CharMaxLength: null.MakeInt64(255)}
+	}
+	tbls, err := ddl.NewTables(
+		ddl.WithConnPool(dbc),
+		ddl.WithTable("customer_entity",
+			&ddl.Column{Field: "entity_id", Pos: 1, DataType: "int", Key: "PRI"},
+			varchar("email", 2, "NO"),
+			varchar("firstname", 3, "YES"),
+			&ddl.Column{Field: "password_hash", Pos: 4, DataType: "varchar", Null: "YES", CharMaxLength: null.MakeInt64(16)},
+			&ddl.Column{Field: "dob", Pos: 5, DataType: "date", Null: "YES"},
+			varchar("rp_token", 6, "YES"),
+			&ddl.Column{Field: "group_id", Pos: 7, DataType: "int"},
+			&ddl.Column{Field: "email_lower", Pos: 8, DataType: "varchar", Generated: "ALWAYS"},
+		),
+		ddl.WithTable("sales_order",
+			&ddl.Column{Field: "entity_id", Pos: 1, DataType: "int", Key: "PRI"},
+			varchar("customer_email", 2, "YES"),
+		),
+		ddl.WithTable("customer_log",
+			&ddl.Column{Field: "log_id", Pos: 1, DataType: "int", Key: "PRI"},
+		),
+	)
+	assert.NoError(t, err)
+	return tbls
+}
+
+func TestRules_Validate(t *testing.T) {
+	tbls := newTables(t, nil)
+
+	t.Run("testdata ok", func(t *testing.T) {
+		rs, err := mask.ParseRulesFile("testdata/rules.yaml")
+		assert.NoError(t, err)
+		assert.NoError(t, rs.Validate(tbls, nil))
+		assert.True(t, rs.Tables["customer_log"].Skip)
+	})
+
+	tests := []struct {
+		name string
+		yaml string
+		kind errors.Kind
+	}{
+		{"unknown field", "tables: {sales_order: {columnz: []}}", errors.NotValid},
+		{"table not found", "tables: {sales_flat_order: {columns: []}}", errors.NotFound},
+		{"column not found", "tables: {sales_order: {columns: [{column: email, type: hash}]}}", errors.NotFound},
+		{"generated column", "tables: {customer_entity: {columns: [{column: email_lower, type: hash}]}}", errors.NotValid},
+		{"not nullable", `tables: {customer_entity: {columns: [{column: email, type: "null"}]}}`, errors.NotValid},
+		{"hash on int", "tables: {customer_entity: {columns: [{column: group_id, type: hash}]}}", errors.NotValid},
+		{"date shift on varchar", "tables: {customer_entity: {columns: [{column: email, type: date_shift}]}}", errors.NotValid},
+		{"fake without generator", "tables: {customer_entity: {columns: [{column: email, type: fake}]}}", errors.Empty},
+		{"unknown type", "tables: {customer_entity: {columns: [{column: email, type: scramble}]}}", errors.NotSupported},
+		{"duplicate rule", "tables: {sales_order: {columns: [{column: customer_email, type: hash},{column: customer_email, type: hash}]}}", errors.AlreadyExists},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rs, err := mask.ParseRules(strings.NewReader(test.yaml))
+			if err == nil {
+				err = rs.Validate(tbls, nil)
+			}
+			assert.ErrorIsKind(t, test.kind, err)
+		})
+	}
+
+	t.Run("unknown fake generator", func(t *testing.T) {
+		rs, err := mask.ParseRules(strings.NewReader("tables: {sales_order: {columns: [{column: customer_email, type: fake, fake: emial}]}}"))
+		assert.NoError(t, err)
+		m, err := mask.NewMasker(tbls, rs, mask.Options{})
+		assert.ErrorIsKind(t, errors.NotFound, err)
+		assert.Nil(t, m)
+	})
+}
+
+func TestMasker_MaskRow(t *testing.T) {
+	rs, err := mask.ParseRulesFile("testdata/rules.yaml")
+	assert.NoError(t, err)
+	m, err := mask.NewMasker(newTables(t, nil), rs, mask.Options{Seed: 1})
+	assert.NoError(t, err)
+
+	cols, err := m.Columns("customer_entity")
+	assert.NoError(t, err)
+	assert.Exactly(t, []string{"entity_id", "email", "firstname", "password_hash", "dob", "rp_token", "group_id"}, cols)
+	assert.Exactly(t, []string{"customer_entity", "sales_order"}, m.TableNames())
+
+	row := [][]byte{[]byte("1"), []byte("jane@example.com"), []byte("Jane"), []byte("$2y$10$xyz"), []byte("1980-03-15"), []byte("token"), []byte("4")}
+	assert.NoError(t, m.MaskRow("customer_entity", row))
+
+	assert.Exactly(t, "1", string(row[0]))
+	assert.NotEqual(t, "jane@example.com", string(row[1]))
+	assert.True(t, strings.Contains(string(row[1]), "@"), "%q", row[1])
+	assert.NotEqual(t, "Jane", string(row[2]))
+	assert.Len(t, row[3], 16)
+	assert.Exactly(t, "1980-02-14", string(row[4]))
+	assert.Nil(t, row[5])
+	assert.Exactly(t, "1", string(row[6]))
+
+	t.Run("consistent across tables", func(t *testing.T) {
+		orderRow := [][]byte{[]byte("33"), []byte("jane@example.com")}
+		assert.NoError(t, m.MaskRow("sales_order", orderRow))
+		assert.Exactly(t, string(row[1]), string(orderRow[1]))
+
+		otherRow := [][]byte{[]byte("34"), []byte("john@example.com")}
+		assert.NoError(t, m.MaskRow("sales_order", otherRow))
+		assert.NotEqual(t, string(row[1]), string(otherRow[1]))
+	})
+	t.Run("NULL stays NULL", func(t *testing.T) {
+		orderRow := [][]byte{[]byte("35"), nil}
+		assert.NoError(t, m.MaskRow("sales_order", orderRow))
+		assert.Nil(t, orderRow[1])
+	})
+	t.Run("row length mismatch", func(t *testing.T) {
+		err := m.MaskRow("sales_order", [][]byte{[]byte("35")})
+		assert.ErrorIsKind(t, errors.Mismatch, err)
+	})
+}
+
+func TestMasker_MaskRow_MultiByte(t *testing.T) {
+	tbls, err := ddl.NewTables(ddl.WithTable("customer_entity",
+		&ddl.Column{Field: "entity_id", Pos: 1, DataType: "int", Key: "PRI"},
+		&ddl.Column{Field: "firstname", Pos: 2, DataType: "varchar", Null: "YES", CharMaxLength: null.MakeInt64(3)},
+		&ddl.Column{Field: "lastname", Pos: 3, DataType: "varchar", Null: "YES", CharMaxLength: null.MakeInt64(5)},
+	))
+	assert.NoError(t, err)
+	rs, err := mask.ParseRules(strings.NewReader(`
+tables:
+  customer_entity:
+    columns:
+      - {column: firstname, type: fake, fake: first_name}
+      - {column: lastname, type: value, value: "Jäger"}
+`))
+	assert.NoError(t, err)
+	assert.NoError(t, rs.Validate(tbls, nil), "Jäger has five characters")
+
+	m, err := mask.NewMasker(tbls, rs, mask.Options{Seed: 1, Pseudo: &pseudo.Options{Lang: "ru"}})
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		row := [][]byte{[]byte("1"), []byte("Jane"), []byte("Doe")}
+		assert.NoError(t, m.MaskRow("customer_entity", row))
+		assert.True(t, utf8.Valid(row[1]), "%q", row[1])
+		assert.Exactly(t, 3, utf8.RuneCount(row[1]), "%q", row[1])
+		assert.True(t, len(row[1]) > 3, "%q must contain multi byte runes", row[1])
+		assert.Exactly(t, "Jäger", string(row[2]))
+	}
+}
+
+func TestMasker_Dump(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	rs, err := mask.ParseRules(strings.NewReader(`
+salt: pepper
+tables:
+  sales_order:
+    columns:
+      - {column: customer_email, type: value, value: "masked@example.com"}
+  customer_entity:
+    skip: true
+  customer_log:
+    skip: true
+`))
+	assert.NoError(t, err)
+	m, err := mask.NewMasker(newTables(t, dbc), rs, mask.Options{BatchSize: 2})
+	assert.NoError(t, err)
+
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `entity_id`, `customer_email` FROM `sales_order`")).
+		WillReturnRows(sqlmock.NewRows([]string{"entity_id", "customer_email"}).
+			AddRow("1", "a@b.c").AddRow("2", nil).AddRow("3", "d@e.f"))
+
+	var buf bytes.Buffer
+	assert.NoError(t, m.Dump(context.Background(), &buf))
+	assert.Exactly(t, "SET FOREIGN_KEY_CHECKS=0;\n\n"+
+		"-- Data for table `sales_order`\n"+
+		"INSERT INTO `sales_order` (`entity_id`,`customer_email`) VALUES ('1','masked@example.com'),('2','masked@example.com');\n"+
+		"INSERT INTO `sales_order` (`entity_id`,`customer_email`) VALUES ('3','masked@example.com');\n"+
+		"\nSET FOREIGN_KEY_CHECKS=1;\n", buf.String())
+}
+
+func TestMasker_Copy(t *testing.T) {
+	srcDB, srcMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, srcDB, srcMock)
+	dstDB, dstMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dstDB, dstMock)
+
+	rs, err := mask.ParseRules(strings.NewReader(`
+tables:
+  sales_order:
+    columns:
+      - {column: customer_email, type: "null"}
+`))
+	assert.NoError(t, err)
+	m, err := mask.NewMasker(newTables(t, srcDB), rs, mask.Options{})
+	assert.NoError(t, err)
+
+	srcMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT `entity_id`, `customer_email` FROM `sales_order`")).
+		WillReturnRows(sqlmock.NewRows([]string{"entity_id", "customer_email"}).AddRow("1", "a@b.c").AddRow("2", "d@e.f"))
+
+	dstMock.ExpectExec("SET FOREIGN_KEY_CHECKS=0").WillReturnResult(sqlmock.NewResult(0, 0))
+	dstMock.ExpectExec(dmltest.SQLMockQuoteMeta("INSERT INTO `sales_order` (`entity_id`,`customer_email`) VALUES (?,?),(?,?)")).
+		WithArgs([]byte("1"), nil, []byte("2"), nil).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	dstMock.ExpectExec("SET FOREIGN_KEY_CHECKS=1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, m.Copy(context.Background(), dstDB, "sales_order"))
+}
@@ -0,0 +1,275 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mask
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/pseudo"
+)
+
+// Options configures a Masker.
+type Options struct {
+	// Seed initializes the random source for the non-consistent fake data.
+	// Zero uses the current time.
+	Seed uint64
+	// Pseudo optional options for the fake data service.
+	Pseudo *pseudo.Options
+	// BatchSize defines the number of rows in one INSERT statement. Defaults
+	// to 200.
+	BatchSize int
+	// Log optional.
+	Log log.Logger
+}
+
+// maskFn masks a raw column value. A nil value represents NULL.
+type maskFn func(v []byte) ([]byte, error)
+
+type tableMask struct {
+	tbl     *ddl.Table
+	columns []string
+	masks   []maskFn // same index as columns, nil keeps the value
+}
+
+// Masker masks the rows of tables according to the Rules. It is safe for
+// concurrent use.
+type Masker struct {
+	src   *ddl.Tables
+	rules *Rules
+	opt   Options
+	// mu protects fake because consistent pseudonymization re-seeds it.
+	mu     sync.Mutex
+	fake   *pseudo.Service
+	jitter *rand.Rand
+	tables map[string]*tableMask
+}
+
+// NewMasker creates a new Masker for the tables in src. The rules get
+// validated against the columns of src.
+func NewMasker(src *ddl.Tables, rs *Rules, o Options) (*Masker, error) {
+	if src == nil || rs == nil {
+		return nil, errors.NotValid.Newf("[mask] Tables and Rules are required")
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 200
+	}
+	if o.Log == nil {
+		o.Log = log.BlackHole{}
+	}
+	fake, err := pseudo.NewService(o.Seed, o.Pseudo)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := rs.Validate(src, fake); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	seed := int64(o.Seed)
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Masker{
+		src:    src,
+		rules:  rs,
+		opt:    o,
+		fake:   fake,
+		jitter: rand.New(rand.NewSource(seed)),
+		tables: make(map[string]*tableMask),
+	}, nil
+}
+
+// TableNames returns the sorted names of all tables which are not skipped.
+func (m *Masker) TableNames() []string {
+	tns := m.src.Tables()
+	ret := tns[:0]
+	for _, tn := range tns {
+		if !m.rules.Tables[tn].Skip {
+			ret = append(ret, tn)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Columns returns the columns of a table which get copied, in the order
+// expected by MaskRow. Generated columns are excluded.
+func (m *Masker) Columns(table string) ([]string, error) {
+	tm, err := m.tableMask(table)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return tm.columns, nil
+}
+
+// MaskRow masks the values of a row in place. The row must contain the values
+// of the columns returned by function Columns, a nil value represents NULL.
+func (m *Masker) MaskRow(table string, row [][]byte) error {
+	tm, err := m.tableMask(table)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(row) != len(tm.columns) {
+		return errors.Mismatch.Newf("[mask] Table %q: row has %d values but %d columns are expected", table, len(row), len(tm.columns))
+	}
+	for i, fn := range tm.masks {
+		if fn == nil {
+			continue
+		}
+		if row[i], err = fn(row[i]); err != nil {
+			return errors.Wrapf(err, "[mask] Table %q column %q", table, tm.columns[i])
+		}
+	}
+	return nil
+}
+
+func (m *Masker) tableMask(table string) (*tableMask, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if tm, ok := m.tables[table]; ok {
+		return tm, nil
+	}
+	t, err := m.src.Table(table)
+	if err != nil {
+		return nil, errors.NotFound.Newf("[mask] Table %q not found: %s", table, err)
+	}
+	rules := make(map[string]Rule, len(m.rules.Tables[table].Columns))
+	for _, r := range m.rules.Tables[table].Columns {
+		rules[r.Column] = r
+	}
+	tm := &tableMask{tbl: t}
+	for _, c := range t.Columns {
+		if c.IsGenerated() {
+			continue
+		}
+		tm.columns = append(tm.columns, c.Field)
+		var fn maskFn
+		if r, ok := rules[c.Field]; ok {
+			fn = m.newMaskFn(r, c)
+		}
+		tm.masks = append(tm.masks, fn)
+	}
+	m.tables[table] = tm
+	return tm, nil
+}
+
+func (m *Masker) newMaskFn(r Rule, c *ddl.Column) maskFn {
+	maxLen := 0
+	if c.CharMaxLength.Valid {
+		maxLen = int(c.CharMaxLength.Int64)
+	}
+	// CHARACTER_MAXIMUM_LENGTH counts characters, so cut after maxLen runes.
+	truncate := func(p []byte) []byte {
+		if maxLen <= 0 || utf8.RuneCount(p) <= maxLen {
+			return p
+		}
+		n := 0
+		for i := 0; i < maxLen; i++ {
+			_, size := utf8.DecodeRune(p[n:])
+			n += size
+		}
+		return p[:n]
+	}
+
+	switch r.Type {
+	case RuleFake:
+		return func(v []byte) ([]byte, error) {
+			if v == nil {
+				return nil, nil
+			}
+			m.mu.Lock()
+			if r.Domain != "" {
+				m.fake.Seed(m.seed(r.Domain, v))
+			}
+			fv, _ := m.fake.FakeByTag(r.Fake, maxLen)
+			m.mu.Unlock()
+			return truncate([]byte(fmt.Sprint(fv))), nil
+		}
+	case RuleHash:
+		return func(v []byte) ([]byte, error) {
+			if v == nil {
+				return nil, nil
+			}
+			h := sha256.New()
+			_, _ = h.Write([]byte(m.rules.Salt))
+			_, _ = h.Write(v)
+			return truncate([]byte(hex.EncodeToString(h.Sum(nil)))), nil
+		}
+	case RuleNull:
+		return func([]byte) ([]byte, error) { return nil, nil }
+	case RuleValue:
+		return func([]byte) ([]byte, error) { return []byte(r.Value), nil }
+	case RuleDateShift:
+		return func(v []byte) ([]byte, error) {
+			if v == nil {
+				return nil, nil
+			}
+			return m.shiftDate(r, v)
+		}
+	}
+	return nil // already validated
+}
+
+// seed calculates a deterministic seed from the salt, the domain and the
+// value.
+func (m *Masker) seed(domain string, v []byte) uint64 {
+	h := sha256.New()
+	_, _ = h.Write([]byte(m.rules.Salt))
+	_, _ = h.Write([]byte(domain))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(v)
+	return binary.LittleEndian.Uint64(h.Sum(nil))
+}
+
+var dateLayouts = [...]string{"2006-01-02 15:04:05.999999", "2006-01-02 15:04:05", "2006-01-02"}
+
+func (m *Masker) shiftDate(r Rule, v []byte) ([]byte, error) {
+	str := string(v)
+	if str == "0000-00-00" || str == "0000-00-00 00:00:00" {
+		return v, nil
+	}
+	for _, layout := range dateLayouts {
+		t, err := time.Parse(layout, str)
+		if err != nil {
+			continue
+		}
+		days := r.ShiftDays
+		if r.JitterDays > 0 {
+			var j int
+			if r.Domain != "" {
+				j = int(m.seed(r.Domain, v) % uint64(2*r.JitterDays+1))
+			} else {
+				m.mu.Lock()
+				j = m.jitter.Intn(2*r.JitterDays + 1)
+				m.mu.Unlock()
+			}
+			days += j - r.JitterDays
+		}
+		if len(str) > len(dateLayouts[1]) {
+			layout = "2006-01-02 15:04:05." + "000000"[:len(str)-len(dateLayouts[1])-1]
+		}
+		return []byte(t.AddDate(0, 0, days).Format(layout)), nil
+	}
+	return nil, errors.BadEncoding.Newf("[mask] Cannot parse %q as date", str)
+}
@@ -0,0 +1,161 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mask
+
+import (
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/pseudo"
+	"gopkg.in/yaml.v2"
+)
+
+// Rule types, see package documentation.
+const (
+	RuleFake      = "fake"
+	RuleHash      = "hash"
+	RuleNull      = "null"
+	RuleValue     = "value"
+	RuleDateShift = "date_shift"
+)
+
+// Rule defines how to mask the value of a column.
+type Rule struct {
+	Column string `yaml:"column"`
+	Type   string `yaml:"type"`
+	// Fake defines the name of the util/pseudo generator for type fake.
+	Fake string `yaml:"fake,omitempty"`
+	// Value defines the constant for type value.
+	Value string `yaml:"value,omitempty"`
+	// ShiftDays and JitterDays are used by type date_shift.
+	ShiftDays  int `yaml:"shift_days,omitempty"`
+	JitterDays int `yaml:"jitter_days,omitempty"`
+	// Domain enables consistent pseudonymization. Equal values in columns with
+	// the same domain get masked to equal values.
+	Domain string `yaml:"domain,omitempty"`
+}
+
+// TableRules defines the masking rules of a table.
+type TableRules struct {
+	// Skip excludes the table data from being copied or dumped.
+	Skip    bool   `yaml:"skip,omitempty"`
+	Columns []Rule `yaml:"columns"`
+}
+
+// Rules defines the masking rules for all tables.
+type Rules struct {
+	// Salt gets used for hashing and for the consistent pseudonymization.
+	// Changing the salt changes all masked values.
+	Salt   string                `yaml:"salt"`
+	Tables map[string]TableRules `yaml:"tables"`
+}
+
+// ParseRules reads the rules from a YAML stream. Unknown fields are
+// treated as an error.
+func ParseRules(r io.Reader) (*Rules, error) {
+	d := yaml.NewDecoder(r)
+	d.SetStrict(true)
+	var rs Rules
+	if err := d.Decode(&rs); err != nil && err != io.EOF {
+		return nil, errors.NotValid.New(err, "[mask] Failed to parse YAML rules")
+	}
+	return &rs, nil
+}
+
+// ParseRulesFile reads the rules from a YAML file.
+func ParseRulesFile(fileName string) (*Rules, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer f.Close()
+	rs, err := ParseRules(f)
+	return rs, errors.WithStack(err)
+}
+
+// Validate checks that all tables and columns exist in tbls and that the
+// rule types fit to the column types. A nil fake service skips the check of
+// the generator names.
+func (rs *Rules) Validate(tbls *ddl.Tables, fake *pseudo.Service) error {
+	for tn, tr := range rs.Tables {
+		t, err := tbls.Table(tn)
+		if err != nil {
+			return errors.NotFound.Newf("[mask] Table %q not found: %s", tn, err)
+		}
+		seen := make(map[string]bool, len(tr.Columns))
+		for _, r := range tr.Columns {
+			if seen[r.Column] {
+				return errors.AlreadyExists.Newf("[mask] Table %q: column %q has more than one rule", tn, r.Column)
+			}
+			seen[r.Column] = true
+			if err := r.validate(t.Columns.ByField(r.Column), fake); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		if tr.Skip && len(tr.Columns) > 0 {
+			return errors.NotValid.Newf("[mask] Table %q is skipped but has column rules", tn)
+		}
+	}
+	return nil
+}
+
+func (r Rule) validate(c *ddl.Column, fake *pseudo.Service) error {
+	if c.Field == "" {
+		return errors.NotFound.Newf("[mask] Column %q not found", r.Column)
+	}
+	if c.IsGenerated() {
+		return errors.NotValid.Newf("[mask] Column %q is generated and cannot be masked", r.Column)
+	}
+	isText := c.IsChar() || c.IsBlobDataType()
+	switch r.Type {
+	case RuleFake:
+		if !isText {
+			return errors.NotValid.Newf("[mask] Column %q with data type %q does not support rule %q", r.Column, c.DataType, r.Type)
+		}
+		if r.Fake == "" {
+			return errors.Empty.Newf("[mask] Column %q: rule %q requires the field fake", r.Column, r.Type)
+		}
+		if fake != nil {
+			if _, ok := fake.FakeByTag(r.Fake, 0); !ok {
+				return errors.NotFound.Newf("[mask] Column %q: fake generator %q not found", r.Column, r.Fake)
+			}
+		}
+	case RuleHash:
+		if !isText {
+			return errors.NotValid.Newf("[mask] Column %q with data type %q does not support rule %q", r.Column, c.DataType, r.Type)
+		}
+	case RuleNull:
+		if !c.IsNull() {
+			return errors.NotValid.Newf("[mask] Column %q is not nullable", r.Column)
+		}
+	case RuleValue:
+		if c.CharMaxLength.Valid && int64(utf8.RuneCountInString(r.Value)) > c.CharMaxLength.Int64 {
+			return errors.NotValid.Newf("[mask] Column %q: value %q exceeds the maximum length of %d", r.Column, r.Value, c.CharMaxLength.Int64)
+		}
+	case RuleDateShift:
+		if !c.IsTime() || c.DataType == "time" {
+			return errors.NotValid.Newf("[mask] Column %q with data type %q does not support rule %q", r.Column, c.DataType, r.Type)
+		}
+		if r.JitterDays < 0 {
+			return errors.NotValid.Newf("[mask] Column %q: jitter_days must not be negative", r.Column)
+		}
+	default:
+		return errors.NotSupported.Newf("[mask] Column %q: rule type %q not supported", r.Column, r.Type)
+	}
+	return nil
+}
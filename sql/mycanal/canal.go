@@ -45,7 +45,7 @@ const (
 // Canal can sync your MySQL data. MySQL must use the binlog format ROW.
 type Canal struct {
 	opts                      Options
-	configPathBackendPosition *config.Path
+	configPathBackendPosition config.Path
 	// mclose acts only during the call to Close().
 	mclose sync.Mutex
 	// DSN contains the parsed DSN
@@ -93,7 +93,7 @@ type DBConFactory func(dsn string) (*dml.ConnPool, error)
 // from the provided DSN.
 func WithMySQL() DBConFactory {
 	return func(dsn string) (*dml.ConnPool, error) {
-		dbc, err := dml.NewConnPool(dml.WithDSN(dsn), dml.WithVerifyConnection())
+		dbc, err := dml.NewConnPool(dml.WithDSN(dsn), dml.WithVerifyConnection(context.Background(), time.Second))
 		return dbc, errors.WithStack(err)
 	}
 }
@@ -0,0 +1,109 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package myghost
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/mycanal"
+)
+
+var _ mycanal.RowsEventHandler = (*Migrator)(nil)
+
+// Do applies the binary log row events of the original table to the ghost
+// table. Inserts and updates run as REPLACE, so rows which have not yet been
+// copied get created and the later chunk copy skips them via INSERT IGNORE.
+func (m *Migrator) Do(ctx context.Context, action string, t *ddl.Table, rows [][]any) error {
+	if atomic.LoadInt32(&m.cutOver) == 1 || m.ghost == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var err error
+	switch action {
+	case mycanal.InsertAction:
+		for _, row := range rows {
+			if err = m.replaceRow(ctx, row); err != nil {
+				break
+			}
+		}
+	case mycanal.UpdateAction:
+		if len(rows)%2 == 1 {
+			return errors.CorruptData.Newf("[myghost] Update event for table %q contains an odd number of rows: %d", t.Name, len(rows))
+		}
+		for i := 0; i < len(rows) && err == nil; i += 2 {
+			before, after := rows[i], rows[i+1]
+			if m.pkIdx < len(before) && m.pkIdx < len(after) && fmt.Sprint(before[m.pkIdx]) != fmt.Sprint(after[m.pkIdx]) {
+				if err = m.deleteRow(ctx, before); err != nil {
+					break
+				}
+			}
+			err = m.replaceRow(ctx, after)
+		}
+	case mycanal.DeleteAction:
+		for _, row := range rows {
+			if err = m.deleteRow(ctx, row); err != nil {
+				break
+			}
+		}
+	default:
+		return errors.NotSupported.Newf("[myghost] Action %q not supported", action)
+	}
+	if err != nil {
+		if m.opt.Log.IsInfo() {
+			m.opt.Log.Info("myghost.Migrator.Do.error", log.Err(err), log.String("action", action), log.String("table", t.Name))
+		}
+		return errors.WithStack(err)
+	}
+	atomic.AddInt64(&m.appliedRows, int64(len(rows)))
+	return nil
+}
+
+// Complete does nothing.
+func (m *Migrator) Complete(context.Context) error { return nil }
+
+// String returns the name of the handler.
+func (m *Migrator) String() string { return "myghost:" + m.opt.Table }
+
+func (m *Migrator) replaceRow(ctx context.Context, row []any) error {
+	args := make([]any, 0, len(m.colIdx))
+	for _, idx := range m.colIdx {
+		if idx >= len(row) {
+			return errors.CorruptData.Newf("[myghost] Row for table %q has %d columns, expected at least %d", m.opt.Table, len(row), idx+1)
+		}
+		args = append(args, row[idx])
+	}
+	_, err := m.tables.ConnPool.WithQueryBuilder(
+		dml.NewInsert(m.ghostName).Replace().AddColumns(m.columns...).BuildValues(),
+	).ExecContext(ctx, args...)
+	return errors.WithStack(err)
+}
+
+func (m *Migrator) deleteRow(ctx context.Context, row []any) error {
+	if m.pkIdx < 0 || m.pkIdx >= len(row) {
+		return errors.CorruptData.Newf("[myghost] Row for table %q does not contain the primary key", m.opt.Table)
+	}
+	_, err := m.tables.ConnPool.WithQueryBuilder(
+		dml.NewDelete(m.ghostName).Where(dml.Column(m.pkName).PlaceHolder()),
+	).ExecContext(ctx, row[m.pkIdx])
+	return errors.WithStack(err)
+}
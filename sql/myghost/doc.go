@@ -0,0 +1,51 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package myghost runs triggerless online schema changes for MySQL/MariaDB
+// tables, inspired by github.com/github/gh-ost.
+//
+// # Overview
+//
+// Instead of altering a busy table in place, the Migrator creates a ghost
+// table with the same structure as the original table and applies the ALTER
+// statement to the empty ghost table. Rows of the original table get copied in
+// small, throttled chunks into the ghost table. Concurrent changes to the
+// original table are read from the binary log via package mycanal and get
+// applied to the ghost table. No triggers are involved, see the documentation
+// of package mycanal for the reasons.
+//
+// # Cut-over
+//
+// Once all rows have been copied, the Migrator locks the original table, waits
+// until mycanal has applied all binary log events up to the current master
+// position and swaps both tables with one atomic RENAME TABLE statement. The
+// RENAME gets issued from a second connection only after the catch-up, because
+// a queued RENAME blocks the writes to the ghost table. Once the process list
+// shows the RENAME waiting for the metadata lock, the lock gets released, so
+// the database executes it before any queued DML statement. If anything fails
+// in between, the RENAME gets killed before the lock gets released.
+// Applications see a short stall of writes but never a missing table.
+//
+// # Throttling
+//
+// The Throttler pauses the row copy while a pause file exists or while the
+// replication lag of any registered replica exceeds a threshold.
+//
+// # Requirements
+//
+// The binary log format must be ROW and the original table must have a
+// single column integer primary key. The mycanal.Canal must be created with a
+// start position at or before the time the Migrator runs, so no change gets
+// missed.
+package myghost
@@ -0,0 +1,420 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package myghost
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/mycanal"
+)
+
+// Canal defines the functions of *mycanal.Canal which the Migrator requires.
+// The canal must already run or must get started by the caller.
+type Canal interface {
+	RegisterRowsEventHandler(tableNames []string, h ...mycanal.RowsEventHandler)
+	SyncedPosition() ddl.MasterStatus
+}
+
+// Options configures the Migrator.
+type Options struct {
+	// Table defines the name of the table to alter. Required.
+	Table string
+	// Alter contains the ALTER TABLE specification without the leading "ALTER
+	// TABLE `name`", for example: "ADD COLUMN `note` TEXT, DROP KEY `idx_x`".
+	// Required.
+	Alter string
+	// ChunkSize defines the range of primary key values which gets copied
+	// with one statement. Defaults to 1000.
+	ChunkSize int64
+	// Throttler optional pauses the row copy.
+	Throttler *Throttler
+	// CutOverLockTimeout defines the WAIT timeout of the LOCK TABLES and RENAME
+	// TABLE statement during the cut-over. Defaults to 3s.
+	CutOverLockTimeout time.Duration
+	// CatchUpTimeout defines how long the cut-over waits, while holding the
+	// table lock, for the canal to apply all pending binary log events.
+	// Defaults to 10s.
+	CatchUpTimeout time.Duration
+	// PollInterval defines the interval for checking the state of the RENAME
+	// and the binary log position during the cut-over. Defaults to 50ms.
+	PollInterval time.Duration
+	// KeepOldTable renames the original table to _<table>_del instead of
+	// dropping it.
+	KeepOldTable bool
+	// Log optional.
+	Log log.Logger
+}
+
+// Migrator alters a table by copying its rows into an altered ghost table
+// and swapping both tables at the end. A Migrator can only run once.
+type Migrator struct {
+	tables *ddl.Tables
+	canal  Canal
+	opt    Options
+
+	orig      *ddl.Table
+	ghost     *ddl.Table
+	ghostName string
+	oldName   string
+
+	// columns contains the column names which exist in both tables and which
+	// are not generated. colIdx contains their index in the original table.
+	columns []string
+	colIdx  []int
+	pkName  string
+	pkIdx   int
+
+	// mu serializes the application of binary log events.
+	mu sync.Mutex
+	// cutOver gets set after the tables have been swapped, all following
+	// events are for the new table and must get ignored.
+	cutOver     int32
+	copiedRows  int64
+	appliedRows int64
+}
+
+// NewMigrator creates a new Migrator for the table defined in Options.Table.
+// The table gets loaded from tables, which requires a connection pool. The
+// Migrator registers itself as a RowsEventHandler in the canal when Run gets
+// called.
+func NewMigrator(tables *ddl.Tables, c Canal, o Options) (*Migrator, error) {
+	if tables == nil || tables.ConnPool == nil {
+		return nil, errors.NotValid.Newf("[myghost] Tables and its ConnPool are required")
+	}
+	if c == nil {
+		return nil, errors.NotValid.Newf("[myghost] Canal is required")
+	}
+	if err := dml.IsValidIdentifier(o.Table); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if strings.TrimSpace(o.Alter) == "" {
+		return nil, errors.Empty.Newf("[myghost] Alter statement for table %q is empty", o.Table)
+	}
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = 1000
+	}
+	if o.CutOverLockTimeout == 0 {
+		o.CutOverLockTimeout = 3 * time.Second
+	}
+	if o.CatchUpTimeout == 0 {
+		o.CatchUpTimeout = 10 * time.Second
+	}
+	if o.PollInterval == 0 {
+		o.PollInterval = 50 * time.Millisecond
+	}
+	if o.Log == nil {
+		o.Log = log.BlackHole{}
+	}
+	return &Migrator{
+		tables:    tables,
+		canal:     c,
+		opt:       o,
+		ghostName: ddl.TableName("_", o.Table, "gho"),
+		oldName:   ddl.TableName("_", o.Table, "del"),
+	}, nil
+}
+
+// Stats returns the number of rows copied by the chunk copy and the number of
+// rows applied from the binary log.
+func (m *Migrator) Stats() (copiedRows, appliedRows int64) {
+	return atomic.LoadInt64(&m.copiedRows), atomic.LoadInt64(&m.appliedRows)
+}
+
+// Run executes the complete migration: creates the ghost table, copies all
+// rows, applies concurrent changes and performs the cut-over. On error the
+// original table stays untouched and the ghost table remains for inspection.
+func (m *Migrator) Run(ctx context.Context) error {
+	if err := m.createGhost(ctx); err != nil {
+		return errors.WithStack(err)
+	}
+	m.canal.RegisterRowsEventHandler([]string{m.opt.Table}, m)
+
+	if err := m.copyRows(ctx); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := m.cutOverTables(ctx); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(m.cleanup(ctx))
+}
+
+func (m *Migrator) createGhost(ctx context.Context) (err error) {
+	if m.orig, err = m.tables.Table(m.opt.Table); err != nil || len(m.orig.Columns) == 0 {
+		if err := m.tables.Options(ddl.WithCreateTable(ctx, m.opt.Table, "")); err != nil {
+			return errors.WithStack(err)
+		}
+		if m.orig, err = m.tables.Table(m.opt.Table); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	pks := m.orig.Columns.PrimaryKeys()
+	if pks.Len() != 1 || !isIntegerType(pks.First().DataType) {
+		return errors.NotSupported.Newf("[myghost] Table %q requires a single column integer primary key", m.opt.Table)
+	}
+	m.pkName = pks.First().Field
+
+	qGhost := dml.Quoter.QualifierName("", m.ghostName)
+	createGhost := "CREATE TABLE " + qGhost + " LIKE " + dml.Quoter.QualifierName("", m.opt.Table)
+	if err := m.tables.Options(ddl.WithCreateTable(ctx, m.ghostName, createGhost)); err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := m.tables.ConnPool.DB.ExecContext(ctx, "ALTER TABLE "+qGhost+" "+m.opt.Alter); err != nil {
+		return errors.WithStack(err)
+	}
+	// reload the altered columns
+	if err := m.tables.Options(ddl.WithCreateTable(ctx, m.ghostName, "")); err != nil {
+		return errors.WithStack(err)
+	}
+	if m.ghost, err = m.tables.Table(m.ghostName); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := m.mapColumns(); err != nil {
+		return errors.WithStack(err)
+	}
+	if m.opt.Log.IsInfo() {
+		m.opt.Log.Info("myghost.Migrator.createGhost",
+			log.String("table", m.opt.Table), log.String("ghost", m.ghostName), log.Strings("columns", m.columns...))
+	}
+	return nil
+}
+
+// mapColumns collects the columns which get copied: all columns of the
+// original table which also exist in the ghost table and which are in neither
+// table a generated column.
+func (m *Migrator) mapColumns() error {
+	m.pkIdx = -1
+	m.columns = m.columns[:0]
+	m.colIdx = m.colIdx[:0]
+	for i, c := range m.orig.Columns {
+		if c.Field == m.pkName {
+			m.pkIdx = i
+		}
+		if c.IsGenerated() {
+			continue
+		}
+		if gc := m.ghost.Columns.ByField(c.Field); gc.Field == "" || gc.IsGenerated() {
+			continue
+		}
+		m.columns = append(m.columns, c.Field)
+		m.colIdx = append(m.colIdx, i)
+	}
+	if !m.ghost.HasColumn(m.pkName) {
+		return errors.NotSupported.Newf("[myghost] The Alter statement must not remove the primary key column %q", m.pkName)
+	}
+	return nil
+}
+
+// copyRows copies all rows between the minimum and maximum primary key value,
+// as seen at the start, in chunks into the ghost table. Rows inserted later
+// arrive via the binary log.
+func (m *Migrator) copyRows(ctx context.Context) error {
+	var minID, maxID sql.NullInt64
+	qPK := dml.Quoter.QualifierName("", m.pkName)
+	if err := m.tables.ConnPool.DB.QueryRowContext(ctx,
+		"SELECT MIN("+qPK+"), MAX("+qPK+") FROM "+dml.Quoter.QualifierName("", m.opt.Table),
+	).Scan(&minID, &maxID); err != nil {
+		return errors.WithStack(err)
+	}
+	if !minID.Valid {
+		return nil // empty table
+	}
+
+	chunk := m.tables.ConnPool.WithQueryBuilder(dml.NewInsert(m.ghostName).Ignore().
+		AddColumns(m.columns...).
+		FromSelect(dml.NewSelect(m.columns...).From(m.opt.Table).Where(
+			dml.Column(m.pkName).Greater().PlaceHolder(),
+			dml.Column(m.pkName).LessOrEqual().PlaceHolder(),
+		).LockInShareMode()))
+
+	for from := minID.Int64 - 1; from < maxID.Int64; from += m.opt.ChunkSize {
+		if err := m.opt.Throttler.Wait(ctx); err != nil {
+			return errors.WithStack(err)
+		}
+		to := from + m.opt.ChunkSize
+		if to > maxID.Int64 {
+			to = maxID.Int64
+		}
+		res, err := chunk.ExecContext(ctx, from, to)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if ra, err := res.RowsAffected(); err == nil {
+			atomic.AddInt64(&m.copiedRows, ra)
+		}
+		if m.opt.Log.IsDebug() {
+			m.opt.Log.Debug("myghost.Migrator.copyRows.chunk",
+				log.String("table", m.opt.Table), log.Int64("from", from), log.Int64("to", to))
+		}
+	}
+	return nil
+}
+
+// cutOverTables locks the original table, waits for the canal to catch up and
+// swaps the original and the ghost table. The RENAME gets issued from a second
+// connection only after the canal has applied all events up to the lock,
+// because a RENAME waiting for the metadata lock also blocks the writes to the
+// ghost table. Once the RENAME waits, the lock gets released and the RENAME
+// runs before any other statement which waits for the lock.
+func (m *Migrator) cutOverTables(ctx context.Context) error {
+	renameErr := make(chan error, 1)
+	renameDone := make(chan struct{})
+
+	lockErr := m.tables.Lock(ctx, ddl.Options{Wait: m.opt.CutOverLockTimeout}, []ddl.TableLock{
+		{Name: m.opt.Table, LockTypeWRITE: true},
+	}, func(conA *dml.Conn) error {
+		var ms ddl.MasterStatus
+		if _, err := conA.WithQueryBuilder(&ms).Load(ctx, &ms); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := m.waitForCatchUp(ctx, ms); err != nil {
+			return errors.WithStack(err)
+		}
+
+		renameConnID := make(chan int64, 1)
+		go func() {
+			defer close(renameDone)
+			renameErr <- m.tables.SingleConnection(ctx, func(conB *dml.Conn) error {
+				var id int64
+				if err := conB.DB.QueryRowContext(ctx, "SELECT CONNECTION_ID()").Scan(&id); err != nil {
+					renameConnID <- 0
+					return errors.WithStack(err)
+				}
+				renameConnID <- id
+				return m.orig.Swap(ctx, m.ghostName, ddl.Options{Execer: conB.DB, Wait: m.opt.CutOverLockTimeout})
+			})
+		}()
+
+		connID := <-renameConnID
+		if err := m.waitForRename(ctx, conA, connID, renameErr); err != nil {
+			m.killRename(ctx, conA, connID, renameDone)
+			return errors.WithStack(err)
+		}
+		atomic.StoreInt32(&m.cutOver, 1)
+		return nil
+	})
+	if lockErr != nil {
+		return errors.WithStack(lockErr)
+	}
+	if err := <-renameErr; err != nil {
+		atomic.StoreInt32(&m.cutOver, 0)
+		return errors.WithStack(err)
+	}
+	if m.opt.Log.IsInfo() {
+		m.opt.Log.Info("myghost.Migrator.cutOver.done", log.String("table", m.opt.Table))
+	}
+	return nil
+}
+
+// killRename kills the waiting RENAME and waits until its connection has
+// returned, because the RENAME must not run once the lock gets released.
+func (m *Migrator) killRename(ctx context.Context, conA *dml.Conn, connID int64, renameDone <-chan struct{}) {
+	if connID > 0 {
+		if _, err := conA.DB.ExecContext(ctx, "KILL QUERY "+strconv.FormatInt(connID, 10)); err != nil && m.opt.Log.IsInfo() {
+			m.opt.Log.Info("myghost.Migrator.cutOver.kill", log.Err(err), log.Int64("connection_id", connID))
+		}
+	}
+	select {
+	case <-renameDone:
+	case <-ctx.Done():
+	case <-time.After(m.opt.CutOverLockTimeout):
+		if m.opt.Log.IsInfo() {
+			m.opt.Log.Info("myghost.Migrator.cutOver.kill.timeout", log.Int64("connection_id", connID))
+		}
+	}
+}
+
+// waitForRename polls the process list until the RENAME statement waits for
+// the table metadata lock.
+func (m *Migrator) waitForRename(ctx context.Context, conA *dml.Conn, connID int64, renameErr <-chan error) error {
+	deadline := time.Now().Add(m.opt.CutOverLockTimeout)
+	for {
+		var state sql.NullString
+		err := conA.DB.QueryRowContext(ctx,
+			"SELECT STATE FROM information_schema.PROCESSLIST WHERE ID = ? AND INFO LIKE 'RENAME TABLE%'", connID,
+		).Scan(&state)
+		switch {
+		case err == nil && strings.Contains(strings.ToLower(state.String), "metadata lock"):
+			return nil
+		case err != nil && err != sql.ErrNoRows:
+			return errors.WithStack(err)
+		}
+
+		select {
+		case err := <-renameErr:
+			// RENAME finished while holding the lock, should not happen.
+			if err == nil {
+				err = errors.AlreadyClosed.Newf("[myghost] RENAME TABLE finished unexpectedly early")
+			}
+			return errors.WithStack(err)
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.opt.PollInterval):
+		}
+		if time.Now().After(deadline) {
+			return errors.Timeout.Newf("[myghost] RENAME TABLE did not appear in the process list within %s", m.opt.CutOverLockTimeout)
+		}
+	}
+}
+
+// waitForCatchUp waits until the canal has synced at least the master status
+// ms.
+func (m *Migrator) waitForCatchUp(ctx context.Context, ms ddl.MasterStatus) error {
+	deadline := time.Now().Add(m.opt.CatchUpTimeout)
+	for m.canal.SyncedPosition().Compare(ms) < 0 {
+		if time.Now().After(deadline) {
+			return errors.Timeout.Newf("[myghost] Canal did not reach position %q within %s", ms.String(), m.opt.CatchUpTimeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.opt.PollInterval):
+		}
+	}
+	return nil
+}
+
+// cleanup removes or renames the old table which now has the ghost name.
+func (m *Migrator) cleanup(ctx context.Context) error {
+	if m.opt.KeepOldTable {
+		if err := m.ghost.Rename(ctx, m.oldName, ddl.Options{}); err != nil {
+			return errors.WithStack(err)
+		}
+	} else if err := m.ghost.Drop(ctx, ddl.Options{}); err != nil {
+		return errors.WithStack(err)
+	}
+	m.tables.DeleteFromCache(m.ghostName)
+	// reload the new structure of the original table
+	return errors.WithStack(m.tables.Options(ddl.WithCreateTable(ctx, m.opt.Table, "")))
+}
+
+func isIntegerType(dataType string) bool {
+	switch strings.ToLower(dataType) {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,391 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package myghost
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/sql/dml"
+	"github.com/corestoreio/pkg/sql/dmltest"
+	"github.com/corestoreio/pkg/sql/mycanal"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+type fakeCanal struct {
+	ms ddl.MasterStatus
+	// lagPolls defines the number of SyncedPosition calls which return an
+	// empty position before ms gets returned.
+	lagPolls int32
+	polls    int32
+}
+
+func (fc *fakeCanal) RegisterRowsEventHandler(_ []string, _ ...mycanal.RowsEventHandler) {}
+
+func (fc *fakeCanal) SyncedPosition() ddl.MasterStatus {
+	if atomic.AddInt32(&fc.polls, 1) <= fc.lagPolls {
+		return ddl.MasterStatus{}
+	}
+	return fc.ms
+}
+
+// newTestMigrator creates a Migrator with already loaded tables, so no
+// information_schema queries are required.
+func newTestMigrator(t *testing.T, dbc *dml.ConnPool) *Migrator {
+	cols := ddl.Columns{
+		&ddl.Column{Field: "id", Pos: 1, DataType: "int", Key: "PRI", Extra: "auto_increment"},
+		&ddl.Column{Field: "email", Pos: 2, DataType: "varchar"},
+		&ddl.Column{Field: "email_hash", Pos: 3, DataType: "varchar", Generated: "ALWAYS"},
+		&ddl.Column{Field: "legacy", Pos: 4, DataType: "varchar"},
+	}
+	ghostCols := ddl.Columns{
+		&ddl.Column{Field: "id", Pos: 1, DataType: "int", Key: "PRI", Extra: "auto_increment"},
+		&ddl.Column{Field: "email", Pos: 2, DataType: "varchar"},
+		&ddl.Column{Field: "email_hash", Pos: 3, DataType: "varchar", Generated: "ALWAYS"},
+		&ddl.Column{Field: "note", Pos: 4, DataType: "text"},
+	}
+	tbls, err := ddl.NewTables(
+		ddl.WithConnPool(dbc),
+		ddl.WithTable("customer", cols...),
+		ddl.WithTable("_customer_gho", ghostCols...),
+	)
+	assert.NoError(t, err)
+
+	m, err := NewMigrator(tbls, &fakeCanal{}, Options{
+		Table:     "customer",
+		Alter:     "DROP COLUMN `legacy`, ADD COLUMN `note` TEXT",
+		ChunkSize: 10,
+	})
+	assert.NoError(t, err)
+
+	m.orig = tbls.MustTable("customer")
+	m.ghost = tbls.MustTable("_customer_gho")
+	m.pkName = "id"
+	m.pkIdx = 0
+	m.columns = []string{"id", "email"}
+	m.colIdx = []int{0, 1}
+	return m
+}
+
+func TestNewMigrator(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+	tbls, err := ddl.NewTables(ddl.WithConnPool(dbc))
+	assert.NoError(t, err)
+
+	t.Run("missing alter", func(t *testing.T) {
+		m, err := NewMigrator(tbls, &fakeCanal{}, Options{Table: "customer"})
+		assert.ErrorIsKind(t, errors.Empty, err)
+		assert.Nil(t, m)
+	})
+	t.Run("missing canal", func(t *testing.T) {
+		m, err := NewMigrator(tbls, nil, Options{Table: "customer", Alter: "ADD COLUMN x INT"})
+		assert.ErrorIsKind(t, errors.NotValid, err)
+		assert.Nil(t, m)
+	})
+	t.Run("defaults", func(t *testing.T) {
+		m, err := NewMigrator(tbls, &fakeCanal{}, Options{Table: "customer", Alter: "ADD COLUMN x INT"})
+		assert.NoError(t, err)
+		assert.Exactly(t, "_customer_gho", m.ghostName)
+		assert.Exactly(t, "_customer_del", m.oldName)
+		assert.Exactly(t, int64(1000), m.opt.ChunkSize)
+		assert.Exactly(t, 3*time.Second, m.opt.CutOverLockTimeout)
+	})
+}
+
+func TestMigrator_copyRows(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+	m := newTestMigrator(t, dbc)
+
+	dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT MIN(`id`), MAX(`id`) FROM `customer`")).
+		WillReturnRows(sqlmock.NewRows([]string{"min", "max"}).AddRow(3, 25))
+
+	const chunk = "INSERT IGNORE INTO `_customer_gho` (`id`,`email`) SELECT `id`, `email` FROM `customer` WHERE (`id` > ?) AND (`id` <= ?) LOCK IN SHARE MODE"
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(chunk)).WithArgs(2, 12).WillReturnResult(sqlmock.NewResult(0, 10))
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(chunk)).WithArgs(12, 22).WillReturnResult(sqlmock.NewResult(0, 10))
+	dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(chunk)).WithArgs(22, 25).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	assert.NoError(t, m.copyRows(context.Background()))
+	copied, applied := m.Stats()
+	assert.Exactly(t, int64(23), copied)
+	assert.Exactly(t, int64(0), applied)
+}
+
+func TestMigrator_Do(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+	m := newTestMigrator(t, dbc)
+	ctx := context.Background()
+
+	const replace = "REPLACE INTO `_customer_gho` (`id`,`email`) VALUES (?,?)"
+	const del = "DELETE FROM `_customer_gho` WHERE (`id` = ?)"
+
+	t.Run("insert", func(t *testing.T) {
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(replace)).WithArgs(1, "a@b.c").WillReturnResult(sqlmock.NewResult(1, 1))
+		err := m.Do(ctx, mycanal.InsertAction, m.orig, [][]any{{1, "a@b.c", "hash", "old"}})
+		assert.NoError(t, err)
+	})
+	t.Run("update with changed primary key", func(t *testing.T) {
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(del)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(replace)).WithArgs(2, "a@b.c").WillReturnResult(sqlmock.NewResult(2, 1))
+		err := m.Do(ctx, mycanal.UpdateAction, m.orig, [][]any{
+			{1, "a@b.c", "hash", "old"},
+			{2, "a@b.c", "hash", "old"},
+		})
+		assert.NoError(t, err)
+	})
+	t.Run("update odd rows", func(t *testing.T) {
+		err := m.Do(ctx, mycanal.UpdateAction, m.orig, [][]any{{2, "a@b.c", "hash", "old"}})
+		assert.ErrorIsKind(t, errors.CorruptData, err)
+	})
+	t.Run("delete", func(t *testing.T) {
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta(del)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+		err := m.Do(ctx, mycanal.DeleteAction, m.orig, [][]any{{2, "a@b.c", "hash", "old"}})
+		assert.NoError(t, err)
+	})
+	t.Run("ignored after cut-over", func(t *testing.T) {
+		m.cutOver = 1
+		err := m.Do(ctx, mycanal.DeleteAction, m.orig, [][]any{{2, "a@b.c", "hash", "old"}})
+		assert.NoError(t, err)
+		m.cutOver = 0
+	})
+	_, applied := m.Stats()
+	assert.Exactly(t, int64(4), applied)
+}
+
+func TestMigrator_mapColumns(t *testing.T) {
+	dbc, dbMock := dmltest.MockDB(t)
+	defer dmltest.MockClose(t, dbc, dbMock)
+
+	t.Run("skips generated, dropped and added columns", func(t *testing.T) {
+		m := newTestMigrator(t, dbc)
+		m.orig = ddl.NewTable("customer",
+			&ddl.Column{Field: "id", Pos: 1, DataType: "int", Key: "PRI"},
+			&ddl.Column{Field: "email", Pos: 2, DataType: "varchar"},
+			&ddl.Column{Field: "email_hash", Pos: 3, DataType: "varchar", Generated: "ALWAYS"},
+			&ddl.Column{Field: "legacy", Pos: 4, DataType: "varchar"},
+			&ddl.Column{Field: "full_name", Pos: 5, DataType: "varchar"},
+		)
+		m.ghost = ddl.NewTable("_customer_gho",
+			&ddl.Column{Field: "id", Pos: 1, DataType: "int", Key: "PRI"},
+			&ddl.Column{Field: "email", Pos: 2, DataType: "varchar"},
+			&ddl.Column{Field: "email_hash", Pos: 3, DataType: "varchar", Generated: "ALWAYS"},
+			&ddl.Column{Field: "full_name", Pos: 4, DataType: "varchar", Generated: "ALWAYS"},
+			&ddl.Column{Field: "note", Pos: 5, DataType: "text"},
+		)
+		assert.NoError(t, m.mapColumns())
+		assert.Exactly(t, []string{"id", "email"}, m.columns)
+		assert.Exactly(t, []int{0, 1}, m.colIdx)
+		assert.Exactly(t, 0, m.pkIdx)
+	})
+	t.Run("primary key removed", func(t *testing.T) {
+		m := newTestMigrator(t, dbc)
+		m.ghost = ddl.NewTable("_customer_gho",
+			&ddl.Column{Field: "email", Pos: 1, DataType: "varchar"},
+		)
+		assert.ErrorIsKind(t, errors.NotSupported, m.mapColumns())
+	})
+}
+
+func TestMigrator_waitForCatchUp(t *testing.T) {
+	ms := ddl.MasterStatus{File: "mysql-bin.000002", Position: 4711}
+
+	t.Run("reaches position", func(t *testing.T) {
+		fc := &fakeCanal{ms: ms, lagPolls: 3}
+		m := &Migrator{canal: fc, opt: Options{CatchUpTimeout: time.Second, PollInterval: time.Millisecond}}
+		assert.NoError(t, m.waitForCatchUp(context.Background(), ms))
+		assert.Exactly(t, int32(4), atomic.LoadInt32(&fc.polls))
+	})
+	t.Run("timeout", func(t *testing.T) {
+		fc := &fakeCanal{ms: ms, lagPolls: 1 << 30}
+		m := &Migrator{canal: fc, opt: Options{CatchUpTimeout: 10 * time.Millisecond, PollInterval: time.Millisecond}}
+		assert.ErrorIsKind(t, errors.Timeout, m.waitForCatchUp(context.Background(), ms))
+	})
+	t.Run("context canceled", func(t *testing.T) {
+		fc := &fakeCanal{ms: ms, lagPolls: 1 << 30}
+		m := &Migrator{canal: fc, opt: Options{CatchUpTimeout: time.Second, PollInterval: time.Millisecond}}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.Exactly(t, context.Canceled, m.waitForCatchUp(ctx, ms))
+	})
+}
+
+const sqlProcessList = "SELECT STATE FROM information_schema.PROCESSLIST WHERE ID = ?"
+
+func TestMigrator_waitForRename(t *testing.T) {
+	ctx := context.Background()
+	opt := Options{CutOverLockTimeout: time.Second, PollInterval: time.Millisecond}
+	stateRows := func(states ...string) *sqlmock.Rows {
+		r := sqlmock.NewRows([]string{"STATE"})
+		for _, s := range states {
+			r.AddRow(s)
+		}
+		return r
+	}
+
+	t.Run("waits for metadata lock", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		conA, err := dbc.Conn(ctx)
+		assert.NoError(t, err)
+		defer conA.Close()
+
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(sqlProcessList)).WithArgs(42).WillReturnRows(stateRows())
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(sqlProcessList)).WithArgs(42).WillReturnRows(stateRows("Waiting for table metadata lock"))
+
+		m := &Migrator{opt: opt}
+		assert.NoError(t, m.waitForRename(ctx, conA, 42, make(chan error)))
+	})
+	t.Run("rename finished early", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		conA, err := dbc.Conn(ctx)
+		assert.NoError(t, err)
+		defer conA.Close()
+
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(sqlProcessList)).WithArgs(42).WillReturnRows(stateRows())
+		renameErr := make(chan error, 1)
+		renameErr <- nil
+
+		m := &Migrator{opt: opt}
+		assert.ErrorIsKind(t, errors.AlreadyClosed, m.waitForRename(ctx, conA, 42, renameErr))
+	})
+	t.Run("timeout", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		conA, err := dbc.Conn(ctx)
+		assert.NoError(t, err)
+		defer conA.Close()
+
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(sqlProcessList)).WithArgs(42).WillReturnRows(stateRows("executing"))
+
+		m := &Migrator{opt: Options{CutOverLockTimeout: time.Millisecond, PollInterval: 5 * time.Millisecond}}
+		assert.ErrorIsKind(t, errors.Timeout, m.waitForRename(ctx, conA, 42, make(chan error)))
+	})
+}
+
+func TestMigrator_cutOverTables(t *testing.T) {
+	ms := ddl.MasterStatus{File: "mysql-bin.000002", Position: 4711}
+	masterStatusRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"File", "Position"}).AddRow(ms.File, ms.Position)
+	}
+	newCutOverMigrator := func(t *testing.T, dbc *dml.ConnPool, fc *fakeCanal) *Migrator {
+		m := newTestMigrator(t, dbc)
+		m.canal = fc
+		m.opt.CutOverLockTimeout = time.Second
+		m.opt.CatchUpTimeout = 50 * time.Millisecond
+		m.opt.PollInterval = time.Millisecond
+		return m
+	}
+
+	t.Run("RENAME after catch-up", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		// The RENAME and the process list query run concurrently.
+		dbMock.MatchExpectationsInOrder(false)
+		fc := &fakeCanal{ms: ms, lagPolls: 2}
+		m := newCutOverMigrator(t, dbc, fc)
+
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("LOCK TABLES `customer` WRITE")).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectQuery("SHOW MASTER STATUS").WillReturnRows(masterStatusRows())
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT CONNECTION_ID()")).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(sqlProcessList)).WithArgs(42).
+			WillReturnRows(sqlmock.NewRows([]string{"STATE"}).AddRow("Waiting for table metadata lock"))
+		dbMock.ExpectExec("RENAME TABLE `customer`").WillDelayFor(20 * time.Millisecond).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("UNLOCK TABLES").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.NoError(t, m.cutOverTables(context.Background()))
+		assert.Exactly(t, int32(3), atomic.LoadInt32(&fc.polls), "canal must catch up before the RENAME")
+		assert.Exactly(t, int32(1), atomic.LoadInt32(&m.cutOver))
+	})
+	t.Run("catch-up timeout aborts before the RENAME", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		m := newCutOverMigrator(t, dbc, &fakeCanal{ms: ms, lagPolls: 1 << 30})
+
+		// any RENAME or CONNECTION_ID query would fail as unexpected.
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("LOCK TABLES `customer` WRITE")).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectQuery("SHOW MASTER STATUS").WillReturnRows(masterStatusRows())
+		dbMock.ExpectExec("UNLOCK TABLES").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIsKind(t, errors.Timeout, m.cutOverTables(context.Background()))
+		assert.Exactly(t, int32(0), atomic.LoadInt32(&m.cutOver))
+	})
+	t.Run("RENAME killed before UNLOCK", func(t *testing.T) {
+		dbc, dbMock := dmltest.MockDB(t)
+		defer dmltest.MockClose(t, dbc, dbMock)
+		dbMock.MatchExpectationsInOrder(false)
+		m := newCutOverMigrator(t, dbc, &fakeCanal{ms: ms})
+
+		dbMock.ExpectExec(dmltest.SQLMockQuoteMeta("LOCK TABLES `customer` WRITE")).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectQuery("SHOW MASTER STATUS").WillReturnRows(masterStatusRows())
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta("SELECT CONNECTION_ID()")).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+		dbMock.ExpectQuery(dmltest.SQLMockQuoteMeta(sqlProcessList)).WithArgs(42).WillReturnError(errors.ConnectionFailed.Newf("lost"))
+		dbMock.ExpectExec("KILL QUERY 42").WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectExec("RENAME TABLE `customer`").WillDelayFor(20 * time.Millisecond).WillReturnError(errors.Aborted.Newf("Query execution was interrupted"))
+		dbMock.ExpectExec("UNLOCK TABLES").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIsKind(t, errors.ConnectionFailed, m.cutOverTables(context.Background()))
+		assert.Exactly(t, int32(0), atomic.LoadInt32(&m.cutOver))
+	})
+}
+
+func TestThrottler(t *testing.T) {
+	t.Run("nil never throttles", func(t *testing.T) {
+		var th *Throttler
+		assert.NoError(t, th.Wait(context.Background()))
+	})
+	t.Run("pause file", func(t *testing.T) {
+		pf := filepath.Join(t.TempDir(), "pause")
+		assert.NoError(t, os.WriteFile(pf, nil, 0o600))
+		th := &Throttler{PauseFile: pf, Interval: time.Millisecond}
+
+		throttled, _, err := th.IsThrottled(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, throttled)
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			_ = os.Remove(pf)
+		}()
+		assert.NoError(t, th.Wait(context.Background()))
+	})
+	t.Run("replica lag", func(t *testing.T) {
+		lag := 5 * time.Second
+		th := &Throttler{
+			MaxLag: time.Second,
+			Replicas: []ReplicaLagFunc{func(context.Context) (time.Duration, error) {
+				return lag, nil
+			}},
+		}
+		throttled, reason, err := th.IsThrottled(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, throttled)
+		assert.Exactly(t, "replica lag 5s exceeds 1s", reason)
+
+		lag = 0
+		throttled, _, err = th.IsThrottled(context.Background())
+		assert.NoError(t, err)
+		assert.False(t, throttled)
+	})
+}
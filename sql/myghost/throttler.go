@@ -0,0 +1,141 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package myghost
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/sql/dml"
+)
+
+// ReplicaLagFunc returns the current replication lag of a replica.
+type ReplicaLagFunc func(ctx context.Context) (time.Duration, error)
+
+// ReplicaLag queries SHOW SLAVE STATUS on a replica and returns the value of
+// the column Seconds_Behind_Master. A stopped replication returns an error
+// with behaviour NotValid because its lag is unknown.
+func ReplicaLag(replica *dml.ConnPool) ReplicaLagFunc {
+	return func(ctx context.Context) (time.Duration, error) {
+		rows, err := replica.DB.QueryContext(ctx, "SHOW SLAVE STATUS")
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		defer rows.Close()
+
+		cols, err := rows.Columns()
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		if !rows.Next() {
+			return 0, errors.NotFound.Newf("[myghost] Server %q is not a replica", replica.Schema())
+		}
+		vals := make([]sql.RawBytes, len(cols))
+		args := make([]any, len(cols))
+		for i := range vals {
+			args[i] = &vals[i]
+		}
+		if err := rows.Scan(args...); err != nil {
+			return 0, errors.WithStack(err)
+		}
+		for i, c := range cols {
+			if c != "Seconds_Behind_Master" {
+				continue
+			}
+			if vals[i] == nil {
+				return 0, errors.NotValid.Newf("[myghost] Replication on %q is not running", replica.Schema())
+			}
+			d, err := time.ParseDuration(string(vals[i]) + "s")
+			return d, errors.WithStack(err)
+		}
+		return 0, errors.NotFound.Newf("[myghost] Column Seconds_Behind_Master not found in SHOW SLAVE STATUS")
+	}
+}
+
+// Throttler decides whether the row copy must pause. A nil *Throttler never
+// throttles.
+type Throttler struct {
+	// PauseFile pauses the row copy as long as the file exists.
+	PauseFile string
+	// MaxLag defines the maximum allowed replication lag. Zero disables the
+	// lag check.
+	MaxLag time.Duration
+	// Replicas provide the replication lag of each replica.
+	Replicas []ReplicaLagFunc
+	// Interval defines the pause between two checks while throttled. Defaults
+	// to one second.
+	Interval time.Duration
+	// Log optional.
+	Log log.Logger
+}
+
+// IsThrottled reports whether the row copy must pause and the reason.
+func (t *Throttler) IsThrottled(ctx context.Context) (bool, string, error) {
+	if t == nil {
+		return false, "", nil
+	}
+	if t.PauseFile != "" {
+		if _, err := os.Stat(t.PauseFile); err == nil {
+			return true, "pause file " + t.PauseFile + " exists", nil
+		} else if !os.IsNotExist(err) {
+			return false, "", errors.WithStack(err)
+		}
+	}
+	if t.MaxLag > 0 {
+		for _, lagFn := range t.Replicas {
+			lag, err := lagFn(ctx)
+			if err != nil {
+				if errors.NotValid.Match(err) {
+					return true, err.Error(), nil
+				}
+				return false, "", errors.WithStack(err)
+			}
+			if lag > t.MaxLag {
+				return true, "replica lag " + lag.String() + " exceeds " + t.MaxLag.String(), nil
+			}
+		}
+	}
+	return false, "", nil
+}
+
+// Wait blocks as long as the throttle conditions apply or the context gets
+// cancelled.
+func (t *Throttler) Wait(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	interval := t.Interval
+	if interval == 0 {
+		interval = time.Second
+	}
+	for {
+		throttled, reason, err := t.IsThrottled(ctx)
+		if err != nil || !throttled {
+			return errors.WithStack(err)
+		}
+		if t.Log != nil && t.Log.IsInfo() {
+			t.Log.Info("myghost.Throttler.Wait.throttled", log.String("reason", reason))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -13,7 +13,8 @@ import (
 
 // StartBackup enables, like the mysqlbinlog command line tool, a remote raw
 // backup. Backup remote binlog from position (filename, offset) and write in
-// backupDir.
+// backupDir. For continuous archiving with compression, checksums, retention
+// and restore planning use the BackupManager.
 func (b *BinlogSyncer) StartBackup(backupDir string, perm os.FileMode, p ddl.MasterStatus, timeout time.Duration) error {
 	if timeout == 0 {
 		// a very long timeout here
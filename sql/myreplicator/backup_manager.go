@@ -0,0 +1,762 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package myreplicator
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/log"
+	"github.com/corestoreio/pkg/sql/ddl"
+	uuid "github.com/satori/go.uuid"
+	"github.com/siddontang/go-mysql/mysql"
+)
+
+// BackupIndexFileName defines the name of the JSON index file stored in the
+// backup directory.
+const BackupIndexFileName = "binlog-index.json"
+
+const (
+	backupPartialSuffix = ".partial"
+	backupGzipSuffix    = ".gz"
+)
+
+// BackupConfig configures the BackupManager.
+type BackupConfig struct {
+	// Dir defines the directory where the archived binlog files and the index
+	// gets stored. Required.
+	Dir string
+	// Perm defines the permissions of the backup directory. Default 0750.
+	Perm os.FileMode
+	// Compress enables gzip compression of finished binlog files.
+	Compress bool
+	// CompressLevel sets the gzip level, defaults to gzip.DefaultCompression.
+	CompressLevel int
+	// MaxAge removes archived binlog files whose last event is older than
+	// MaxAge. Zero disables the check.
+	MaxAge time.Duration
+	// MaxSegments defines the maximum amount of archived binlog files to keep.
+	// Zero disables the check.
+	MaxSegments int
+	// MaxBytes defines the maximum size on disk of all archived binlog files.
+	// Zero disables the check.
+	MaxBytes int64
+	// Log gets inherited from the BinlogSyncer if nil.
+	Log log.Logger
+}
+
+// BackupSegment describes a single archived binlog file.
+type BackupSegment struct {
+	// File is the original binlog file name on the server, e.g.
+	// mysql-bin.000002.
+	File string `json:"file"`
+	// Archive is the file name in the backup directory.
+	Archive    string `json:"archive"`
+	Compressed bool   `json:"compressed,omitempty"`
+	// SHA256 checksum of the archive file.
+	SHA256 string `json:"sha256"`
+	// Size of the archive file in bytes.
+	Size int64 `json:"size"`
+	// StartPos defines the position of the first event in the file.
+	StartPos uint32 `json:"start_pos"`
+	// EndPos defines the end position of the last event in the file.
+	EndPos         uint32    `json:"end_pos"`
+	FirstEventTime time.Time `json:"first_event_time"`
+	LastEventTime  time.Time `json:"last_event_time"`
+	// FirstGTID and LastGTID contain the first and last seen GTID in the
+	// file. MySQL format: UUID:GNO, MariaDB format: domain-server-sequence.
+	FirstGTID string `json:"first_gtid,omitempty"`
+	LastGTID  string `json:"last_gtid,omitempty"`
+}
+
+// Path returns the full path to the archive file in directory dir.
+func (bs BackupSegment) Path(dir string) string {
+	return filepath.Join(dir, bs.Archive)
+}
+
+// Open opens the archived binlog file and decompresses it on the fly, if
+// needed. The returned reader starts with the binlog file header.
+func (bs BackupSegment) Open(dir string) (io.ReadCloser, error) {
+	f, err := os.Open(bs.Path(dir))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !bs.Compressed {
+		return f, nil
+	}
+	zr, err := gzip.NewReader(bufio.NewReader(f))
+	if err != nil {
+		_ = f.Close()
+		return nil, errors.WithStack(err)
+	}
+	return gzipFileReader{Reader: zr, f: f}, nil
+}
+
+// Verify recalculates the checksum of the archive file and compares it with
+// the stored one.
+func (bs BackupSegment) Verify(dir string) error {
+	sum, _, err := fileChecksum(bs.Path(dir))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if sum != bs.SHA256 {
+		return errors.CorruptData.Newf("[myreplicator] Binlog archive %q checksum mismatch: have %q want %q", bs.Archive, sum, bs.SHA256)
+	}
+	return nil
+}
+
+type gzipFileReader struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g gzipFileReader) Close() error {
+	err := g.Reader.Close()
+	if err2 := g.f.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// BackupIndex contains all archived binlog files ordered by their file name.
+type BackupIndex struct {
+	Segments []BackupSegment `json:"segments"`
+}
+
+// LoadBackupIndex reads the index from the backup directory. A non-existing
+// index file returns an empty index.
+func LoadBackupIndex(dir string) (*BackupIndex, error) {
+	idx := new(BackupIndex)
+	data, err := os.ReadFile(filepath.Join(dir, BackupIndexFileName))
+	if os.IsNotExist(err) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := json.Unmarshal(data, idx); err != nil {
+		return nil, errors.BadEncoding.New(err, "[myreplicator] Failed to decode binlog backup index in %q", dir)
+	}
+	idx.sort()
+	return idx, nil
+}
+
+// Save writes the index atomically into the backup directory.
+func (idx *BackupIndex) Save(dir string) error {
+	idx.sort()
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tmp := filepath.Join(dir, BackupIndexFileName+".tmp")
+	if err := os.WriteFile(tmp, data, 0640); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.Rename(tmp, filepath.Join(dir, BackupIndexFileName)))
+}
+
+func (idx *BackupIndex) sort() {
+	sort.Slice(idx.Segments, func(i, j int) bool {
+		return idx.Segments[i].File < idx.Segments[j].File
+	})
+}
+
+// Find returns the segment for the binlog file name.
+func (idx *BackupIndex) Find(file string) (BackupSegment, bool) {
+	for _, s := range idx.Segments {
+		if s.File == file {
+			return s, true
+		}
+	}
+	return BackupSegment{}, false
+}
+
+// Size returns the total size of all archive files.
+func (idx *BackupIndex) Size() (total int64) {
+	for _, s := range idx.Segments {
+		total += s.Size
+	}
+	return total
+}
+
+// BackupManager continuously archives the binary log of a server into a
+// directory. Finished files get optionally compressed, checksummed and
+// registered in an index which maps each file to its GTID and time range. The
+// retention policy runs after each finished file. The index can be used to
+// plan a point-in-time restore.
+type BackupManager struct {
+	cfg    BackupConfig
+	syncer *BinlogSyncer
+
+	mu    sync.Mutex
+	index *BackupIndex
+	// current contains the state of the file currently being written.
+	current    BackupSegment
+	currentF   *os.File
+	currentBuf *bufio.Writer
+	// nextFile gets set by the rotate event.
+	nextFile string
+}
+
+// NewBackupManager creates a new manager and loads an existing index from the
+// backup directory. The BinlogSyncer might be nil when only the index and
+// restore planning functions are used.
+func NewBackupManager(b *BinlogSyncer, cfg BackupConfig) (*BackupManager, error) {
+	if cfg.Dir == "" {
+		return nil, errors.Empty.Newf("[myreplicator] BackupConfig.Dir cannot be empty")
+	}
+	if cfg.Perm == 0 {
+		cfg.Perm = 0750
+	}
+	if cfg.CompressLevel == 0 {
+		cfg.CompressLevel = gzip.DefaultCompression
+	}
+	if cfg.Log == nil && b != nil {
+		cfg.Log = b.cfg.Log
+	}
+	if cfg.Log == nil {
+		cfg.Log = log.BlackHole{}
+	}
+	if err := os.MkdirAll(cfg.Dir, cfg.Perm); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	idx, err := LoadBackupIndex(cfg.Dir)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &BackupManager{
+		cfg:    cfg,
+		syncer: b,
+		index:  idx,
+	}, nil
+}
+
+// Index returns a copy of the current index.
+func (bm *BackupManager) Index() BackupIndex {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	return BackupIndex{Segments: append([]BackupSegment(nil), bm.index.Segments...)}
+}
+
+// Run starts the raw binlog sync at position p and archives all received
+// events until the context gets cancelled or an error occurs. If p.File is
+// empty, Run resumes with the binlog file following the last archived one.
+// The unfinished file stays in the directory with suffix ".partial" and gets
+// downloaded again on the next Run.
+func (bm *BackupManager) Run(ctx context.Context, p ddl.MasterStatus) (err error) {
+	if bm.syncer == nil {
+		return errors.NotValid.Newf("[myreplicator] BackupManager requires a BinlogSyncer to run")
+	}
+	if p.File == "" {
+		bm.mu.Lock()
+		if l := len(bm.index.Segments); l > 0 {
+			p.File = nextBinlogFileName(bm.index.Segments[l-1].File)
+		}
+		bm.mu.Unlock()
+		if p.File == "" {
+			return errors.Empty.Newf("[myreplicator] BackupManager.Run requires a start position as the index is empty")
+		}
+	}
+	if p.Position < uint(len(BinLogFileHeader)) {
+		p.Position = uint(len(BinLogFileHeader))
+	}
+
+	bm.syncer.parser.SetRawMode(true)
+	s, err := bm.syncer.StartSync(p)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		if err2 := bm.closeCurrent(); err == nil {
+			err = err2
+		}
+	}()
+
+	for {
+		e, err := s.GetEvent(ctx)
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return nil
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := bm.handleEvent(e); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+}
+
+// handleEvent writes a raw event into the current file and rotates the file
+// when a new format description event arrives.
+func (bm *BackupManager) handleEvent(e *BinlogEvent) error {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+
+	switch e.Header.EventType {
+	case ROTATE_EVENT:
+		if re, ok := e.Event.(*RotateEvent); ok {
+			bm.nextFile = string(re.NextLogName)
+		}
+		if e.Header.Timestamp == 0 || e.Header.LogPos == 0 {
+			return nil // fake rotate event
+		}
+	case FORMAT_DESCRIPTION_EVENT:
+		if err := bm.finishCurrent(); err != nil {
+			return errors.WithStack(err)
+		}
+		if bm.nextFile == "" {
+			return errors.Empty.Newf("[myreplicator] Empty binlog filename for FormatDescriptionEvent")
+		}
+		if err := bm.openCurrent(bm.nextFile); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	if bm.currentF == nil {
+		// Sync started in the middle of a file without a format description
+		// event, which cannot happen with a MySQL server.
+		return errors.NotValid.Newf("[myreplicator] Received event %s before a FormatDescriptionEvent", e.Header.EventType)
+	}
+
+	if _, err := bm.currentBuf.Write(e.RawData); err != nil {
+		return errors.WithStack(err)
+	}
+	bm.trackEvent(e)
+	return nil
+}
+
+func (bm *BackupManager) trackEvent(e *BinlogEvent) {
+	c := &bm.current
+	ts := time.Unix(int64(e.Header.Timestamp), 0).UTC()
+	if e.Header.Timestamp > 0 {
+		if c.FirstEventTime.IsZero() {
+			c.FirstEventTime = ts
+		}
+		if ts.After(c.LastEventTime) {
+			c.LastEventTime = ts
+		}
+	}
+	if c.StartPos == 0 && e.Header.LogPos > 0 {
+		c.StartPos = e.Header.LogPos - e.Header.EventSize
+	}
+	if e.Header.LogPos > c.EndPos {
+		c.EndPos = e.Header.LogPos
+	}
+	if gtid := rawGTID(e.Header, e.RawData); gtid != "" {
+		if c.FirstGTID == "" {
+			c.FirstGTID = gtid
+		}
+		c.LastGTID = gtid
+	}
+}
+
+// rawGTID decodes the GTID from the raw data because in raw mode the parser
+// does not decode GTID events.
+func rawGTID(h *EventHeader, rawData []byte) string {
+	if len(rawData) < EventHeaderSize {
+		return ""
+	}
+	body := rawData[EventHeaderSize:]
+	switch h.EventType {
+	case GTID_EVENT:
+		if len(body) < 1+SidLength+8 {
+			return ""
+		}
+		ge := new(GTIDEvent)
+		if err := ge.decode(body); err != nil {
+			return ""
+		}
+		u, err := uuid.FromBytes(ge.SID)
+		if err != nil {
+			return ""
+		}
+		return u.String() + ":" + strconv.FormatInt(ge.GNO, 10)
+	case MARIADB_GTID_EVENT:
+		if len(body) < 12 {
+			return ""
+		}
+		gtid := mysql.MariadbGTID{
+			DomainID:       binary.LittleEndian.Uint32(body[8:]),
+			ServerID:       h.ServerID,
+			SequenceNumber: binary.LittleEndian.Uint64(body),
+		}
+		return gtid.String()
+	}
+	return ""
+}
+
+func (bm *BackupManager) openCurrent(file string) error {
+	f, err := os.OpenFile(filepath.Join(bm.cfg.Dir, file+backupPartialSuffix), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	bm.currentF = f
+	bm.currentBuf = bufio.NewWriter(f)
+	bm.current = BackupSegment{File: file}
+	if _, err := bm.currentBuf.Write(BinLogFileHeader); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// closeCurrent flushes and closes the partial file without archiving it.
+func (bm *BackupManager) closeCurrent() error {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	if bm.currentF == nil {
+		return nil
+	}
+	err := bm.currentBuf.Flush()
+	if err2 := bm.currentF.Close(); err == nil {
+		err = err2
+	}
+	bm.currentF = nil
+	bm.currentBuf = nil
+	return errors.WithStack(err)
+}
+
+// finishCurrent archives the current file, updates the index and applies the
+// retention policy.
+func (bm *BackupManager) finishCurrent() error {
+	if bm.currentF == nil {
+		return nil
+	}
+	if err := bm.currentBuf.Flush(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := bm.currentF.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	bm.currentF = nil
+	bm.currentBuf = nil
+
+	seg := bm.current
+	partial := filepath.Join(bm.cfg.Dir, seg.File+backupPartialSuffix)
+	seg.Archive = seg.File
+	if bm.cfg.Compress {
+		seg.Archive += backupGzipSuffix
+		seg.Compressed = true
+		if err := gzipFile(partial, filepath.Join(bm.cfg.Dir, seg.Archive), bm.cfg.CompressLevel); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := os.Remove(partial); err != nil {
+			return errors.WithStack(err)
+		}
+	} else if err := os.Rename(partial, filepath.Join(bm.cfg.Dir, seg.Archive)); err != nil {
+		return errors.WithStack(err)
+	}
+
+	sum, size, err := fileChecksum(seg.Path(bm.cfg.Dir))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	seg.SHA256 = sum
+	seg.Size = size
+
+	segs := bm.index.Segments[:0]
+	for _, s := range bm.index.Segments {
+		if s.File != seg.File {
+			segs = append(segs, s)
+		}
+	}
+	bm.index.Segments = append(segs, seg)
+
+	if bm.cfg.Log.IsInfo() {
+		bm.cfg.Log.Info("myreplicator.BackupManager.finishCurrent.archived",
+			log.String("file", seg.File), log.String("archive", seg.Archive), log.Int64("size", seg.Size),
+			log.String("first_gtid", seg.FirstGTID), log.String("last_gtid", seg.LastGTID))
+	}
+
+	if _, err := bm.applyRetention(time.Now()); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(bm.index.Save(bm.cfg.Dir))
+}
+
+// ApplyRetention removes archived files which violate the retention policy and
+// returns the removed segments. The newest segment never gets removed.
+func (bm *BackupManager) ApplyRetention(now time.Time) ([]BackupSegment, error) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	removed, err := bm.applyRetention(now)
+	if err != nil {
+		return removed, errors.WithStack(err)
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	return removed, errors.WithStack(bm.index.Save(bm.cfg.Dir))
+}
+
+func (bm *BackupManager) applyRetention(now time.Time) (removed []BackupSegment, _ error) {
+	bm.index.sort()
+	total := bm.index.Size()
+	for len(bm.index.Segments) > 1 {
+		oldest := bm.index.Segments[0]
+		switch {
+		case bm.cfg.MaxSegments > 0 && len(bm.index.Segments) > bm.cfg.MaxSegments:
+		case bm.cfg.MaxBytes > 0 && total > bm.cfg.MaxBytes:
+		case bm.cfg.MaxAge > 0 && oldest.LastEventTime.Before(now.Add(-bm.cfg.MaxAge)):
+		default:
+			return removed, nil
+		}
+		if err := os.Remove(oldest.Path(bm.cfg.Dir)); err != nil && !os.IsNotExist(err) {
+			return removed, errors.WithStack(err)
+		}
+		total -= oldest.Size
+		removed = append(removed, oldest)
+		bm.index.Segments = bm.index.Segments[1:]
+	}
+	return removed, nil
+}
+
+// RestoreStep describes a single binlog file to replay.
+type RestoreStep struct {
+	Segment BackupSegment
+	// StartPos defines the position where to start replaying, compare with
+	// mysqlbinlog --start-position.
+	StartPos uint32
+	// StopPos defines the position where to stop replaying, compare with
+	// mysqlbinlog --stop-position. Zero means until the end of the file.
+	StopPos uint32
+}
+
+// RestorePlan contains the ordered list of binlog files and positions which
+// must be replayed on top of a base dump to reach the target time.
+type RestorePlan struct {
+	Base   ddl.MasterStatus
+	Target time.Time
+	Steps  []RestoreStep
+	// Complete is false when the archived binlogs end before the target time.
+	Complete bool
+}
+
+// WriteTo writes a human readable representation of the plan to w, one step
+// per line.
+func (rp RestorePlan) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# base %s target %s complete %t\n", rp.Base.String(), rp.Target.UTC().Format(time.RFC3339), rp.Complete)
+	for _, s := range rp.Steps {
+		fmt.Fprintf(&buf, "%s start-position=%d", s.Segment.Archive, s.StartPos)
+		if s.StopPos > 0 {
+			fmt.Fprintf(&buf, " stop-position=%d", s.StopPos)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.WriteTo(w)
+}
+
+// PlanRestore calculates the binlog files and positions which must be
+// replayed on top of a base dump, taken at position base, to restore the
+// database to its state at the target time. The stop position in the last
+// file points to the end of the last transaction committed at or before the
+// target.
+func (bm *BackupManager) PlanRestore(base ddl.MasterStatus, target time.Time) (*RestorePlan, error) {
+	idx := bm.Index()
+	rp := &RestorePlan{
+		Base:   base,
+		Target: target,
+	}
+	if len(idx.Segments) == 0 {
+		return nil, errors.NotFound.Newf("[myreplicator] No archived binlog files found in %q", bm.cfg.Dir)
+	}
+	if _, ok := idx.Find(base.File); !ok {
+		return nil, errors.NotFound.Newf("[myreplicator] Base binlog file %q not found in backup index", base.File)
+	}
+
+	var prev string
+	for _, seg := range idx.Segments {
+		if seg.File < base.File {
+			continue
+		}
+		if prev != "" && nextBinlogFileName(prev) != seg.File {
+			return nil, errors.NotFound.Newf("[myreplicator] Gap in binlog archive: %q does not follow %q", seg.File, prev)
+		}
+		prev = seg.File
+
+		step := RestoreStep{Segment: seg, StartPos: seg.StartPos}
+		if seg.File == base.File {
+			step.StartPos = uint32(base.Position)
+		}
+		if step.StartPos < uint32(len(BinLogFileHeader)) {
+			step.StartPos = uint32(len(BinLogFileHeader))
+		}
+
+		if !seg.LastEventTime.After(target) {
+			rp.Steps = append(rp.Steps, step)
+			continue
+		}
+
+		stop, err := bm.stopPosition(seg, step.StartPos, target)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if stop > step.StartPos {
+			step.StopPos = stop
+			rp.Steps = append(rp.Steps, step)
+		}
+		rp.Complete = true
+		return rp, nil
+	}
+	return rp, nil
+}
+
+// stopPosition scans the archived file and returns the end position of the
+// last transaction committed at or before target.
+func (bm *BackupManager) stopPosition(seg BackupSegment, startPos uint32, target time.Time) (uint32, error) {
+	rc, err := seg.Open(bm.cfg.Dir)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	defer rc.Close()
+
+	var stop uint32
+	err = scanRawEvents(rc, func(h *EventHeader, body []byte) bool {
+		if int64(h.Timestamp) > target.Unix() {
+			return false
+		}
+		if h.LogPos > startPos && isTransactionBoundary(h, body) {
+			stop = h.LogPos
+		}
+		return true
+	})
+	return stop, errors.WithStack(err)
+}
+
+// isTransactionBoundary reports whether the event ends a transaction, which
+// is an XID event or any query event except BEGIN.
+func isTransactionBoundary(h *EventHeader, body []byte) bool {
+	switch h.EventType {
+	case XID_EVENT:
+		return true
+	case QUERY_EVENT:
+		// post header: slave_proxy_id(4) exec_time(4) schema_length(1)
+		// error_code(2) status_vars_length(2)
+		if len(body) < 13 {
+			return false
+		}
+		schemaLen := int(body[8])
+		statusLen := int(binary.LittleEndian.Uint16(body[11:]))
+		pos := 13 + statusLen + schemaLen + 1
+		if pos > len(body) {
+			return false
+		}
+		return !bytes.HasPrefix(body[pos:], []byte("BEGIN"))
+	}
+	return false
+}
+
+// scanRawEvents reads a binlog file including its magic header and calls fn
+// for each event without decoding it. fn returns false to stop scanning.
+func scanRawEvents(r io.Reader, fn func(h *EventHeader, body []byte) bool) error {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(BinLogFileHeader))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return errors.WithStack(err)
+	}
+	if !bytes.Equal(magic, BinLogFileHeader) {
+		return errors.NotValid.Newf("[myreplicator] Invalid binlog file header %q", magic)
+	}
+	var hdr [EventHeaderSize]byte
+	for {
+		if _, err := io.ReadFull(br, hdr[:]); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.WithStack(err)
+		}
+		h := new(EventHeader)
+		if err := h.decode(hdr[:]); err != nil {
+			return errors.WithStack(err)
+		}
+		if h.EventSize < EventHeaderSize {
+			return errors.CorruptData.Newf("[myreplicator] Invalid event size %d", h.EventSize)
+		}
+		body := make([]byte, h.EventSize-EventHeaderSize)
+		if _, err := io.ReadFull(br, body); err != nil {
+			return errors.WithStack(err)
+		}
+		if !fn(h, body) {
+			return nil
+		}
+	}
+}
+
+// nextBinlogFileName increments the numeric extension of a binlog file name
+// while keeping its width: mysql-bin.000009 => mysql-bin.000010.
+func nextBinlogFileName(file string) string {
+	ext := filepath.Ext(file)
+	if len(ext) < 2 {
+		return ""
+	}
+	n, err := strconv.ParseUint(ext[1:], 10, 64)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s.%0*d", file[:len(file)-len(ext)], len(ext)-1, n+1)
+}
+
+func gzipFile(src, dst string, level int) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		if err2 := out.Close(); err == nil {
+			err = errors.WithStack(err2)
+		}
+	}()
+	zw, err := gzip.NewWriterLevel(out, level)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := io.Copy(zw, in); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(zw.Close())
+}
+
+func fileChecksum(name string) (string, int64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", 0, errors.WithStack(err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, errors.WithStack(err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
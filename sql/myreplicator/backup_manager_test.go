@@ -0,0 +1,166 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package myreplicator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/corestoreio/errors"
+	"github.com/corestoreio/pkg/sql/ddl"
+	"github.com/corestoreio/pkg/util/assert"
+)
+
+// fakeBinlog creates raw binlog events with increasing positions.
+type fakeBinlog struct {
+	pos uint32
+}
+
+func (fb *fakeBinlog) event(et EventType, ts uint32, body []byte) *BinlogEvent {
+	if fb.pos == 0 {
+		fb.pos = uint32(len(BinLogFileHeader))
+	}
+	size := uint32(EventHeaderSize + len(body))
+	fb.pos += size
+	h := &EventHeader{
+		Timestamp: ts,
+		EventType: et,
+		ServerID:  1,
+		EventSize: size,
+		LogPos:    fb.pos,
+	}
+	raw := make([]byte, EventHeaderSize, size)
+	binary.LittleEndian.PutUint32(raw[0:], h.Timestamp)
+	raw[4] = byte(h.EventType)
+	binary.LittleEndian.PutUint32(raw[5:], h.ServerID)
+	binary.LittleEndian.PutUint32(raw[9:], h.EventSize)
+	binary.LittleEndian.PutUint32(raw[13:], h.LogPos)
+	raw = append(raw, body...)
+	return &BinlogEvent{RawData: raw, Header: h}
+}
+
+func fakeQueryBody(query string) []byte {
+	body := make([]byte, 13)
+	// schema length 0, status vars length 0
+	body = append(body, 0x00)
+	return append(body, query...)
+}
+
+func fakeGTIDBody(gno uint64) []byte {
+	body := make([]byte, 1+SidLength+8)
+	for i := 1; i <= SidLength; i++ {
+		body[i] = byte(i)
+	}
+	binary.LittleEndian.PutUint64(body[1+SidLength:], gno)
+	return body
+}
+
+// writeFakeFile feeds a complete binlog file with one transaction per
+// timestamp into the manager.
+func writeFakeFile(t *testing.T, bm *BackupManager, file string, gno uint64, timestamps ...uint32) {
+	fb := &fakeBinlog{}
+	rotate := &BinlogEvent{
+		Header: &EventHeader{EventType: ROTATE_EVENT},
+		Event:  &RotateEvent{Position: 4, NextLogName: []byte(file)},
+	}
+	assert.NoError(t, bm.handleEvent(rotate))
+	assert.NoError(t, bm.handleEvent(fb.event(FORMAT_DESCRIPTION_EVENT, timestamps[0], make([]byte, 20))))
+	for _, ts := range timestamps {
+		assert.NoError(t, bm.handleEvent(fb.event(GTID_EVENT, ts, fakeGTIDBody(gno))))
+		assert.NoError(t, bm.handleEvent(fb.event(QUERY_EVENT, ts, fakeQueryBody("BEGIN"))))
+		assert.NoError(t, bm.handleEvent(fb.event(WRITE_ROWS_EVENTv2, ts, make([]byte, 10))))
+		assert.NoError(t, bm.handleEvent(fb.event(XID_EVENT, ts, make([]byte, 8))))
+		gno++
+	}
+}
+
+func TestBackupManager(t *testing.T) {
+	dir := t.TempDir()
+	bm, err := NewBackupManager(nil, BackupConfig{
+		Dir:         dir,
+		Compress:    true,
+		MaxSegments: 3,
+	})
+	assert.NoError(t, err)
+
+	writeFakeFile(t, bm, "mysql-bin.000001", 1, 1000, 1010, 1020)
+	writeFakeFile(t, bm, "mysql-bin.000002", 4, 2000, 2010, 2020)
+	writeFakeFile(t, bm, "mysql-bin.000003", 7, 3000, 3010, 3020)
+	writeFakeFile(t, bm, "mysql-bin.000004", 10, 4000)
+	// forces archiving of the last file
+	assert.NoError(t, bm.finishCurrent())
+
+	t.Run("index and retention", func(t *testing.T) {
+		idx, err := LoadBackupIndex(dir)
+		assert.NoError(t, err)
+		assert.Len(t, idx.Segments, 3)
+		seg := idx.Segments[0]
+		assert.Exactly(t, "mysql-bin.000002", seg.File)
+		assert.Exactly(t, "mysql-bin.000002.gz", seg.Archive)
+		assert.True(t, seg.Compressed)
+		assert.Exactly(t, time.Unix(2000, 0).UTC(), seg.FirstEventTime)
+		assert.Exactly(t, time.Unix(2020, 0).UTC(), seg.LastEventTime)
+		assert.Exactly(t, "01020304-0506-0708-090a-0b0c0d0e0f10:4", seg.FirstGTID)
+		assert.Exactly(t, "01020304-0506-0708-090a-0b0c0d0e0f10:6", seg.LastGTID)
+		assert.NoError(t, seg.Verify(dir))
+	})
+
+	t.Run("plan restore complete", func(t *testing.T) {
+		rp, err := bm.PlanRestore(ddl.MasterStatus{File: "mysql-bin.000002", Position: 100}, time.Unix(3015, 0))
+		assert.NoError(t, err)
+		assert.True(t, rp.Complete)
+		assert.Len(t, rp.Steps, 2)
+		assert.Exactly(t, uint32(100), rp.Steps[0].StartPos)
+		assert.Exactly(t, uint32(0), rp.Steps[0].StopPos)
+		// two transactions in the last file, each with 4 events
+		fb := &fakeBinlog{}
+		fb.event(FORMAT_DESCRIPTION_EVENT, 0, make([]byte, 20))
+		var stop uint32
+		for i := 0; i < 2; i++ {
+			fb.event(GTID_EVENT, 0, fakeGTIDBody(0))
+			fb.event(QUERY_EVENT, 0, fakeQueryBody("BEGIN"))
+			fb.event(WRITE_ROWS_EVENTv2, 0, make([]byte, 10))
+			stop = fb.event(XID_EVENT, 0, make([]byte, 8)).Header.LogPos
+		}
+		assert.Exactly(t, stop, rp.Steps[1].StopPos)
+
+		var buf bytes.Buffer
+		_, err = rp.WriteTo(&buf)
+		assert.NoError(t, err)
+		assert.True(t, strings.Contains(buf.String(), "mysql-bin.000003.gz start-position=4 stop-position="), buf.String())
+	})
+
+	t.Run("plan restore incomplete", func(t *testing.T) {
+		rp, err := bm.PlanRestore(ddl.MasterStatus{File: "mysql-bin.000003", Position: 4}, time.Unix(9000, 0))
+		assert.NoError(t, err)
+		assert.False(t, rp.Complete)
+		assert.Len(t, rp.Steps, 2)
+	})
+
+	t.Run("base not found", func(t *testing.T) {
+		rp, err := bm.PlanRestore(ddl.MasterStatus{File: "mysql-bin.000001", Position: 4}, time.Unix(9000, 0))
+		assert.ErrorIsKind(t, errors.NotFound, err)
+		assert.Nil(t, rp)
+	})
+}
+
+func TestNextBinlogFileName(t *testing.T) {
+	assert.Exactly(t, "mysql-bin.000010", nextBinlogFileName("mysql-bin.000009"))
+	assert.Exactly(t, "mariadb-bin.1000000", nextBinlogFileName("mariadb-bin.999999"))
+	assert.Exactly(t, "", nextBinlogFileName("mysql-bin"))
+}
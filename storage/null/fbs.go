@@ -0,0 +1,91 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package null
+
+import (
+	"time"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// The functions in this file implement the FlatBuffers encoding of the types
+// defined in null.fbs. They get used by the code generated with
+// sql/dmlgen.
+
+// PrependFBSSlot writes the time as inline struct null.Time into the slot of
+// the current object. Must be called between StartObject and EndObject. A
+// NULL time does not get written.
+func (a Time) PrependFBSSlot(b *flatbuffers.Builder, slot int) {
+	if !a.Valid {
+		return
+	}
+	b.Prep(8, 16)
+	b.Pad(4)
+	b.PrependInt32(int32(a.Time.Nanosecond()))
+	b.PrependInt64(a.Time.Unix())
+	b.Slot(slot)
+}
+
+// FBSTime reads the inline struct null.Time from the vtable offset of tab. An
+// absent field returns a NULL time.
+func FBSTime(tab *flatbuffers.Table, vtOffset flatbuffers.VOffsetT) Time {
+	o := flatbuffers.UOffsetT(tab.Offset(vtOffset))
+	if o == 0 {
+		return Time{}
+	}
+	x := tab.Pos + o
+	return MakeTime(time.Unix(tab.GetInt64(x), int64(tab.GetInt32(x+8))))
+}
+
+// CreateFBS writes the decimal as table null.Decimal and returns its offset.
+// Must be called before StartObject of the parent object. A NULL decimal
+// returns zero, which omits the field.
+func (d Decimal) CreateFBS(b *flatbuffers.Builder) flatbuffers.UOffsetT {
+	if !d.Valid {
+		return 0
+	}
+	var ps flatbuffers.UOffsetT
+	if d.PrecisionStr != "" {
+		ps = b.CreateString(d.PrecisionStr)
+	}
+	b.StartObject(5)
+	b.PrependUint64Slot(1, d.Precision, 0)
+	b.PrependUOffsetTSlot(0, ps, 0)
+	b.PrependInt32Slot(2, d.Scale, 0)
+	b.PrependBoolSlot(3, d.Negative, false)
+	b.PrependBoolSlot(4, d.Quote, false)
+	return b.EndObject()
+}
+
+// FBSDecimal reads the table null.Decimal from the vtable offset of tab. An
+// absent field returns a NULL decimal.
+func FBSDecimal(tab *flatbuffers.Table, vtOffset flatbuffers.VOffsetT) Decimal {
+	o := flatbuffers.UOffsetT(tab.Offset(vtOffset))
+	if o == 0 {
+		return Decimal{}
+	}
+	dt := flatbuffers.Table{Bytes: tab.Bytes, Pos: tab.Indirect(tab.Pos + o)}
+	d := Decimal{
+		Precision: dt.GetUint64Slot(6, 0),
+		Scale:     dt.GetInt32Slot(8, 0),
+		Negative:  dt.GetBoolSlot(10, false),
+		Quote:     dt.GetBoolSlot(12, false),
+		Valid:     true,
+	}
+	if o := flatbuffers.UOffsetT(dt.Offset(4)); o != 0 {
+		d.PrecisionStr = dt.String(dt.Pos + o)
+	}
+	return d
+}
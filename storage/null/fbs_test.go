@@ -0,0 +1,53 @@
+// Copyright 2015-present, Cyrill @ Schumacher.fm and the CoreStore contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package null
+
+import (
+	"testing"
+	"time"
+
+	"github.com/corestoreio/pkg/util/assert"
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+func TestFBS_RoundTrip(t *testing.T) {
+	write := func(tm Time, d Decimal) []byte {
+		b := flatbuffers.NewBuilder(0)
+		do := d.CreateFBS(b)
+		b.StartObject(2)
+		tm.PrependFBSSlot(b, 0)
+		b.PrependUOffsetTSlot(1, do, 0)
+		b.Finish(b.EndObject())
+		return b.FinishedBytes()
+	}
+	read := func(buf []byte) (Time, Decimal) {
+		tab := &flatbuffers.Table{Bytes: buf, Pos: flatbuffers.GetUOffsetT(buf)}
+		return FBSTime(tab, 4), FBSDecimal(tab, 6)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		now := time.Date(2024, 2, 29, 13, 14, 15, 123456789, time.UTC)
+		d := Decimal{PrecisionStr: "123456789012345678901234", Precision: 1234, Scale: 2, Negative: true, Quote: true, Valid: true}
+		tm, d2 := read(write(MakeTime(now), d))
+		assert.True(t, tm.Valid)
+		assert.True(t, now.Equal(tm.Time), "%s", tm.Time)
+		assert.Exactly(t, d, d2)
+	})
+	t.Run("NULL", func(t *testing.T) {
+		tm, d := read(write(Time{}, Decimal{}))
+		assert.Exactly(t, Time{}, tm)
+		assert.Exactly(t, Decimal{}, d)
+	})
+}
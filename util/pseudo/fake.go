@@ -313,6 +313,30 @@ func (s *Service) SetLang(newLang string) error {
 	return nil
 }
 
+// Seed resets the random number generator. Seeding with a hash of an input
+// value generates the same fake data for the same input value, which allows a
+// consistent pseudonymization. Seed and the following generation must not run
+// concurrently with other generators of the same Service.
+func (s *Service) Seed(seed uint64) {
+	s.r.Seed(seed)
+}
+
+// FakeByTag generates a fake value for a tag name or an alias, e.g. "email",
+// "first_name" or "telephone". The tags are the same as in the struct tag
+// `faker`. Returns false if the tag is not known.
+func (s *Service) FakeByTag(tag string, maxLen int) (interface{}, bool) {
+	s.mu.RLock()
+	if fnAlias, ok := s.funcsAliases[tag]; ok && fnAlias != "" {
+		tag = fnAlias
+	}
+	fn, ok := s.funcs[tag]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return fn(maxLen), true
+}
+
 func join(parts ...string) string {
 	var filtered []string
 	for _, part := range parts {
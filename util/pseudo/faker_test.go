@@ -156,6 +156,22 @@ func TestFakerData(t *testing.T) {
 	// {Int:8906957488773767119 Int8:6 Int16:14 Int32:391219825 Int64:2374447092794071106 String:poraKzAxVbWVkMkpcZCcWlYMd Bool:false SString:[MehdV aVotHsi] SInt:[528955241289647236 7620047312653801973 2774096449863851732] SInt8:[122 -92 -92] SInt16:[15679 -19444 -30246] SInt32:[1146660378 946021799 852909987] SInt64:[6079203475736033758 6913211867841842836 3269201978513619428] SFloat32:[0.019562425 0.12729558 0.36450312] SFloat64:[0.7825838989890364 0.9732903338838912 0.8316541489234004] SBool:[true false true] Struct:{Number:7693944638490551161 Height:6513508020379591917}}
 }
 
+func TestService_FakeByTag(t *testing.T) {
+	s := MustNewService(0, nil)
+
+	s.Seed(4711)
+	v1, ok := s.FakeByTag("telephone", 0) // alias
+	assert.True(t, ok)
+	s.Seed(4711)
+	v2, ok := s.FakeByTag("phone_number", 0)
+	assert.True(t, ok)
+	assert.Exactly(t, v1, v2)
+
+	v3, ok := s.FakeByTag("not_existent", 0)
+	assert.False(t, ok)
+	assert.Nil(t, v3)
+}
+
 func TestUnsuportedMapStringInterface(t *testing.T) {
 	s := MustNewService(0, nil)
 